@@ -36,13 +36,24 @@ type CapabilityScanConfigSpec struct {
 	// +kubebuilder:validation:Maximum=300
 	// +optional
 	DebounceWindowSeconds int `json:"debounceWindowSeconds,omitempty"`
+
+	// Notifications configures Slack, Google Chat or NotificationChannel notifications
+	// sent when this CapabilityScanConfig fails its scan, and when it recovers
+	// +optional
+	Notifications *StatusNotificationConfig `json:"notifications,omitempty"`
 }
 
 // MCPCapabilityConfig holds MCP server configuration for capability scanning
 type MCPCapabilityConfig struct {
 	// Endpoint is the MCP server URL
-	// +required
-	Endpoint string `json:"endpoint"`
+	// Required unless mcpServerRef is set.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// McpServerRef is the name of a cluster-scoped MCPServer that provides the URL, auth and TLS settings.
+	// When set, endpoint, authSecretRef, auth, tls and proxyURL are ignored.
+	// +optional
+	McpServerRef string `json:"mcpServerRef,omitempty"`
 
 	// Collection is the Qdrant collection name for storing capabilities
 	// +kubebuilder:default=capabilities
@@ -51,8 +62,23 @@ type MCPCapabilityConfig struct {
 
 	// AuthSecretRef references a Kubernetes Secret containing the MCP authentication token
 	// The Secret must exist in the same namespace as the CapabilityScanConfig
-	// +required
-	AuthSecretRef SecretReference `json:"authSecretRef"`
+	// Required with the bearer auth mode (default); ignored by other modes.
+	// +optional
+	AuthSecretRef SecretReference `json:"authSecretRef,omitempty"`
+
+	// Auth selects an alternative authentication mode (mTLS, OAuth2 or ServiceAccount token)
+	// +optional
+	Auth *McpAuthConfig `json:"auth,omitempty"`
+
+	// TLS configures the CA bundle, minimum version and server name of TLS connections to the MCP server
+	// +optional
+	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// ProxyURL is the HTTP proxy used to reach the MCP server
+	// Defaults to the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables of the controller.
+	// +kubebuilder:validation:Pattern=`^https?://.*`
+	// +optional
+	ProxyURL string `json:"proxyURL,omitempty"`
 }
 
 // RetryConfig defines retry behavior for MCP API calls
@@ -94,6 +120,10 @@ type CapabilityScanConfigStatus struct {
 	// +optional
 	LastError string `json:"lastError,omitempty"`
 
+	// Notifications records the last notified health state to de-duplicate notifications
+	// +optional
+	Notifications StatusNotificationState `json:"notifications,omitempty"`
+
 	// Current conditions of the config
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
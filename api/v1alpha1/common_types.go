@@ -1,5 +1,9 @@
 package v1alpha1
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // Common types shared across multiple CRDs
 
 // SecretReference references a key in a Kubernetes Secret
@@ -12,3 +16,193 @@ type SecretReference struct {
 	// +required
 	Key string `json:"key"`
 }
+
+// MCP authentication modes
+const (
+	// McpAuthModeBearer sends a static bearer token from the auth Secret (default)
+	McpAuthModeBearer = "bearer"
+	// McpAuthModeMTLS authenticates with a client certificate from a TLS Secret
+	McpAuthModeMTLS = "mtls"
+	// McpAuthModeOAuth2 sends an access token obtained with the OAuth2 client-credentials grant
+	McpAuthModeOAuth2 = "oauth2"
+	// McpAuthModeServiceAccountToken sends a short-lived ServiceAccount token requested for an audience
+	McpAuthModeServiceAccountToken = "serviceAccountToken"
+)
+
+// McpAuthConfig selects how the controller authenticates to an MCP endpoint.
+// Without it, the static bearer token from the auth Secret reference is used.
+type McpAuthConfig struct {
+	// Mode is the authentication mode
+	// +kubebuilder:validation:Enum=bearer;mtls;oauth2;serviceAccountToken
+	// +kubebuilder:default=bearer
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// MTLS configures client certificate authentication (mode mtls)
+	// +optional
+	MTLS *McpMTLSConfig `json:"mtls,omitempty"`
+
+	// OAuth2 configures the OAuth2 client-credentials grant (mode oauth2)
+	// +optional
+	OAuth2 *McpOAuth2Config `json:"oauth2,omitempty"`
+
+	// ServiceAccountToken configures projected ServiceAccount tokens (mode serviceAccountToken)
+	// +optional
+	ServiceAccountToken *McpServiceAccountTokenConfig `json:"serviceAccountToken,omitempty"`
+}
+
+// GetMode returns the authentication mode (default bearer)
+func (c *McpAuthConfig) GetMode() string {
+	if c == nil || c.Mode == "" {
+		return McpAuthModeBearer
+	}
+	return c.Mode
+}
+
+// McpMTLSConfig configures client certificate authentication
+type McpMTLSConfig struct {
+	// SecretName is a kubernetes.io/tls Secret in the same namespace as the resource.
+	// It must contain tls.crt and tls.key, and may contain ca.crt to verify the server.
+	// +required
+	SecretName string `json:"secretName"`
+}
+
+// McpOAuth2Config configures the OAuth2 client-credentials grant.
+// Access tokens are cached and refreshed shortly before they expire.
+type McpOAuth2Config struct {
+	// TokenURL is the token endpoint of the authorization server
+	// +kubebuilder:validation:Pattern=`^https?://.*`
+	// +required
+	TokenURL string `json:"tokenURL"`
+
+	// ClientID is the OAuth2 client ID
+	// +required
+	ClientID string `json:"clientID"`
+
+	// ClientSecretRef references the Secret key containing the OAuth2 client secret
+	// +required
+	ClientSecretRef SecretReference `json:"clientSecretRef"`
+
+	// Scopes are the requested scopes
+	// +optional
+	Scopes []string `json:"scopes,omitempty"`
+
+	// Audience is sent as the audience parameter when the authorization server requires it
+	// +optional
+	Audience string `json:"audience,omitempty"`
+}
+
+// McpServiceAccountTokenConfig configures short-lived ServiceAccount tokens requested
+// with the TokenRequest API. Tokens are cached and refreshed before they expire.
+type McpServiceAccountTokenConfig struct {
+	// ServiceAccountName is a ServiceAccount in the same namespace as the resource.
+	// It must list the audience in its dot-ai.devopstoolkit.live/mcp-token-audiences annotation.
+	// +kubebuilder:default=default
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Audience is the intended audience of the token, as expected by the MCP server.
+	// Audiences of the Kubernetes API server are rejected.
+	// +required
+	Audience string `json:"audience"`
+
+	// ExpirationSeconds is the requested token lifetime
+	// +kubebuilder:default=3600
+	// +kubebuilder:validation:Minimum=600
+	// +kubebuilder:validation:Maximum=86400
+	// +optional
+	ExpirationSeconds int64 `json:"expirationSeconds,omitempty"`
+}
+
+// ConfigMapReference references a key in a Kubernetes ConfigMap
+type ConfigMapReference struct {
+	// Name of the ConfigMap in the same namespace as the resource
+	// +required
+	Name string `json:"name"`
+
+	// Key within the ConfigMap containing the value
+	// +required
+	Key string `json:"key"`
+}
+
+// Minimum TLS versions
+const (
+	// TLSVersion12 requires TLS 1.2 or later (default)
+	TLSVersion12 = "1.2"
+	// TLSVersion13 requires TLS 1.3
+	TLSVersion13 = "1.3"
+)
+
+// TLSConfig configures TLS for connections to an MCP server or a notification webhook
+type TLSConfig struct {
+	// CASecretRef references a Secret key containing PEM-encoded CA certificates that verify the server.
+	// Without a CA bundle, the system roots are used.
+	// +optional
+	CASecretRef *SecretReference `json:"caSecretRef,omitempty"`
+
+	// CAConfigMapRef references a ConfigMap key containing PEM-encoded CA certificates that verify the server,
+	// e.g. a bundle distributed by trust-manager. It is combined with caSecretRef when both are set.
+	// +optional
+	CAConfigMapRef *ConfigMapReference `json:"caConfigMapRef,omitempty"`
+
+	// MinVersion is the minimum TLS version
+	// +kubebuilder:validation:Enum="1.2";"1.3"
+	// +kubebuilder:default="1.2"
+	// +optional
+	MinVersion string `json:"minVersion,omitempty"`
+
+	// ServerName overrides the server name sent with SNI and verified in the server certificate
+	// Use it when the endpoint is reached through an address that is not in the certificate.
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+
+	// InsecureSkipVerify disables verification of the server certificate (testing only)
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// StatusNotificationConfig configures notifications sent when a resource becomes
+// unhealthy or recovers. Notifications fire only on transitions, so a resource that
+// stays unhealthy across many reconciliations produces a single message.
+type StatusNotificationConfig struct {
+	// Channels are names of NotificationChannels in the same namespace as the resource
+	// +optional
+	Channels []string `json:"channels,omitempty"`
+
+	// Slack sends notifications to a Slack webhook
+	// +optional
+	Slack *SlackRouteTarget `json:"slack,omitempty"`
+
+	// GoogleChat sends notifications to a Google Chat webhook
+	// +optional
+	GoogleChat *GoogleChatRouteTarget `json:"googleChat,omitempty"`
+
+	// NotifyOnRecovery sends a notification when the resource becomes healthy again
+	// +kubebuilder:default=true
+	// +optional
+	NotifyOnRecovery *bool `json:"notifyOnRecovery,omitempty"`
+}
+
+// IsNotifyOnRecoveryEnabled returns whether recovery notifications are sent (default true)
+func (c *StatusNotificationConfig) IsNotifyOnRecoveryEnabled() bool {
+	if c.NotifyOnRecovery == nil {
+		return true
+	}
+	return *c.NotifyOnRecovery
+}
+
+// StatusNotificationState records the last health state that was notified.
+// It is used to de-duplicate notifications across reconciliations and restarts.
+type StatusNotificationState struct {
+	// State is the last notified health state (healthy or unhealthy)
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// Reason is the reason of the last state transition
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// LastTransitionTime is when the health state last changed
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
@@ -67,6 +67,11 @@ type GitKnowledgeSourceSpec struct {
 	// +kubebuilder:default=Delete
 	// +optional
 	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Notifications configures Slack, Google Chat or NotificationChannel notifications
+	// sent when this GitKnowledgeSource enters the Error phase, and when it recovers
+	// +optional
+	Notifications *StatusNotificationConfig `json:"notifications,omitempty"`
 }
 
 // RepositoryConfig defines the Git repository configuration
@@ -141,6 +146,10 @@ type GitKnowledgeSourceStatus struct {
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
+	// Notifications records the last notified health state to de-duplicate notifications
+	// +optional
+	Notifications StatusNotificationState `json:"notifications,omitempty"`
+
 	// Conditions represent the latest available observations of the GitKnowledgeSource's state
 	// +optional
 	// +patchMergeKey=type
@@ -7,13 +7,34 @@ package v1alpha1
 type McpServerConfig struct {
 	// URL is the MCP server endpoint
 	// Example: "http://mcp-server.dot-ai.svc:3456"
-	// +kubebuilder:validation:Required
+	// Required unless mcpServerRef is set.
 	// +kubebuilder:validation:Pattern=`^https?://.*`
-	URL string `json:"url"`
+	// +optional
+	URL string `json:"url,omitempty"`
 
-	// AuthSecretRef references a Secret containing the MCP authentication token
-	// +kubebuilder:validation:Required
-	AuthSecretRef SecretReference `json:"authSecretRef"`
+	// McpServerRef is the name of a cluster-scoped MCPServer that provides the URL, auth and TLS settings.
+	// When set, url, authSecretRef, auth, tls and proxyURL are ignored.
+	// +optional
+	McpServerRef string `json:"mcpServerRef,omitempty"`
+
+	// AuthSecretRef references a Secret containing the MCP authentication token.
+	// Required with the bearer auth mode (default); ignored by other modes.
+	// +optional
+	AuthSecretRef SecretReference `json:"authSecretRef,omitempty"`
+
+	// Auth selects an alternative authentication mode (mTLS, OAuth2 or ServiceAccount token)
+	// +optional
+	Auth *McpAuthConfig `json:"auth,omitempty"`
+
+	// TLS configures the CA bundle, minimum version and server name of TLS connections to the MCP server
+	// +optional
+	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// ProxyURL is the HTTP proxy used to reach the MCP server
+	// Defaults to the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables of the controller.
+	// +kubebuilder:validation:Pattern=`^https?://.*`
+	// +optional
+	ProxyURL string `json:"proxyURL,omitempty"`
 }
 
 // SkippedFile represents a file or document that was skipped during sync
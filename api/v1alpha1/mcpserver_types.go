@@ -0,0 +1,160 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MCPServerSpec defines the desired state of MCPServer
+type MCPServerSpec struct {
+	// URL is the base URL of the MCP server
+	// Resources that reference the server append the path of the API they call.
+	// Example: "http://dot-ai-mcp.dot-ai.svc:3456"
+	// +kubebuilder:validation:Pattern=`^https?://.*`
+	// +required
+	URL string `json:"url"`
+
+	// CredentialsNamespace is the namespace of the Secrets and ServiceAccount referenced by
+	// the auth and TLS settings. MCPServer is cluster-scoped, so Secret references need a namespace.
+	// +optional
+	CredentialsNamespace string `json:"credentialsNamespace,omitempty"`
+
+	// AuthSecretRef references a Secret in the credentials namespace containing the MCP authentication token.
+	// Required with the bearer auth mode (default); ignored by other modes.
+	// +optional
+	AuthSecretRef SecretReference `json:"authSecretRef,omitempty"`
+
+	// Auth selects an alternative authentication mode (mTLS, OAuth2 or ServiceAccount token)
+	// +optional
+	Auth *McpAuthConfig `json:"auth,omitempty"`
+
+	// TLS configures the CA bundle, minimum version and server name of TLS connections
+	// CA bundle references are resolved in the credentials namespace.
+	// +optional
+	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// ProxyURL is the HTTP proxy used to reach the server
+	// Defaults to the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables of the controller.
+	// +kubebuilder:validation:Pattern=`^https?://.*`
+	// +optional
+	ProxyURL string `json:"proxyURL,omitempty"`
+
+	// TimeoutSeconds is the timeout of requests to the server
+	// Defaults to the controller-wide MCP timeout (--mcp-timeout)
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+
+	// Retry sets the retry defaults for resource sync and knowledge sync requests.
+	// CapabilityScanConfig keeps its own retry settings, and remediation requests are not retried.
+	// +optional
+	Retry *RetryConfig `json:"retry,omitempty"`
+
+	// HealthCheck configures the periodic probe of the server
+	// +optional
+	HealthCheck MCPServerHealthCheck `json:"healthCheck,omitempty"`
+}
+
+// MCPServerHealthCheck defines the periodic probe of the server's health/version endpoint
+type MCPServerHealthCheck struct {
+	// Path is requested with GET to probe the server
+	// The server version is read from the JSON response (info.version, version or data.version).
+	// +kubebuilder:default="/api/v1/openapi"
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// IntervalSeconds is the time between probes
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	IntervalSeconds int `json:"intervalSeconds,omitempty"`
+}
+
+// MCPServerStatus defines the observed state of MCPServer
+type MCPServerStatus struct {
+	// Reachable indicates whether the last probe received a response from the server
+	// +optional
+	Reachable bool `json:"reachable,omitempty"`
+
+	// LatencyMilliseconds is the duration of the last successful probe
+	// +optional
+	LatencyMilliseconds int64 `json:"latencyMilliseconds,omitempty"`
+
+	// ServerVersion is the version reported by the server
+	// +optional
+	ServerVersion string `json:"serverVersion,omitempty"`
+
+	// LastProbeTime is the timestamp of the last probe
+	// +optional
+	LastProbeTime *metav1.Time `json:"lastProbeTime,omitempty"`
+
+	// LastError contains the most recent probe error
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// ObservedGeneration reflects the generation most recently observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// CredentialsVersion fingerprints the values of the referenced Secret keys.
+	// A change, e.g. a rotated Secret, triggers an immediate probe.
+	// +optional
+	CredentialsVersion string `json:"credentialsVersion,omitempty"`
+
+	// Conditions represent the latest available observations of the server's state
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=mcps
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.spec.url`,description="MCP server URL"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the server is ready"
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.serverVersion`,description="Server version"
+// +kubebuilder:printcolumn:name="Latency",type=integer,JSONPath=`.status.latencyMilliseconds`,description="Probe latency in milliseconds"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Time since creation"
+
+// MCPServer is the Schema for the mcpservers API
+// It holds the connection settings of an MCP server that other resources reference by name
+type MCPServer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MCPServerSpec   `json:"spec,omitempty"`
+	Status MCPServerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MCPServerList contains a list of MCPServer
+type MCPServerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MCPServer `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MCPServer{}, &MCPServerList{})
+}
+
+// GetHealthCheckPath returns the health check path with default
+func (r *MCPServer) GetHealthCheckPath() string {
+	if r.Spec.HealthCheck.Path == "" {
+		return "/api/v1/openapi"
+	}
+	return r.Spec.HealthCheck.Path
+}
+
+// GetHealthCheckIntervalSeconds returns the health check interval with default
+func (r *MCPServer) GetHealthCheckIntervalSeconds() int {
+	if r.Spec.HealthCheck.IntervalSeconds <= 0 {
+		return 60 // default 60 seconds
+	}
+	return r.Spec.HealthCheck.IntervalSeconds
+}
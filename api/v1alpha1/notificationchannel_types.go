@@ -0,0 +1,153 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Notification channel types
+const (
+	// NotificationChannelTypeSlack sends notifications to a Slack incoming webhook
+	NotificationChannelTypeSlack = "slack"
+	// NotificationChannelTypeGoogleChat sends notifications to a Google Chat webhook
+	NotificationChannelTypeGoogleChat = "googleChat"
+)
+
+// NotificationChannelSpec defines the desired state of NotificationChannel
+type NotificationChannelSpec struct {
+	// Type of the notification channel
+	// +kubebuilder:validation:Enum=slack;googleChat
+	// +required
+	Type string `json:"type"`
+
+	// WebhookUrlSecretRef references a Secret containing the webhook URL
+	// The Secret must exist in the same namespace as the NotificationChannel
+	// +required
+	WebhookUrlSecretRef SecretReference `json:"webhookUrlSecretRef"`
+
+	// Channel name (Slack only, for display purposes)
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// HealthCheck configures periodic webhook verification
+	// +optional
+	HealthCheck NotificationChannelHealthCheck `json:"healthCheck,omitempty"`
+
+	// TLS configures the CA bundle, minimum version and server name of TLS connections to the webhook
+	// +optional
+	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// ProxyURL is the HTTP proxy used to reach the webhook
+	// Defaults to the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables of the controller.
+	// +kubebuilder:validation:Pattern=`^https?://.*`
+	// +optional
+	ProxyURL string `json:"proxyURL,omitempty"`
+}
+
+// NotificationChannelHealthCheck defines periodic webhook health verification
+// The health check performs a dry send: an empty payload that the chat service
+// rejects without posting a message, which verifies the webhook exists and is authorized.
+type NotificationChannelHealthCheck struct {
+	// Enabled controls whether periodic health checks are performed
+	// +kubebuilder:default=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// IntervalMinutes is the time between health checks
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=5
+	// +kubebuilder:validation:Maximum=1440
+	// +optional
+	IntervalMinutes int `json:"intervalMinutes,omitempty"`
+}
+
+// NotificationChannelStatus defines the observed state of NotificationChannel
+type NotificationChannelStatus struct {
+	// Healthy indicates whether the last health check or delivery succeeded
+	// +optional
+	Healthy bool `json:"healthy,omitempty"`
+
+	// LastHealthCheckTime is the timestamp of the last health check
+	// +optional
+	LastHealthCheckTime *metav1.Time `json:"lastHealthCheckTime,omitempty"`
+
+	// SuccessfulDeliveries is the number of notifications delivered through this channel
+	// +optional
+	SuccessfulDeliveries int64 `json:"successfulDeliveries,omitempty"`
+
+	// FailedDeliveries is the number of notifications that failed to deliver through this channel
+	// +optional
+	FailedDeliveries int64 `json:"failedDeliveries,omitempty"`
+
+	// LastDeliveryTime is the timestamp of the last delivery attempt
+	// +optional
+	LastDeliveryTime *metav1.Time `json:"lastDeliveryTime,omitempty"`
+
+	// LastError contains the most recent health check or delivery error
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// ObservedGeneration reflects the generation most recently observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// CredentialsVersion fingerprints the values of the referenced Secret keys.
+	// A change, e.g. a rotated Secret, triggers an immediate health check.
+	// +optional
+	CredentialsVersion string `json:"credentialsVersion,omitempty"`
+
+	// Conditions represent the latest available observations of the channel's state
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced,shortName=nc
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`,description="Channel type"
+// +kubebuilder:printcolumn:name="Healthy",type=boolean,JSONPath=`.status.healthy`,description="Whether the channel is healthy"
+// +kubebuilder:printcolumn:name="Delivered",type=integer,JSONPath=`.status.successfulDeliveries`,description="Successful deliveries"
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failedDeliveries`,description="Failed deliveries"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Time since creation"
+
+// NotificationChannel is the Schema for the notificationchannels API
+// It defines a reusable notification destination that policies reference by name
+type NotificationChannel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NotificationChannelSpec   `json:"spec,omitempty"`
+	Status NotificationChannelStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NotificationChannelList contains a list of NotificationChannel
+type NotificationChannelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NotificationChannel `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NotificationChannel{}, &NotificationChannelList{})
+}
+
+// IsHealthCheckEnabled returns whether periodic health checks are enabled (default true)
+func (r *NotificationChannel) IsHealthCheckEnabled() bool {
+	if r.Spec.HealthCheck.Enabled == nil {
+		return true
+	}
+	return *r.Spec.HealthCheck.Enabled
+}
+
+// GetHealthCheckIntervalMinutes returns the health check interval with default
+func (r *NotificationChannel) GetHealthCheckIntervalMinutes() int {
+	if r.Spec.HealthCheck.IntervalMinutes <= 0 {
+		return 60 // default 60 minutes
+	}
+	return r.Spec.HealthCheck.IntervalMinutes
+}
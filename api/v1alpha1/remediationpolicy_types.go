@@ -82,6 +82,16 @@ type SlackConfig struct {
 	// +optional
 	Channel string `json:"channel,omitempty"`
 
+	// TLS configures the CA bundle, minimum version and server name of TLS connections to the webhook
+	// +optional
+	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// ProxyURL is the HTTP proxy used to reach the webhook
+	// Defaults to the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables of the controller.
+	// +kubebuilder:validation:Pattern=`^https?://.*`
+	// +optional
+	ProxyURL string `json:"proxyURL,omitempty"`
+
 	// Notify when remediation starts (optional, default false)
 	// +kubebuilder:default=false
 	// +optional
@@ -91,6 +101,12 @@ type SlackConfig struct {
 	// +kubebuilder:default=true
 	// +optional
 	NotifyOnComplete bool `json:"notifyOnComplete,omitempty"`
+
+	// Notify about progress reported by the MCP server while remediation runs (optional, default false)
+	// Requires mcpProtocol "jsonrpc"; updates are throttled to at most one message every 30 seconds
+	// +kubebuilder:default=false
+	// +optional
+	NotifyOnProgress bool `json:"notifyOnProgress,omitempty"`
 }
 
 // GoogleChatConfig defines Google Chat notification configuration
@@ -111,6 +127,76 @@ type GoogleChatConfig struct {
 	// +optional
 	WebhookUrlSecretRef *SecretReference `json:"webhookUrlSecretRef,omitempty"`
 
+	// TLS configures the CA bundle, minimum version and server name of TLS connections to the webhook
+	// +optional
+	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// ProxyURL is the HTTP proxy used to reach the webhook
+	// Defaults to the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables of the controller.
+	// +kubebuilder:validation:Pattern=`^https?://.*`
+	// +optional
+	ProxyURL string `json:"proxyURL,omitempty"`
+
+	// Notify when remediation starts (optional, default false)
+	// +kubebuilder:default=false
+	// +optional
+	NotifyOnStart bool `json:"notifyOnStart,omitempty"`
+
+	// Notify when remediation completes (default true)
+	// +kubebuilder:default=true
+	// +optional
+	NotifyOnComplete bool `json:"notifyOnComplete,omitempty"`
+
+	// Notify about progress reported by the MCP server while remediation runs (optional, default false)
+	// Requires mcpProtocol "jsonrpc"; updates are throttled to at most one message every 30 seconds
+	// +kubebuilder:default=false
+	// +optional
+	NotifyOnProgress bool `json:"notifyOnProgress,omitempty"`
+}
+
+// NotificationBatchingConfig defines digest settings for completion notifications
+// When enabled, completion notifications are buffered per channel for the configured
+// window and sent as a single summary message instead of one message per object.
+type NotificationBatchingConfig struct {
+	// Enable notification batching
+	// +kubebuilder:default=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// WindowSeconds is how long notifications are buffered before a summary is sent
+	// +kubebuilder:default=300
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	WindowSeconds int `json:"windowSeconds,omitempty"`
+
+	// SendFailuresImmediately sends an individual notification for failed remediations
+	// in addition to including them in the summary (default true)
+	// +kubebuilder:default=true
+	// +optional
+	SendFailuresImmediately *bool `json:"sendFailuresImmediately,omitempty"`
+
+	// LinkTemplate is an optional URL template used to link each object in the summary
+	// Supported placeholders: {namespace}, {kind}, {name}, {policy}
+	// Example: "https://console.example.com/ns/{namespace}/{kind}/{name}"
+	// +optional
+	LinkTemplate string `json:"linkTemplate,omitempty"`
+}
+
+// GetSendFailuresImmediately returns whether failures are also sent individually (default true)
+func (c *NotificationBatchingConfig) GetSendFailuresImmediately() bool {
+	if c == nil || c.SendFailuresImmediately == nil {
+		return true
+	}
+	return *c.SendFailuresImmediately
+}
+
+// NotificationChannelReference references a NotificationChannel by name
+type NotificationChannelReference struct {
+	// Name of the NotificationChannel in the same namespace as the RemediationPolicy
+	// +required
+	Name string `json:"name"`
+
 	// Notify when remediation starts (optional, default false)
 	// +kubebuilder:default=false
 	// +optional
@@ -122,6 +208,106 @@ type GoogleChatConfig struct {
 	NotifyOnComplete bool `json:"notifyOnComplete,omitempty"`
 }
 
+// NotificationRouteMatch defines criteria for matching a notification route
+// All specified criteria must match; empty criteria match everything
+type NotificationRouteMatch struct {
+	// Namespaces of the involved object, supports glob patterns (e.g., "prod-*")
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Outcomes of the remediation: "success" or "failure"
+	// +kubebuilder:validation:items:Enum=success;failure
+	// +optional
+	Outcomes []string `json:"outcomes,omitempty"`
+
+	// Modes of the remediation: "manual" or "automatic"
+	// +kubebuilder:validation:items:Enum=manual;automatic
+	// +optional
+	Modes []string `json:"modes,omitempty"`
+
+	// RiskLevels reported by MCP for the remediation: "low", "medium" or "high"
+	// +kubebuilder:validation:items:Enum=low;medium;high
+	// +optional
+	RiskLevels []string `json:"riskLevels,omitempty"`
+
+	// Executed matches whether MCP executed commands (true) or only recommended them (false)
+	// +optional
+	Executed *bool `json:"executed,omitempty"`
+
+	// Reasons of the triggering event (e.g., "BackOff", "FailedScheduling")
+	// +optional
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// SlackRouteTarget defines a Slack destination for a notification route
+type SlackRouteTarget struct {
+	// WebhookUrlSecretRef references a Secret containing the Slack webhook URL
+	// References a Secret in the same namespace as the RemediationPolicy
+	// +required
+	WebhookUrlSecretRef SecretReference `json:"webhookUrlSecretRef"`
+
+	// Slack channel (for display purposes only)
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// TLS configures the CA bundle, minimum version and server name of TLS connections to the webhook
+	// +optional
+	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// ProxyURL is the HTTP proxy used to reach the webhook
+	// Defaults to the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables of the controller.
+	// +kubebuilder:validation:Pattern=`^https?://.*`
+	// +optional
+	ProxyURL string `json:"proxyURL,omitempty"`
+}
+
+// GoogleChatRouteTarget defines a Google Chat destination for a notification route
+type GoogleChatRouteTarget struct {
+	// WebhookUrlSecretRef references a Secret containing the Google Chat webhook URL
+	// References a Secret in the same namespace as the RemediationPolicy
+	// +required
+	WebhookUrlSecretRef SecretReference `json:"webhookUrlSecretRef"`
+
+	// TLS configures the CA bundle, minimum version and server name of TLS connections to the webhook
+	// +optional
+	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// ProxyURL is the HTTP proxy used to reach the webhook
+	// Defaults to the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables of the controller.
+	// +kubebuilder:validation:Pattern=`^https?://.*`
+	// +optional
+	ProxyURL string `json:"proxyURL,omitempty"`
+}
+
+// NotificationRoute sends completion notifications matching criteria to a specific target
+type NotificationRoute struct {
+	// Name of the route (used in logs and events)
+	// +required
+	Name string `json:"name"`
+
+	// Match criteria evaluated after the MCP response is known
+	// +optional
+	Match NotificationRouteMatch `json:"match,omitempty"`
+
+	// Slack target for matching notifications
+	// +optional
+	Slack *SlackRouteTarget `json:"slack,omitempty"`
+
+	// Google Chat target for matching notifications
+	// +optional
+	GoogleChat *GoogleChatRouteTarget `json:"googleChat,omitempty"`
+
+	// ChannelRef is the name of a NotificationChannel (in the same namespace) for matching notifications
+	// +optional
+	ChannelRef string `json:"channelRef,omitempty"`
+
+	// Continue evaluating subsequent routes after this route matches
+	// By default the first matching route wins
+	// +kubebuilder:default=false
+	// +optional
+	Continue bool `json:"continue,omitempty"`
+}
+
 // NotificationConfig defines notification settings
 type NotificationConfig struct {
 	// Slack notification configuration
@@ -131,6 +317,22 @@ type NotificationConfig struct {
 	// Google Chat notification configuration
 	// +optional
 	GoogleChat GoogleChatConfig `json:"googleChat,omitempty"`
+
+	// Channels references reusable NotificationChannel resources by name
+	// Can be combined with the inline slack and googleChat settings
+	// +optional
+	Channels []NotificationChannelReference `json:"channels,omitempty"`
+
+	// Routes send completion notifications to additional targets based on match criteria
+	// Routes are evaluated in order after the MCP response is known, independently of
+	// the slack and googleChat settings above
+	// +optional
+	Routes []NotificationRoute `json:"routes,omitempty"`
+
+	// Batching configuration for digest-style completion notifications
+	// Applies to all enabled channels. Start notifications are suppressed while batching is enabled.
+	// +optional
+	Batching *NotificationBatchingConfig `json:"batching,omitempty"`
 }
 
 // PersistenceConfig defines cooldown state persistence settings
@@ -149,14 +351,45 @@ type RemediationPolicySpec struct {
 	EventSelectors []EventSelector `json:"eventSelectors"`
 
 	// MCP endpoint URL
-	// +required
-	McpEndpoint string `json:"mcpEndpoint"`
+	// Required unless mcpServerRef is set.
+	// +optional
+	McpEndpoint string `json:"mcpEndpoint,omitempty"`
+
+	// McpServerRef is the name of a cluster-scoped MCPServer that provides the URL, auth and TLS settings.
+	// The endpoint is the server URL followed by /api/v1/tools/<mcpTool>, or /mcp with the jsonrpc protocol.
+	// When set, mcpEndpoint, mcpAuthSecretRef, mcpAuth, mcpTLS and mcpProxyURL are ignored.
+	// +optional
+	McpServerRef string `json:"mcpServerRef,omitempty"`
 
 	// McpAuthSecretRef references a Kubernetes Secret containing the MCP authentication token
 	// The controller will include "Authorization: Bearer <token>" header in MCP requests
 	// The Secret must exist in the same namespace as the RemediationPolicy
-	// +required
-	McpAuthSecretRef SecretReference `json:"mcpAuthSecretRef"`
+	// Required with the bearer auth mode (default); ignored by other modes.
+	// +optional
+	McpAuthSecretRef SecretReference `json:"mcpAuthSecretRef,omitempty"`
+
+	// McpAuth selects an alternative MCP authentication mode (mTLS, OAuth2 or ServiceAccount token)
+	// +optional
+	McpAuth *McpAuthConfig `json:"mcpAuth,omitempty"`
+
+	// McpTLS configures the CA bundle, minimum version and server name of TLS connections to the MCP server
+	// +optional
+	McpTLS *TLSConfig `json:"mcpTLS,omitempty"`
+
+	// McpProxyURL is the HTTP proxy used to reach the MCP server
+	// Defaults to the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables of the controller.
+	// +kubebuilder:validation:Pattern=`^https?://.*`
+	// +optional
+	McpProxyURL string `json:"mcpProxyURL,omitempty"`
+
+	// McpProtocol selects how the controller calls the MCP server:
+	// "rest" posts the request to the dot-ai REST API (e.g., http://dot-ai/api/v1/tools/remediate),
+	// "jsonrpc" calls the tool with MCP JSON-RPC over Streamable HTTP (e.g., http://dot-ai/mcp)
+	// and reports progress notifications while the remediation runs
+	// +kubebuilder:validation:Enum=rest;jsonrpc
+	// +kubebuilder:default="rest"
+	// +optional
+	McpProtocol string `json:"mcpProtocol,omitempty"`
 
 	// MCP tool name (always "remediate")
 	// +kubebuilder:default="remediate"
@@ -194,6 +427,13 @@ type RemediationPolicySpec struct {
 	// When not specified, persistence is enabled by default
 	// +optional
 	Persistence *PersistenceConfig `json:"persistence,omitempty"`
+
+	// ResultHistoryLimit is the number of recent remediation results kept in status.recentResults
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=50
+	// +kubebuilder:default=10
+	// +optional
+	ResultHistoryLimit int `json:"resultHistoryLimit,omitempty"`
 }
 
 // McpRequest represents the JSON request structure sent to the MCP remediate tool
@@ -214,6 +454,153 @@ type McpRequest struct {
 	MaxRiskLevel string `json:"maxRiskLevel,omitempty"`
 }
 
+// RemediationProgress describes a remediation in progress
+type RemediationProgress struct {
+	// Object is the involved object being remediated (Kind/namespace/name)
+	// +required
+	Object string `json:"object"`
+
+	// Timestamp when the remediation started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Completion percentage, when the MCP server reports a total
+	// +optional
+	Percent *int32 `json:"percent,omitempty"`
+
+	// Latest progress message reported by the MCP server
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Timestamp of the latest progress update
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// RemediationAction is an action recommended or executed by the MCP server
+type RemediationAction struct {
+	// Command is the command of the action, e.g. a kubectl command
+	// +optional
+	Command string `json:"command,omitempty"`
+
+	// Description explains what the action does
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Risk is the risk level of the action (low, medium or high)
+	// +optional
+	Risk string `json:"risk,omitempty"`
+}
+
+// RemediationValidation is the outcome of the validation that follows executed actions
+type RemediationValidation struct {
+	// Success indicates whether the issue was resolved
+	// +required
+	Success bool `json:"success"`
+
+	// Message describes the validation outcome
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// RemediationResult is the typed result of a remediation reported by the MCP server
+type RemediationResult struct {
+	// SchemaVersion is the version of the result schema reported by the MCP server;
+	// results without a version are decoded with the latest schema (v1)
+	// +optional
+	SchemaVersion string `json:"schemaVersion,omitempty"`
+
+	// Message summarizes the result
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// RootCause is the root cause identified by the analysis
+	// +optional
+	RootCause string `json:"rootCause,omitempty"`
+
+	// Confidence is the overall confidence in the remediation (0.0-1.0)
+	// +optional
+	Confidence *float64 `json:"confidence,omitempty"`
+
+	// AnalysisConfidence is the confidence in the root cause analysis (0.0-1.0)
+	// +optional
+	AnalysisConfidence *float64 `json:"analysisConfidence,omitempty"`
+
+	// Risk is the overall risk level of the remediation (low, medium or high)
+	// +optional
+	Risk string `json:"risk,omitempty"`
+
+	// Actions are the recommended or executed actions
+	// +optional
+	Actions []RemediationAction `json:"actions,omitempty"`
+
+	// Executed indicates whether the actions were executed or only recommended
+	// +optional
+	Executed bool `json:"executed,omitempty"`
+
+	// ActionsTaken is the number of action results reported for executed actions
+	// +optional
+	ActionsTaken int `json:"actionsTaken,omitempty"`
+
+	// Validation is the outcome of the validation that follows executed actions
+	// +optional
+	Validation *RemediationValidation `json:"validation,omitempty"`
+}
+
+// RemediationResultRecord records a completed remediation in the policy status
+type RemediationResultRecord struct {
+	// Time is the timestamp when the remediation completed
+	// +required
+	Time metav1.Time `json:"time"`
+
+	// Object is the involved object of the event (Kind/namespace/name)
+	// +required
+	Object string `json:"object"`
+
+	// Reason is the reason of the event
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Success indicates whether the MCP server completed the remediation
+	// +required
+	Success bool `json:"success"`
+
+	// Error is the error reported by the MCP server for failed remediations
+	// +optional
+	Error string `json:"error,omitempty"`
+
+	// Result is the remediation result, for successful remediations
+	// +optional
+	Result *RemediationResult `json:"result,omitempty"`
+}
+
+// NotificationDeliveryStatus tracks notification deliveries for a single notification target
+type NotificationDeliveryStatus struct {
+	// Channel identifies the notification target (slack, googleChat, channel/<name> or route/<name>/<service>)
+	// +required
+	Channel string `json:"channel"`
+
+	// Number of notifications delivered successfully
+	// +optional
+	Delivered int64 `json:"delivered,omitempty"`
+
+	// Number of notifications that could not be delivered and were dropped
+	// +optional
+	Failed int64 `json:"failed,omitempty"`
+
+	// Number of notifications waiting in the dead-letter queue for redelivery
+	// +optional
+	Pending int `json:"pending,omitempty"`
+
+	// Timestamp of the last successful delivery
+	// +optional
+	LastDeliveryTime *metav1.Time `json:"lastDeliveryTime,omitempty"`
+
+	// Last delivery error, cleared on successful delivery
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
 // RemediationPolicyStatus defines the observed state of RemediationPolicy.
 type RemediationPolicyStatus struct {
 	// Timestamp of last processed event
@@ -248,6 +635,23 @@ type RemediationPolicyStatus struct {
 	// +optional
 	LastRateLimitedEvent *metav1.Time `json:"lastRateLimitedEvent,omitempty"`
 
+	// Remediations currently in progress with the latest progress reported by the MCP server
+	// Only populated when mcpProtocol is "jsonrpc"
+	// +optional
+	// +listType=map
+	// +listMapKey=object
+	ActiveRemediations []RemediationProgress `json:"activeRemediations,omitempty"`
+
+	// Notification delivery statistics per notification target
+	// +optional
+	// +listType=map
+	// +listMapKey=channel
+	NotificationDelivery []NotificationDeliveryStatus `json:"notificationDelivery,omitempty"`
+
+	// RecentResults are the most recent remediation results, newest first, bounded by spec.resultHistoryLimit
+	// +optional
+	RecentResults []RemediationResultRecord `json:"recentResults,omitempty"`
+
 	// Current conditions of the policy
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
@@ -292,3 +696,24 @@ type RemediationPolicyList struct {
 func init() {
 	SchemeBuilder.Register(&RemediationPolicy{}, &RemediationPolicyList{})
 }
+
+// IsBatchingEnabled returns true if notification batching is configured and enabled
+func (r *RemediationPolicy) IsBatchingEnabled() bool {
+	return r.Spec.Notifications.Batching != nil && r.Spec.Notifications.Batching.Enabled
+}
+
+// GetResultHistoryLimit returns the number of recent remediation results kept in status with default
+func (r *RemediationPolicy) GetResultHistoryLimit() int {
+	if r.Spec.ResultHistoryLimit <= 0 {
+		return 10
+	}
+	return r.Spec.ResultHistoryLimit
+}
+
+// GetBatchingWindowSeconds returns the notification batching window with default
+func (r *RemediationPolicy) GetBatchingWindowSeconds() int {
+	if r.Spec.Notifications.Batching == nil || r.Spec.Notifications.Batching.WindowSeconds <= 0 {
+		return 300 // default 5 minutes
+	}
+	return r.Spec.Notifications.Batching.WindowSeconds
+}
@@ -7,14 +7,48 @@ import (
 // ResourceSyncConfigSpec defines the desired state of ResourceSyncConfig
 type ResourceSyncConfigSpec struct {
 	// MCP endpoint URL for resource sync
-	// +required
-	McpEndpoint string `json:"mcpEndpoint"`
+	// Required unless mcpServerRef is set.
+	// +optional
+	McpEndpoint string `json:"mcpEndpoint,omitempty"`
+
+	// McpServerRef is the name of a cluster-scoped MCPServer that provides the URL, auth and TLS settings.
+	// The endpoint is the server URL followed by /api/v1/resources/sync.
+	// When set, mcpEndpoint, mcpAuthSecretRef, mcpAuth, mcpTLS and mcpProxyURL are ignored.
+	// +optional
+	McpServerRef string `json:"mcpServerRef,omitempty"`
 
 	// McpAuthSecretRef references a Kubernetes Secret containing the MCP authentication token
 	// The controller will include "Authorization: Bearer <token>" header in MCP requests
 	// The Secret must exist in the same namespace as the ResourceSyncConfig
-	// +required
-	McpAuthSecretRef SecretReference `json:"mcpAuthSecretRef"`
+	// Required with the bearer auth mode (default); ignored by other modes.
+	// +optional
+	McpAuthSecretRef SecretReference `json:"mcpAuthSecretRef,omitempty"`
+
+	// McpAuth selects an alternative MCP authentication mode (mTLS, OAuth2 or ServiceAccount token)
+	// +optional
+	McpAuth *McpAuthConfig `json:"mcpAuth,omitempty"`
+
+	// McpTLS configures the CA bundle, minimum version and server name of TLS connections to the MCP server
+	// +optional
+	McpTLS *TLSConfig `json:"mcpTLS,omitempty"`
+
+	// McpProxyURL is the HTTP proxy used to reach the MCP server
+	// Defaults to the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables of the controller.
+	// +kubebuilder:validation:Pattern=`^https?://.*`
+	// +optional
+	McpProxyURL string `json:"mcpProxyURL,omitempty"`
+
+	// Cluster selects a remote cluster to sync instead of the cluster the controller runs in
+	// +optional
+	Cluster *RemoteClusterConfig `json:"cluster,omitempty"`
+
+	// ClusterName identifies the synced cluster in MCP and is added to every synced resource
+	// Set it when several clusters sync to the same MCP server; resyncs then only replace
+	// the resources of this cluster. Required with cluster.kubeconfigSecretRef; defaults to
+	// cluster.clusterAPIClusterName for Cluster API clusters.
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
 
 	// DebounceWindowSeconds is the time window to collect changes before sending to MCP
 	// Multiple changes to the same resource within this window are batched together
@@ -24,6 +58,14 @@ type ResourceSyncConfigSpec struct {
 	// +optional
 	DebounceWindowSeconds int `json:"debounceWindowSeconds,omitempty"`
 
+	// ChangeQueueWaitSeconds is how long informer event handlers wait for room in the full change
+	// queue before dropping a change. 0 drops immediately. Waiting slows down event delivery for
+	// all watched resource types; dropped changes are recovered by a targeted resync either way.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=30
+	// +optional
+	ChangeQueueWaitSeconds int `json:"changeQueueWaitSeconds,omitempty"`
+
 	// ResyncIntervalMinutes is how often to perform a full resync with MCP
 	// This ensures eventual consistency by reconciling any missed changes
 	// +kubebuilder:default=60
@@ -31,6 +73,445 @@ type ResourceSyncConfigSpec struct {
 	// +kubebuilder:validation:Maximum=1440
 	// +optional
 	ResyncIntervalMinutes int `json:"resyncIntervalMinutes,omitempty"`
+
+	// ResyncChunkSize is the number of resources uploaded per request during a full resync
+	// Full resyncs are uploaded in chunks within a session that MCP commits once all chunks arrived
+	// +kubebuilder:default=1000
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=10000
+	// +optional
+	ResyncChunkSize int `json:"resyncChunkSize,omitempty"`
+
+	// ResyncMode selects how periodic resyncs reconcile MCP with the cluster
+	// Full uploads every resource; Incremental exchanges content hashes bucketed by namespace
+	// and resource type first and uploads only the resources that differ
+	// +kubebuilder:default=Full
+	// +optional
+	ResyncMode ResyncMode `json:"resyncMode,omitempty"`
+
+	// Relationships selects the relationships synced with each resource
+	// All syncs owner references and computed edges (Service selectors, Ingress and HTTPRoute
+	// backends, PVC volumes, and Pod references to ConfigMaps, Secrets, ServiceAccounts and PVCs)
+	// +kubebuilder:default=All
+	// +optional
+	Relationships RelationshipMode `json:"relationships,omitempty"`
+
+	// IncludeResources specifies patterns for resource types to sync
+	// Patterns support wildcards: "*.crossplane.io", "Deployment.apps", "Service"
+	// Format: "Kind.group" for grouped resources, "Kind" for core resources
+	// If empty, all resource types are included (subject to excludeResources)
+	// +optional
+	IncludeResources []string `json:"includeResources,omitempty"`
+
+	// ExcludeResources specifies patterns for resource types not to sync
+	// Applied after includeResources filtering
+	// Patterns support wildcards: "Secret", "ReplicaSet.apps", "*.internal.example.com"
+	// +optional
+	ExcludeResources []string `json:"excludeResources,omitempty"`
+
+	// NamespaceSelector selects the namespaces whose resources are synced by namespace labels
+	// Cluster-scoped resources are not affected. If empty, resources in all namespaces are synced.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// ObjectSelector selects the resources that are synced by their labels
+	// Resources whose labels stop matching are removed from MCP. If empty, all resources are synced.
+	// +optional
+	ObjectSelector *metav1.LabelSelector `json:"objectSelector,omitempty"`
+
+	// Fields configures additional fields extracted from resources and synced to MCP,
+	// such as container images, replicas, phase or a computed health
+	// Changes of extracted fields trigger a sync, unlike other spec and status changes
+	// +optional
+	Fields []ResourceFieldExtraction `json:"fields,omitempty"`
+
+	// AnnotationAllowlist specifies regular expressions for the annotation keys that are synced
+	// If empty, only "description" and "*/description" annotations are synced.
+	// kubectl.kubernetes.io/last-applied-configuration is never synced.
+	// +optional
+	AnnotationAllowlist []string `json:"annotationAllowlist,omitempty"`
+
+	// LabelDenylist specifies regular expressions for label keys that are not synced
+	// +optional
+	LabelDenylist []string `json:"labelDenylist,omitempty"`
+
+	// Redactions hash or mask parts of label, annotation and field values before they are synced
+	// Use them for values that must not leave the cluster in clear text, such as emails or tokens
+	// +optional
+	Redactions []RedactionRule `json:"redactions,omitempty"`
+
+	// RedactionKeySecretRef references a Secret key holding the HMAC key of hash redactions
+	// Required when a redaction uses the hash action. Rotating the key restarts the watcher,
+	// so every synced hash is recomputed with the new key.
+	// +optional
+	RedactionKeySecretRef *SecretReference `json:"redactionKeySecretRef,omitempty"`
+
+	// Notifications configures Slack, Google Chat or NotificationChannel notifications
+	// sent when this ResourceSyncConfig records sync errors or its watcher stops, and when it recovers
+	// +optional
+	Notifications *StatusNotificationConfig `json:"notifications,omitempty"`
+
+	// Sinks deliver the de-duplicated, debounced change stream to consumers other than MCP,
+	// such as HTTP webhooks, CloudEvents brokers or NDJSON files on a volume
+	// The MCP endpoint, when configured, remains the default sink named "mcp".
+	// Each sink retries failed batches independently and reports its own status.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	Sinks []ResourceSyncSink `json:"sinks,omitempty"`
+}
+
+// ResourceSyncSinkType is the type of a resource sync sink
+// +kubebuilder:validation:Enum=Webhook;CloudEvents;NDJSON
+type ResourceSyncSinkType string
+
+const (
+	// ResourceSyncSinkTypeWebhook posts batches of changes as JSON to an HTTP endpoint
+	ResourceSyncSinkTypeWebhook ResourceSyncSinkType = "Webhook"
+	// ResourceSyncSinkTypeCloudEvents posts changes as a batch of CloudEvents
+	ResourceSyncSinkTypeCloudEvents ResourceSyncSinkType = "CloudEvents"
+	// ResourceSyncSinkTypeNDJSON appends changes to a newline-delimited JSON file
+	ResourceSyncSinkTypeNDJSON ResourceSyncSinkType = "NDJSON"
+)
+
+// ResourceSyncSink configures a consumer of the resource change stream
+type ResourceSyncSink struct {
+	// Name identifies the sink in status, logs and metrics
+	// "mcp" is reserved for the MCP endpoint.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	// +required
+	Name string `json:"name"`
+
+	// Type of the sink
+	// +required
+	Type ResourceSyncSinkType `json:"type"`
+
+	// URL receives the changes of Webhook and CloudEvents sinks
+	// +kubebuilder:validation:Pattern=`^https?://.*`
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// AuthSecretRef references a Secret containing a bearer token sent to Webhook and CloudEvents sinks
+	// The Secret must exist in the same namespace as the ResourceSyncConfig
+	// +optional
+	AuthSecretRef *SecretReference `json:"authSecretRef,omitempty"`
+
+	// TLS configures the CA bundle, minimum version and server name of TLS connections to the sink
+	// +optional
+	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// Path of the file NDJSON sinks append to, relative to the directory of the namespace below the
+	// controller's sink directory (--resourcesync-sink-dir), for example "audit/changes.ndjson"
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// MaxFileSizeMB rotates the file of NDJSON sinks to "<path>.1" when it grows beyond this size
+	// +kubebuilder:default=100
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxFileSizeMB int `json:"maxFileSizeMB,omitempty"`
+
+	// MaxAttempts is the number of delivery attempts per flush, including the first one
+	// Batches that still fail are retried with the next flush.
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+}
+
+// GetMaxFileSizeMB returns the file size that rotates NDJSON files with default
+func (s *ResourceSyncSink) GetMaxFileSizeMB() int {
+	if s.MaxFileSizeMB <= 0 {
+		return 100 // default 100 MB
+	}
+	return s.MaxFileSizeMB
+}
+
+// GetMaxAttempts returns the delivery attempts per flush with default
+func (s *ResourceSyncSink) GetMaxAttempts() int {
+	if s.MaxAttempts <= 0 {
+		return 3 // default 3 attempts
+	}
+	return s.MaxAttempts
+}
+
+// RemoteClusterConfig selects the kubeconfig of a remote cluster
+// Exactly one of kubeconfigSecretRef and clusterAPIClusterName must be set
+type RemoteClusterConfig struct {
+	// KubeconfigSecretRef references a Secret key containing a kubeconfig for the remote cluster
+	// The Secret must exist in the same namespace as the ResourceSyncConfig
+	// +optional
+	KubeconfigSecretRef *SecretReference `json:"kubeconfigSecretRef,omitempty"`
+
+	// ClusterAPIClusterName is the name of a Cluster API Cluster in the same namespace
+	// as the ResourceSyncConfig. Its kubeconfig is read from the "<name>-kubeconfig" Secret
+	// maintained by Cluster API.
+	// +optional
+	ClusterAPIClusterName string `json:"clusterAPIClusterName,omitempty"`
+}
+
+// ResourceFieldExtraction configures the fields extracted from a set of resource types
+type ResourceFieldExtraction struct {
+	// Resources specifies patterns for the resource types the fields are extracted from
+	// Same format as includeResources: "Deployment.apps", "*.crossplane.io", "PersistentVolumeClaim"
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	Resources []string `json:"resources"`
+
+	// Fields are the extraction rules applied to matching resources
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	Fields []ResourceFieldRule `json:"fields"`
+}
+
+// ResourceFieldRule extracts a single field from a resource
+// Exactly one of jsonPath or cel must be set
+type ResourceFieldRule struct {
+	// Name of the field in the synced resource data
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+
+	// JSONPath is a kubectl-style JSONPath expression evaluated against the resource
+	// Example: "{.spec.template.spec.containers[*].image}"
+	// A single result is synced as a value, multiple results as a list
+	// +optional
+	JSONPath string `json:"jsonPath,omitempty"`
+
+	// CEL is a CEL expression evaluated with the resource bound to "object"
+	// Example: "object.status.readyReplicas == object.spec.replicas ? 'healthy' : 'degraded'"
+	// +optional
+	CEL string `json:"cel,omitempty"`
+
+	// ThrottleSeconds is the minimum time between syncs of a resource triggered by changes of this field
+	// Use it for fields that change frequently, such as replica counts. Throttled changes are
+	// synced with the next change or full resync. 0 syncs every change.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ThrottleSeconds int `json:"throttleSeconds,omitempty"`
+}
+
+// RedactionAction defines how matched values are redacted
+// +kubebuilder:validation:Enum=mask;hash
+type RedactionAction string
+
+const (
+	// RedactionActionMask replaces matched values with "[REDACTED]"
+	RedactionActionMask RedactionAction = "mask"
+	// RedactionActionHash replaces matched values with an HMAC-SHA256 of the redaction key, so equal
+	// values can still be matched without the values being recoverable from their hashes
+	RedactionActionHash RedactionAction = "hash"
+)
+
+// RedactionRule redacts the parts of synced values that match a regular expression
+type RedactionRule struct {
+	// Pattern is a regular expression matched against label, annotation and field values
+	// Example: "[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+" for emails
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Pattern string `json:"pattern"`
+
+	// Action is how matched parts are redacted
+	// +kubebuilder:default=mask
+	// +optional
+	Action RedactionAction `json:"action,omitempty"`
+
+	// Keys specifies regular expressions for the label, annotation and field keys the rule applies to
+	// If empty, the rule applies to all keys
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+}
+
+// ResyncMode selects how resyncs reconcile MCP with the cluster
+// +kubebuilder:validation:Enum=Full;Incremental
+type ResyncMode string
+
+const (
+	// ResyncModeFull uploads every resource on each resync
+	ResyncModeFull ResyncMode = "Full"
+	// ResyncModeIncremental compares content hashes and uploads only differing resources
+	ResyncModeIncremental ResyncMode = "Incremental"
+)
+
+// RelationshipMode selects the relationships synced with each resource
+// +kubebuilder:validation:Enum=All;OwnerReferences;None
+type RelationshipMode string
+
+const (
+	// RelationshipModeAll syncs owner references and computed edges
+	RelationshipModeAll RelationshipMode = "All"
+	// RelationshipModeOwnerReferences syncs only owner references
+	RelationshipModeOwnerReferences RelationshipMode = "OwnerReferences"
+	// RelationshipModeNone syncs no relationships
+	RelationshipModeNone RelationshipMode = "None"
+)
+
+// IncrementalResyncResult reports the outcome of the last incremental resync
+type IncrementalResyncResult struct {
+	// Time of the resync
+	Time metav1.Time `json:"time"`
+
+	// TotalBuckets is the number of namespace and resource type buckets compared
+	// +optional
+	TotalBuckets int `json:"totalBuckets,omitempty"`
+
+	// DifferingBuckets is the number of buckets whose hashes differed in MCP
+	// +optional
+	DifferingBuckets int `json:"differingBuckets,omitempty"`
+
+	// Upserted is the number of resources uploaded because MCP lacked them or had a different hash
+	// +optional
+	Upserted int `json:"upserted,omitempty"`
+
+	// Deleted is the number of stale resources deleted from MCP
+	// +optional
+	Deleted int `json:"deleted,omitempty"`
+}
+
+// DropRecoveryResult reports the last targeted resync of changes dropped because the change queue was full
+type DropRecoveryResult struct {
+	// Time of the recovery
+	Time metav1.Time `json:"time"`
+
+	// Buckets is the number of namespace and resource type buckets with dropped changes
+	// +optional
+	Buckets int `json:"buckets,omitempty"`
+
+	// Upserted is the number of resources uploaded because MCP lacked them or had a different hash
+	// +optional
+	Upserted int `json:"upserted,omitempty"`
+
+	// Deleted is the number of stale resources deleted from MCP
+	// +optional
+	Deleted int `json:"deleted,omitempty"`
+}
+
+// ResyncPhase is the phase of a full resync session
+// +kubebuilder:validation:Enum=InProgress;Incomplete;Completed
+type ResyncPhase string
+
+const (
+	// ResyncPhaseInProgress means chunks are being uploaded
+	ResyncPhaseInProgress ResyncPhase = "InProgress"
+	// ResyncPhaseIncomplete means chunks or the commit failed; the next resync resumes the session
+	ResyncPhaseIncomplete ResyncPhase = "Incomplete"
+	// ResyncPhaseCompleted means the session was committed
+	ResyncPhaseCompleted ResyncPhase = "Completed"
+)
+
+// ResyncProgress reports the progress of a chunked full resync session
+type ResyncProgress struct {
+	// SessionID identifies the resync session in MCP
+	// +optional
+	SessionID string `json:"sessionID,omitempty"`
+
+	// Phase of the session
+	// +optional
+	Phase ResyncPhase `json:"phase,omitempty"`
+
+	// StartTime is when the session began
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Attempt counts how often the session was begun or resumed. It is part of the idempotency keys,
+	// so MCP does not answer a resumed session with the responses of an earlier attempt.
+	// +optional
+	Attempt int `json:"attempt,omitempty"`
+
+	// CompletionTime is when the session was committed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// TotalResources is the number of resources in the session
+	// +optional
+	TotalResources int `json:"totalResources,omitempty"`
+
+	// TotalChunks is the number of chunks in the session
+	// +optional
+	TotalChunks int `json:"totalChunks,omitempty"`
+
+	// CompletedChunks is the number of chunks MCP received
+	// +optional
+	CompletedChunks int `json:"completedChunks,omitempty"`
+
+	// FailedChunks lists the most recent chunk upload failures of the session
+	// +optional
+	FailedChunks []ResyncChunkFailure `json:"failedChunks,omitempty"`
+
+	// UploadedChunkHashes holds the content hash of each chunk uploaded in the session, indexed by
+	// chunk; empty for chunks not uploaded yet. A resumed session, also after a restart, skips the
+	// chunks MCP received whose resources did not change since they were uploaded.
+	// +optional
+	UploadedChunkHashes []string `json:"uploadedChunkHashes,omitempty"`
+}
+
+// ResyncChunkFailure records a chunk that failed to upload after retries
+type ResyncChunkFailure struct {
+	// Index of the chunk
+	Index int `json:"index"`
+
+	// Error message of the last attempt
+	Error string `json:"error"`
+
+	// Time of the failure
+	Time metav1.Time `json:"time"`
+}
+
+// ClusterHealth reports the connection to a remote cluster
+type ClusterHealth struct {
+	// Name of the cluster, as added to synced resources
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Server is the API server URL of the remote cluster
+	// +optional
+	Server string `json:"server,omitempty"`
+
+	// Reachable reports whether the last check reached the API server
+	Reachable bool `json:"reachable"`
+
+	// KubernetesVersion is the version reported by the API server
+	// +optional
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// LastCheckTime is when the API server was last checked
+	LastCheckTime metav1.Time `json:"lastCheckTime"`
+
+	// Error of the last check, if it failed
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// SinkStatus reports the deliveries of a resource sync sink
+type SinkStatus struct {
+	// Name of the sink; "mcp" is the MCP endpoint
+	Name string `json:"name"`
+
+	// Type of the sink
+	Type string `json:"type"`
+
+	// TotalDelivered is the number of changes delivered by the sink
+	// +optional
+	TotalDelivered int64 `json:"totalDelivered,omitempty"`
+
+	// PendingChanges is the number of failed changes waiting for the next flush
+	// +optional
+	PendingChanges int `json:"pendingChanges,omitempty"`
+
+	// LastDeliveryTime is when the sink last delivered a batch
+	// +optional
+	LastDeliveryTime *metav1.Time `json:"lastDeliveryTime,omitempty"`
+
+	// LastError of the sink, cleared by the next successful delivery
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+
+	// LastErrorTime is when the last delivery failed
+	// +optional
+	LastErrorTime *metav1.Time `json:"lastErrorTime,omitempty"`
 }
 
 // ResourceSyncConfigStatus defines the observed state of ResourceSyncConfig
@@ -43,6 +524,11 @@ type ResourceSyncConfigStatus struct {
 	// +optional
 	WatchedResourceTypes int `json:"watchedResourceTypes,omitempty"`
 
+	// WatchedResources lists the watched resource types as group/version/resource
+	// after includeResources and excludeResources are applied
+	// +optional
+	WatchedResources []string `json:"watchedResources,omitempty"`
+
 	// Total resources synced to MCP
 	// +optional
 	TotalResourcesSynced int64 `json:"totalResourcesSynced,omitempty"`
@@ -55,6 +541,31 @@ type ResourceSyncConfigStatus struct {
 	// +optional
 	LastResyncTime *metav1.Time `json:"lastResyncTime,omitempty"`
 
+	// Resync reports the progress of the current or last full resync
+	// +optional
+	Resync *ResyncProgress `json:"resync,omitempty"`
+
+	// LastIncrementalResync reports the outcome of the last incremental resync
+	// +optional
+	LastIncrementalResync *IncrementalResyncResult `json:"lastIncrementalResync,omitempty"`
+
+	// Cluster reports the health of the remote cluster, when cluster is set
+	// +optional
+	Cluster *ClusterHealth `json:"cluster,omitempty"`
+
+	// Sinks reports the delivery state of the MCP endpoint and each configured sink
+	// +optional
+	Sinks []SinkStatus `json:"sinks,omitempty"`
+
+	// DroppedChanges is the number of changes dropped because the change queue was full,
+	// since the watcher started
+	// +optional
+	DroppedChanges int64 `json:"droppedChanges,omitempty"`
+
+	// LastDropRecovery reports the last targeted resync of the buckets with dropped changes
+	// +optional
+	LastDropRecovery *DropRecoveryResult `json:"lastDropRecovery,omitempty"`
+
 	// Number of sync errors
 	// +optional
 	SyncErrors int64 `json:"syncErrors,omitempty"`
@@ -63,6 +574,10 @@ type ResourceSyncConfigStatus struct {
 	// +optional
 	LastError string `json:"lastError,omitempty"`
 
+	// Notifications records the last notified health state to de-duplicate notifications
+	// +optional
+	Notifications StatusNotificationState `json:"notifications,omitempty"`
+
 	// Current conditions of the config
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
@@ -70,6 +585,7 @@ type ResourceSyncConfigStatus struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName",description="Synced cluster",priority=1
 // +kubebuilder:printcolumn:name="Active",type="boolean",JSONPath=".status.active",description="Whether sync is active"
 // +kubebuilder:printcolumn:name="Watched",type="integer",JSONPath=".status.watchedResourceTypes",description="Resource types being watched"
 // +kubebuilder:printcolumn:name="Synced",type="integer",JSONPath=".status.totalResourcesSynced",description="Total resources synced"
@@ -116,6 +632,39 @@ func (r *ResourceSyncConfig) GetDebounceWindow() int {
 	return r.Spec.DebounceWindowSeconds
 }
 
+// GetResyncChunkSize returns the number of resources per resync chunk with default
+func (r *ResourceSyncConfig) GetResyncChunkSize() int {
+	if r.Spec.ResyncChunkSize <= 0 {
+		return 1000 // default 1000 resources
+	}
+	return r.Spec.ResyncChunkSize
+}
+
+// GetResyncMode returns the resync mode with default
+func (r *ResourceSyncConfig) GetResyncMode() ResyncMode {
+	if r.Spec.ResyncMode == "" {
+		return ResyncModeFull
+	}
+	return r.Spec.ResyncMode
+}
+
+// GetRelationshipMode returns the relationship mode with default
+func (r *ResourceSyncConfig) GetRelationshipMode() RelationshipMode {
+	if r.Spec.Relationships == "" {
+		return RelationshipModeAll
+	}
+	return r.Spec.Relationships
+}
+
+// GetClusterName returns the name of the synced cluster
+// Cluster API clusters default to their name; the local cluster has no name unless one is set
+func (r *ResourceSyncConfig) GetClusterName() string {
+	if r.Spec.ClusterName == "" && r.Spec.Cluster != nil {
+		return r.Spec.Cluster.ClusterAPIClusterName
+	}
+	return r.Spec.ClusterName
+}
+
 // GetResyncInterval returns the resync interval with default
 func (r *ResourceSyncConfig) GetResyncInterval() int {
 	if r.Spec.ResyncIntervalMinutes <= 0 {
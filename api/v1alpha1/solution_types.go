@@ -26,6 +26,11 @@ type SolutionSpec struct {
 	// +optional
 	// +kubebuilder:validation:Pattern=`^https?://.*`
 	DocumentationURL string `json:"documentationURL,omitempty"`
+
+	// Notifications configures Slack, Google Chat or NotificationChannel notifications
+	// sent when this Solution becomes degraded, and when it recovers
+	// +optional
+	Notifications *StatusNotificationConfig `json:"notifications,omitempty"`
 }
 
 // SolutionContext contains contextual information about the solution deployment
@@ -81,6 +86,10 @@ type SolutionStatus struct {
 	// +optional
 	Resources ResourceSummary `json:"resources,omitempty"`
 
+	// Notifications records the last notified health state to de-duplicate notifications
+	// +optional
+	Notifications StatusNotificationState `json:"notifications,omitempty"`
+
 	// Conditions represent the latest available observations of the solution's state
 	// +optional
 	// +patchMergeKey=type
@@ -71,7 +71,7 @@ func (in *CapabilityScanConfigList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CapabilityScanConfigSpec) DeepCopyInto(out *CapabilityScanConfigSpec) {
 	*out = *in
-	out.MCP = in.MCP
+	in.MCP.DeepCopyInto(&out.MCP)
 	if in.IncludeResources != nil {
 		in, out := &in.IncludeResources, &out.IncludeResources
 		*out = make([]string, len(*in))
@@ -83,6 +83,11 @@ func (in *CapabilityScanConfigSpec) DeepCopyInto(out *CapabilityScanConfigSpec)
 		copy(*out, *in)
 	}
 	out.Retry = in.Retry
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(StatusNotificationConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapabilityScanConfigSpec.
@@ -102,6 +107,7 @@ func (in *CapabilityScanConfigStatus) DeepCopyInto(out *CapabilityScanConfigStat
 		in, out := &in.LastScanTime, &out.LastScanTime
 		*out = (*in).DeepCopy()
 	}
+	in.Notifications.DeepCopyInto(&out.Notifications)
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -121,6 +127,53 @@ func (in *CapabilityScanConfigStatus) DeepCopy() *CapabilityScanConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHealth) DeepCopyInto(out *ClusterHealth) {
+	*out = *in
+	in.LastCheckTime.DeepCopyInto(&out.LastCheckTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealth.
+func (in *ClusterHealth) DeepCopy() *ClusterHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapReference) DeepCopyInto(out *ConfigMapReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapReference.
+func (in *ConfigMapReference) DeepCopy() *ConfigMapReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DropRecoveryResult) DeepCopyInto(out *DropRecoveryResult) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DropRecoveryResult.
+func (in *DropRecoveryResult) DeepCopy() *DropRecoveryResult {
+	if in == nil {
+		return nil
+	}
+	out := new(DropRecoveryResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EventSelector) DeepCopyInto(out *EventSelector) {
 	*out = *in
@@ -214,7 +267,7 @@ func (in *GitKnowledgeSourceSpec) DeepCopyInto(out *GitKnowledgeSourceSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	out.McpServer = in.McpServer
+	in.McpServer.DeepCopyInto(&out.McpServer)
 	if in.Metadata != nil {
 		in, out := &in.Metadata, &out.Metadata
 		*out = make(map[string]string, len(*in))
@@ -227,6 +280,11 @@ func (in *GitKnowledgeSourceSpec) DeepCopyInto(out *GitKnowledgeSourceSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(StatusNotificationConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitKnowledgeSourceSpec.
@@ -255,6 +313,7 @@ func (in *GitKnowledgeSourceStatus) DeepCopyInto(out *GitKnowledgeSourceStatus)
 		*out = make([]SkippedFile, len(*in))
 		copy(*out, *in)
 	}
+	in.Notifications.DeepCopyInto(&out.Notifications)
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -282,6 +341,11 @@ func (in *GoogleChatConfig) DeepCopyInto(out *GoogleChatConfig) {
 		*out = new(SecretReference)
 		**out = **in
 	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleChatConfig.
@@ -295,162 +359,136 @@ func (in *GoogleChatConfig) DeepCopy() *GoogleChatConfig {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPCapabilityConfig) DeepCopyInto(out *MCPCapabilityConfig) {
+func (in *GoogleChatRouteTarget) DeepCopyInto(out *GoogleChatRouteTarget) {
 	*out = *in
-	out.AuthSecretRef = in.AuthSecretRef
+	out.WebhookUrlSecretRef = in.WebhookUrlSecretRef
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPCapabilityConfig.
-func (in *MCPCapabilityConfig) DeepCopy() *MCPCapabilityConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleChatRouteTarget.
+func (in *GoogleChatRouteTarget) DeepCopy() *GoogleChatRouteTarget {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPCapabilityConfig)
+	out := new(GoogleChatRouteTarget)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *McpRequest) DeepCopyInto(out *McpRequest) {
+func (in *IncrementalResyncResult) DeepCopyInto(out *IncrementalResyncResult) {
 	*out = *in
-	if in.ConfidenceThreshold != nil {
-		in, out := &in.ConfidenceThreshold, &out.ConfidenceThreshold
-		*out = new(float64)
-		**out = **in
-	}
+	in.Time.DeepCopyInto(&out.Time)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new McpRequest.
-func (in *McpRequest) DeepCopy() *McpRequest {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncrementalResyncResult.
+func (in *IncrementalResyncResult) DeepCopy() *IncrementalResyncResult {
 	if in == nil {
 		return nil
 	}
-	out := new(McpRequest)
+	out := new(IncrementalResyncResult)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *McpServerConfig) DeepCopyInto(out *McpServerConfig) {
+func (in *MCPCapabilityConfig) DeepCopyInto(out *MCPCapabilityConfig) {
 	*out = *in
 	out.AuthSecretRef = in.AuthSecretRef
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new McpServerConfig.
-func (in *McpServerConfig) DeepCopy() *McpServerConfig {
-	if in == nil {
-		return nil
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(McpAuthConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
 	}
-	out := new(McpServerConfig)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NotificationConfig) DeepCopyInto(out *NotificationConfig) {
-	*out = *in
-	in.Slack.DeepCopyInto(&out.Slack)
-	in.GoogleChat.DeepCopyInto(&out.GoogleChat)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationConfig.
-func (in *NotificationConfig) DeepCopy() *NotificationConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPCapabilityConfig.
+func (in *MCPCapabilityConfig) DeepCopy() *MCPCapabilityConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(NotificationConfig)
+	out := new(MCPCapabilityConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PersistenceConfig) DeepCopyInto(out *PersistenceConfig) {
+func (in *MCPServer) DeepCopyInto(out *MCPServer) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistenceConfig.
-func (in *PersistenceConfig) DeepCopy() *PersistenceConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServer.
+func (in *MCPServer) DeepCopy() *MCPServer {
 	if in == nil {
 		return nil
 	}
-	out := new(PersistenceConfig)
+	out := new(MCPServer)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RateLimiting) DeepCopyInto(out *RateLimiting) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimiting.
-func (in *RateLimiting) DeepCopy() *RateLimiting {
-	if in == nil {
-		return nil
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MCPServer) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	out := new(RateLimiting)
-	in.DeepCopyInto(out)
-	return out
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RemediationPolicy) DeepCopyInto(out *RemediationPolicy) {
+func (in *MCPServerHealthCheck) DeepCopyInto(out *MCPServerHealthCheck) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationPolicy.
-func (in *RemediationPolicy) DeepCopy() *RemediationPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerHealthCheck.
+func (in *MCPServerHealthCheck) DeepCopy() *MCPServerHealthCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(RemediationPolicy)
+	out := new(MCPServerHealthCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RemediationPolicy) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RemediationPolicyList) DeepCopyInto(out *RemediationPolicyList) {
+func (in *MCPServerList) DeepCopyInto(out *MCPServerList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]RemediationPolicy, len(*in))
+		*out = make([]MCPServer, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationPolicyList.
-func (in *RemediationPolicyList) DeepCopy() *RemediationPolicyList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerList.
+func (in *MCPServerList) DeepCopy() *MCPServerList {
 	if in == nil {
 		return nil
 	}
-	out := new(RemediationPolicyList)
+	out := new(MCPServerList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RemediationPolicyList) DeepCopyObject() runtime.Object {
+func (in *MCPServerList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -458,53 +496,42 @@ func (in *RemediationPolicyList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RemediationPolicySpec) DeepCopyInto(out *RemediationPolicySpec) {
+func (in *MCPServerSpec) DeepCopyInto(out *MCPServerSpec) {
 	*out = *in
-	if in.EventSelectors != nil {
-		in, out := &in.EventSelectors, &out.EventSelectors
-		*out = make([]EventSelector, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	out.McpAuthSecretRef = in.McpAuthSecretRef
-	if in.ConfidenceThreshold != nil {
-		in, out := &in.ConfidenceThreshold, &out.ConfidenceThreshold
-		*out = new(float64)
-		**out = **in
+	out.AuthSecretRef = in.AuthSecretRef
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(McpAuthConfig)
+		(*in).DeepCopyInto(*out)
 	}
-	out.RateLimiting = in.RateLimiting
-	in.Notifications.DeepCopyInto(&out.Notifications)
-	if in.Persistence != nil {
-		in, out := &in.Persistence, &out.Persistence
-		*out = new(PersistenceConfig)
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetryConfig)
+		**out = **in
+	}
+	out.HealthCheck = in.HealthCheck
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationPolicySpec.
-func (in *RemediationPolicySpec) DeepCopy() *RemediationPolicySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerSpec.
+func (in *MCPServerSpec) DeepCopy() *MCPServerSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RemediationPolicySpec)
+	out := new(MCPServerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RemediationPolicyStatus) DeepCopyInto(out *RemediationPolicyStatus) {
+func (in *MCPServerStatus) DeepCopyInto(out *MCPServerStatus) {
 	*out = *in
-	if in.LastProcessedEvent != nil {
-		in, out := &in.LastProcessedEvent, &out.LastProcessedEvent
-		*out = (*in).DeepCopy()
-	}
-	if in.LastMcpMessageGenerated != nil {
-		in, out := &in.LastMcpMessageGenerated, &out.LastMcpMessageGenerated
-		*out = (*in).DeepCopy()
-	}
-	if in.LastRateLimitedEvent != nil {
-		in, out := &in.LastRateLimitedEvent, &out.LastRateLimitedEvent
+	if in.LastProbeTime != nil {
+		in, out := &in.LastProbeTime, &out.LastProbeTime
 		*out = (*in).DeepCopy()
 	}
 	if in.Conditions != nil {
@@ -516,150 +543,1036 @@ func (in *RemediationPolicyStatus) DeepCopyInto(out *RemediationPolicyStatus) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationPolicyStatus.
-func (in *RemediationPolicyStatus) DeepCopy() *RemediationPolicyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerStatus.
+func (in *MCPServerStatus) DeepCopy() *MCPServerStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RemediationPolicyStatus)
+	out := new(MCPServerStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RepositoryConfig) DeepCopyInto(out *RepositoryConfig) {
+func (in *McpAuthConfig) DeepCopyInto(out *McpAuthConfig) {
 	*out = *in
-	if in.Depth != nil {
-		in, out := &in.Depth, &out.Depth
-		*out = new(int)
+	if in.MTLS != nil {
+		in, out := &in.MTLS, &out.MTLS
+		*out = new(McpMTLSConfig)
 		**out = **in
 	}
-	if in.SecretRef != nil {
-		in, out := &in.SecretRef, &out.SecretRef
-		*out = new(SecretReference)
+	if in.OAuth2 != nil {
+		in, out := &in.OAuth2, &out.OAuth2
+		*out = new(McpOAuth2Config)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAccountToken != nil {
+		in, out := &in.ServiceAccountToken, &out.ServiceAccountToken
+		*out = new(McpServiceAccountTokenConfig)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryConfig.
-func (in *RepositoryConfig) DeepCopy() *RepositoryConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new McpAuthConfig.
+func (in *McpAuthConfig) DeepCopy() *McpAuthConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(RepositoryConfig)
+	out := new(McpAuthConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceReference) DeepCopyInto(out *ResourceReference) {
+func (in *McpMTLSConfig) DeepCopyInto(out *McpMTLSConfig) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceReference.
-func (in *ResourceReference) DeepCopy() *ResourceReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new McpMTLSConfig.
+func (in *McpMTLSConfig) DeepCopy() *McpMTLSConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceReference)
+	out := new(McpMTLSConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceSummary) DeepCopyInto(out *ResourceSummary) {
+func (in *McpOAuth2Config) DeepCopyInto(out *McpOAuth2Config) {
 	*out = *in
+	out.ClientSecretRef = in.ClientSecretRef
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSummary.
-func (in *ResourceSummary) DeepCopy() *ResourceSummary {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new McpOAuth2Config.
+func (in *McpOAuth2Config) DeepCopy() *McpOAuth2Config {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceSummary)
+	out := new(McpOAuth2Config)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceSyncConfig) DeepCopyInto(out *ResourceSyncConfig) {
+func (in *McpRequest) DeepCopyInto(out *McpRequest) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
+	if in.ConfidenceThreshold != nil {
+		in, out := &in.ConfidenceThreshold, &out.ConfidenceThreshold
+		*out = new(float64)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSyncConfig.
-func (in *ResourceSyncConfig) DeepCopy() *ResourceSyncConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new McpRequest.
+func (in *McpRequest) DeepCopy() *McpRequest {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceSyncConfig)
+	out := new(McpRequest)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ResourceSyncConfig) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceSyncConfigList) DeepCopyInto(out *ResourceSyncConfigList) {
+func (in *McpServerConfig) DeepCopyInto(out *McpServerConfig) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ResourceSyncConfig, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	out.AuthSecretRef = in.AuthSecretRef
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(McpAuthConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSyncConfigList.
-func (in *ResourceSyncConfigList) DeepCopy() *ResourceSyncConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new McpServerConfig.
+func (in *McpServerConfig) DeepCopy() *McpServerConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceSyncConfigList)
+	out := new(McpServerConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ResourceSyncConfigList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceSyncConfigSpec) DeepCopyInto(out *ResourceSyncConfigSpec) {
+func (in *McpServiceAccountTokenConfig) DeepCopyInto(out *McpServiceAccountTokenConfig) {
 	*out = *in
-	out.McpAuthSecretRef = in.McpAuthSecretRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSyncConfigSpec.
-func (in *ResourceSyncConfigSpec) DeepCopy() *ResourceSyncConfigSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new McpServiceAccountTokenConfig.
+func (in *McpServiceAccountTokenConfig) DeepCopy() *McpServiceAccountTokenConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceSyncConfigSpec)
+	out := new(McpServiceAccountTokenConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceSyncConfigStatus) DeepCopyInto(out *ResourceSyncConfigStatus) {
+func (in *NotificationBatchingConfig) DeepCopyInto(out *NotificationBatchingConfig) {
 	*out = *in
-	if in.LastSyncTime != nil {
+	if in.SendFailuresImmediately != nil {
+		in, out := &in.SendFailuresImmediately, &out.SendFailuresImmediately
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationBatchingConfig.
+func (in *NotificationBatchingConfig) DeepCopy() *NotificationBatchingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationBatchingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationChannel) DeepCopyInto(out *NotificationChannel) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationChannel.
+func (in *NotificationChannel) DeepCopy() *NotificationChannel {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationChannel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NotificationChannel) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationChannelHealthCheck) DeepCopyInto(out *NotificationChannelHealthCheck) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationChannelHealthCheck.
+func (in *NotificationChannelHealthCheck) DeepCopy() *NotificationChannelHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationChannelHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationChannelList) DeepCopyInto(out *NotificationChannelList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NotificationChannel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationChannelList.
+func (in *NotificationChannelList) DeepCopy() *NotificationChannelList {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationChannelList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NotificationChannelList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationChannelReference) DeepCopyInto(out *NotificationChannelReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationChannelReference.
+func (in *NotificationChannelReference) DeepCopy() *NotificationChannelReference {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationChannelReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationChannelSpec) DeepCopyInto(out *NotificationChannelSpec) {
+	*out = *in
+	out.WebhookUrlSecretRef = in.WebhookUrlSecretRef
+	in.HealthCheck.DeepCopyInto(&out.HealthCheck)
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationChannelSpec.
+func (in *NotificationChannelSpec) DeepCopy() *NotificationChannelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationChannelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationChannelStatus) DeepCopyInto(out *NotificationChannelStatus) {
+	*out = *in
+	if in.LastHealthCheckTime != nil {
+		in, out := &in.LastHealthCheckTime, &out.LastHealthCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastDeliveryTime != nil {
+		in, out := &in.LastDeliveryTime, &out.LastDeliveryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationChannelStatus.
+func (in *NotificationChannelStatus) DeepCopy() *NotificationChannelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationChannelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationConfig) DeepCopyInto(out *NotificationConfig) {
+	*out = *in
+	in.Slack.DeepCopyInto(&out.Slack)
+	in.GoogleChat.DeepCopyInto(&out.GoogleChat)
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]NotificationChannelReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]NotificationRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Batching != nil {
+		in, out := &in.Batching, &out.Batching
+		*out = new(NotificationBatchingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationConfig.
+func (in *NotificationConfig) DeepCopy() *NotificationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationDeliveryStatus) DeepCopyInto(out *NotificationDeliveryStatus) {
+	*out = *in
+	if in.LastDeliveryTime != nil {
+		in, out := &in.LastDeliveryTime, &out.LastDeliveryTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationDeliveryStatus.
+func (in *NotificationDeliveryStatus) DeepCopy() *NotificationDeliveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationDeliveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationRoute) DeepCopyInto(out *NotificationRoute) {
+	*out = *in
+	in.Match.DeepCopyInto(&out.Match)
+	if in.Slack != nil {
+		in, out := &in.Slack, &out.Slack
+		*out = new(SlackRouteTarget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GoogleChat != nil {
+		in, out := &in.GoogleChat, &out.GoogleChat
+		*out = new(GoogleChatRouteTarget)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationRoute.
+func (in *NotificationRoute) DeepCopy() *NotificationRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationRouteMatch) DeepCopyInto(out *NotificationRouteMatch) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Modes != nil {
+		in, out := &in.Modes, &out.Modes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RiskLevels != nil {
+		in, out := &in.RiskLevels, &out.RiskLevels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Executed != nil {
+		in, out := &in.Executed, &out.Executed
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationRouteMatch.
+func (in *NotificationRouteMatch) DeepCopy() *NotificationRouteMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationRouteMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistenceConfig) DeepCopyInto(out *PersistenceConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistenceConfig.
+func (in *PersistenceConfig) DeepCopy() *PersistenceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistenceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimiting) DeepCopyInto(out *RateLimiting) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimiting.
+func (in *RateLimiting) DeepCopy() *RateLimiting {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimiting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedactionRule) DeepCopyInto(out *RedactionRule) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedactionRule.
+func (in *RedactionRule) DeepCopy() *RedactionRule {
+	if in == nil {
+		return nil
+	}
+	out := new(RedactionRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationAction) DeepCopyInto(out *RemediationAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationAction.
+func (in *RemediationAction) DeepCopy() *RemediationAction {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationPolicy) DeepCopyInto(out *RemediationPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationPolicy.
+func (in *RemediationPolicy) DeepCopy() *RemediationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RemediationPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationPolicyList) DeepCopyInto(out *RemediationPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RemediationPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationPolicyList.
+func (in *RemediationPolicyList) DeepCopy() *RemediationPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RemediationPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationPolicySpec) DeepCopyInto(out *RemediationPolicySpec) {
+	*out = *in
+	if in.EventSelectors != nil {
+		in, out := &in.EventSelectors, &out.EventSelectors
+		*out = make([]EventSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.McpAuthSecretRef = in.McpAuthSecretRef
+	if in.McpAuth != nil {
+		in, out := &in.McpAuth, &out.McpAuth
+		*out = new(McpAuthConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.McpTLS != nil {
+		in, out := &in.McpTLS, &out.McpTLS
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfidenceThreshold != nil {
+		in, out := &in.ConfidenceThreshold, &out.ConfidenceThreshold
+		*out = new(float64)
+		**out = **in
+	}
+	out.RateLimiting = in.RateLimiting
+	in.Notifications.DeepCopyInto(&out.Notifications)
+	if in.Persistence != nil {
+		in, out := &in.Persistence, &out.Persistence
+		*out = new(PersistenceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationPolicySpec.
+func (in *RemediationPolicySpec) DeepCopy() *RemediationPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationPolicyStatus) DeepCopyInto(out *RemediationPolicyStatus) {
+	*out = *in
+	if in.LastProcessedEvent != nil {
+		in, out := &in.LastProcessedEvent, &out.LastProcessedEvent
+		*out = (*in).DeepCopy()
+	}
+	if in.LastMcpMessageGenerated != nil {
+		in, out := &in.LastMcpMessageGenerated, &out.LastMcpMessageGenerated
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRateLimitedEvent != nil {
+		in, out := &in.LastRateLimitedEvent, &out.LastRateLimitedEvent
+		*out = (*in).DeepCopy()
+	}
+	if in.ActiveRemediations != nil {
+		in, out := &in.ActiveRemediations, &out.ActiveRemediations
+		*out = make([]RemediationProgress, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NotificationDelivery != nil {
+		in, out := &in.NotificationDelivery, &out.NotificationDelivery
+		*out = make([]NotificationDeliveryStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RecentResults != nil {
+		in, out := &in.RecentResults, &out.RecentResults
+		*out = make([]RemediationResultRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationPolicyStatus.
+func (in *RemediationPolicyStatus) DeepCopy() *RemediationPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationProgress) DeepCopyInto(out *RemediationProgress) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Percent != nil {
+		in, out := &in.Percent, &out.Percent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationProgress.
+func (in *RemediationProgress) DeepCopy() *RemediationProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationResult) DeepCopyInto(out *RemediationResult) {
+	*out = *in
+	if in.Confidence != nil {
+		in, out := &in.Confidence, &out.Confidence
+		*out = new(float64)
+		**out = **in
+	}
+	if in.AnalysisConfidence != nil {
+		in, out := &in.AnalysisConfidence, &out.AnalysisConfidence
+		*out = new(float64)
+		**out = **in
+	}
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]RemediationAction, len(*in))
+		copy(*out, *in)
+	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(RemediationValidation)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationResult.
+func (in *RemediationResult) DeepCopy() *RemediationResult {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationResultRecord) DeepCopyInto(out *RemediationResultRecord) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Result != nil {
+		in, out := &in.Result, &out.Result
+		*out = new(RemediationResult)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationResultRecord.
+func (in *RemediationResultRecord) DeepCopy() *RemediationResultRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationResultRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationValidation) DeepCopyInto(out *RemediationValidation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationValidation.
+func (in *RemediationValidation) DeepCopy() *RemediationValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteClusterConfig) DeepCopyInto(out *RemoteClusterConfig) {
+	*out = *in
+	if in.KubeconfigSecretRef != nil {
+		in, out := &in.KubeconfigSecretRef, &out.KubeconfigSecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterConfig.
+func (in *RemoteClusterConfig) DeepCopy() *RemoteClusterConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteClusterConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryConfig) DeepCopyInto(out *RepositoryConfig) {
+	*out = *in
+	if in.Depth != nil {
+		in, out := &in.Depth, &out.Depth
+		*out = new(int)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryConfig.
+func (in *RepositoryConfig) DeepCopy() *RepositoryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceFieldExtraction) DeepCopyInto(out *ResourceFieldExtraction) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]ResourceFieldRule, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceFieldExtraction.
+func (in *ResourceFieldExtraction) DeepCopy() *ResourceFieldExtraction {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceFieldExtraction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceFieldRule) DeepCopyInto(out *ResourceFieldRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceFieldRule.
+func (in *ResourceFieldRule) DeepCopy() *ResourceFieldRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceFieldRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceReference) DeepCopyInto(out *ResourceReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceReference.
+func (in *ResourceReference) DeepCopy() *ResourceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSummary) DeepCopyInto(out *ResourceSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSummary.
+func (in *ResourceSummary) DeepCopy() *ResourceSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSyncConfig) DeepCopyInto(out *ResourceSyncConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSyncConfig.
+func (in *ResourceSyncConfig) DeepCopy() *ResourceSyncConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSyncConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ResourceSyncConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSyncConfigList) DeepCopyInto(out *ResourceSyncConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ResourceSyncConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSyncConfigList.
+func (in *ResourceSyncConfigList) DeepCopy() *ResourceSyncConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSyncConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ResourceSyncConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSyncConfigSpec) DeepCopyInto(out *ResourceSyncConfigSpec) {
+	*out = *in
+	out.McpAuthSecretRef = in.McpAuthSecretRef
+	if in.McpAuth != nil {
+		in, out := &in.McpAuth, &out.McpAuth
+		*out = new(McpAuthConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.McpTLS != nil {
+		in, out := &in.McpTLS, &out.McpTLS
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cluster != nil {
+		in, out := &in.Cluster, &out.Cluster
+		*out = new(RemoteClusterConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IncludeResources != nil {
+		in, out := &in.IncludeResources, &out.IncludeResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeResources != nil {
+		in, out := &in.ExcludeResources, &out.ExcludeResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ObjectSelector != nil {
+		in, out := &in.ObjectSelector, &out.ObjectSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]ResourceFieldExtraction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AnnotationAllowlist != nil {
+		in, out := &in.AnnotationAllowlist, &out.AnnotationAllowlist
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LabelDenylist != nil {
+		in, out := &in.LabelDenylist, &out.LabelDenylist
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Redactions != nil {
+		in, out := &in.Redactions, &out.Redactions
+		*out = make([]RedactionRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RedactionKeySecretRef != nil {
+		in, out := &in.RedactionKeySecretRef, &out.RedactionKeySecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(StatusNotificationConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sinks != nil {
+		in, out := &in.Sinks, &out.Sinks
+		*out = make([]ResourceSyncSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSyncConfigSpec.
+func (in *ResourceSyncConfigSpec) DeepCopy() *ResourceSyncConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSyncConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSyncConfigStatus) DeepCopyInto(out *ResourceSyncConfigStatus) {
+	*out = *in
+	if in.WatchedResources != nil {
+		in, out := &in.WatchedResources, &out.WatchedResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSyncTime != nil {
 		in, out := &in.LastSyncTime, &out.LastSyncTime
 		*out = (*in).DeepCopy()
 	}
@@ -667,6 +1580,34 @@ func (in *ResourceSyncConfigStatus) DeepCopyInto(out *ResourceSyncConfigStatus)
 		in, out := &in.LastResyncTime, &out.LastResyncTime
 		*out = (*in).DeepCopy()
 	}
+	if in.Resync != nil {
+		in, out := &in.Resync, &out.Resync
+		*out = new(ResyncProgress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastIncrementalResync != nil {
+		in, out := &in.LastIncrementalResync, &out.LastIncrementalResync
+		*out = new(IncrementalResyncResult)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cluster != nil {
+		in, out := &in.Cluster, &out.Cluster
+		*out = new(ClusterHealth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sinks != nil {
+		in, out := &in.Sinks, &out.Sinks
+		*out = make([]SinkStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastDropRecovery != nil {
+		in, out := &in.LastDropRecovery, &out.LastDropRecovery
+		*out = new(DropRecoveryResult)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Notifications.DeepCopyInto(&out.Notifications)
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -686,6 +1627,82 @@ func (in *ResourceSyncConfigStatus) DeepCopy() *ResourceSyncConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSyncSink) DeepCopyInto(out *ResourceSyncSink) {
+	*out = *in
+	if in.AuthSecretRef != nil {
+		in, out := &in.AuthSecretRef, &out.AuthSecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSyncSink.
+func (in *ResourceSyncSink) DeepCopy() *ResourceSyncSink {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSyncSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResyncChunkFailure) DeepCopyInto(out *ResyncChunkFailure) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResyncChunkFailure.
+func (in *ResyncChunkFailure) DeepCopy() *ResyncChunkFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(ResyncChunkFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResyncProgress) DeepCopyInto(out *ResyncProgress) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.FailedChunks != nil {
+		in, out := &in.FailedChunks, &out.FailedChunks
+		*out = make([]ResyncChunkFailure, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UploadedChunkHashes != nil {
+		in, out := &in.UploadedChunkHashes, &out.UploadedChunkHashes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResyncProgress.
+func (in *ResyncProgress) DeepCopy() *ResyncProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(ResyncProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RetryConfig) DeepCopyInto(out *RetryConfig) {
 	*out = *in
@@ -716,6 +1733,29 @@ func (in *SecretReference) DeepCopy() *SecretReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SinkStatus) DeepCopyInto(out *SinkStatus) {
+	*out = *in
+	if in.LastDeliveryTime != nil {
+		in, out := &in.LastDeliveryTime, &out.LastDeliveryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastErrorTime != nil {
+		in, out := &in.LastErrorTime, &out.LastErrorTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SinkStatus.
+func (in *SinkStatus) DeepCopy() *SinkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SinkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SkippedFile) DeepCopyInto(out *SkippedFile) {
 	*out = *in
@@ -739,6 +1779,11 @@ func (in *SlackConfig) DeepCopyInto(out *SlackConfig) {
 		*out = new(SecretReference)
 		**out = **in
 	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackConfig.
@@ -751,6 +1796,27 @@ func (in *SlackConfig) DeepCopy() *SlackConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackRouteTarget) DeepCopyInto(out *SlackRouteTarget) {
+	*out = *in
+	out.WebhookUrlSecretRef = in.WebhookUrlSecretRef
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackRouteTarget.
+func (in *SlackRouteTarget) DeepCopy() *SlackRouteTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackRouteTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Solution) DeepCopyInto(out *Solution) {
 	*out = *in
@@ -844,6 +1910,11 @@ func (in *SolutionSpec) DeepCopyInto(out *SolutionSpec) {
 		*out = make([]ResourceReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(StatusNotificationConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SolutionSpec.
@@ -860,6 +1931,7 @@ func (in *SolutionSpec) DeepCopy() *SolutionSpec {
 func (in *SolutionStatus) DeepCopyInto(out *SolutionStatus) {
 	*out = *in
 	out.Resources = in.Resources
+	in.Notifications.DeepCopyInto(&out.Notifications)
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -878,3 +1950,82 @@ func (in *SolutionStatus) DeepCopy() *SolutionStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusNotificationConfig) DeepCopyInto(out *StatusNotificationConfig) {
+	*out = *in
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Slack != nil {
+		in, out := &in.Slack, &out.Slack
+		*out = new(SlackRouteTarget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GoogleChat != nil {
+		in, out := &in.GoogleChat, &out.GoogleChat
+		*out = new(GoogleChatRouteTarget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NotifyOnRecovery != nil {
+		in, out := &in.NotifyOnRecovery, &out.NotifyOnRecovery
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusNotificationConfig.
+func (in *StatusNotificationConfig) DeepCopy() *StatusNotificationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusNotificationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusNotificationState) DeepCopyInto(out *StatusNotificationState) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusNotificationState.
+func (in *StatusNotificationState) DeepCopy() *StatusNotificationState {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusNotificationState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSConfig) DeepCopyInto(out *TLSConfig) {
+	*out = *in
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+	if in.CAConfigMapRef != nil {
+		in, out := &in.CAConfigMapRef, &out.CAConfigMapRef
+		*out = new(ConfigMapReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSConfig.
+func (in *TLSConfig) DeepCopy() *TLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"net/http"
@@ -28,7 +29,9 @@ import (
 
 	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
 	"github.com/vfarcic/dot-ai-controller/internal/controller"
+	"github.com/vfarcic/dot-ai-controller/internal/mcp"
 	"github.com/vfarcic/dot-ai-controller/internal/shutdown"
+	"github.com/vfarcic/dot-ai-controller/internal/tracing"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -54,7 +57,10 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var resourceSyncSinkDir string
 	var tlsOpts []func(*tls.Config)
+	mcpSettings := mcp.DefaultSettings()
+	tracingConfig := tracing.DefaultConfig()
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -72,6 +78,27 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.DurationVar(&mcpSettings.Timeout, "mcp-timeout", mcpSettings.Timeout,
+		"Timeout of a single MCP request attempt for resource sync, capability scan and knowledge requests.")
+	flag.IntVar(&mcpSettings.Retry.MaxRetries, "mcp-max-retries", mcpSettings.Retry.MaxRetries,
+		"Number of retries of MCP requests that fail with transport errors or transient HTTP statuses.")
+	flag.DurationVar(&mcpSettings.Retry.InitialBackoff, "mcp-initial-backoff", mcpSettings.Retry.InitialBackoff,
+		"Backoff before the first MCP request retry; it doubles with every retry.")
+	flag.DurationVar(&mcpSettings.Retry.MaxBackoff, "mcp-max-backoff", mcpSettings.Retry.MaxBackoff,
+		"Maximum backoff between MCP request retries.")
+	flag.IntVar(&mcpSettings.RemediationMaxRetries, "mcp-remediation-max-retries", mcpSettings.RemediationMaxRetries,
+		"Number of retries of MCP remediation requests. Remediation is long-running, so it is not retried by default.")
+	flag.IntVar(&mcpSettings.MaxIdleConnsPerEndpoint, "mcp-max-idle-conns-per-endpoint", mcpSettings.MaxIdleConnsPerEndpoint,
+		"Number of idle connections kept per MCP endpoint.")
+	flag.StringVar(&tracingConfig.Endpoint, "otlp-endpoint", tracingConfig.Endpoint,
+		"The host:port of the OTLP gRPC collector that receives traces. Tracing is disabled when empty.")
+	flag.BoolVar(&tracingConfig.Insecure, "otlp-insecure", tracingConfig.Insecure,
+		"If set, the connection to the OTLP collector does not use TLS.")
+	flag.Float64Var(&tracingConfig.SampleRatio, "tracing-sample-ratio", tracingConfig.SampleRatio,
+		"The fraction of traces that are sampled (0.0-1.0). Traces started by a sampled parent are always recorded.")
+	flag.StringVar(&resourceSyncSinkDir, "resourcesync-sink-dir", "",
+		"The directory NDJSON resource sync sinks write to, in a subdirectory per namespace. "+
+			"NDJSON sinks are rejected when empty.")
 
 	opts := zap.Options{
 		Development: true,
@@ -80,6 +107,16 @@ func main() {
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	mcp.SetSettings(mcpSettings)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracingConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	if tracingConfig.Endpoint != "" {
+		setupLog.Info("Tracing enabled", "endpoint", tracingConfig.Endpoint, "sampleRatio", tracingConfig.SampleRatio)
+	}
 
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
@@ -201,9 +238,12 @@ func main() {
 
 	// Create HTTP client with extended timeouts for MCP communication
 	// MCP remediation can take up to 15 minutes for complex tasks
+	// Endpoints with TLS or proxy settings get a derived client that keeps this timeout
 	httpClient := &http.Client{
 		Timeout: 15 * time.Minute,
 		Transport: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			TLSClientConfig:       &tls.Config{MinVersion: tls.VersionTLS12},
 			TLSHandshakeTimeout:   30 * time.Second,
 			ResponseHeaderTimeout: 15 * time.Minute,
 			IdleConnTimeout:       90 * time.Second,
@@ -218,21 +258,47 @@ func main() {
 		mgr.GetScheme(),
 	)
 
+	// Create the dead-letter queue for notifications that fail with transient errors
+	notificationDeadLetterQueue := controller.NewNotificationDeadLetterQueue(
+		mgr.GetClient(),
+		mgr.GetScheme(),
+		controller.NotificationDeadLetterQueueConfig{},
+	)
+
 	if err := (&controller.RemediationPolicyReconciler{
 		Client:              mgr.GetClient(),
 		Scheme:              mgr.GetScheme(),
 		Recorder:            mgr.GetEventRecorderFor("dot-ai-controller"),
 		HttpClient:          httpClient,
 		CooldownPersistence: cooldownPersistence,
+		DeadLetterQueue:     notificationDeadLetterQueue,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "RemediationPolicy")
 		os.Exit(1)
 	}
 
+	if err := (&controller.NotificationChannelReconciler{
+		Client:     mgr.GetClient(),
+		Scheme:     mgr.GetScheme(),
+		Recorder:   mgr.GetEventRecorderFor("dot-ai-controller"),
+		HttpClient: httpClient,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NotificationChannel")
+		os.Exit(1)
+	}
+
+	// Shared notifier for health transitions of knowledge, sync, scan and solution resources
+	statusNotifier := &controller.StatusNotifier{
+		Client:     mgr.GetClient(),
+		HttpClient: httpClient,
+		Recorder:   mgr.GetEventRecorderFor("dot-ai-controller"),
+	}
+
 	if err := (&controller.SolutionReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Recorder: mgr.GetEventRecorderFor("dot-ai-controller"),
+		Notifier: statusNotifier,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Solution")
 		os.Exit(1)
@@ -243,7 +309,8 @@ func main() {
 		Scheme:     mgr.GetScheme(),
 		Recorder:   mgr.GetEventRecorderFor("dot-ai-controller"),
 		RestConfig: mgr.GetConfig(),
-		HttpClient: httpClient,
+		Notifier:   statusNotifier,
+		SinkDir:    resourceSyncSinkDir,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ResourceSyncConfig")
 		os.Exit(1)
@@ -254,6 +321,7 @@ func main() {
 		Scheme:     mgr.GetScheme(),
 		Recorder:   mgr.GetEventRecorderFor("dot-ai-controller"),
 		RestConfig: mgr.GetConfig(),
+		Notifier:   statusNotifier,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "CapabilityScanConfig")
 		os.Exit(1)
@@ -264,10 +332,20 @@ func main() {
 		Scheme:         mgr.GetScheme(),
 		Recorder:       mgr.GetEventRecorderFor("dot-ai-controller"),
 		ScheduleParser: controller.NewScheduleParser(),
+		Notifier:       statusNotifier,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "GitKnowledgeSource")
 		os.Exit(1)
 	}
+
+	if err := (&controller.MCPServerReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("dot-ai-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MCPServer")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -314,4 +392,11 @@ func main() {
 
 	// Perform final cooldown state sync on shutdown
 	cooldownPersistence.Stop()
+
+	// Flush pending spans
+	tracingCtx, cancel := context.WithTimeout(context.Background(), tracing.ShutdownTimeout)
+	defer cancel()
+	if err := shutdownTracing(tracingCtx); err != nil {
+		setupLog.Error(err, "failed to flush traces")
+	}
 }
@@ -12,6 +12,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -26,12 +27,14 @@ import (
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+	"github.com/vfarcic/dot-ai-controller/internal/tracing"
 )
 
 // CapabilityScanReconciler reconciles CapabilityScanConfig objects
@@ -43,6 +46,9 @@ type CapabilityScanReconciler struct {
 	// RestConfig for creating discovery client
 	RestConfig *rest.Config
 
+	// Notifier sends notifications when scans start failing or recover
+	Notifier *StatusNotifier
+
 	// discoveryClient for finding all resource types
 	discoveryClient discovery.DiscoveryInterface
 
@@ -58,6 +64,8 @@ type capabilityScanState struct {
 	mcpClient    *MCPCapabilityScanClient
 	buffer       *CapabilityScanBuffer
 	bufferCancel context.CancelFunc
+	// credentialsVersion fingerprints the referenced Secrets; a change triggers a full scan
+	credentialsVersion string
 }
 
 // +kubebuilder:rbac:groups=dot-ai.devopstoolkit.live,resources=capabilityscanconfigs,verbs=get;list;watch;create;update;patch;delete
@@ -65,9 +73,15 @@ type capabilityScanState struct {
 // +kubebuilder:rbac:groups=dot-ai.devopstoolkit.live,resources=capabilityscanconfigs/finalizers,verbs=update
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts/token,verbs=create
 
 // Reconcile handles CapabilityScanConfig CR changes
-func (r *CapabilityScanReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *CapabilityScanReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	ctx, span := tracing.StartReconcile(ctx, "CapabilityScanConfig", req.NamespacedName)
+	defer func() { tracing.End(span, err) }()
+
 	logger := logf.FromContext(ctx).WithValues("capabilityscanconfig", req.Name)
 
 	// Fetch the CapabilityScanConfig
@@ -77,6 +91,7 @@ func (r *CapabilityScanReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			// CR was deleted - remove from active configs
 			logger.Info("CapabilityScanConfig deleted, removing from active configs")
 			r.removeConfig(req.Namespace + "/" + req.Name)
+			forgetCapabilityScanMetrics(req.Namespace + "/" + req.Name)
 			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "Failed to get CapabilityScanConfig")
@@ -85,9 +100,18 @@ func (r *CapabilityScanReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	logger.Info("Reconciling CapabilityScanConfig",
 		"mcpEndpoint", config.Spec.MCP.Endpoint,
+		"mcpServerRef", config.Spec.MCP.McpServerRef,
 		"collection", config.GetCollection(),
 	)
 
+	if config.Spec.MCP.Endpoint == "" && config.Spec.MCP.McpServerRef == "" {
+		err := fmt.Errorf("either mcp.endpoint or mcp.mcpServerRef is required")
+		logger.Error(err, "Invalid CapabilityScanConfig")
+		r.removeConfig(req.Namespace + "/" + req.Name)
+		r.updateStatus(ctx, &config, false, err.Error())
+		return ctrl.Result{}, nil
+	}
+
 	key := req.Namespace + "/" + req.Name
 
 	// Check if we already have this config active
@@ -107,11 +131,17 @@ func (r *CapabilityScanReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	if exists {
 		// Check if config changed
+		credentialsVersion := secretReferencesVersion(ctx, r.Client, config.Namespace, capabilityScanSecretReferences(&config))
 		if r.configChanged(existingState.config, &config) {
 			logger.Info("CapabilityScanConfig changed, updating state")
 			r.removeConfig(key)
+		} else if credentialsVersion != existingState.credentialsVersion {
+			// Rotated credentials may fix a failed scan, so scan again with the new Secrets
+			logger.Info("🔑 Referenced Secrets changed, rescanning capabilities")
+			r.removeConfig(key)
 		} else {
-			// Config unchanged, nothing to do
+			// Config unchanged, only refresh the readiness of the referenced MCPServer and Secrets
+			r.updateReferenceConditions(ctx, &config)
 			return ctrl.Result{RequeueAfter: 60 * time.Second}, nil
 		}
 	}
@@ -123,6 +153,10 @@ func (r *CapabilityScanReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		K8sClient:           r.Client,
 		AuthSecretRef:       config.Spec.MCP.AuthSecretRef,
 		AuthSecretNamespace: config.Namespace,
+		Auth:                config.Spec.MCP.Auth,
+		TLS:                 config.Spec.MCP.TLS,
+		ProxyURL:            config.Spec.MCP.ProxyURL,
+		ServerRef:           config.Spec.MCP.McpServerRef,
 		MaxRetries:          ptr.To(config.GetMaxAttempts()),
 		InitialBackoff:      time.Duration(config.GetBackoffSeconds()) * time.Second,
 		MaxBackoff:          time.Duration(config.GetMaxBackoffSeconds()) * time.Second,
@@ -134,6 +168,7 @@ func (r *CapabilityScanReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		Window:    debounceWindow,
 		MCPClient: mcpClient,
 		OnFlush: func(scans int, deletes int, err error) {
+			recordCapabilityScanDiff(key, scans, deletes)
 			if err != nil {
 				r.updateStatusByKey(context.Background(), key, false, err.Error())
 			} else if scans > 0 || deletes > 0 {
@@ -147,10 +182,11 @@ func (r *CapabilityScanReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	go buffer.Run(bufferCtx)
 
 	state := &capabilityScanState{
-		config:       config.DeepCopy(),
-		mcpClient:    mcpClient,
-		buffer:       buffer,
-		bufferCancel: bufferCancel,
+		config:             config.DeepCopy(),
+		mcpClient:          mcpClient,
+		buffer:             buffer,
+		bufferCancel:       bufferCancel,
+		credentialsVersion: secretReferencesVersion(ctx, r.Client, config.Namespace, capabilityScanSecretReferences(&config)),
 	}
 
 	r.configsMu.Lock()
@@ -174,7 +210,7 @@ func (r *CapabilityScanReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 // configChanged checks if the relevant config fields have changed
 func (r *CapabilityScanReconciler) configChanged(old, new *dotaiv1alpha1.CapabilityScanConfig) bool {
-	if old.Spec.MCP.Endpoint != new.Spec.MCP.Endpoint {
+	if old.Spec.MCP.Endpoint != new.Spec.MCP.Endpoint || old.Spec.MCP.McpServerRef != new.Spec.MCP.McpServerRef {
 		return true
 	}
 	if old.Spec.MCP.Collection != new.Spec.MCP.Collection {
@@ -184,6 +220,12 @@ func (r *CapabilityScanReconciler) configChanged(old, new *dotaiv1alpha1.Capabil
 		old.Spec.MCP.AuthSecretRef.Key != new.Spec.MCP.AuthSecretRef.Key {
 		return true
 	}
+	if !reflect.DeepEqual(old.Spec.MCP.Auth, new.Spec.MCP.Auth) {
+		return true
+	}
+	if !reflect.DeepEqual(old.Spec.MCP.TLS, new.Spec.MCP.TLS) || old.Spec.MCP.ProxyURL != new.Spec.MCP.ProxyURL {
+		return true
+	}
 	if !stringSlicesEqual(old.Spec.IncludeResources, new.Spec.IncludeResources) {
 		return true
 	}
@@ -252,6 +294,7 @@ func (r *CapabilityScanReconciler) performStartupReconciliation(ctx context.Cont
 
 	// Compute diff
 	toScan, toDelete := computeCapabilityDiff(clusterResources, mcpCapabilities)
+	recordCapabilityScanDiff(configKey, len(toScan), len(toDelete))
 
 	logger.Info("Computed capability diff",
 		"toScan", len(toScan),
@@ -471,10 +514,16 @@ func (r *CapabilityScanReconciler) listAllResourceIDs(ctx context.Context, confi
 
 // shouldProcessResource checks if a resource matches include/exclude filters
 func (r *CapabilityScanReconciler) shouldProcessResource(config *dotaiv1alpha1.CapabilityScanConfig, resourceID string) bool {
+	return matchesResourcePatterns(resourceID, config.Spec.IncludeResources, config.Spec.ExcludeResources)
+}
+
+// matchesResourcePatterns checks if a resource ID matches include/exclude patterns
+// If include patterns are specified, the resource must match at least one of them
+func matchesResourcePatterns(resourceID string, include, exclude []string) bool {
 	// If include list is specified, resource must match at least one pattern
-	if len(config.Spec.IncludeResources) > 0 {
+	if len(include) > 0 {
 		matched := false
-		for _, pattern := range config.Spec.IncludeResources {
+		for _, pattern := range include {
 			if matchesPattern(resourceID, pattern) {
 				matched = true
 				break
@@ -486,7 +535,7 @@ func (r *CapabilityScanReconciler) shouldProcessResource(config *dotaiv1alpha1.C
 	}
 
 	// Check exclude list
-	for _, pattern := range config.Spec.ExcludeResources {
+	for _, pattern := range exclude {
 		if matchesPattern(resourceID, pattern) {
 			return false
 		}
@@ -606,6 +655,13 @@ func (r *CapabilityScanReconciler) updateStatus(ctx context.Context, config *dot
 	if !updated {
 		fresh.Status.Conditions = append(fresh.Status.Conditions, readyCondition)
 	}
+	setMCPServerReadyCondition(ctx, r.Client, &fresh.Status.Conditions, fresh.Spec.MCP.McpServerRef, fresh.Generation)
+	setCredentialsResolvedCondition(ctx, r.Client, &fresh.Status.Conditions, fresh.Namespace,
+		capabilityScanSecretReferences(fresh), fresh.Generation)
+
+	// Record health transitions for notifications
+	transition := r.Notifier.Prepare(fresh.Spec.Notifications, &fresh.Status.Notifications,
+		readyCondition.Status == metav1.ConditionTrue, readyCondition.Reason, readyCondition.Message)
 
 	if err := r.Status().Update(ctx, fresh); err != nil {
 		if apierrors.IsConflict(err) {
@@ -613,6 +669,27 @@ func (r *CapabilityScanReconciler) updateStatus(ctx context.Context, config *dot
 			return
 		}
 		logger.Error(err, "Failed to update CapabilityScanConfig status")
+		return
+	}
+
+	r.Notifier.Send(ctx, fresh, "CapabilityScanConfig", fresh.Spec.Notifications, transition)
+}
+
+// updateReferenceConditions refreshes the readiness of the referenced MCPServer and the
+// CredentialsResolved condition in the status
+func (r *CapabilityScanReconciler) updateReferenceConditions(ctx context.Context, config *dotaiv1alpha1.CapabilityScanConfig) {
+	fresh := &dotaiv1alpha1.CapabilityScanConfig{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(config), fresh); err != nil {
+		return
+	}
+	serverChanged := setMCPServerReadyCondition(ctx, r.Client, &fresh.Status.Conditions, fresh.Spec.MCP.McpServerRef, fresh.Generation)
+	credentialsChanged := setCredentialsResolvedCondition(ctx, r.Client, &fresh.Status.Conditions, fresh.Namespace,
+		capabilityScanSecretReferences(fresh), fresh.Generation)
+	if !serverChanged && !credentialsChanged {
+		return
+	}
+	if err := r.Status().Update(ctx, fresh); err != nil && !apierrors.IsConflict(err) {
+		logf.FromContext(ctx).Error(err, "Failed to update reference conditions")
 	}
 }
 
@@ -648,6 +725,24 @@ func (r *CapabilityScanReconciler) updateLastScanTime(ctx context.Context, key s
 	}
 }
 
+// mapSecretToRequests enqueues the CapabilityScanConfigs that reference a Secret,
+// so that they validate it again and rescan when it is rotated
+func (r *CapabilityScanReconciler) mapSecretToRequests(ctx context.Context, obj client.Object) []reconcile.Request {
+	var configs dotaiv1alpha1.CapabilityScanConfigList
+	if err := r.List(ctx, &configs, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list CapabilityScanConfigs for Secret", "secret", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, config := range configs.Items {
+		if referencesSecret(capabilityScanSecretReferences(&config), obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&config)})
+		}
+	}
+	return requests
+}
+
 // capabilityScanConfigKey returns a unique key for a CapabilityScanConfig (namespace/name)
 func capabilityScanConfigKey(config *dotaiv1alpha1.CapabilityScanConfig) string {
 	return config.Namespace + "/" + config.Name
@@ -661,6 +756,15 @@ func (r *CapabilityScanReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&apiextensionsv1.CustomResourceDefinition{},
 			handler.EnqueueRequestsFromMapFunc(r.mapCRDToRequests),
 		).
+		Watches(
+			&dotaiv1alpha1.MCPServer{},
+			handler.EnqueueRequestsFromMapFunc(r.mapMCPServerToRequests),
+		).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.mapSecretToRequests),
+			builder.WithPredicates(secretDataChangedPredicate()),
+		).
 		Named("capabilityscan").
 		Complete(r)
 }
@@ -694,3 +798,21 @@ func (r *CapabilityScanReconciler) mapCRDToRequests(ctx context.Context, obj cli
 	// Don't enqueue any reconcile requests - we handle CRD events directly
 	return nil
 }
+
+// mapMCPServerToRequests enqueues the CapabilityScanConfigs that reference an MCPServer,
+// so that they surface its readiness
+func (r *CapabilityScanReconciler) mapMCPServerToRequests(ctx context.Context, obj client.Object) []reconcile.Request {
+	var configs dotaiv1alpha1.CapabilityScanConfigList
+	if err := r.List(ctx, &configs); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list CapabilityScanConfigs for MCPServer", "mcpserver", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, config := range configs.Items {
+		if config.Spec.MCP.McpServerRef == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&config)})
+		}
+	}
+	return requests
+}
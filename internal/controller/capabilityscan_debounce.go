@@ -9,7 +9,10 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/vfarcic/dot-ai-controller/internal/tracing"
 )
 
 // CRDChange represents a CRD create/update or delete event
@@ -150,6 +153,12 @@ func (b *CapabilityScanBuffer) flush(ctx context.Context) {
 	b.pendingDeletes = make(map[string]struct{})
 	b.mu.Unlock()
 
+	ctx, span := tracing.Start(ctx, "CapabilityScanConfig.flush",
+		attribute.Int("capabilityscan.scans", len(scans)),
+		attribute.Int("capabilityscan.deletes", len(deletes)),
+	)
+	defer span.End()
+
 	logger.Info("Flushing CRD changes to MCP",
 		"scans", len(scans),
 		"deletes", len(deletes),
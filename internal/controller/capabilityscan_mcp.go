@@ -1,28 +1,26 @@
 // capabilityscan_mcp.go contains the MCP client for capability scanning.
-// This file handles HTTP communication with the MCP server's manageOrgData endpoint
-// for listing, scanning, and deleting capabilities.
+// This file handles the manageOrgData endpoint of the MCP server for listing, scanning,
+// and deleting capabilities; HTTP communication and retries are delegated to internal/mcp.
 package controller
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"math"
-	"math/rand"
 	"net/http"
 	"strings"
 	"time"
 
-	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+	"github.com/vfarcic/dot-ai-controller/internal/mcp"
 )
 
+// ManageOrgDataPath is the path of the manageOrgData endpoint relative to the MCP server URL
+const ManageOrgDataPath = "/api/v1/tools/manageOrgData"
+
 // ManageOrgDataRequest is the request body for POST /api/v1/tools/manageOrgData
 type ManageOrgDataRequest struct {
 	DataType     string `json:"dataType"`
@@ -104,6 +102,10 @@ type MCPCapabilityScanClient struct {
 	k8sClient           client.Client
 	authSecretRef       dotaiv1alpha1.SecretReference
 	authSecretNamespace string
+	auth                *dotaiv1alpha1.McpAuthConfig
+	tls                 *dotaiv1alpha1.TLSConfig
+	proxyURL            string
+	serverRef           string
 	maxRetries          int
 	initialBackoff      time.Duration
 	maxBackoff          time.Duration
@@ -117,15 +119,19 @@ type MCPCapabilityScanClientConfig struct {
 	K8sClient           client.Client
 	AuthSecretRef       dotaiv1alpha1.SecretReference
 	AuthSecretNamespace string
-	MaxRetries          *int // Pointer to distinguish "not set" (nil->default 3) from "set to 0"
+	Auth                *dotaiv1alpha1.McpAuthConfig
+	TLS                 *dotaiv1alpha1.TLSConfig
+	ProxyURL            string
+	ServerRef           string // MCPServer providing the endpoint and credentials instead
+	MaxRetries          *int   // Pointer to distinguish "not set" (nil->MCP default) from "set to 0"
 	InitialBackoff      time.Duration
 	MaxBackoff          time.Duration
 }
 
 // NewMCPCapabilityScanClient creates a new MCP capability scan client
 func NewMCPCapabilityScanClient(cfg MCPCapabilityScanClientConfig) *MCPCapabilityScanClient {
-	// Apply defaults
-	maxRetries := 3
+	// Apply defaults; backoff defaults match the CapabilityScanConfig retry defaults
+	maxRetries := mcp.DefaultRetryPolicy().MaxRetries
 	if cfg.MaxRetries != nil {
 		maxRetries = *cfg.MaxRetries
 	}
@@ -136,9 +142,7 @@ func NewMCPCapabilityScanClient(cfg MCPCapabilityScanClientConfig) *MCPCapabilit
 		cfg.MaxBackoff = 300 * time.Second
 	}
 	if cfg.HTTPClient == nil {
-		cfg.HTTPClient = &http.Client{
-			Timeout: 60 * time.Second,
-		}
+		cfg.HTTPClient = mcp.NewHTTPClient()
 	}
 	if cfg.Collection == "" {
 		cfg.Collection = "capabilities"
@@ -146,8 +150,8 @@ func NewMCPCapabilityScanClient(cfg MCPCapabilityScanClientConfig) *MCPCapabilit
 
 	// Build endpoint URL
 	endpoint := strings.TrimSuffix(cfg.Endpoint, "/")
-	if !strings.HasSuffix(endpoint, "/api/v1/tools/manageOrgData") {
-		endpoint = endpoint + "/api/v1/tools/manageOrgData"
+	if !strings.HasSuffix(endpoint, ManageOrgDataPath) {
+		endpoint = endpoint + ManageOrgDataPath
 	}
 
 	return &MCPCapabilityScanClient{
@@ -157,6 +161,10 @@ func NewMCPCapabilityScanClient(cfg MCPCapabilityScanClientConfig) *MCPCapabilit
 		k8sClient:           cfg.K8sClient,
 		authSecretRef:       cfg.AuthSecretRef,
 		authSecretNamespace: cfg.AuthSecretNamespace,
+		auth:                cfg.Auth,
+		tls:                 cfg.TLS,
+		proxyURL:            cfg.ProxyURL,
+		serverRef:           cfg.ServerRef,
 		maxRetries:          maxRetries,
 		initialBackoff:      cfg.InitialBackoff,
 		maxBackoff:          cfg.MaxBackoff,
@@ -270,152 +278,40 @@ func (c *MCPCapabilityScanClient) DeleteCapability(ctx context.Context, id strin
 	return nil
 }
 
-// sendWithRetry sends the request with exponential backoff retry
+// sendWithRetry sends the request through the shared MCP client, which retries transport
+// errors, HTTP error statuses and "success": false responses with exponential backoff
 func (c *MCPCapabilityScanClient) sendWithRetry(ctx context.Context, req ManageOrgDataRequest) (*ManageOrgDataResponse, error) {
 	logger := logf.FromContext(ctx).WithName("capabilityscan-mcp")
 
-	var lastErr error
-	for attempt := 0; attempt <= c.maxRetries; attempt++ {
-		if attempt > 0 {
-			// Calculate backoff with jitter
-			backoff := c.calculateBackoff(attempt)
-			logger.V(1).Info("Retrying MCP request",
-				"attempt", attempt,
-				"maxRetries", c.maxRetries,
-				"backoff", backoff,
-			)
-
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(backoff):
-			}
-		}
-
-		resp, err := c.send(ctx, req)
-		if err == nil && resp.Success {
-			return resp, nil
-		}
-
-		if err != nil {
-			lastErr = err
-			logger.V(1).Info("MCP request failed",
-				"attempt", attempt,
-				"error", err,
-			)
-		} else if !resp.Success {
-			lastErr = fmt.Errorf("MCP returned error: %s", resp.GetErrorMessage())
-			logger.V(1).Info("MCP returned error response",
-				"attempt", attempt,
-				"error", resp.GetErrorMessage(),
-			)
-		}
-
-		// Don't retry on context cancellation
-		if ctx.Err() != nil {
-			return nil, ctx.Err()
-		}
-	}
-
-	return nil, fmt.Errorf("failed after %d retries: %w", c.maxRetries, lastErr)
-}
-
-// calculateBackoff returns the backoff duration with jitter for the given attempt
-func (c *MCPCapabilityScanClient) calculateBackoff(attempt int) time.Duration {
-	// Exponential backoff: initialBackoff * 2^attempt
-	backoff := float64(c.initialBackoff) * math.Pow(2, float64(attempt-1))
-
-	// Cap at maxBackoff
-	if backoff > float64(c.maxBackoff) {
-		backoff = float64(c.maxBackoff)
-	}
-
-	// Add jitter (±25%)
-	jitter := backoff * 0.25 * (rand.Float64()*2 - 1)
-	return time.Duration(backoff + jitter)
-}
-
-// send performs a single HTTP request to the MCP endpoint
-func (c *MCPCapabilityScanClient) send(ctx context.Context, req ManageOrgDataRequest) (*ManageOrgDataResponse, error) {
-	logger := logf.FromContext(ctx).WithName("capabilityscan-mcp")
-
-	// Get auth token if configured
-	authToken, err := c.getAuthToken(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get auth token: %w", err)
-	}
-
-	// Marshal request
-	requestBody, err := json.Marshal(req)
+	endpoint, credentials, httpClient, err := c.connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, err
 	}
 
 	logger.V(1).Info("Sending request",
-		"endpoint", c.endpoint,
+		"endpoint", endpoint,
 		"operation", req.Operation,
 		"mode", req.Mode,
 		"resourceList", req.ResourceList,
 		"id", req.ID,
 	)
 
-	// Create HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewBuffer(requestBody))
+	resp, err := c.mcpClient(httpClient).Do(ctx, mcp.Request{
+		URL:   endpoint,
+		Body:  req,
+		Token: credentials.Token,
+		Retry: mcp.RetryFailures,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set headers
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("User-Agent", "dot-ai-controller/v1.0.0")
-	if authToken != "" {
-		httpReq.Header.Set("Authorization", "Bearer "+authToken)
-	}
-
-	// Send request
-	startTime := time.Now()
-	resp, err := c.httpClient.Do(httpReq)
-	duration := time.Since(startTime)
-
-	if err != nil {
-		logger.Error(err, "❌ HTTP request failed", "duration", duration)
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Read response body
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	logger.V(1).Info("Received response",
-		"statusCode", resp.StatusCode,
-		"duration", duration,
-		"bodySize", len(responseBody),
-	)
-
-	// Handle HTTP errors
-	if resp.StatusCode >= 400 {
-		return &ManageOrgDataResponse{
-			Success: false,
-			Error: &struct {
-				Code    string `json:"code"`
-				Message string `json:"message"`
-			}{
-				Code:    fmt.Sprintf("%d", resp.StatusCode),
-				Message: fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(responseBody)),
-			},
-		}, nil
+		return nil, err
 	}
 
 	// Parse JSON response
 	var mcpResponse ManageOrgDataResponse
-	if err := json.Unmarshal(responseBody, &mcpResponse); err != nil {
+	if err := json.Unmarshal(resp.Body, &mcpResponse); err != nil {
 		// If JSON parsing fails but HTTP was successful, treat as success
 		logger.Info("⚠️ Response is not JSON, treating as successful",
-			"response", string(responseBody),
+			"response", string(resp.Body),
 			"parseError", err.Error(),
 		)
 		return &ManageOrgDataResponse{
@@ -426,50 +322,49 @@ func (c *MCPCapabilityScanClient) send(ctx context.Context, req ManageOrgDataReq
 	logger.Info("✅ Request completed",
 		"success", mcpResponse.Success,
 		"operation", req.Operation,
-		"duration", duration,
+		"duration", resp.Duration,
+		"attempts", resp.Attempts,
 	)
 
 	return &mcpResponse, nil
 }
 
-// getAuthToken resolves the auth token from the secret reference
-func (c *MCPCapabilityScanClient) getAuthToken(ctx context.Context) (string, error) {
-	// If no auth secret is configured, skip auth
-	if c.authSecretRef.Name == "" {
-		return "", nil
-	}
-
-	logger := logf.FromContext(ctx).WithName("capabilityscan-mcp")
-
-	secret := &corev1.Secret{}
-	secretKey := client.ObjectKey{
-		Namespace: c.authSecretNamespace,
-		Name:      c.authSecretRef.Name,
-	}
-
-	if err := c.k8sClient.Get(ctx, secretKey, secret); err != nil {
-		if apierrors.IsNotFound(err) {
-			return "", fmt.Errorf("auth secret '%s' not found in namespace '%s'",
-				c.authSecretRef.Name, c.authSecretNamespace)
+// connect resolves the endpoint, credentials and HTTP client of the next request. With an
+// MCPServer reference they are resolved from the MCPServer; the retry settings of this client still apply.
+func (c *MCPCapabilityScanClient) connect(ctx context.Context) (string, mcp.Credentials, *http.Client, error) {
+	if c.serverRef == "" {
+		settings, err := mcp.ResolveTLSSettings(ctx, c.k8sClient, c.authSecretNamespace, c.tls, c.proxyURL)
+		if err != nil {
+			return "", mcp.Credentials{}, nil, fmt.Errorf("invalid MCP TLS settings: %w", err)
 		}
-		return "", fmt.Errorf("failed to fetch auth secret: %w", err)
-	}
-
-	tokenBytes, exists := secret.Data[c.authSecretRef.Key]
-	if !exists {
-		return "", fmt.Errorf("auth secret '%s' does not contain key '%s'",
-			c.authSecretRef.Name, c.authSecretRef.Key)
+		credentials, err := mcp.ResolveEndpointCredentials(ctx, c.k8sClient, c.authSecretNamespace, c.authSecretRef, c.auth, settings)
+		if err != nil {
+			return "", mcp.Credentials{}, nil, fmt.Errorf("failed to get MCP credentials: %w", err)
+		}
+		return c.endpoint, credentials, credentials.HTTPClient(c.httpClient), nil
 	}
 
-	if len(tokenBytes) == 0 {
-		return "", fmt.Errorf("auth secret '%s' key '%s' is empty",
-			c.authSecretRef.Name, c.authSecretRef.Key)
+	connection, err := resolveMCPServerConnection(ctx, c.k8sClient, c.serverRef)
+	if err != nil {
+		return "", mcp.Credentials{}, nil, err
 	}
+	return connection.URL(ManageOrgDataPath), connection.Credentials, connection.HTTPClient(c.httpClient), nil
+}
 
-	logger.V(2).Info("Resolved auth token from secret",
-		"secretName", c.authSecretRef.Name,
-		"secretKey", c.authSecretRef.Key,
-	)
+// mcpClient returns the shared MCP client configured with the retry settings of this client
+func (c *MCPCapabilityScanClient) mcpClient(httpClient *http.Client) *mcp.Client {
+	return mcp.NewClient(mcp.Config{
+		Component:  "capabilityscan",
+		HTTPClient: httpClient,
+		Retry:      c.retryPolicy(),
+	})
+}
 
-	return string(tokenBytes), nil
+// retryPolicy returns the retry policy of this client
+func (c *MCPCapabilityScanClient) retryPolicy() mcp.RetryPolicy {
+	return mcp.RetryPolicy{
+		MaxRetries:     c.maxRetries,
+		InitialBackoff: c.initialBackoff,
+		MaxBackoff:     c.maxBackoff,
+	}
 }
@@ -632,6 +632,36 @@ func TestMCPCapabilityScanClient_RetryBehavior(t *testing.T) {
 	}
 }
 
+func TestMCPCapabilityScanClient_RetriesUnsuccessfulResponses(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Content-Type", "application/json")
+		if attempts < 3 {
+			// manageOrgData reports failures of its backends with HTTP 200 and success:false
+			_, _ = w.Write([]byte(`{"success":false,"error":{"code":"SCAN_FAILED","message":"vector DB unavailable"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+	defer server.Close()
+
+	client := NewMCPCapabilityScanClient(MCPCapabilityScanClientConfig{
+		Endpoint:       server.URL,
+		Collection:     "test-capabilities",
+		MaxRetries:     ptr.To(3),
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     50 * time.Millisecond,
+	})
+
+	if err := client.TriggerScan(context.Background(), "Deployment.apps"); err != nil {
+		t.Errorf("Expected success after retries, got error: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+}
+
 func TestMCPCapabilityScanClient_EndpointConstruction(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -15,12 +16,17 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+	"github.com/vfarcic/dot-ai-controller/internal/mcp"
+	"github.com/vfarcic/dot-ai-controller/internal/tracing"
 )
 
 const (
@@ -50,12 +56,16 @@ type GitKnowledgeSourceReconciler struct {
 	Scheme         *runtime.Scheme
 	Recorder       record.EventRecorder
 	ScheduleParser *ScheduleParser
+	Notifier       *StatusNotifier
 }
 
 // +kubebuilder:rbac:groups=dot-ai.devopstoolkit.live,resources=gitknowledgesources,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=dot-ai.devopstoolkit.live,resources=gitknowledgesources/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=dot-ai.devopstoolkit.live,resources=gitknowledgesources/finalizers,verbs=update
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts/token,verbs=create
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile handles GitKnowledgeSource reconciliation.
@@ -68,7 +78,10 @@ type GitKnowledgeSourceReconciler struct {
 // 6. Updates status with sync results
 // 7. Cleans up clone directory after sync
 // 8. Schedules next sync using RequeueAfter
-func (r *GitKnowledgeSourceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *GitKnowledgeSourceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	ctx, span := tracing.StartReconcile(ctx, "GitKnowledgeSource", req.NamespacedName)
+	defer func() { tracing.End(span, err) }()
+
 	logger := logf.FromContext(ctx).WithValues("gitknowledgesource", req.NamespacedName)
 
 	// Fetch the GitKnowledgeSource instance
@@ -96,6 +109,7 @@ func (r *GitKnowledgeSourceReconciler) Reconcile(ctx context.Context, req ctrl.R
 
 			// Clean up sync lock to prevent memory leak
 			syncLocks.Delete(req.NamespacedName.String())
+			forgetKnowledgeSourceMetrics(req.Namespace, req.Name)
 
 			logger.Info("Finalizer removed, deletion complete")
 		}
@@ -152,12 +166,22 @@ func (r *GitKnowledgeSourceReconciler) Reconcile(ctx context.Context, req ctrl.R
 		r.Recorder.Event(&gks, corev1.EventTypeWarning, "SyncTimeout", "Sync operation timed out")
 	}
 
+	// Surface the readiness of the referenced MCPServer and the referenced Secrets
+	setMCPServerReadyCondition(ctx, r.Client, &gks.Status.Conditions, gks.Spec.McpServer.McpServerRef, gks.Generation)
+	setCredentialsResolvedCondition(ctx, r.Client, &gks.Status.Conditions, gks.Namespace,
+		gitKnowledgeSourceSecretReferences(&gks), gks.Generation)
+
+	// Record health transitions for notifications
+	transition := r.prepareStatusNotification(&gks)
+
 	// Update status regardless of error
 	if statusErr := r.Status().Update(ctx, &gks); statusErr != nil {
 		logger.Error(statusErr, "Failed to update status")
 		if err == nil {
 			err = statusErr
 		}
+	} else {
+		r.Notifier.Send(ctx, &gks, "GitKnowledgeSource", gks.Spec.Notifications, transition)
 	}
 
 	return result, err
@@ -194,8 +218,8 @@ func (r *GitKnowledgeSourceReconciler) handleDeletion(ctx context.Context, gks *
 		"sourceIdentifier", fmt.Sprintf("%s/%s", gks.Namespace, gks.Name),
 	)
 
-	// Get MCP auth token
-	mcpAuthToken, err := r.getSecretValue(ctx, gks.Namespace, &gks.Spec.McpServer.AuthSecretRef)
+	// Get MCP connection settings
+	mcpConnection, err := r.getMcpConnection(ctx, gks)
 	if err != nil {
 		// If secret is not found, we can't delete from MCP but should still allow CR deletion
 		logger.Error(err, "Failed to get MCP auth token, cannot delete documents from MCP")
@@ -206,14 +230,10 @@ func (r *GitKnowledgeSourceReconciler) handleDeletion(ctx context.Context, gks *
 
 	// Build the delete URL
 	sourceIdentifier := fmt.Sprintf("%s/%s", gks.Namespace, gks.Name)
-	baseURL := strings.TrimSuffix(gks.Spec.McpServer.URL, "/")
-	deleteURL := fmt.Sprintf("%s/api/v1/knowledge/source/%s", baseURL, url.PathEscape(sourceIdentifier))
+	deleteURL := mcpConnection.URL("/api/v1/knowledge/source/" + url.PathEscape(sourceIdentifier))
 
 	// Create MCP client and delete documents
-	mcpClient := NewMCPKnowledgeClient(MCPKnowledgeClientConfig{
-		Endpoint:  deleteURL, // Not used by DeleteBySource but needed for client creation
-		AuthToken: mcpAuthToken,
-	})
+	mcpClient := NewMCPKnowledgeClient(mcpConnection.knowledgeClientConfig(deleteURL)) // Endpoint not used by DeleteBySource
 
 	resp, err := mcpClient.DeleteBySource(ctx, deleteURL)
 	if err != nil {
@@ -240,6 +260,16 @@ func (r *GitKnowledgeSourceReconciler) handleDeletion(ctx context.Context, gks *
 func (r *GitKnowledgeSourceReconciler) doSync(ctx context.Context, gks *dotaiv1alpha1.GitKnowledgeSource, specChanged bool) (ctrl.Result, error) {
 	logger := logf.FromContext(ctx)
 
+	// Syncs that return before the documents are ingested count as failed
+	syncStart := time.Now()
+	syncResult := "failed"
+	defer func() {
+		if syncResult == "failed" {
+			knowledgeSyncErrors.WithLabelValues(gks.Namespace, gks.Name).Inc()
+		}
+		knowledgeSyncDuration.WithLabelValues(gks.Namespace, gks.Name, syncResult).Observe(time.Since(syncStart).Seconds())
+	}()
+
 	// Mark as active
 	gks.Status.Active = true
 
@@ -270,8 +300,8 @@ func (r *GitKnowledgeSourceReconciler) doSync(ctx context.Context, gks *dotaiv1a
 		gitAuthToken = token
 	}
 
-	// Get MCP auth token
-	mcpAuthToken, err := r.getSecretValue(ctx, gks.Namespace, &gks.Spec.McpServer.AuthSecretRef)
+	// Get MCP connection settings
+	mcpConnection, err := r.getMcpConnection(ctx, gks)
 	if err != nil {
 		r.setErrorCondition(gks, "MCPAuthError", err.Error())
 		r.Recorder.Event(gks, corev1.EventTypeWarning, "MCPAuthError", err.Error())
@@ -362,11 +392,7 @@ func (r *GitKnowledgeSourceReconciler) doSync(ctx context.Context, gks *dotaiv1a
 processFiles:
 
 	// Create MCP client
-	mcpEndpoint := strings.TrimSuffix(gks.Spec.McpServer.URL, "/") + "/api/v1/tools/manageKnowledge"
-	mcpClient := NewMCPKnowledgeClient(MCPKnowledgeClientConfig{
-		Endpoint:  mcpEndpoint,
-		AuthToken: mcpAuthToken,
-	})
+	mcpClient := NewMCPKnowledgeClient(mcpConnection.knowledgeClientConfig(mcpConnection.URL("/api/v1/tools/manageKnowledge")))
 
 	// M7: Build metadata with sourceIdentifier for MCP bulk operations
 	metadata := make(map[string]string)
@@ -381,9 +407,10 @@ processFiles:
 	var lastError string
 	var skippedFiles []dotaiv1alpha1.SkippedFile
 
+	ingestCtx, ingestSpan := tracing.Start(ctx, "GitKnowledgeSource.ingest", attribute.Int("git.files", len(filesToProcess)))
 	for _, filePath := range filesToProcess {
 		// Read file content
-		content, err := gitClient.GetFileContent(ctx, filePath)
+		content, err := gitClient.GetFileContent(ingestCtx, filePath)
 		if err != nil {
 			logger.Error(err, "Failed to read file", "path", filePath)
 			syncErrors++
@@ -409,7 +436,7 @@ processFiles:
 		uri := BuildDocumentURI(gks.Spec.Repository.URL, gks.Spec.Repository.Branch, filePath)
 
 		// Ingest document
-		resp, err := mcpClient.IngestDocument(ctx, uri, string(content), metadata)
+		resp, err := mcpClient.IngestDocument(ingestCtx, uri, string(content), metadata)
 		if err != nil {
 			logger.Error(err, "Failed to ingest document", "path", filePath, "uri", uri)
 			syncErrors++
@@ -424,6 +451,18 @@ processFiles:
 		)
 		documentCount++
 	}
+	ingestSpan.SetAttributes(
+		attribute.Int("git.documents", documentCount),
+		attribute.Int("git.errors", syncErrors),
+	)
+	ingestSpan.End()
+
+	knowledgeDocumentsIngested.WithLabelValues(gks.Namespace, gks.Name).Add(float64(documentCount))
+	knowledgeDocumentErrors.WithLabelValues(gks.Namespace, gks.Name).Add(float64(syncErrors))
+	syncResult = "success"
+	if syncErrors > 0 {
+		syncResult = "partial"
+	}
 
 	// Update status
 	now := metav1.Now()
@@ -524,6 +563,58 @@ func (r *GitKnowledgeSourceReconciler) getSecretValue(ctx context.Context, names
 	return string(value), nil
 }
 
+// getMcpConnection resolves the connection settings of the MCP server, either from the referenced
+// MCPServer or from the inline settings. The inline bearer mode (default) reads the static token
+// from the auth Secret; other modes are resolved by internal/mcp.
+func (r *GitKnowledgeSourceReconciler) getMcpConnection(ctx context.Context, gks *dotaiv1alpha1.GitKnowledgeSource) (*mcpConnection, error) {
+	server := gks.Spec.McpServer
+	if server.McpServerRef != "" {
+		return resolveMCPServerConnection(ctx, r.Client, server.McpServerRef)
+	}
+	if server.URL == "" {
+		return nil, fmt.Errorf("either mcpServer.url or mcpServer.mcpServerRef is required")
+	}
+
+	settings, err := mcp.ResolveTLSSettings(ctx, r.Client, gks.Namespace, server.TLS, server.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MCP TLS settings: %w", err)
+	}
+	if server.Auth.GetMode() != dotaiv1alpha1.McpAuthModeBearer {
+		credentials, err := mcp.ResolveEndpointCredentials(ctx, r.Client, gks.Namespace, server.AuthSecretRef, server.Auth, settings)
+		if err != nil {
+			return nil, err
+		}
+		return &mcpConnection{Endpoint: server.URL, Credentials: credentials}, nil
+	}
+
+	token, err := r.getSecretValue(ctx, gks.Namespace, &server.AuthSecretRef)
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := mcp.Credentials{Token: token}.WithTLS(settings)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MCP TLS settings: %w", err)
+	}
+	return &mcpConnection{Endpoint: server.URL, Credentials: credentials}, nil
+}
+
+// prepareStatusNotification records the notification state for Error and Synced phases
+func (r *GitKnowledgeSourceReconciler) prepareStatusNotification(gks *dotaiv1alpha1.GitKnowledgeSource) *StatusTransition {
+	reason := string(gks.Status.Phase)
+	if condition := meta.FindStatusCondition(gks.Status.Conditions, ConditionTypeSynced); condition != nil {
+		reason = condition.Reason
+	}
+
+	switch gks.Status.Phase {
+	case dotaiv1alpha1.PhaseError:
+		return r.Notifier.Prepare(gks.Spec.Notifications, &gks.Status.Notifications, false, reason, gks.Status.LastError)
+	case dotaiv1alpha1.PhaseSynced:
+		return r.Notifier.Prepare(gks.Spec.Notifications, &gks.Status.Notifications, true, reason,
+			fmt.Sprintf("Synced %d documents from %s", gks.Status.DocumentCount, gks.Spec.Repository.URL))
+	}
+	return nil
+}
+
 // setErrorCondition sets the Synced condition to False with an error.
 func (r *GitKnowledgeSourceReconciler) setErrorCondition(gks *dotaiv1alpha1.GitKnowledgeSource, reason, message string) {
 	meta.SetStatusCondition(&gks.Status.Conditions, metav1.Condition{
@@ -616,8 +707,30 @@ func BuildDocumentURI(repoURL, branch, filePath string) string {
 // SetupWithManager sets up the controller with the Manager.
 func (r *GitKnowledgeSourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&dotaiv1alpha1.GitKnowledgeSource{}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		For(&dotaiv1alpha1.GitKnowledgeSource{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.mapSecretToRequests),
+			builder.WithPredicates(secretDataChangedPredicate()),
+		).
 		Named("gitknowledgesource").
 		Complete(r)
 }
+
+// mapSecretToRequests enqueues the GitKnowledgeSources that reference a Secret, so that a
+// created or rotated Secret is used right away instead of on the next scheduled sync
+func (r *GitKnowledgeSourceReconciler) mapSecretToRequests(ctx context.Context, obj client.Object) []reconcile.Request {
+	var sources dotaiv1alpha1.GitKnowledgeSourceList
+	if err := r.List(ctx, &sources, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list GitKnowledgeSources for Secret", "secret", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, gks := range sources.Items {
+		if referencesSecret(gitKnowledgeSourceSecretReferences(&gks), obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gks)})
+		}
+	}
+	return requests
+}
@@ -12,6 +12,9 @@ import (
 	"github.com/go-git/go-git/v6/plumbing"
 	"github.com/go-git/go-git/v6/plumbing/object"
 	"github.com/go-git/go-git/v6/plumbing/transport/http"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/vfarcic/dot-ai-controller/internal/tracing"
 )
 
 const (
@@ -82,6 +85,17 @@ func NewGitClient(cfg GitClientConfig) *GitClient {
 // Clone clones the repository to the configured directory.
 // This is the primary operation - always clone fresh, never reuse existing clones.
 func (g *GitClient) Clone(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "git.clone",
+		attribute.String("git.branch", g.branch),
+		attribute.Int("git.depth", g.depth),
+	)
+	err := g.clone(ctx)
+	tracing.End(span, err)
+	return err
+}
+
+// clone removes any previous clone and clones the repository
+func (g *GitClient) clone(ctx context.Context) error {
 	// Ensure clean state - remove any existing directory
 	if err := os.RemoveAll(g.cloneDir); err != nil {
 		return fmt.Errorf("failed to clean clone directory: %w", err)
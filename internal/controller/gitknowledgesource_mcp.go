@@ -1,25 +1,24 @@
 package controller
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"math/rand"
 	"net/http"
 	"time"
+
+	"github.com/vfarcic/dot-ai-controller/internal/mcp"
 )
 
 const (
 	// DefaultMCPMaxRetries is the default number of retry attempts for MCP calls
-	DefaultMCPMaxRetries = 3
+	DefaultMCPMaxRetries = mcp.DefaultMaxRetries
 	// DefaultMCPInitialBackoff is the default initial backoff duration
-	DefaultMCPInitialBackoff = 1 * time.Second
+	DefaultMCPInitialBackoff = mcp.DefaultInitialBackoff
 	// DefaultMCPMaxBackoff is the default maximum backoff duration
-	DefaultMCPMaxBackoff = 30 * time.Second
+	DefaultMCPMaxBackoff = mcp.DefaultMaxBackoff
 	// DefaultMCPTimeout is the default HTTP timeout for MCP calls
-	DefaultMCPTimeout = 30 * time.Second
+	DefaultMCPTimeout = mcp.DefaultTimeout
 )
 
 // MCPKnowledgeClientConfig holds the configuration for creating an MCPKnowledgeClient.
@@ -51,26 +50,26 @@ type MCPKnowledgeClient struct {
 
 // NewMCPKnowledgeClient creates a new MCPKnowledgeClient with the given configuration.
 func NewMCPKnowledgeClient(cfg MCPKnowledgeClientConfig) *MCPKnowledgeClient {
-	maxRetries := DefaultMCPMaxRetries
+	defaults := mcp.DefaultRetryPolicy()
+
+	maxRetries := defaults.MaxRetries
 	if cfg.MaxRetries != nil {
 		maxRetries = *cfg.MaxRetries
 	}
 
 	initialBackoff := cfg.InitialBackoff
 	if initialBackoff == 0 {
-		initialBackoff = DefaultMCPInitialBackoff
+		initialBackoff = defaults.InitialBackoff
 	}
 
 	maxBackoff := cfg.MaxBackoff
 	if maxBackoff == 0 {
-		maxBackoff = DefaultMCPMaxBackoff
+		maxBackoff = defaults.MaxBackoff
 	}
 
 	httpClient := cfg.HTTPClient
 	if httpClient == nil {
-		httpClient = &http.Client{
-			Timeout: DefaultMCPTimeout,
-		}
+		httpClient = mcp.NewHTTPClient()
 	}
 
 	return &MCPKnowledgeClient{
@@ -213,166 +212,47 @@ func (c *MCPKnowledgeClient) DeleteBySource(ctx context.Context, deleteURL strin
 
 // doRequestWithRetry performs an HTTP POST request with retry logic.
 func (c *MCPKnowledgeClient) doRequestWithRetry(ctx context.Context, reqBody interface{}, respBody interface{}) error {
-	var lastErr error
-
-	for attempt := 0; attempt <= c.maxRetries; attempt++ {
-		if attempt > 0 {
-			backoff := c.calculateBackoff(attempt)
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(backoff):
-			}
-		}
-
-		err := c.doRequest(ctx, reqBody, respBody)
-		if err == nil {
-			return nil
-		}
-
-		lastErr = err
-
-		// Don't retry on context cancellation
-		if ctx.Err() != nil {
-			return ctx.Err()
-		}
-	}
-
-	return fmt.Errorf("MCP request failed after %d attempts: %w", c.maxRetries+1, lastErr)
-}
-
-// doRequest performs a single HTTP POST request.
-func (c *MCPKnowledgeClient) doRequest(ctx context.Context, reqBody interface{}, respBody interface{}) error {
-	body, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("HTTP request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	// Check for HTTP-level errors
-	if resp.StatusCode >= 500 {
-		return fmt.Errorf("server error (HTTP %d): %s", resp.StatusCode, string(respBytes))
-	}
-
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("client error (HTTP %d): %s", resp.StatusCode, string(respBytes))
-	}
-
-	if err := json.Unmarshal(respBytes, respBody); err != nil {
-		return fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	return nil
+	return c.do(ctx, mcp.Request{URL: c.endpoint, Body: reqBody, Token: c.authToken, Retry: mcp.RetryFailures}, respBody)
 }
 
 // doDeleteRequestWithRetry performs an HTTP DELETE request with retry logic.
 func (c *MCPKnowledgeClient) doDeleteRequestWithRetry(ctx context.Context, url string, respBody interface{}) error {
-	var lastErr error
-
-	for attempt := 0; attempt <= c.maxRetries; attempt++ {
-		if attempt > 0 {
-			backoff := c.calculateBackoff(attempt)
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(backoff):
-			}
-		}
-
-		err := c.doDeleteRequest(ctx, url, respBody)
-		if err == nil {
-			return nil
-		}
-
-		lastErr = err
-
-		// Don't retry on context cancellation
-		if ctx.Err() != nil {
-			return ctx.Err()
-		}
-	}
-
-	return fmt.Errorf("MCP DELETE request failed after %d attempts: %w", c.maxRetries+1, lastErr)
+	return c.do(ctx, mcp.Request{Method: http.MethodDelete, URL: url, Token: c.authToken, Retry: mcp.RetryFailures}, respBody)
 }
 
-// doDeleteRequest performs a single HTTP DELETE request.
-func (c *MCPKnowledgeClient) doDeleteRequest(ctx context.Context, url string, respBody interface{}) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
-
-	resp, err := c.httpClient.Do(req)
+// do sends a request through the shared MCP client and decodes the JSON response.
+// Transport errors and the failures selected by the Retry function of the request are
+// retried by the MCP client.
+func (c *MCPKnowledgeClient) do(ctx context.Context, req mcp.Request, respBody interface{}) error {
+	client := mcp.NewClient(mcp.Config{
+		Component:  "gitknowledgesource",
+		HTTPClient: c.httpClient,
+		Retry:      c.retryPolicy(),
+	})
+
+	resp, err := client.Do(ctx, req)
 	if err != nil {
-		return fmt.Errorf("HTTP request failed: %w", err)
+		return err
 	}
-	defer resp.Body.Close()
 
-	respBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	// Check for HTTP-level errors
-	if resp.StatusCode >= 500 {
-		return fmt.Errorf("server error (HTTP %d): %s", resp.StatusCode, string(respBytes))
-	}
-
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("client error (HTTP %d): %s", resp.StatusCode, string(respBytes))
-	}
-
-	if err := json.Unmarshal(respBytes, respBody); err != nil {
+	if err := json.Unmarshal(resp.Body, respBody); err != nil {
 		return fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
 	return nil
 }
 
+// retryPolicy returns the retry policy of this client
+func (c *MCPKnowledgeClient) retryPolicy() mcp.RetryPolicy {
+	return mcp.RetryPolicy{
+		MaxRetries:     c.maxRetries,
+		InitialBackoff: c.initialBackoff,
+		MaxBackoff:     c.maxBackoff,
+	}
+}
+
 // calculateBackoff returns the backoff duration for the given attempt number.
 // Uses exponential backoff with jitter.
 func (c *MCPKnowledgeClient) calculateBackoff(attempt int) time.Duration {
-	// Exponential backoff: initialBackoff * 2^(attempt-1)
-	backoff := c.initialBackoff
-	for i := 1; i < attempt; i++ {
-		backoff *= 2
-		if backoff > c.maxBackoff {
-			backoff = c.maxBackoff
-			break
-		}
-	}
-
-	// Add jitter: +/- 25%
-	jitter := float64(backoff) * 0.25 * (rand.Float64()*2 - 1)
-	backoff = time.Duration(float64(backoff) + jitter)
-
-	if backoff > c.maxBackoff {
-		backoff = c.maxBackoff
-	}
-
-	return backoff
+	return c.retryPolicy().Backoff(attempt)
 }
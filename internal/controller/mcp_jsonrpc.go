@@ -0,0 +1,385 @@
+// mcp_jsonrpc.go implements a minimal MCP (Model Context Protocol) client that speaks
+// JSON-RPC 2.0 over the Streamable HTTP transport. It initializes a session, calls a
+// tool with tools/call and reads the response either as a single JSON body or as a
+// Server-Sent Events stream, forwarding notifications/progress messages to a callback.
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/vfarcic/dot-ai-controller/internal/tracing"
+)
+
+const (
+	// mcpProtocolRest selects the dot-ai REST API
+	mcpProtocolRest = "rest"
+	// mcpProtocolJsonRpc selects MCP JSON-RPC over Streamable HTTP
+	mcpProtocolJsonRpc = "jsonrpc"
+
+	// mcpProtocolVersion is the MCP protocol version requested during initialization
+	mcpProtocolVersion = "2025-03-26"
+
+	// mcpSessionIdHeader carries the MCP session ID assigned by the server
+	mcpSessionIdHeader = "Mcp-Session-Id"
+	// mcpProtocolVersionHeader carries the negotiated MCP protocol version
+	mcpProtocolVersionHeader = "MCP-Protocol-Version"
+
+	// maxSseEventSize limits the size of a single Server-Sent Event
+	maxSseEventSize = 10 * 1024 * 1024
+)
+
+// McpProgress is a progress notification reported by an MCP server during a tool call
+type McpProgress struct {
+	// Progress is the current progress value
+	Progress float64 `json:"progress"`
+	// Total is the total progress value, if known
+	Total *float64 `json:"total,omitempty"`
+	// Message is an optional human-readable progress message
+	Message string `json:"message,omitempty"`
+}
+
+// Percent returns the completion percentage, or nil when the total is unknown
+func (p McpProgress) Percent() *int32 {
+	if p.Total == nil || *p.Total <= 0 {
+		return nil
+	}
+	percent := int32(p.Progress / *p.Total * 100)
+	if percent > 100 {
+		percent = 100
+	}
+	return &percent
+}
+
+// jsonRpcMessage is a JSON-RPC 2.0 request, notification or response
+type jsonRpcMessage struct {
+	JsonRpc string          `json:"jsonrpc"`
+	ID      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  interface{}     `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *jsonRpcError   `json:"error,omitempty"`
+}
+
+// jsonRpcError is a JSON-RPC 2.0 error object
+type jsonRpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Error implements the error interface
+func (e *jsonRpcError) Error() string {
+	return fmt.Sprintf("JSON-RPC error %d: %s", e.Code, e.Message)
+}
+
+// mcpHttpError is returned when the MCP server responds with an HTTP error status
+type mcpHttpError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error implements the error interface
+func (e *mcpHttpError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Body)
+}
+
+// McpToolResult is the result of an MCP tools/call request
+type McpToolResult struct {
+	// Content is the unstructured tool output
+	Content []McpToolContent `json:"content"`
+	// StructuredContent is the structured tool output, if provided
+	StructuredContent map[string]interface{} `json:"structuredContent,omitempty"`
+	// IsError is true when the tool reported an error
+	IsError bool `json:"isError,omitempty"`
+}
+
+// McpToolContent is a single content item of a tool result
+type McpToolContent struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
+// Text returns the concatenated text content of a tool result
+func (r *McpToolResult) Text() string {
+	var parts []string
+	for _, content := range r.Content {
+		if content.Type == "text" && content.Text != "" {
+			parts = append(parts, content.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// McpJsonRpcClient calls MCP tools over the Streamable HTTP transport.
+// A client holds a single session and is not safe for concurrent tool calls.
+type McpJsonRpcClient struct {
+	httpClient *http.Client
+	endpoint   string
+	authToken  string
+
+	sessionId       string
+	protocolVersion string
+	nextId          atomic.Int64
+}
+
+// NewMcpJsonRpcClient creates a new MCP JSON-RPC client for an endpoint
+func NewMcpJsonRpcClient(httpClient *http.Client, endpoint, authToken string) *McpJsonRpcClient {
+	return &McpJsonRpcClient{
+		httpClient: httpClient,
+		endpoint:   endpoint,
+		authToken:  authToken,
+	}
+}
+
+// Initialize performs the MCP initialization handshake and stores the session ID
+func (c *McpJsonRpcClient) Initialize(ctx context.Context) error {
+	params := map[string]interface{}{
+		"protocolVersion": mcpProtocolVersion,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo": map[string]interface{}{
+			"name":    "dot-ai-controller",
+			"version": "v1.0.0",
+		},
+	}
+
+	result, err := c.call(ctx, "initialize", params, nil)
+	if err != nil {
+		return fmt.Errorf("MCP initialize failed: %w", err)
+	}
+
+	var initResult struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if err := json.Unmarshal(result, &initResult); err != nil {
+		return fmt.Errorf("failed to parse MCP initialize result: %w", err)
+	}
+	c.protocolVersion = initResult.ProtocolVersion
+
+	if err := c.notify(ctx, "notifications/initialized", nil); err != nil {
+		return fmt.Errorf("MCP initialized notification failed: %w", err)
+	}
+	return nil
+}
+
+// CallTool calls an MCP tool and forwards progress notifications to onProgress (which may be nil)
+func (c *McpJsonRpcClient) CallTool(ctx context.Context, name string, arguments interface{}, onProgress func(McpProgress)) (*McpToolResult, error) {
+	params := map[string]interface{}{
+		"name":      name,
+		"arguments": arguments,
+	}
+	if onProgress != nil {
+		params["_meta"] = map[string]interface{}{
+			"progressToken": fmt.Sprintf("dot-ai-controller-%d", time.Now().UnixNano()),
+		}
+	}
+
+	result, err := c.call(ctx, "tools/call", params, onProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	var toolResult McpToolResult
+	if err := json.Unmarshal(result, &toolResult); err != nil {
+		return nil, fmt.Errorf("failed to parse MCP tool result: %w", err)
+	}
+	return &toolResult, nil
+}
+
+// Close terminates the MCP session. Servers that do not support session termination are ignored.
+func (c *McpJsonRpcClient) Close(ctx context.Context) {
+	if c.sessionId == "" {
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.endpoint, nil)
+	if err != nil {
+		return
+	}
+	c.setHeaders(req)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logf.FromContext(ctx).V(1).Info("Failed to terminate MCP session", "error", err.Error())
+		return
+	}
+	_ = resp.Body.Close()
+	c.sessionId = ""
+}
+
+// call sends a JSON-RPC request and waits for its response
+func (c *McpJsonRpcClient) call(ctx context.Context, method string, params interface{}, onProgress func(McpProgress)) (json.RawMessage, error) {
+	id := c.nextId.Add(1)
+	resp, err := c.post(ctx, jsonRpcMessage{JsonRpc: "2.0", ID: &id, Method: method, Params: params})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if sessionId := resp.Header.Get(mcpSessionIdHeader); sessionId != "" {
+		c.sessionId = sessionId
+	}
+
+	var response *jsonRpcMessage
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		response, err = readSseResponse(resp.Body, id, onProgress)
+	} else {
+		response, err = readJsonResponse(resp.Body, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != nil {
+		return nil, response.Error
+	}
+	return response.Result, nil
+}
+
+// notify sends a JSON-RPC notification, which has no response
+func (c *McpJsonRpcClient) notify(ctx context.Context, method string, params interface{}) error {
+	resp, err := c.post(ctx, jsonRpcMessage{JsonRpc: "2.0", Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.Body.Close()
+}
+
+// post sends a JSON-RPC message and returns the HTTP response for successful status codes
+func (c *McpJsonRpcClient) post(ctx context.Context, message jsonRpcMessage) (*http.Response, error) {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON-RPC message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	c.setHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return nil, &mcpHttpError{StatusCode: resp.StatusCode, Body: string(responseBody)}
+	}
+	return resp, nil
+}
+
+// setHeaders sets the common headers for MCP requests
+func (c *McpJsonRpcClient) setHeaders(req *http.Request) {
+	req.Header.Set("User-Agent", "dot-ai-controller/v1.0.0")
+	tracing.Inject(req.Context(), req.Header)
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+	if c.sessionId != "" {
+		req.Header.Set(mcpSessionIdHeader, c.sessionId)
+	}
+	if c.protocolVersion != "" {
+		req.Header.Set(mcpProtocolVersionHeader, c.protocolVersion)
+	}
+}
+
+// readJsonResponse reads a single JSON-RPC response (or a batch containing it)
+func readJsonResponse(body io.Reader, id int64) (*jsonRpcMessage, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		var batch []jsonRpcMessage
+		if err := json.Unmarshal(trimmed, &batch); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON-RPC batch response: %w", err)
+		}
+		for i := range batch {
+			if batch[i].ID != nil && *batch[i].ID == id {
+				return &batch[i], nil
+			}
+		}
+		return nil, fmt.Errorf("JSON-RPC response for request %d not found", id)
+	}
+
+	var message jsonRpcMessage
+	if err := json.Unmarshal(trimmed, &message); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON-RPC response: %w", err)
+	}
+	return &message, nil
+}
+
+// readSseResponse reads a Server-Sent Events stream until the response for the request arrives,
+// forwarding progress notifications received in the meantime
+func readSseResponse(body io.Reader, id int64, onProgress func(McpProgress)) (*jsonRpcMessage, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), maxSseEventSize)
+
+	var data strings.Builder
+	dispatch := func() *jsonRpcMessage {
+		if data.Len() == 0 {
+			return nil
+		}
+		payload := data.String()
+		data.Reset()
+
+		var message jsonRpcMessage
+		if err := json.Unmarshal([]byte(payload), &message); err != nil {
+			// Ignore events that are not JSON-RPC messages
+			return nil
+		}
+		if message.Method == "notifications/progress" {
+			if onProgress != nil {
+				var progress McpProgress
+				if raw, err := json.Marshal(message.Params); err == nil && json.Unmarshal(raw, &progress) == nil {
+					onProgress(progress)
+				}
+			}
+			return nil
+		}
+		if message.ID != nil && *message.ID == id && message.Method == "" {
+			return &message
+		}
+		return nil
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if message := dispatch(); message != nil {
+				return message, nil
+			}
+		case strings.HasPrefix(line, ":"):
+			// Comment, used by servers as keep-alive
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteString("\n")
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read event stream: %w", err)
+	}
+
+	// The stream may end without a trailing blank line
+	if message := dispatch(); message != nil {
+		return message, nil
+	}
+	return nil, fmt.Errorf("event stream closed before JSON-RPC response for request %d", id)
+}
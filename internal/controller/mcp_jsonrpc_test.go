@@ -0,0 +1,231 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// fakeMcpServer is a minimal Streamable HTTP MCP server for tests
+type fakeMcpServer struct {
+	// streaming selects an SSE response for tools/call
+	streaming bool
+	// toolResult is the tools/call result
+	toolResult map[string]interface{}
+
+	mu            sync.Mutex
+	methods       []string
+	sessionIds    []string
+	toolArguments map[string]interface{}
+	deleted       bool
+}
+
+func (s *fakeMcpServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method == http.MethodDelete {
+		s.deleted = true
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var request struct {
+		ID     *int64                 `json:"id"`
+		Method string                 `json:"method"`
+		Params map[string]interface{} `json:"params"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&request)
+	s.methods = append(s.methods, request.Method)
+	s.sessionIds = append(s.sessionIds, r.Header.Get(mcpSessionIdHeader))
+
+	switch request.Method {
+	case "initialize":
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(mcpSessionIdHeader, "session-1")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      *request.ID,
+			"result": map[string]interface{}{
+				"protocolVersion": mcpProtocolVersion,
+				"capabilities":    map[string]interface{}{},
+				"serverInfo":      map[string]interface{}{"name": "dot-ai", "version": "1.0.0"},
+			},
+		})
+	case "notifications/initialized":
+		w.WriteHeader(http.StatusAccepted)
+	case "tools/call":
+		s.toolArguments, _ = request.Params["arguments"].(map[string]interface{})
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": *request.ID, "result": s.toolResult}
+		if !s.streaming {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(response)
+			return
+		}
+
+		meta, _ := request.Params["_meta"].(map[string]interface{})
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, ": keep-alive\n\n")
+		for i, message := range []string{"Analyzing issue", "Executing remediation"} {
+			progress, _ := json.Marshal(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"method":  "notifications/progress",
+				"params": map[string]interface{}{
+					"progressToken": meta["progressToken"],
+					"progress":      i + 1,
+					"total":         4,
+					"message":       message,
+				},
+			})
+			_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", progress)
+		}
+		result, _ := json.Marshal(response)
+		_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", result)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func newTextToolResult(text string, isError bool) map[string]interface{} {
+	return map[string]interface{}{
+		"content": []map[string]interface{}{{"type": "text", "text": text}},
+		"isError": isError,
+	}
+}
+
+func TestMcpJsonRpcClient_CallTool(t *testing.T) {
+	t.Run("streamed response with progress", func(t *testing.T) {
+		mcp := &fakeMcpServer{streaming: true, toolResult: newTextToolResult(`{"status":"success","executed":true}`, false)}
+		server := httptest.NewServer(mcp)
+		defer server.Close()
+
+		ctx := context.Background()
+		mcpClient := NewMcpJsonRpcClient(server.Client(), server.URL, "token")
+		require.NoError(t, mcpClient.Initialize(ctx))
+
+		var progress []McpProgress
+		result, err := mcpClient.CallTool(ctx, "remediate", map[string]interface{}{"issue": "pod failing"}, func(p McpProgress) {
+			progress = append(progress, p)
+		})
+		require.NoError(t, err)
+		mcpClient.Close(ctx)
+
+		assert.Equal(t, `{"status":"success","executed":true}`, result.Text())
+		require.Len(t, progress, 2)
+		assert.Equal(t, "Executing remediation", progress[1].Message)
+		require.NotNil(t, progress[1].Percent())
+		assert.Equal(t, int32(50), *progress[1].Percent())
+
+		assert.Equal(t, []string{"initialize", "notifications/initialized", "tools/call"}, mcp.methods)
+		assert.Equal(t, []string{"", "session-1", "session-1"}, mcp.sessionIds)
+		assert.Equal(t, "pod failing", mcp.toolArguments["issue"])
+		assert.True(t, mcp.deleted, "session should be terminated")
+	})
+
+	t.Run("JSON response", func(t *testing.T) {
+		mcp := &fakeMcpServer{toolResult: newTextToolResult("done", false)}
+		server := httptest.NewServer(mcp)
+		defer server.Close()
+
+		ctx := context.Background()
+		mcpClient := NewMcpJsonRpcClient(server.Client(), server.URL, "")
+		require.NoError(t, mcpClient.Initialize(ctx))
+		result, err := mcpClient.CallTool(ctx, "remediate", map[string]interface{}{}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "done", result.Text())
+	})
+}
+
+func TestRemediationPolicyReconciler_SendMcpJsonRpcRequest(t *testing.T) {
+	request := &dotaiv1alpha1.McpRequest{Issue: "pod failing", Mode: "automatic"}
+
+	t.Run("JSON text result is parsed into result data", func(t *testing.T) {
+		server := httptest.NewServer(&fakeMcpServer{toolResult: newTextToolResult(`{"message":"Pod restarted","executed":true}`, false)})
+		defer server.Close()
+
+		r := &RemediationPolicyReconciler{HttpClient: server.Client()}
+		response, err := r.sendMcpJsonRpcRequest(context.Background(), request, &mcpConnection{Endpoint: server.URL}, "remediate", nil)
+		require.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "Pod restarted", response.GetResultMessage())
+		assert.True(t, r.getMcpExecutedStatus(response))
+	})
+
+	t.Run("tool error is a failed response", func(t *testing.T) {
+		server := httptest.NewServer(&fakeMcpServer{toolResult: newTextToolResult("cluster unreachable", true)})
+		defer server.Close()
+
+		r := &RemediationPolicyReconciler{HttpClient: server.Client()}
+		response, err := r.sendMcpJsonRpcRequest(context.Background(), request, &mcpConnection{Endpoint: server.URL}, "remediate", nil)
+		require.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "cluster unreachable", response.GetErrorMessage())
+	})
+
+	t.Run("HTTP error is a failed response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("invalid token"))
+		}))
+		defer server.Close()
+
+		r := &RemediationPolicyReconciler{HttpClient: server.Client()}
+		response, err := r.sendMcpJsonRpcRequest(context.Background(), request, &mcpConnection{Endpoint: server.URL}, "remediate", nil)
+		require.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "401", response.Error.Code)
+		assert.Contains(t, response.GetErrorMessage(), "invalid token")
+	})
+}
+
+func TestRemediationProgressTracker(t *testing.T) {
+	scheme := newNotificationChannelTestScheme()
+	policy := &dotaiv1alpha1.RemediationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "progress-policy", Namespace: "default"},
+		Spec:       dotaiv1alpha1.RemediationPolicySpec{McpProtocol: mcpProtocolJsonRpc},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(policy).
+		WithStatusSubresource(policy).
+		Build()
+
+	r := &RemediationPolicyReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+	ctx := context.Background()
+	tracker := r.newRemediationProgressTracker(ctx, policy, newRoutingTestEvent("production", "BackOff"))
+
+	getActive := func() []dotaiv1alpha1.RemediationProgress {
+		updated := &dotaiv1alpha1.RemediationPolicy{}
+		require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), updated))
+		return updated.Status.ActiveRemediations
+	}
+
+	tracker.Start()
+	require.Len(t, getActive(), 1)
+
+	total := 4.0
+	tracker.Update(McpProgress{Progress: 3, Total: &total, Message: "Executing remediation"})
+	tracker.Update(McpProgress{Progress: 4, Total: &total, Message: "Verifying"})
+
+	active := getActive()
+	require.Len(t, active, 1)
+	require.NotNil(t, active[0].Percent)
+	assert.Equal(t, int32(75), *active[0].Percent, "second update within the interval should be throttled")
+	assert.Equal(t, "Executing remediation", active[0].Message)
+
+	tracker.Finish()
+	assert.Empty(t, getActive())
+}
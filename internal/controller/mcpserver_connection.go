@@ -0,0 +1,166 @@
+// mcpserver_connection.go resolves the connection settings of MCPServer resources.
+// Resources that reference an MCPServer by name resolve it on every request, so that
+// changing the URL or credentials of the server is a one-object change.
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+	"github.com/vfarcic/dot-ai-controller/internal/mcp"
+)
+
+const (
+	// ConditionTypeMCPServerReady mirrors the Ready condition of the referenced MCPServer
+	ConditionTypeMCPServerReady = "MCPServerReady"
+)
+
+// mcpConnection holds the resolved connection settings of an MCP endpoint
+type mcpConnection struct {
+	// Endpoint is the base URL of an MCPServer, or the endpoint configured inline
+	Endpoint string
+
+	// Credentials are the resolved auth and TLS credentials
+	Credentials mcp.Credentials
+
+	// Timeout overrides the request timeout when set
+	Timeout time.Duration
+
+	// Retry overrides the retry policy when set
+	Retry *mcp.RetryPolicy
+}
+
+// HTTPClient returns the HTTP client of the connection. The timeout of the connection
+// takes precedence over the timeout of base; a nil base uses the shared MCP pool.
+func (c *mcpConnection) HTTPClient(base *http.Client) *http.Client {
+	if c.Timeout > 0 {
+		base = mcp.NewHTTPClientWithTimeout(c.Timeout)
+	}
+	return c.Credentials.HTTPClient(base)
+}
+
+// URL returns the endpoint followed by path
+func (c *mcpConnection) URL(path string) string {
+	return strings.TrimSuffix(c.Endpoint, "/") + path
+}
+
+// knowledgeClientConfig returns the configuration of a knowledge client for an endpoint of the connection
+func (c *mcpConnection) knowledgeClientConfig(endpoint string) MCPKnowledgeClientConfig {
+	cfg := MCPKnowledgeClientConfig{
+		Endpoint:   endpoint,
+		AuthToken:  c.Credentials.Token,
+		HTTPClient: c.HTTPClient(nil),
+	}
+	if c.Retry != nil {
+		cfg.MaxRetries = &c.Retry.MaxRetries
+		cfg.InitialBackoff = c.Retry.InitialBackoff
+		cfg.MaxBackoff = c.Retry.MaxBackoff
+	}
+	return cfg
+}
+
+// resolveMCPServerConnection resolves the connection settings of the MCPServer with the given name
+func resolveMCPServerConnection(ctx context.Context, c client.Client, name string) (*mcpConnection, error) {
+	server := &dotaiv1alpha1.MCPServer{}
+	if err := c.Get(ctx, client.ObjectKey{Name: name}, server); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("MCPServer '%s' not found", name)
+		}
+		return nil, fmt.Errorf("failed to fetch MCPServer '%s': %w", name, err)
+	}
+	return newMCPServerConnection(ctx, c, server)
+}
+
+// newMCPServerConnection resolves the credentials, TLS, proxy, timeout and retry settings of an MCPServer
+func newMCPServerConnection(ctx context.Context, c client.Client, server *dotaiv1alpha1.MCPServer) (*mcpConnection, error) {
+	spec := server.Spec
+	needsNamespace := spec.Auth.GetMode() != dotaiv1alpha1.McpAuthModeBearer || spec.AuthSecretRef.Name != "" ||
+		(spec.TLS != nil && (spec.TLS.CASecretRef != nil || spec.TLS.CAConfigMapRef != nil))
+	if needsNamespace && spec.CredentialsNamespace == "" {
+		return nil, fmt.Errorf("MCPServer '%s' requires credentialsNamespace to resolve its Secrets", server.Name)
+	}
+
+	settings, err := mcp.ResolveTLSSettings(ctx, c, spec.CredentialsNamespace, spec.TLS, spec.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS settings of MCPServer '%s': %w", server.Name, err)
+	}
+	credentials, err := mcp.ResolveEndpointCredentials(ctx, c, spec.CredentialsNamespace, spec.AuthSecretRef, spec.Auth, settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MCP credentials of MCPServer '%s': %w", server.Name, err)
+	}
+
+	connection := &mcpConnection{
+		Endpoint:    spec.URL,
+		Credentials: credentials,
+		Timeout:     time.Duration(spec.TimeoutSeconds) * time.Second,
+	}
+	if spec.Retry != nil {
+		retry := retryPolicyFromConfig(spec.Retry)
+		connection.Retry = &retry
+	}
+	return connection, nil
+}
+
+// retryPolicyFromConfig converts a RetryConfig to a retry policy. MaxAttempts includes the
+// initial attempt; unset fields fall back to the process-wide MCP defaults.
+func retryPolicyFromConfig(retry *dotaiv1alpha1.RetryConfig) mcp.RetryPolicy {
+	policy := mcp.DefaultRetryPolicy()
+	if retry.MaxAttempts > 0 {
+		policy.MaxRetries = retry.MaxAttempts - 1
+	}
+	if retry.BackoffSeconds > 0 {
+		policy.InitialBackoff = time.Duration(retry.BackoffSeconds) * time.Second
+	}
+	if retry.MaxBackoffSeconds > 0 {
+		policy.MaxBackoff = time.Duration(retry.MaxBackoffSeconds) * time.Second
+	}
+	return policy
+}
+
+// setMCPServerReadyCondition mirrors the Ready condition of the referenced MCPServer in conditions.
+// The condition is removed when no MCPServer is referenced. It returns whether conditions changed.
+func setMCPServerReadyCondition(ctx context.Context, c client.Reader, conditions *[]metav1.Condition, serverRef string, generation int64) bool {
+	if serverRef == "" {
+		return meta.RemoveStatusCondition(conditions, ConditionTypeMCPServerReady)
+	}
+
+	condition := metav1.Condition{
+		Type:               ConditionTypeMCPServerReady,
+		Status:             metav1.ConditionUnknown,
+		ObservedGeneration: generation,
+		Reason:             "Probing",
+		Message:            fmt.Sprintf("MCPServer '%s' has not been probed yet", serverRef),
+	}
+
+	server := &dotaiv1alpha1.MCPServer{}
+	if err := c.Get(ctx, client.ObjectKey{Name: serverRef}, server); err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "MCPServerNotFound"
+		condition.Message = fmt.Sprintf("MCPServer '%s' not found", serverRef)
+		if !apierrors.IsNotFound(err) {
+			condition.Reason = "MCPServerError"
+			condition.Message = fmt.Sprintf("failed to fetch MCPServer '%s': %v", serverRef, err)
+		}
+	} else if ready := meta.FindStatusCondition(server.Status.Conditions, ConditionTypeReady); ready != nil {
+		condition.Status = ready.Status
+		condition.Reason = ready.Reason
+		condition.Message = fmt.Sprintf("MCPServer '%s': %s", serverRef, ready.Message)
+	}
+
+	existing := meta.FindStatusCondition(*conditions, ConditionTypeMCPServerReady)
+	if existing != nil && existing.Status == condition.Status && existing.Reason == condition.Reason &&
+		existing.Message == condition.Message && existing.ObservedGeneration == condition.ObservedGeneration {
+		return false
+	}
+	meta.SetStatusCondition(conditions, condition)
+	return true
+}
@@ -0,0 +1,257 @@
+// mcpserver_controller.go contains the MCPServer controller.
+// MCPServers are cluster-scoped MCP connection settings referenced by name from
+// other resources. The controller validates the settings, periodically probes the
+// server's health/version endpoint, and reports reachability, latency and version.
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+	"github.com/vfarcic/dot-ai-controller/internal/mcp"
+	"github.com/vfarcic/dot-ai-controller/internal/tracing"
+)
+
+// MCPServerReconciler reconciles a MCPServer object
+type MCPServerReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=dot-ai.devopstoolkit.live,resources=mcpservers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=dot-ai.devopstoolkit.live,resources=mcpservers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts/token,verbs=create
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile validates an MCPServer and periodically probes its health/version endpoint
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *MCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	ctx, span := tracing.StartReconcile(ctx, "MCPServer", req.NamespacedName)
+	defer func() { tracing.End(span, err) }()
+
+	logger := logf.FromContext(ctx).WithValues("mcpserver", req.Name)
+
+	var server dotaiv1alpha1.MCPServer
+	if err := r.Get(ctx, req.NamespacedName, &server); err != nil {
+		// Not found - likely deleted, nothing to do
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// A rotated Secret triggers an immediate probe
+	refs := credentialsNamespaceSecretReferences(&server)
+	credentialsVersion := secretReferencesVersion(ctx, r.Client, server.Spec.CredentialsNamespace, refs)
+	credentialsChanged := server.Status.CredentialsVersion != credentialsVersion
+
+	interval := time.Duration(server.GetHealthCheckIntervalSeconds()) * time.Second
+	lastProbe := server.Status.LastProbeTime
+	specChanged := server.Status.ObservedGeneration != server.Generation
+	if lastProbe != nil && !specChanged && !credentialsChanged && time.Since(lastProbe.Time) < interval {
+		return ctrl.Result{RequeueAfter: interval - time.Since(lastProbe.Time)}, nil
+	}
+
+	logger.Info("Probing MCPServer", "url", server.Spec.URL)
+
+	now := metav1.NewTime(time.Now())
+	server.Status.LastProbeTime = &now
+	server.Status.CredentialsVersion = credentialsVersion
+	setCredentialsResolvedCondition(ctx, r.Client, &server.Status.Conditions, server.Spec.CredentialsNamespace, refs, server.Generation)
+
+	connection, err := newMCPServerConnection(ctx, r.Client, &server)
+	if err != nil {
+		logger.Error(err, "failed to resolve MCPServer connection settings")
+		r.Recorder.Eventf(&server, corev1.EventTypeWarning, "CredentialsError",
+			"Failed to resolve connection settings: %v", err)
+		server.Status.Reachable = false
+		server.Status.LastError = err.Error()
+		r.setReadyCondition(&server, false, "CredentialsError", err.Error())
+		// Requeue to pick up Secret creation
+		return ctrl.Result{RequeueAfter: time.Minute}, r.updateServerStatus(ctx, &server)
+	}
+
+	result := probeMCPServer(ctx, connection, server.GetHealthCheckPath())
+	server.Status.Reachable = result.Reachable
+	if result.Err != nil {
+		logger.Info("⚠️ MCPServer probe failed", "reason", result.Reason, "error", result.Err.Error())
+		r.Recorder.Eventf(&server, corev1.EventTypeWarning, result.Reason, "Probe failed: %v", result.Err)
+		server.Status.LastError = result.Err.Error()
+		r.setReadyCondition(&server, false, result.Reason, result.Err.Error())
+	} else {
+		logger.V(1).Info("MCPServer probe passed", "latency", result.Latency, "version", result.Version)
+		server.Status.LatencyMilliseconds = result.Latency.Milliseconds()
+		server.Status.ServerVersion = result.Version
+		server.Status.LastError = ""
+		message := "MCP server is reachable"
+		if result.Version != "" {
+			message = fmt.Sprintf("MCP server %s is reachable", result.Version)
+		}
+		r.setReadyCondition(&server, true, "Reachable", message)
+	}
+
+	if err := r.updateServerStatus(ctx, &server); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// mcpServerProbeResult is the outcome of a probe of an MCP server
+type mcpServerProbeResult struct {
+	Reachable bool
+	Latency   time.Duration
+	Version   string
+	Reason    string
+	Err       error
+}
+
+// probeMCPServer requests the health/version path of an MCP server once, without retries.
+// HTTP error statuses mean the server is reachable but not ready, e.g. because of invalid credentials.
+func probeMCPServer(ctx context.Context, connection *mcpConnection, path string) mcpServerProbeResult {
+	mcpClient := mcp.NewClient(mcp.Config{
+		Component:  "mcpserver",
+		HTTPClient: connection.HTTPClient(nil),
+	})
+	resp, err := mcpClient.Do(ctx, mcp.Request{
+		Method: http.MethodGet,
+		URL:    connection.URL(path),
+		Token:  connection.Credentials.Token,
+	})
+
+	var statusErr *mcp.StatusError
+	switch {
+	case errors.As(err, &statusErr):
+		reason := "ProbeFailed"
+		if statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusForbidden {
+			reason = "Unauthorized"
+		}
+		return mcpServerProbeResult{Reachable: true, Reason: reason, Err: err}
+	case err != nil:
+		return mcpServerProbeResult{Reason: "Unreachable", Err: err}
+	}
+
+	return mcpServerProbeResult{
+		Reachable: true,
+		Latency:   resp.Duration,
+		Version:   parseMCPServerVersion(resp.Body),
+	}
+}
+
+// parseMCPServerVersion reads the server version from an OpenAPI document (info.version)
+// or a version response (version or data.version). It returns "" when none is found.
+func parseMCPServerVersion(body []byte) string {
+	var document struct {
+		Version string `json:"version"`
+		Info    *struct {
+			Version string `json:"version"`
+		} `json:"info"`
+		Data *struct {
+			Version string `json:"version"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return ""
+	}
+	switch {
+	case document.Info != nil && document.Info.Version != "":
+		return document.Info.Version
+	case document.Version != "":
+		return document.Version
+	case document.Data != nil:
+		return document.Data.Version
+	}
+	return ""
+}
+
+// setReadyCondition sets the Ready condition on an MCPServer
+func (r *MCPServerReconciler) setReadyCondition(server *dotaiv1alpha1.MCPServer, ready bool, reason, message string) {
+	status := metav1.ConditionFalse
+	if ready {
+		status = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&server.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             status,
+		ObservedGeneration: server.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// updateServerStatus persists the status computed during reconciliation
+func (r *MCPServerReconciler) updateServerStatus(ctx context.Context, server *dotaiv1alpha1.MCPServer) error {
+	fresh := &dotaiv1alpha1.MCPServer{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(server), fresh); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	fresh.Status = server.Status
+	fresh.Status.ObservedGeneration = server.Generation
+
+	if err := r.Status().Update(ctx, fresh); err != nil {
+		return fmt.Errorf("failed to update MCPServer status: %w", err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MCPServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&dotaiv1alpha1.MCPServer{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.mapSecretToServers),
+			builder.WithPredicates(secretDataChangedPredicate()),
+		).
+		Named("mcpserver").
+		Complete(r)
+}
+
+// credentialsNamespaceSecretReferences returns the Secrets of an MCPServer, or none without a
+// credentials namespace; the Ready condition already reports that the namespace is missing
+func credentialsNamespaceSecretReferences(server *dotaiv1alpha1.MCPServer) []secretKeyReference {
+	if server.Spec.CredentialsNamespace == "" {
+		return nil
+	}
+	return mcpServerSecretReferences(server)
+}
+
+// mapSecretToServers enqueues the MCPServers that reference a Secret in their credentials
+// namespace, so that they validate it again and probe the server when it is rotated
+func (r *MCPServerReconciler) mapSecretToServers(ctx context.Context, obj client.Object) []reconcile.Request {
+	var servers dotaiv1alpha1.MCPServerList
+	if err := r.List(ctx, &servers); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to list MCPServers for Secret", "secret", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, server := range servers.Items {
+		if server.Spec.CredentialsNamespace == obj.GetNamespace() &&
+			referencesSecret(mcpServerSecretReferences(&server), obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&server)})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+func newTestMCPServer(url string) *dotaiv1alpha1.MCPServer {
+	return &dotaiv1alpha1.MCPServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "dot-ai", Generation: 1},
+		Spec: dotaiv1alpha1.MCPServerSpec{
+			URL:                  url,
+			CredentialsNamespace: "dot-ai",
+			AuthSecretRef:        dotaiv1alpha1.SecretReference{Name: "mcp-auth", Key: "token"},
+		},
+	}
+}
+
+func newMCPAuthSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "mcp-auth", Namespace: "dot-ai"},
+		Data:       map[string][]byte{"token": []byte("secret")},
+	}
+}
+
+func TestParseMCPServerVersion(t *testing.T) {
+	assert.Equal(t, "0.150.0", parseMCPServerVersion([]byte(`{"openapi":"3.0.0","info":{"title":"dot-ai","version":"0.150.0"}}`)))
+	assert.Equal(t, "1.2.3", parseMCPServerVersion([]byte(`{"version":"1.2.3"}`)))
+	assert.Equal(t, "2.0.0", parseMCPServerVersion([]byte(`{"success":true,"data":{"version":"2.0.0"}}`)))
+	assert.Empty(t, parseMCPServerVersion([]byte(`OK`)))
+}
+
+func TestMCPServerReconciler_Reconcile(t *testing.T) {
+	t.Run("reachable server", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "/api/v1/openapi", r.URL.Path)
+			assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"info": map[string]string{"version": "0.150.0"}})
+		}))
+		defer server.Close()
+
+		scheme := newNotificationChannelTestScheme()
+		mcpServer := newTestMCPServer(server.URL)
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithRuntimeObjects(mcpServer, newMCPAuthSecret()).
+			WithStatusSubresource(mcpServer).
+			Build()
+
+		r := &MCPServerReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "dot-ai"}})
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, result.RequeueAfter, "should requeue for the next probe")
+
+		updated := &dotaiv1alpha1.MCPServer{}
+		require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(mcpServer), updated))
+		assert.True(t, updated.Status.Reachable)
+		assert.Equal(t, "0.150.0", updated.Status.ServerVersion)
+		assert.NotNil(t, updated.Status.LastProbeTime)
+		assert.Empty(t, updated.Status.LastError)
+		assert.Equal(t, int64(1), updated.Status.ObservedGeneration)
+		assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeReady))
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		scheme := newNotificationChannelTestScheme()
+		mcpServer := newTestMCPServer(server.URL)
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithRuntimeObjects(mcpServer, newMCPAuthSecret()).
+			WithStatusSubresource(mcpServer).
+			Build()
+
+		r := &MCPServerReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "dot-ai"}})
+		require.NoError(t, err)
+
+		updated := &dotaiv1alpha1.MCPServer{}
+		require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(mcpServer), updated))
+		assert.True(t, updated.Status.Reachable, "an HTTP error status means the server is reachable")
+		ready := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeReady)
+		require.NotNil(t, ready)
+		assert.Equal(t, metav1.ConditionFalse, ready.Status)
+		assert.Equal(t, "Unauthorized", ready.Reason)
+	})
+
+	t.Run("missing credentials namespace", func(t *testing.T) {
+		scheme := newNotificationChannelTestScheme()
+		mcpServer := newTestMCPServer("http://dot-ai-mcp.dot-ai.svc:3456")
+		mcpServer.Spec.CredentialsNamespace = ""
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithRuntimeObjects(mcpServer).
+			WithStatusSubresource(mcpServer).
+			Build()
+
+		r := &MCPServerReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "dot-ai"}})
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, result.RequeueAfter)
+
+		updated := &dotaiv1alpha1.MCPServer{}
+		require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(mcpServer), updated))
+		assert.False(t, updated.Status.Reachable)
+		assert.Contains(t, updated.Status.LastError, "requires credentialsNamespace")
+		ready := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeReady)
+		require.NotNil(t, ready)
+		assert.Equal(t, "CredentialsError", ready.Reason)
+	})
+}
+
+func TestMCPResourceSyncClient_ServerRef(t *testing.T) {
+	var requestPath, authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestPath = r.URL.Path
+		authorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"success":true,"data":{"upserted":1,"deleted":0}}`))
+	}))
+	defer server.Close()
+
+	scheme := newNotificationChannelTestScheme()
+	mcpServer := newTestMCPServer("http://unused.example.com")
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(mcpServer, newMCPAuthSecret()).
+		Build()
+	ctx := context.Background()
+
+	syncClient := NewMCPResourceSyncClient(MCPResourceSyncClientConfig{
+		K8sClient: fakeClient,
+		ServerRef: "dot-ai",
+	})
+
+	// Rotating the endpoint of the MCPServer applies to the next request without a new client
+	mcpServer.Spec.URL = server.URL
+	require.NoError(t, fakeClient.Update(ctx, mcpServer))
+
+	resp, err := syncClient.SyncResources(ctx, []*ResourceData{{Name: "app", Kind: "Deployment", APIVersion: "apps/v1"}}, nil)
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Equal(t, ResourceSyncPath, requestPath)
+	assert.Equal(t, "Bearer secret", authorization)
+}
+
+func TestSetMCPServerReadyCondition(t *testing.T) {
+	scheme := newNotificationChannelTestScheme()
+	mcpServer := newTestMCPServer("http://dot-ai-mcp.dot-ai.svc:3456")
+	meta.SetStatusCondition(&mcpServer.Status.Conditions, metav1.Condition{
+		Type: ConditionTypeReady, Status: metav1.ConditionFalse, Reason: "Unreachable", Message: "connection refused",
+	})
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(mcpServer).Build()
+	ctx := context.Background()
+
+	var conditions []metav1.Condition
+	assert.True(t, setMCPServerReadyCondition(ctx, fakeClient, &conditions, "dot-ai", 1))
+	condition := meta.FindStatusCondition(conditions, ConditionTypeMCPServerReady)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "Unreachable", condition.Reason)
+	assert.False(t, setMCPServerReadyCondition(ctx, fakeClient, &conditions, "dot-ai", 1), "unchanged readiness needs no update")
+
+	assert.True(t, setMCPServerReadyCondition(ctx, fakeClient, &conditions, "missing", 1))
+	assert.Equal(t, "MCPServerNotFound", meta.FindStatusCondition(conditions, ConditionTypeMCPServerReady).Reason)
+
+	assert.True(t, setMCPServerReadyCondition(ctx, fakeClient, &conditions, "", 1))
+	assert.Nil(t, meta.FindStatusCondition(conditions, ConditionTypeMCPServerReady), "condition is removed without a reference")
+}
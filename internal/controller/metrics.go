@@ -0,0 +1,337 @@
+// metrics.go registers the Prometheus collectors of the controllers with the
+// controller-runtime metrics registry, so they are served on the manager's
+// metrics endpoint next to the default controller-runtime metrics.
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// Remediation outcomes reported in the remediations metric
+const (
+	remediationOutcomeSuccess = "success"
+	remediationOutcomeFailure = "failure"
+	remediationOutcomeError   = "error"
+)
+
+// solutionStates are the states reported by the Solution state gauge
+var solutionStates = []string{"deployed", "degraded", "pending"}
+
+var (
+	// remediationEventsMatched counts events that matched a RemediationPolicy
+	remediationEventsMatched = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dot_ai_remediation_events_matched_total",
+			Help: "Total number of Kubernetes events that matched a RemediationPolicy",
+		},
+		[]string{"namespace", "policy"},
+	)
+
+	// remediationEventsBlocked counts matched events that were not remediated
+	remediationEventsBlocked = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dot_ai_remediation_events_blocked_total",
+			Help: "Total number of matched events skipped by rate limiting (\"rate_limit\") or object cooldown (\"cooldown\")",
+		},
+		[]string{"namespace", "policy", "reason"},
+	)
+
+	// remediationsTotal counts completed remediations
+	remediationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dot_ai_remediations_total",
+			Help: "Total number of remediations by mode and outcome (success, failure reported by MCP, or error reaching MCP)",
+		},
+		[]string{"namespace", "policy", "mode", "outcome"},
+	)
+
+	// remediationDuration observes the latency of MCP remediation calls
+	remediationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "dot_ai_remediation_mcp_duration_seconds",
+			Help:    "Latency of MCP remediation calls by mode and outcome",
+			Buckets: []float64{1, 5, 10, 30, 60, 120, 300, 600, 900, 1200},
+		},
+		[]string{"namespace", "policy", "mode", "outcome"},
+	)
+
+	// notificationDeliveries counts notification delivery results
+	notificationDeliveries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dot_ai_notification_deliveries_total",
+			Help: "Total number of RemediationPolicy notification deliveries by target and result",
+		},
+		[]string{"namespace", "policy", "channel", "result"},
+	)
+
+	// resourceSyncFlushSize observes the number of changes sent per flush
+	resourceSyncFlushSize = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "dot_ai_resourcesync_flush_size",
+			Help:    "Number of resource changes sent to MCP per debounce flush",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 8),
+		},
+		[]string{"config"},
+	)
+
+	// resourceSyncFlushDuration observes the latency of debounce flushes
+	resourceSyncFlushDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "dot_ai_resourcesync_flush_duration_seconds",
+			Help:    "Latency of debounce flushes to MCP by result",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		},
+		[]string{"config", "result"},
+	)
+
+	// capabilityScanDiffSize observes the number of capabilities to scan and delete
+	capabilityScanDiffSize = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "dot_ai_capabilityscan_diff_size",
+			Help:    "Number of capabilities to scan or delete per reconciliation diff and debounce flush",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 7),
+		},
+		[]string{"config", "operation"},
+	)
+
+	// knowledgeDocumentsIngested counts documents ingested into the knowledge base
+	knowledgeDocumentsIngested = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dot_ai_knowledge_documents_ingested_total",
+			Help: "Total number of documents ingested by GitKnowledgeSources",
+		},
+		[]string{"namespace", "name"},
+	)
+
+	// knowledgeDocumentErrors counts documents that failed to be ingested by otherwise completed syncs
+	knowledgeDocumentErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dot_ai_knowledge_document_errors_total",
+			Help: "Total number of documents GitKnowledgeSources failed to ingest",
+		},
+		[]string{"namespace", "name"},
+	)
+
+	// knowledgeSyncErrors counts syncs that failed before their documents were ingested
+	knowledgeSyncErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dot_ai_knowledge_sync_errors_total",
+			Help: "Total number of failed GitKnowledgeSource syncs",
+		},
+		[]string{"namespace", "name"},
+	)
+
+	// knowledgeSyncDuration observes the duration of GitKnowledgeSource syncs
+	knowledgeSyncDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "dot_ai_knowledge_sync_duration_seconds",
+			Help:    "Duration of GitKnowledgeSource syncs by result (success, partial or failed)",
+			Buckets: []float64{1, 5, 10, 30, 60, 120, 300, 600, 1800},
+		},
+		[]string{"namespace", "name", "result"},
+	)
+
+	// solutionState reports the state of each Solution (1 for the current state, 0 otherwise)
+	solutionState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "dot_ai_solution_state",
+			Help: "State of each Solution; 1 for the current state, 0 for the others",
+		},
+		[]string{"namespace", "name", "state"},
+	)
+
+	// resourceSyncBuffers exports the statistics of the active resource sync watchers
+	resourceSyncBuffers = newResourceSyncCollector()
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		remediationEventsMatched,
+		remediationEventsBlocked,
+		remediationsTotal,
+		remediationDuration,
+		notificationDeliveries,
+		resourceSyncFlushSize,
+		resourceSyncFlushDuration,
+		capabilityScanDiffSize,
+		knowledgeDocumentsIngested,
+		knowledgeDocumentErrors,
+		knowledgeSyncErrors,
+		knowledgeSyncDuration,
+		solutionState,
+		resourceSyncBuffers,
+	)
+}
+
+// deliveryOutcomeResults are the result labels of delivery outcomes
+var deliveryOutcomeResults = map[deliveryOutcome]string{
+	deliveryDelivered:   "delivered",
+	deliveryFailed:      "failed",
+	deliveryQueued:      "queued",
+	deliveryRedelivered: "redelivered",
+	deliveryDropped:     "dropped",
+	deliveryRequeued:    "requeued",
+}
+
+// recordRemediation records the outcome and MCP latency of a remediation
+func recordRemediation(policy *dotaiv1alpha1.RemediationPolicy, mode string, mcpResponse *McpResponse, err error, duration time.Duration) {
+	outcome := remediationOutcomeError
+	if err == nil {
+		outcome = remediationOutcomeFailure
+		if mcpResponse.Success {
+			outcome = remediationOutcomeSuccess
+		}
+	}
+	remediationsTotal.WithLabelValues(policy.Namespace, policy.Name, mode, outcome).Inc()
+	remediationDuration.WithLabelValues(policy.Namespace, policy.Name, mode, outcome).Observe(duration.Seconds())
+}
+
+// recordCapabilityScanDiff observes the number of capabilities to scan and delete
+func recordCapabilityScanDiff(config string, scans, deletes int) {
+	capabilityScanDiffSize.WithLabelValues(config, "scan").Observe(float64(scans))
+	capabilityScanDiffSize.WithLabelValues(config, "delete").Observe(float64(deletes))
+}
+
+// forgetRemediationPolicyMetrics removes the series of a deleted RemediationPolicy
+func forgetRemediationPolicyMetrics(namespace, name string) {
+	labels := prometheus.Labels{"namespace": namespace, "policy": name}
+	remediationEventsMatched.DeletePartialMatch(labels)
+	remediationEventsBlocked.DeletePartialMatch(labels)
+	remediationsTotal.DeletePartialMatch(labels)
+	remediationDuration.DeletePartialMatch(labels)
+	notificationDeliveries.DeletePartialMatch(labels)
+}
+
+// forgetResourceSyncMetrics removes the flush series of a deleted ResourceSyncConfig
+func forgetResourceSyncMetrics(config string) {
+	labels := prometheus.Labels{"config": config}
+	resourceSyncFlushSize.DeletePartialMatch(labels)
+	resourceSyncFlushDuration.DeletePartialMatch(labels)
+}
+
+// forgetCapabilityScanMetrics removes the diff series of a deleted CapabilityScanConfig
+func forgetCapabilityScanMetrics(config string) {
+	capabilityScanDiffSize.DeletePartialMatch(prometheus.Labels{"config": config})
+}
+
+// forgetKnowledgeSourceMetrics removes the series of a deleted GitKnowledgeSource
+func forgetKnowledgeSourceMetrics(namespace, name string) {
+	labels := prometheus.Labels{"namespace": namespace, "name": name}
+	knowledgeDocumentsIngested.DeletePartialMatch(labels)
+	knowledgeDocumentErrors.DeletePartialMatch(labels)
+	knowledgeSyncErrors.DeletePartialMatch(labels)
+	knowledgeSyncDuration.DeletePartialMatch(labels)
+}
+
+// recordSolutionState sets the state gauge of a Solution
+func recordSolutionState(solution *dotaiv1alpha1.Solution) {
+	for _, state := range solutionStates {
+		value := 0.0
+		if solution.Status.State == state {
+			value = 1
+		}
+		solutionState.WithLabelValues(solution.Namespace, solution.Name, state).Set(value)
+	}
+}
+
+// forgetSolutionState removes the state gauge of a deleted Solution
+func forgetSolutionState(namespace, name string) {
+	solutionState.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "name": name})
+}
+
+// resourceSyncCollector exports the queue depth and counters of active resource sync
+// watchers. The debounce buffers keep their own statistics, which are read on scrape.
+type resourceSyncCollector struct {
+	mu     sync.RWMutex
+	states map[string]*activeConfigState
+
+	queueDepth     *prometheus.Desc
+	pendingChanges *prometheus.Desc
+	dropped        *prometheus.Desc
+	synced         *prometheus.Desc
+	flushes        *prometheus.Desc
+	cachedObjects  *prometheus.Desc
+	sinkPending    *prometheus.Desc
+	sinkDelivered  *prometheus.Desc
+}
+
+// newResourceSyncCollector creates a collector without watchers
+func newResourceSyncCollector() *resourceSyncCollector {
+	labels := []string{"config"}
+	return &resourceSyncCollector{
+		states: make(map[string]*activeConfigState),
+		queueDepth: prometheus.NewDesc("dot_ai_resourcesync_queue_depth",
+			"Number of resource changes waiting in the informer change queue", labels, nil),
+		pendingChanges: prometheus.NewDesc("dot_ai_resourcesync_pending_changes",
+			"Number of deduplicated resource changes waiting in the debounce buffer", labels, nil),
+		dropped: prometheus.NewDesc("dot_ai_resourcesync_changes_dropped_total",
+			"Total number of resource changes dropped because the change queue was full or the change was invalid", labels, nil),
+		synced: prometheus.NewDesc("dot_ai_resourcesync_resources_synced_total",
+			"Total number of resources upserted or deleted in MCP", append(labels, "action"), nil),
+		flushes: prometheus.NewDesc("dot_ai_resourcesync_flushes_total",
+			"Total number of debounce flushes sent to MCP", labels, nil),
+		cachedObjects: prometheus.NewDesc("dot_ai_resourcesync_cached_objects",
+			"Number of objects in the informer cache of a watched resource type, by cache mode (metadata or full)",
+			append(labels, "resource", "cache"), nil),
+		sinkPending: prometheus.NewDesc("dot_ai_resourcesync_sink_pending_changes",
+			"Number of failed resource changes waiting for the next flush of a sink", append(labels, "sink"), nil),
+		sinkDelivered: prometheus.NewDesc("dot_ai_resourcesync_sink_delivered_total",
+			"Total number of resource changes delivered by a sink", append(labels, "sink"), nil),
+	}
+}
+
+// track adds the watcher of a ResourceSyncConfig to the collector
+func (c *resourceSyncCollector) track(key string, state *activeConfigState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.states[key] = state
+}
+
+// untrack removes the watcher of a ResourceSyncConfig from the collector
+func (c *resourceSyncCollector) untrack(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.states, key)
+}
+
+// Describe implements prometheus.Collector
+func (c *resourceSyncCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.queueDepth
+	ch <- c.pendingChanges
+	ch <- c.dropped
+	ch <- c.synced
+	ch <- c.flushes
+	ch <- c.cachedObjects
+	ch <- c.sinkPending
+	ch <- c.sinkDelivered
+}
+
+// Collect implements prometheus.Collector
+func (c *resourceSyncCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for key, state := range c.states {
+		bufferMetrics := state.debounceBuffer.GetMetrics()
+		ch <- prometheus.MustNewConstMetric(c.queueDepth, prometheus.GaugeValue, float64(len(state.changeQueue)), key)
+		ch <- prometheus.MustNewConstMetric(c.pendingChanges, prometheus.GaugeValue, float64(bufferMetrics.PendingChanges), key)
+		ch <- prometheus.MustNewConstMetric(c.dropped, prometheus.CounterValue,
+			float64(state.droppedChanges.Load()+bufferMetrics.TotalDropped), key)
+		ch <- prometheus.MustNewConstMetric(c.synced, prometheus.CounterValue, float64(bufferMetrics.TotalUpserts), key, "upsert")
+		ch <- prometheus.MustNewConstMetric(c.synced, prometheus.CounterValue, float64(bufferMetrics.TotalDeletes), key, "delete")
+		ch <- prometheus.MustNewConstMetric(c.flushes, prometheus.CounterValue, float64(bufferMetrics.TotalFlushes), key)
+		for _, size := range state.cacheSizes() {
+			ch <- prometheus.MustNewConstMetric(c.cachedObjects, prometheus.GaugeValue, float64(size.objects), key, size.resource, size.mode)
+		}
+		for _, sink := range state.debounceBuffer.SinkStatuses() {
+			ch <- prometheus.MustNewConstMetric(c.sinkPending, prometheus.GaugeValue, float64(sink.PendingChanges), key, sink.Name)
+			ch <- prometheus.MustNewConstMetric(c.sinkDelivered, prometheus.CounterValue, float64(sink.TotalDelivered), key, sink.Name)
+		}
+	}
+}
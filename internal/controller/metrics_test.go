@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+func TestRecordRemediation(t *testing.T) {
+	policy := &dotaiv1alpha1.RemediationPolicy{ObjectMeta: metav1.ObjectMeta{Name: "metrics-policy", Namespace: "default"}}
+
+	recordRemediation(policy, "automatic", &McpResponse{Success: true}, nil, 2*time.Second)
+	recordRemediation(policy, "automatic", newMcpErrorResponse("TOOL_ERROR", "failed"), nil, time.Second)
+	recordRemediation(policy, "manual", nil, errors.New("connection refused"), time.Second)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(remediationsTotal.WithLabelValues("default", "metrics-policy", "automatic", remediationOutcomeSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(remediationsTotal.WithLabelValues("default", "metrics-policy", "automatic", remediationOutcomeFailure)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(remediationsTotal.WithLabelValues("default", "metrics-policy", "manual", remediationOutcomeError)))
+}
+
+func TestForgetRemediationPolicyMetrics(t *testing.T) {
+	policy := &dotaiv1alpha1.RemediationPolicy{ObjectMeta: metav1.ObjectMeta{Name: "forgotten-policy", Namespace: "default"}}
+	remediations := testutil.CollectAndCount(remediationsTotal)
+	deliveries := testutil.CollectAndCount(notificationDeliveries)
+	recordRemediation(policy, "automatic", &McpResponse{Success: true}, nil, time.Second)
+	notificationDeliveries.WithLabelValues("default", "forgotten-policy", "slack", "delivered").Inc()
+	require.Equal(t, remediations+1, testutil.CollectAndCount(remediationsTotal))
+
+	forgetRemediationPolicyMetrics("default", "forgotten-policy")
+
+	assert.Equal(t, remediations, testutil.CollectAndCount(remediationsTotal), "deleted policies are not reported")
+	assert.Equal(t, deliveries, testutil.CollectAndCount(notificationDeliveries), "deleted policies are not reported")
+}
+
+func TestForgetKnowledgeSourceMetrics(t *testing.T) {
+	ingested := testutil.CollectAndCount(knowledgeDocumentsIngested)
+	documentErrors := testutil.CollectAndCount(knowledgeDocumentErrors)
+	syncErrors := testutil.CollectAndCount(knowledgeSyncErrors)
+	knowledgeDocumentsIngested.WithLabelValues("default", "forgotten-source").Add(3)
+	knowledgeDocumentErrors.WithLabelValues("default", "forgotten-source").Inc()
+	knowledgeSyncErrors.WithLabelValues("default", "forgotten-source").Inc()
+
+	forgetKnowledgeSourceMetrics("default", "forgotten-source")
+
+	assert.Equal(t, ingested, testutil.CollectAndCount(knowledgeDocumentsIngested))
+	assert.Equal(t, documentErrors, testutil.CollectAndCount(knowledgeDocumentErrors))
+	assert.Equal(t, syncErrors, testutil.CollectAndCount(knowledgeSyncErrors))
+}
+
+func TestForgetCapabilityScanMetrics(t *testing.T) {
+	before := testutil.CollectAndCount(capabilityScanDiffSize)
+	recordCapabilityScanDiff("default/forgotten-scan", 2, 1)
+	require.Equal(t, before+2, testutil.CollectAndCount(capabilityScanDiffSize))
+
+	forgetCapabilityScanMetrics("default/forgotten-scan")
+	assert.Equal(t, before, testutil.CollectAndCount(capabilityScanDiffSize))
+}
+
+func TestRecordSolutionState(t *testing.T) {
+	solution := &dotaiv1alpha1.Solution{ObjectMeta: metav1.ObjectMeta{Name: "metrics-solution", Namespace: "default"}}
+	solution.Status.State = "degraded"
+	recordSolutionState(solution)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(solutionState.WithLabelValues("default", "metrics-solution", "degraded")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(solutionState.WithLabelValues("default", "metrics-solution", "deployed")))
+
+	forgetSolutionState("default", "metrics-solution")
+	assert.Zero(t, testutil.CollectAndCount(solutionState), "deleted Solutions are not reported")
+}
+
+func TestResourceSyncCollector(t *testing.T) {
+	changeQueue := make(chan *ResourceChange, 1)
+	buffer := NewDebounceBuffer(DebounceBufferConfig{ConfigName: "default/sync", ChangeQueue: changeQueue})
+	state := &activeConfigState{changeQueue: changeQueue, debounceBuffer: buffer}
+
+	collector := newResourceSyncCollector()
+	collector.track("default/sync", state)
+
+	// One change is buffered, one waits in the queue and one is dropped because the queue is full
+	buffer.record(&ResourceChange{ID: "pod-a", Action: ActionUpsert})
+	require.True(t, trySendChange(state, &ResourceChange{ID: "pod-b", Action: ActionUpsert}))
+	assert.False(t, trySendChange(state, &ResourceChange{ID: "pod-c", Action: ActionUpsert}))
+
+	expected := `
+# HELP dot_ai_resourcesync_changes_dropped_total Total number of resource changes dropped because the change queue was full or the change was invalid
+# TYPE dot_ai_resourcesync_changes_dropped_total counter
+dot_ai_resourcesync_changes_dropped_total{config="default/sync"} 1
+# HELP dot_ai_resourcesync_pending_changes Number of deduplicated resource changes waiting in the debounce buffer
+# TYPE dot_ai_resourcesync_pending_changes gauge
+dot_ai_resourcesync_pending_changes{config="default/sync"} 1
+# HELP dot_ai_resourcesync_queue_depth Number of resource changes waiting in the informer change queue
+# TYPE dot_ai_resourcesync_queue_depth gauge
+dot_ai_resourcesync_queue_depth{config="default/sync"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"dot_ai_resourcesync_changes_dropped_total",
+		"dot_ai_resourcesync_pending_changes",
+		"dot_ai_resourcesync_queue_depth",
+	))
+
+	collector.untrack("default/sync")
+	assert.Zero(t, testutil.CollectAndCount(collector), "stopped watchers are not reported")
+}
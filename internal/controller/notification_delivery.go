@@ -0,0 +1,224 @@
+// notification_delivery.go implements reliable webhook delivery for Slack and Google Chat
+// notifications. Transient failures (network errors, 429 and 5xx responses) are retried
+// with exponential backoff, honoring the Retry-After header when the service sends one.
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+	"github.com/vfarcic/dot-ai-controller/internal/mcp"
+	"github.com/vfarcic/dot-ai-controller/internal/tracing"
+)
+
+// WebhookRetryConfig configures retries for webhook deliveries
+type WebhookRetryConfig struct {
+	// MaxAttempts is the maximum number of attempts (including the initial attempt)
+	MaxAttempts int
+	// BaseBackoff is the initial backoff, doubled after each attempt
+	BaseBackoff time.Duration
+	// MaxBackoff caps the backoff and any Retry-After value sent by the service
+	MaxBackoff time.Duration
+}
+
+// defaultWebhookRetryConfig is used for all notification webhook deliveries
+var defaultWebhookRetryConfig = WebhookRetryConfig{
+	MaxAttempts: 3,
+	BaseBackoff: 1 * time.Second,
+	MaxBackoff:  30 * time.Second,
+}
+
+// WebhookError is returned when a webhook responds with a non-success status code
+type WebhookError struct {
+	// Service is the notification service name (e.g. Slack, Google Chat)
+	Service string
+	// StatusCode is the HTTP status code returned by the webhook
+	StatusCode int
+	// Body is the response body returned by the webhook
+	Body string
+	// RetryAfter is the delay requested by the service, if any
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *WebhookError) Error() string {
+	return fmt.Sprintf("%s webhook returned status %d: %s", e.Service, e.StatusCode, e.Body)
+}
+
+// Retryable returns true for rate limiting and server errors
+func (e *WebhookError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// webhookSendError wraps transport errors, which are always retryable
+type webhookSendError struct {
+	err error
+}
+
+// Error implements the error interface
+func (e *webhookSendError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *webhookSendError) Unwrap() error {
+	return e.err
+}
+
+// isRetryableDeliveryError returns true if a failed delivery may succeed when retried later
+func isRetryableDeliveryError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var webhookErr *WebhookError
+	if errors.As(err, &webhookErr) {
+		return webhookErr.Retryable()
+	}
+	var sendErr *webhookSendError
+	return errors.As(err, &sendErr)
+}
+
+// parseRetryAfter parses a Retry-After header value (delay in seconds or HTTP date)
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := date.Sub(now); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
+
+// webhookHTTPClient returns the HTTP client for a webhook with custom TLS or proxy settings,
+// resolving CA bundle references in namespace. Without them it returns base.
+func webhookHTTPClient(ctx context.Context, c client.Reader, namespace string, tlsConfig *dotaiv1alpha1.TLSConfig, proxyURL string, base *http.Client) (*http.Client, error) {
+	if tlsConfig == nil && proxyURL == "" {
+		return base, nil
+	}
+	settings, err := mcp.ResolveTLSSettings(ctx, c, namespace, tlsConfig, proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := mcp.Credentials{}.WithTLS(settings)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.HTTPClient(base), nil
+}
+
+// postWebhook posts a JSON payload to a webhook URL, retrying transient failures
+func postWebhook(ctx context.Context, httpClient *http.Client, webhookUrl, service string, payload []byte) error {
+	ctx, span := tracing.Start(ctx, "notification.send", attribute.String("notification.service", service))
+	err := postWebhookWithRetry(ctx, httpClient, webhookUrl, service, payload, defaultWebhookRetryConfig)
+	tracing.End(span, err)
+	return err
+}
+
+// postWebhookWithRetry posts a JSON payload to a webhook URL using the given retry configuration
+func postWebhookWithRetry(ctx context.Context, httpClient *http.Client, webhookUrl, service string, payload []byte, cfg WebhookRetryConfig) error {
+	return postWebhookWithHeaders(ctx, httpClient, webhookUrl, service, payload, nil, cfg)
+}
+
+// postWebhookWithHeaders posts a payload with additional headers, such as authorization or
+// a content type other than JSON, using the given retry configuration
+func postWebhookWithHeaders(ctx context.Context, httpClient *http.Client, webhookUrl, service string, payload []byte, header http.Header, cfg WebhookRetryConfig) error {
+	logger := logf.FromContext(ctx)
+
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		lastErr = postWebhookOnce(ctx, httpClient, webhookUrl, service, payload, header)
+		if lastErr == nil || !isRetryableDeliveryError(lastErr) || attempt == maxAttempts {
+			break
+		}
+
+		// Exponential backoff, overridden by Retry-After when the service requests a delay
+		delay := time.Duration(float64(cfg.BaseBackoff) * math.Pow(2, float64(attempt-1)))
+		var webhookErr *WebhookError
+		if errors.As(lastErr, &webhookErr) && webhookErr.RetryAfter > 0 {
+			delay = webhookErr.RetryAfter
+		}
+		if delay > cfg.MaxBackoff {
+			delay = cfg.MaxBackoff
+		}
+
+		logger.Info("⏳ Webhook delivery failed, retrying",
+			"service", service,
+			"attempt", attempt,
+			"maxAttempts", maxAttempts,
+			"delay", delay,
+			"error", lastErr.Error())
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s webhook delivery cancelled: %w", service, ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+
+	if lastErr != nil {
+		return lastErr
+	}
+
+	logger.V(1).Info("Webhook sent successfully",
+		"service", service,
+		"payloadSize", len(payload))
+	return nil
+}
+
+// postWebhookOnce performs a single webhook delivery attempt
+func postWebhookOnce(ctx context.Context, httpClient *http.Client, webhookUrl, service string, payload []byte, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", webhookUrl, bytes.NewBuffer(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create %s webhook request: %w", service, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	response, err := httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("failed to send %s webhook: %w", service, err)
+		}
+		return &webhookSendError{err: fmt.Errorf("failed to send %s webhook: %w", service, err)}
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 4096))
+		return &WebhookError{
+			Service:    service,
+			StatusCode: response.StatusCode,
+			Body:       string(body),
+			RetryAfter: parseRetryAfter(response.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	return nil
+}
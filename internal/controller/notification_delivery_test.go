@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastWebhookRetryConfig keeps retry tests fast
+var fastWebhookRetryConfig = WebhookRetryConfig{
+	MaxAttempts: 3,
+	BaseBackoff: time.Millisecond,
+	MaxBackoff:  5 * time.Millisecond,
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter("30", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("-5", now))
+	assert.Equal(t, 90*time.Second, parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+}
+
+func TestIsRetryableDeliveryError(t *testing.T) {
+	assert.False(t, isRetryableDeliveryError(nil))
+	assert.True(t, isRetryableDeliveryError(&WebhookError{Service: "Slack", StatusCode: http.StatusTooManyRequests}))
+	assert.True(t, isRetryableDeliveryError(&WebhookError{Service: "Slack", StatusCode: http.StatusBadGateway}))
+	assert.False(t, isRetryableDeliveryError(&WebhookError{Service: "Slack", StatusCode: http.StatusNotFound}))
+	assert.True(t, isRetryableDeliveryError(&webhookSendError{err: errors.New("connection refused")}))
+	assert.False(t, isRetryableDeliveryError(errors.New("Slack webhook Secret 'x' not found")))
+}
+
+func TestPostWebhookWithRetry(t *testing.T) {
+	t.Run("retries rate limiting and honors Retry-After", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		cfg := WebhookRetryConfig{MaxAttempts: 3, BaseBackoff: time.Millisecond, MaxBackoff: 2 * time.Second}
+		start := time.Now()
+		err := postWebhookWithRetry(context.Background(), server.Client(), server.URL, "Slack", []byte(`{}`), cfg)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), requests.Load())
+		assert.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond, "should wait for Retry-After")
+	})
+
+	t.Run("gives up after max attempts on server errors", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("unavailable"))
+		}))
+		defer server.Close()
+
+		err := postWebhookWithRetry(context.Background(), server.Client(), server.URL, "Google Chat", []byte(`{}`), fastWebhookRetryConfig)
+		require.Error(t, err)
+		assert.Equal(t, "Google Chat webhook returned status 503: unavailable", err.Error())
+		assert.Equal(t, int32(3), requests.Load())
+		assert.True(t, isRetryableDeliveryError(err))
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		err := postWebhookWithRetry(context.Background(), server.Client(), server.URL, "Slack", []byte(`{}`), fastWebhookRetryConfig)
+		require.Error(t, err)
+		assert.Equal(t, int32(1), requests.Load())
+		assert.False(t, isRetryableDeliveryError(err))
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := postWebhookWithRetry(ctx, server.Client(), server.URL, "Slack", []byte(`{}`),
+			WebhookRetryConfig{MaxAttempts: 3, BaseBackoff: time.Hour, MaxBackoff: time.Hour})
+		require.Error(t, err)
+		assert.False(t, isRetryableDeliveryError(err))
+	})
+}
@@ -0,0 +1,352 @@
+// notificationchannel_controller.go contains the NotificationChannel controller.
+// NotificationChannels are reusable notification destinations referenced by name
+// from policies. The controller validates channel configuration, periodically
+// verifies the webhook with a dry send, and exposes delivery statistics in status.
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+	"github.com/vfarcic/dot-ai-controller/internal/tracing"
+)
+
+const (
+	// ConditionTypeHealthy indicates whether the last health check or delivery succeeded
+	ConditionTypeHealthy = "Healthy"
+)
+
+// NotificationChannelReconciler reconciles a NotificationChannel object
+type NotificationChannelReconciler struct {
+	client.Client
+	Scheme     *runtime.Scheme
+	Recorder   record.EventRecorder
+	HttpClient *http.Client
+}
+
+// +kubebuilder:rbac:groups=dot-ai.devopstoolkit.live,resources=notificationchannels,verbs=get;list;watch
+// +kubebuilder:rbac:groups=dot-ai.devopstoolkit.live,resources=notificationchannels/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile validates a NotificationChannel and performs periodic health checks
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
+func (r *NotificationChannelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	ctx, span := tracing.StartReconcile(ctx, "NotificationChannel", req.NamespacedName)
+	defer func() { tracing.End(span, err) }()
+
+	logger := logf.FromContext(ctx).WithValues("notificationchannel", req.NamespacedName)
+
+	var channel dotaiv1alpha1.NotificationChannel
+	if err := r.Get(ctx, req.NamespacedName, &channel); err != nil {
+		// Not found - likely deleted, nothing to do
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	logger.Info("Reconciling NotificationChannel", "type", channel.Spec.Type)
+
+	// Validate configuration
+	if err := validateNotificationChannel(&channel); err != nil {
+		logger.Error(err, "invalid NotificationChannel configuration")
+		r.Recorder.Eventf(&channel, corev1.EventTypeWarning, "InvalidConfiguration",
+			"Invalid notification channel configuration: %v", err)
+		r.setReadyCondition(&channel, false, "InvalidConfiguration", err.Error())
+		return ctrl.Result{}, r.updateChannelStatus(ctx, &channel)
+	}
+
+	// Validate the referenced Secrets; a rotated Secret triggers an immediate health check
+	refs := notificationChannelSecretReferences(&channel)
+	setCredentialsResolvedCondition(ctx, r.Client, &channel.Status.Conditions, channel.Namespace, refs, channel.Generation)
+	credentialsVersion := secretReferencesVersion(ctx, r.Client, channel.Namespace, refs)
+	credentialsChanged := channel.Status.CredentialsVersion != credentialsVersion
+	channel.Status.CredentialsVersion = credentialsVersion
+
+	// Resolve webhook URL from Secret
+	webhookUrl, err := resolveNotificationChannelWebhook(ctx, r.Client, &channel)
+	if err != nil {
+		logger.Error(err, "failed to resolve webhook URL")
+		r.setReadyCondition(&channel, false, "SecretError", err.Error())
+		channel.Status.Healthy = false
+		channel.Status.LastError = err.Error()
+		// Requeue to pick up Secret creation
+		return ctrl.Result{RequeueAfter: time.Minute}, r.updateChannelStatus(ctx, &channel)
+	}
+
+	// Resolve TLS and proxy settings of the webhook
+	httpClient, err := webhookHTTPClient(ctx, r.Client, channel.Namespace, channel.Spec.TLS, channel.Spec.ProxyURL, r.HttpClient)
+	if err != nil {
+		logger.Error(err, "failed to resolve webhook TLS settings")
+		r.setReadyCondition(&channel, false, "TLSError", err.Error())
+		channel.Status.Healthy = false
+		channel.Status.LastError = err.Error()
+		// Requeue to pick up CA bundle creation
+		return ctrl.Result{RequeueAfter: time.Minute}, r.updateChannelStatus(ctx, &channel)
+	}
+	r.setReadyCondition(&channel, true, "Configured", "Notification channel is configured")
+
+	interval := time.Duration(channel.GetHealthCheckIntervalMinutes()) * time.Minute
+	if !channel.IsHealthCheckEnabled() {
+		return ctrl.Result{}, r.updateChannelStatus(ctx, &channel)
+	}
+
+	// Run health check when due, or when the spec or the referenced Secrets changed
+	lastCheck := channel.Status.LastHealthCheckTime
+	specChanged := channel.Status.ObservedGeneration != channel.Generation
+	if lastCheck == nil || specChanged || credentialsChanged || time.Since(lastCheck.Time) >= interval {
+		now := metav1.NewTime(time.Now())
+		channel.Status.LastHealthCheckTime = &now
+
+		if err := dryRunWebhook(ctx, httpClient, webhookUrl); err != nil {
+			logger.Info("⚠️ Notification channel health check failed", "error", err.Error())
+			r.Recorder.Eventf(&channel, corev1.EventTypeWarning, "HealthCheckFailed",
+				"Health check failed: %v", err)
+			channel.Status.Healthy = false
+			channel.Status.LastError = err.Error()
+			setChannelHealthyCondition(&channel, false, "HealthCheckFailed", err.Error())
+		} else {
+			logger.V(1).Info("Notification channel health check passed")
+			channel.Status.Healthy = true
+			channel.Status.LastError = ""
+			setChannelHealthyCondition(&channel, true, "HealthCheckPassed", "Webhook accepted dry send")
+		}
+	}
+
+	if err := r.updateChannelStatus(ctx, &channel); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Requeue for next health check
+	nextCheck := interval - time.Since(channel.Status.LastHealthCheckTime.Time)
+	if nextCheck < time.Minute {
+		nextCheck = time.Minute
+	}
+	return ctrl.Result{RequeueAfter: nextCheck}, nil
+}
+
+// validateNotificationChannel validates NotificationChannel settings
+func validateNotificationChannel(channel *dotaiv1alpha1.NotificationChannel) error {
+	switch channel.Spec.Type {
+	case dotaiv1alpha1.NotificationChannelTypeSlack, dotaiv1alpha1.NotificationChannelTypeGoogleChat:
+	default:
+		return fmt.Errorf("unsupported notification channel type '%s'", channel.Spec.Type)
+	}
+
+	if channel.Spec.WebhookUrlSecretRef.Name == "" {
+		return fmt.Errorf("webhookUrlSecretRef.name cannot be empty")
+	}
+	if channel.Spec.WebhookUrlSecretRef.Key == "" {
+		return fmt.Errorf("webhookUrlSecretRef.key cannot be empty")
+	}
+	if channel.Spec.Channel != "" && channel.Spec.Type != dotaiv1alpha1.NotificationChannelTypeSlack {
+		return fmt.Errorf("channel is only supported for slack notification channels")
+	}
+	return nil
+}
+
+// resolveNotificationChannelWebhook resolves the webhook URL of a NotificationChannel from its Secret
+func resolveNotificationChannelWebhook(ctx context.Context, c client.Reader, channel *dotaiv1alpha1.NotificationChannel) (string, error) {
+	return resolveWebhookSecret(ctx, c, channel.Namespace, channel.Spec.WebhookUrlSecretRef)
+}
+
+// resolveWebhookSecret resolves a webhook URL from a Secret in the given namespace
+func resolveWebhookSecret(ctx context.Context, c client.Reader, namespace string, ref dotaiv1alpha1.SecretReference) (string, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("webhook Secret '%s' not found in namespace '%s'", ref.Name, namespace)
+		}
+		return "", fmt.Errorf("failed to fetch webhook Secret: %w", err)
+	}
+
+	value, exists := secret.Data[ref.Key]
+	if !exists {
+		return "", fmt.Errorf("webhook Secret '%s' does not contain key '%s'", ref.Name, ref.Key)
+	}
+	if len(value) == 0 {
+		return "", fmt.Errorf("webhook Secret '%s' key '%s' is empty", ref.Name, ref.Key)
+	}
+
+	return string(value), nil
+}
+
+// dryRunWebhook verifies a chat webhook without posting a message.
+// It sends an empty JSON payload: Slack and Google Chat reject it with 400 Bad Request
+// when the webhook is valid, and with 401/403/404/410 when the webhook is invalid or revoked.
+func dryRunWebhook(ctx context.Context, httpClient *http.Client, webhookUrl string) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", webhookUrl, bytes.NewBufferString("{}"))
+	if err != nil {
+		return fmt.Errorf("failed to create health check request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	response, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach webhook: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 300 || response.StatusCode == http.StatusBadRequest {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+	return fmt.Errorf("webhook returned status %d: %s", response.StatusCode, string(body))
+}
+
+// setReadyCondition sets the Ready condition on a NotificationChannel
+func (r *NotificationChannelReconciler) setReadyCondition(channel *dotaiv1alpha1.NotificationChannel, ready bool, reason, message string) {
+	status := metav1.ConditionFalse
+	if ready {
+		status = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&channel.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             status,
+		ObservedGeneration: channel.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// setChannelHealthyCondition sets the Healthy condition on a NotificationChannel
+func setChannelHealthyCondition(channel *dotaiv1alpha1.NotificationChannel, healthy bool, reason, message string) {
+	status := metav1.ConditionFalse
+	if healthy {
+		status = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&channel.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeHealthy,
+		Status:             status,
+		ObservedGeneration: channel.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// updateChannelStatus persists the status computed during reconciliation
+// Delivery counters are taken from the fresh copy so concurrent deliveries are not lost
+func (r *NotificationChannelReconciler) updateChannelStatus(ctx context.Context, channel *dotaiv1alpha1.NotificationChannel) error {
+	fresh := &dotaiv1alpha1.NotificationChannel{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(channel), fresh); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	fresh.Status.Healthy = channel.Status.Healthy
+	fresh.Status.LastHealthCheckTime = channel.Status.LastHealthCheckTime
+	fresh.Status.LastError = channel.Status.LastError
+	fresh.Status.Conditions = channel.Status.Conditions
+	fresh.Status.CredentialsVersion = channel.Status.CredentialsVersion
+	fresh.Status.ObservedGeneration = channel.Generation
+
+	if err := r.Status().Update(ctx, fresh); err != nil {
+		return fmt.Errorf("failed to update NotificationChannel status: %w", err)
+	}
+	return nil
+}
+
+// recordNotificationChannelDelivery records a delivery attempt in the NotificationChannel status
+// with retry logic for conflicts caused by concurrent deliveries
+func recordNotificationChannelDelivery(ctx context.Context, c client.Client, key client.ObjectKey, deliveryErr error) error {
+	maxRetries := 3
+	baseDelay := 100 * time.Millisecond
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			// Exponential backoff with jitter (±25%)
+			delay := time.Duration(float64(baseDelay) * math.Pow(2, float64(attempt-1)))
+			delay += time.Duration(float64(delay) * 0.25 * (2*rand.Float64() - 1))
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		fresh := &dotaiv1alpha1.NotificationChannel{}
+		if err := c.Get(ctx, key, fresh); err != nil {
+			return fmt.Errorf("failed to fetch NotificationChannel: %w", err)
+		}
+
+		now := metav1.NewTime(time.Now())
+		fresh.Status.LastDeliveryTime = &now
+		if deliveryErr != nil {
+			fresh.Status.FailedDeliveries++
+			fresh.Status.Healthy = false
+			fresh.Status.LastError = deliveryErr.Error()
+			setChannelHealthyCondition(fresh, false, "DeliveryFailed", deliveryErr.Error())
+		} else {
+			fresh.Status.SuccessfulDeliveries++
+			fresh.Status.Healthy = true
+			fresh.Status.LastError = ""
+			setChannelHealthyCondition(fresh, true, "DeliverySucceeded", "Last notification was delivered")
+		}
+
+		if err := c.Status().Update(ctx, fresh); err != nil {
+			if apierrors.IsConflict(err) {
+				lastErr = err
+				continue
+			}
+			return fmt.Errorf("failed to update NotificationChannel status: %w", err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("failed to record delivery after %d attempts: %w", maxRetries+1, lastErr)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NotificationChannelReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&dotaiv1alpha1.NotificationChannel{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.mapSecretToChannels),
+			builder.WithPredicates(secretDataChangedPredicate()),
+		).
+		Named("notificationchannel").
+		Complete(r)
+}
+
+// mapSecretToChannels enqueues the NotificationChannels that reference a Secret,
+// so that they validate it again and run a health check when it is rotated
+func (r *NotificationChannelReconciler) mapSecretToChannels(ctx context.Context, obj client.Object) []reconcile.Request {
+	var channels dotaiv1alpha1.NotificationChannelList
+	if err := r.List(ctx, &channels, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to list NotificationChannels for Secret", "secret", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, channel := range channels.Items {
+		if referencesSecret(notificationChannelSecretReferences(&channel), obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&channel)})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,378 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+	"github.com/vfarcic/dot-ai-controller/internal/tracing"
+)
+
+func newNotificationChannelTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = dotaiv1alpha1.AddToScheme(scheme)
+	return scheme
+}
+
+func newTestNotificationChannel(channelType string) *dotaiv1alpha1.NotificationChannel {
+	return &dotaiv1alpha1.NotificationChannel{
+		ObjectMeta: metav1.ObjectMeta{Name: "sre", Namespace: "default", Generation: 1},
+		Spec: dotaiv1alpha1.NotificationChannelSpec{
+			Type:                channelType,
+			WebhookUrlSecretRef: dotaiv1alpha1.SecretReference{Name: "webhooks", Key: "url"},
+		},
+	}
+}
+
+func newWebhookSecret(url string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhooks", Namespace: "default"},
+		Data:       map[string][]byte{"url": []byte(url)},
+	}
+}
+
+func TestValidateNotificationChannel(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(*dotaiv1alpha1.NotificationChannel)
+		expectedErr string
+	}{
+		{
+			name:   "valid slack channel",
+			modify: func(c *dotaiv1alpha1.NotificationChannel) { c.Spec.Channel = "#alerts" },
+		},
+		{
+			name:        "unsupported type",
+			modify:      func(c *dotaiv1alpha1.NotificationChannel) { c.Spec.Type = "teams" },
+			expectedErr: "unsupported notification channel type",
+		},
+		{
+			name:        "missing secret key",
+			modify:      func(c *dotaiv1alpha1.NotificationChannel) { c.Spec.WebhookUrlSecretRef.Key = "" },
+			expectedErr: "webhookUrlSecretRef.key cannot be empty",
+		},
+		{
+			name: "channel on google chat",
+			modify: func(c *dotaiv1alpha1.NotificationChannel) {
+				c.Spec.Type = dotaiv1alpha1.NotificationChannelTypeGoogleChat
+				c.Spec.Channel = "#alerts"
+			},
+			expectedErr: "channel is only supported for slack",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel := newTestNotificationChannel(dotaiv1alpha1.NotificationChannelTypeSlack)
+			tt.modify(channel)
+			err := validateNotificationChannel(channel)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestDryRunWebhook(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		expectErr  bool
+	}{
+		{name: "accepted", statusCode: http.StatusOK},
+		{name: "empty payload rejected by valid webhook", statusCode: http.StatusBadRequest},
+		{name: "revoked webhook", statusCode: http.StatusNotFound, expectErr: true},
+		{name: "forbidden webhook", statusCode: http.StatusForbidden, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+
+			err := dryRunWebhook(context.Background(), server.Client(), server.URL)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNotificationChannelReconciler_Reconcile(t *testing.T) {
+	t.Run("healthy webhook", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		scheme := newNotificationChannelTestScheme()
+		channel := newTestNotificationChannel(dotaiv1alpha1.NotificationChannelTypeSlack)
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithRuntimeObjects(channel, newWebhookSecret(server.URL)).
+			WithStatusSubresource(channel).
+			Build()
+
+		r := &NotificationChannelReconciler{
+			Client:     fakeClient,
+			Scheme:     scheme,
+			Recorder:   record.NewFakeRecorder(10),
+			HttpClient: server.Client(),
+		}
+
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "sre", Namespace: "default"}})
+		require.NoError(t, err)
+		assert.Greater(t, result.RequeueAfter.Minutes(), 1.0, "should requeue for next health check")
+
+		updated := &dotaiv1alpha1.NotificationChannel{}
+		require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(channel), updated))
+		assert.True(t, updated.Status.Healthy)
+		assert.NotNil(t, updated.Status.LastHealthCheckTime)
+		assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeReady))
+		assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeHealthy))
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		scheme := newNotificationChannelTestScheme()
+		channel := newTestNotificationChannel(dotaiv1alpha1.NotificationChannelTypeGoogleChat)
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithRuntimeObjects(channel).
+			WithStatusSubresource(channel).
+			Build()
+
+		r := &NotificationChannelReconciler{
+			Client:     fakeClient,
+			Scheme:     scheme,
+			Recorder:   record.NewFakeRecorder(10),
+			HttpClient: http.DefaultClient,
+		}
+
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "sre", Namespace: "default"}})
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, result.RequeueAfter.Minutes())
+
+		updated := &dotaiv1alpha1.NotificationChannel{}
+		require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(channel), updated))
+		assert.False(t, updated.Status.Healthy)
+		assert.Contains(t, updated.Status.LastError, "not found")
+		ready := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeReady)
+		require.NotNil(t, ready)
+		assert.Equal(t, "SecretError", ready.Reason)
+	})
+}
+
+func TestNotificationChannelReconciler_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	caBundle := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "internal-ca", Namespace: "default"},
+		Data: map[string]string{
+			"ca.crt": string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})),
+		},
+	}
+
+	t.Run("webhook verified with CA bundle", func(t *testing.T) {
+		scheme := newNotificationChannelTestScheme()
+		channel := newTestNotificationChannel(dotaiv1alpha1.NotificationChannelTypeSlack)
+		channel.Spec.TLS = &dotaiv1alpha1.TLSConfig{
+			CAConfigMapRef: &dotaiv1alpha1.ConfigMapReference{Name: "internal-ca", Key: "ca.crt"},
+		}
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithRuntimeObjects(channel, caBundle, newWebhookSecret(server.URL)).
+			WithStatusSubresource(channel).
+			Build()
+
+		// The shared client only trusts the system roots
+		r := &NotificationChannelReconciler{
+			Client:     fakeClient,
+			Scheme:     scheme,
+			Recorder:   record.NewFakeRecorder(10),
+			HttpClient: http.DefaultClient,
+		}
+
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "sre", Namespace: "default"}})
+		require.NoError(t, err)
+
+		updated := &dotaiv1alpha1.NotificationChannel{}
+		require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(channel), updated))
+		assert.True(t, updated.Status.Healthy, updated.Status.LastError)
+	})
+
+	t.Run("missing CA bundle", func(t *testing.T) {
+		scheme := newNotificationChannelTestScheme()
+		channel := newTestNotificationChannel(dotaiv1alpha1.NotificationChannelTypeSlack)
+		channel.Spec.TLS = &dotaiv1alpha1.TLSConfig{
+			CAConfigMapRef: &dotaiv1alpha1.ConfigMapReference{Name: "missing", Key: "ca.crt"},
+		}
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithRuntimeObjects(channel, newWebhookSecret(server.URL)).
+			WithStatusSubresource(channel).
+			Build()
+
+		r := &NotificationChannelReconciler{
+			Client:     fakeClient,
+			Scheme:     scheme,
+			Recorder:   record.NewFakeRecorder(10),
+			HttpClient: http.DefaultClient,
+		}
+
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "sre", Namespace: "default"}})
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, result.RequeueAfter.Minutes())
+
+		updated := &dotaiv1alpha1.NotificationChannel{}
+		require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(channel), updated))
+		assert.False(t, updated.Status.Healthy)
+		ready := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeReady)
+		require.NotNil(t, ready)
+		assert.Equal(t, "TLSError", ready.Reason)
+	})
+}
+
+func TestRecordNotificationChannelDelivery(t *testing.T) {
+	scheme := newNotificationChannelTestScheme()
+	channel := newTestNotificationChannel(dotaiv1alpha1.NotificationChannelTypeSlack)
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(channel).
+		WithStatusSubresource(channel).
+		Build()
+
+	ctx := context.Background()
+	key := client.ObjectKeyFromObject(channel)
+	require.NoError(t, recordNotificationChannelDelivery(ctx, fakeClient, key, nil))
+	require.NoError(t, recordNotificationChannelDelivery(ctx, fakeClient, key, nil))
+	require.NoError(t, recordNotificationChannelDelivery(ctx, fakeClient, key, errors.New("webhook returned status 500")))
+
+	updated := &dotaiv1alpha1.NotificationChannel{}
+	require.NoError(t, fakeClient.Get(ctx, key, updated))
+	assert.Equal(t, int64(2), updated.Status.SuccessfulDeliveries)
+	assert.Equal(t, int64(1), updated.Status.FailedDeliveries)
+	assert.False(t, updated.Status.Healthy)
+	assert.Equal(t, "webhook returned status 500", updated.Status.LastError)
+	assert.NotNil(t, updated.Status.LastDeliveryTime)
+}
+
+func TestRemediationPolicyReconciler_DeliverToNotificationChannel(t *testing.T) {
+	var mu sync.Mutex
+	var received []SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message SlackMessage
+		_ = json.NewDecoder(r.Body).Decode(&message)
+		mu.Lock()
+		received = append(received, message)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	scheme := newNotificationChannelTestScheme()
+	channel := newTestNotificationChannel(dotaiv1alpha1.NotificationChannelTypeSlack)
+	channel.Spec.Channel = "#sre"
+	policy := &dotaiv1alpha1.RemediationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "channel-policy", Namespace: "default"},
+		Spec: dotaiv1alpha1.RemediationPolicySpec{
+			Notifications: dotaiv1alpha1.NotificationConfig{
+				Channels: []dotaiv1alpha1.NotificationChannelReference{{Name: "sre", NotifyOnComplete: true}},
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(policy, channel, newWebhookSecret(server.URL)).
+		WithStatusSubresource(policy, channel).
+		Build()
+
+	r := &RemediationPolicyReconciler{
+		Client:     fakeClient,
+		Scheme:     scheme,
+		Recorder:   record.NewFakeRecorder(10),
+		HttpClient: server.Client(),
+	}
+
+	failed := createFailedMcpResponse("boom")
+	r.sendChannelNotifications(context.Background(), policy, newRoutingTestEvent("default", "BackOff"), "complete",
+		&dotaiv1alpha1.McpRequest{Issue: "pod failing", Mode: "manual"}, &failed)
+	r.sendChannelNotifications(context.Background(), policy, newRoutingTestEvent("default", "BackOff"), "start",
+		&dotaiv1alpha1.McpRequest{Issue: "pod failing", Mode: "manual"}, nil)
+
+	mu.Lock()
+	require.Len(t, received, 1, "start notifications are disabled by default")
+	assert.Equal(t, "#sre", received[0].Channel)
+	mu.Unlock()
+
+	updated := &dotaiv1alpha1.NotificationChannel{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(channel), updated))
+	assert.Equal(t, int64(1), updated.Status.SuccessfulDeliveries)
+
+	err := r.deliverToNotificationChannel(context.Background(), policy, "missing",
+		func() SlackMessage { return SlackMessage{} },
+		func() GoogleChatMessage { return GoogleChatMessage{} })
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestNotificationChannelReconciler_ReconcileSpanRecordsError(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.NewTracerProvider(tracing.DefaultConfig(), sdktrace.WithSyncer(exporter))
+	previousProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previousProvider)
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(newNotificationChannelTestScheme()).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				return errors.New("api server unavailable")
+			},
+		}).
+		Build()
+	r := &NotificationChannelReconciler{Client: fakeClient, Recorder: record.NewFakeRecorder(10)}
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "sre", Namespace: "default"}})
+	require.Error(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "NotificationChannel.Reconcile", spans[0].Name)
+	assert.Equal(t, codes.Error, spans[0].Status.Code, "failed reconciles mark their span as failed")
+	assert.Equal(t, "api server unavailable", spans[0].Status.Description)
+}
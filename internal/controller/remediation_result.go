@@ -0,0 +1,194 @@
+// remediation_result.go decodes MCP remediation results into typed RemediationResults
+// and records them in the RemediationPolicy status. Results are decoded once per MCP
+// response; notifications, routing and the policy status read the typed result.
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+const (
+	// remediationResultSchemaV1 is the result schema of the remediate tool: the root cause is
+	// nested under analysis, and the actions and risk level under remediation
+	remediationResultSchemaV1 = "v1"
+
+	// latestRemediationResultSchema decodes results without or with an unknown schema version
+	latestRemediationResultSchema = remediationResultSchemaV1
+
+	// maxRecordedRemediationActions limits the actions kept per result in the policy status
+	maxRecordedRemediationActions = 10
+)
+
+// remediationResultDecoders decode raw MCP results by schema version
+var remediationResultDecoders = map[string]func(raw map[string]interface{}) dotaiv1alpha1.RemediationResult{
+	remediationResultSchemaV1: decodeRemediationResultV1,
+}
+
+// decodeRemediationResult decodes a raw MCP result with the decoder of its schemaVersion.
+// Results without a version use the latest schema. Unknown versions are decoded with the
+// latest schema on a best-effort basis and keep the version reported by the server.
+func decodeRemediationResult(raw map[string]interface{}) dotaiv1alpha1.RemediationResult {
+	version, _ := raw["schemaVersion"].(string)
+	if version == "" {
+		version = latestRemediationResultSchema
+	}
+	decode, ok := remediationResultDecoders[version]
+	if !ok {
+		decode = remediationResultDecoders[latestRemediationResultSchema]
+	}
+
+	result := decode(raw)
+	result.SchemaVersion = version
+	return result
+}
+
+// decodeRemediationResultV1 decodes a v1 result. Fields with unexpected types are ignored.
+func decodeRemediationResultV1(raw map[string]interface{}) dotaiv1alpha1.RemediationResult {
+	result := dotaiv1alpha1.RemediationResult{
+		Message:    firstResultString(raw, "message", "summary", "output"),
+		Confidence: resultFloat(raw, "confidence"),
+	}
+	result.Executed, _ = raw["executed"].(bool)
+
+	if analysis, ok := raw["analysis"].(map[string]interface{}); ok {
+		result.RootCause, _ = analysis["rootCause"].(string)
+		result.AnalysisConfidence = resultFloat(analysis, "confidence")
+	}
+
+	if remediation, ok := raw["remediation"].(map[string]interface{}); ok {
+		result.Risk, _ = remediation["risk"].(string)
+		if actions, ok := remediation["actions"].([]interface{}); ok {
+			for _, action := range actions {
+				actionMap, ok := action.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				var typed dotaiv1alpha1.RemediationAction
+				typed.Command, _ = actionMap["command"].(string)
+				typed.Description, _ = actionMap["description"].(string)
+				typed.Risk, _ = actionMap["risk"].(string)
+				result.Actions = append(result.Actions, typed)
+			}
+		}
+	}
+
+	// Validation is only reported when the server states whether it succeeded
+	if validation, ok := raw["validation"].(map[string]interface{}); ok {
+		if success, ok := validation["success"].(bool); ok {
+			result.Validation = &dotaiv1alpha1.RemediationValidation{Success: success}
+			result.Validation.Message, _ = validation["message"].(string)
+		}
+	}
+
+	if results, ok := raw["results"].([]interface{}); ok {
+		result.ActionsTaken = len(results)
+	}
+	return result
+}
+
+// firstResultString returns the first non-empty string among the given keys
+func firstResultString(raw map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := raw[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// resultFloat returns a numeric field, or nil when it is missing or not a number
+func resultFloat(raw map[string]interface{}, key string) *float64 {
+	if value, ok := raw[key].(float64); ok {
+		return &value
+	}
+	return nil
+}
+
+// highestActionRisk returns the highest risk level among the actions of a result
+func highestActionRisk(result *dotaiv1alpha1.RemediationResult) string {
+	highest := ""
+	for _, action := range result.Actions {
+		if riskLevelOrder[action.Risk] > riskLevelOrder[highest] {
+			highest = action.Risk
+		}
+	}
+	return highest
+}
+
+// newRemediationResultRecord creates the status record of a completed remediation.
+// Long strings are truncated and actions are capped to keep the policy status small.
+func newRemediationResultRecord(event *corev1.Event, mcpResponse *McpResponse) dotaiv1alpha1.RemediationResultRecord {
+	record := dotaiv1alpha1.RemediationResultRecord{
+		Time: metav1.NewTime(time.Now()),
+		Object: fmt.Sprintf("%s/%s/%s", event.InvolvedObject.Kind,
+			event.InvolvedObject.Namespace, event.InvolvedObject.Name),
+		Reason:  event.Reason,
+		Success: mcpResponse.Success,
+	}
+	if !mcpResponse.Success {
+		record.Error = truncateStatusNotificationMessage(mcpResponse.GetErrorMessage())
+		return record
+	}
+
+	if result := mcpResponse.RemediationResult(); result != nil {
+		recorded := *result.DeepCopy()
+		recorded.Message = truncateStatusNotificationMessage(recorded.Message)
+		recorded.RootCause = truncateStatusNotificationMessage(recorded.RootCause)
+		if len(recorded.Actions) > maxRecordedRemediationActions {
+			recorded.Actions = recorded.Actions[:maxRecordedRemediationActions]
+		}
+		for i := range recorded.Actions {
+			recorded.Actions[i].Command = truncateStatusNotificationMessage(recorded.Actions[i].Command)
+			recorded.Actions[i].Description = truncateStatusNotificationMessage(recorded.Actions[i].Description)
+		}
+		if recorded.Validation != nil {
+			recorded.Validation.Message = truncateStatusNotificationMessage(recorded.Validation.Message)
+		}
+		record.Result = &recorded
+	}
+	return record
+}
+
+// prependRemediationResult adds a record to the recent results, newest first, keeping at most limit records
+func prependRemediationResult(records []dotaiv1alpha1.RemediationResultRecord, record dotaiv1alpha1.RemediationResultRecord, limit int) []dotaiv1alpha1.RemediationResultRecord {
+	records = append([]dotaiv1alpha1.RemediationResultRecord{record}, records...)
+	if len(records) > limit {
+		records = records[:limit]
+	}
+	return records
+}
+
+// recordRemediationResult records the result of a completed remediation in status.recentResults,
+// retrying on conflicts with concurrent status updates
+func (r *RemediationPolicyReconciler) recordRemediationResult(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy, event *corev1.Event, mcpResponse *McpResponse) error {
+	record := newRemediationResultRecord(event, mcpResponse)
+
+	var lastErr error
+	for attempt := 0; attempt < maxDeliveryStatusRetries; attempt++ {
+		fresh := &dotaiv1alpha1.RemediationPolicy{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(policy), fresh); err != nil {
+			return fmt.Errorf("failed to fetch fresh policy: %w", err)
+		}
+
+		fresh.Status.RecentResults = prependRemediationResult(fresh.Status.RecentResults, record, fresh.GetResultHistoryLimit())
+
+		if err := r.Status().Update(ctx, fresh); err != nil {
+			if apierrors.IsConflict(err) {
+				lastErr = err
+				continue
+			}
+			return fmt.Errorf("failed to update remediation results: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("failed to update remediation results after %d attempts: %w", maxDeliveryStatusRetries, lastErr)
+}
@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// newTestMcpResponse parses a REST MCP response body the same way sendMcpRequest does
+func newTestMcpResponse(t *testing.T, body string) *McpResponse {
+	t.Helper()
+	var response McpResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &response))
+	return &response
+}
+
+func TestDecodeRemediationResult(t *testing.T) {
+	response := newTestMcpResponse(t, `{
+		"success": true,
+		"data": {
+			"result": {
+				"summary": "Increased memory limit",
+				"confidence": 0.92,
+				"executed": true,
+				"analysis": {"rootCause": "OOMKilled", "confidence": 0.95},
+				"remediation": {
+					"risk": "medium",
+					"actions": [
+						{"command": "kubectl set resources deployment/api --limits=memory=512Mi", "description": "Raise limit", "risk": "medium"},
+						"not an action"
+					]
+				},
+				"validation": {"success": true, "message": "Pod is running"},
+				"results": [{"action": "patch"}]
+			},
+			"executionTime": 1500
+		}
+	}`)
+
+	result := response.RemediationResult()
+	require.NotNil(t, result)
+	assert.Same(t, result, response.RemediationResult(), "the result is decoded once")
+
+	assert.Equal(t, remediationResultSchemaV1, result.SchemaVersion)
+	assert.Equal(t, "Increased memory limit", result.Message)
+	assert.Equal(t, "OOMKilled", result.RootCause)
+	require.NotNil(t, result.Confidence)
+	assert.InDelta(t, 0.92, *result.Confidence, 0.001)
+	require.NotNil(t, result.AnalysisConfidence)
+	assert.InDelta(t, 0.95, *result.AnalysisConfidence, 0.001)
+	assert.Equal(t, "medium", result.Risk)
+	assert.Equal(t, []dotaiv1alpha1.RemediationAction{{
+		Command:     "kubectl set resources deployment/api --limits=memory=512Mi",
+		Description: "Raise limit",
+		Risk:        "medium",
+	}}, result.Actions)
+	assert.True(t, result.Executed)
+	assert.Equal(t, 1, result.ActionsTaken)
+	assert.Equal(t, &dotaiv1alpha1.RemediationValidation{Success: true, Message: "Pod is running"}, result.Validation)
+	assert.Equal(t, "Increased memory limit", response.GetResultMessage())
+}
+
+func TestDecodeRemediationResult_SchemaVersions(t *testing.T) {
+	t.Run("unknown version is decoded with the latest schema", func(t *testing.T) {
+		result := decodeRemediationResult(map[string]interface{}{
+			"schemaVersion": "v2",
+			"message":       "done",
+			"analysis":      map[string]interface{}{"rootCause": "CrashLoopBackOff"},
+		})
+		assert.Equal(t, "v2", result.SchemaVersion, "the reported version is kept")
+		assert.Equal(t, "done", result.Message)
+		assert.Equal(t, "CrashLoopBackOff", result.RootCause)
+	})
+
+	t.Run("unexpected types are ignored", func(t *testing.T) {
+		result := decodeRemediationResult(map[string]interface{}{
+			"confidence":  "high",
+			"executed":    "yes",
+			"analysis":    "OOMKilled",
+			"validation":  map[string]interface{}{"message": "no success flag"},
+			"remediation": map[string]interface{}{"actions": "kubectl delete pod"},
+		})
+		assert.Nil(t, result.Confidence)
+		assert.False(t, result.Executed)
+		assert.Empty(t, result.RootCause)
+		assert.Nil(t, result.Validation, "validation without a success flag is not reported")
+		assert.Empty(t, result.Actions)
+	})
+
+	t.Run("responses without result data", func(t *testing.T) {
+		assert.Nil(t, newMcpErrorResponse("TOOL_ERROR", "failed").RemediationResult())
+		assert.Nil(t, (*McpResponse)(nil).RemediationResult())
+	})
+}
+
+func TestPrependRemediationResult(t *testing.T) {
+	var records []dotaiv1alpha1.RemediationResultRecord
+	for _, reason := range []string{"first", "second", "third"} {
+		records = prependRemediationResult(records, dotaiv1alpha1.RemediationResultRecord{Reason: reason}, 2)
+	}
+
+	require.Len(t, records, 2)
+	assert.Equal(t, "third", records[0].Reason, "newest first")
+	assert.Equal(t, "second", records[1].Reason)
+}
+
+func TestRecordRemediationResult(t *testing.T) {
+	scheme := newNotificationChannelTestScheme()
+	policy := &dotaiv1alpha1.RemediationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: "default"},
+		Spec:       dotaiv1alpha1.RemediationPolicySpec{ResultHistoryLimit: 2},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(policy).
+		WithStatusSubresource(policy).
+		Build()
+	r := &RemediationPolicyReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+	ctx := context.Background()
+	event := &corev1.Event{
+		Reason: "BackOff",
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: "default",
+			Name:      "api",
+		},
+	}
+
+	actions := make([]interface{}, 15)
+	for i := range actions {
+		actions[i] = map[string]interface{}{"command": "kubectl get pods"}
+	}
+	success := newMcpResponseFromToolResult("remediate", &McpToolResult{
+		StructuredContent: map[string]interface{}{
+			"message":     strings.Repeat("x", 2*maxStatusNotificationMessageLength),
+			"remediation": map[string]interface{}{"actions": actions},
+		},
+	}, 0)
+	require.NoError(t, r.recordRemediationResult(ctx, policy, event, success))
+	require.NoError(t, r.recordRemediationResult(ctx, policy, event, newMcpErrorResponse("TOOL_ERROR", "MCP unavailable")))
+	require.NoError(t, r.recordRemediationResult(ctx, policy, event, newMcpErrorResponse("TOOL_ERROR", "MCP still unavailable")))
+
+	updated := &dotaiv1alpha1.RemediationPolicy{}
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), updated))
+	require.Len(t, updated.Status.RecentResults, 2, "history is bounded by resultHistoryLimit")
+	assert.Equal(t, "MCP still unavailable", updated.Status.RecentResults[0].Error)
+	assert.Equal(t, "Pod/default/api", updated.Status.RecentResults[0].Object)
+	assert.Equal(t, "BackOff", updated.Status.RecentResults[0].Reason)
+	assert.False(t, updated.Status.RecentResults[0].Success)
+	assert.Nil(t, updated.Status.RecentResults[0].Result)
+
+	// The successful result fell out of the history; check the bounds applied when it was recorded
+	record := newRemediationResultRecord(event, success)
+	assert.True(t, record.Success)
+	require.NotNil(t, record.Result)
+	assert.Len(t, record.Result.Actions, maxRecordedRemediationActions)
+	assert.Len(t, record.Result.Message, maxStatusNotificationMessageLength)
+	assert.Len(t, success.RemediationResult().Actions, 15, "the decoded result is not modified")
+}
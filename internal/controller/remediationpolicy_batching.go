@@ -0,0 +1,593 @@
+// remediationpolicy_batching.go implements notification batching for the
+// RemediationPolicy controller. Completion notifications are buffered per policy
+// and channel for a configurable window and sent as a single summary message.
+// The collect-and-flush approach mirrors the DebounceBuffer used by resource sync.
+package controller
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+	"github.com/vfarcic/dot-ai-controller/internal/tracing"
+)
+
+const (
+	// notificationChannelSlack identifies the Slack notification channel
+	notificationChannelSlack = "slack"
+	// notificationChannelGoogleChat identifies the Google Chat notification channel
+	notificationChannelGoogleChat = "googleChat"
+
+	// notificationOutcomeExecuted means MCP executed remediation commands
+	notificationOutcomeExecuted = "executed"
+	// notificationOutcomeManual means MCP returned recommendations requiring manual action
+	notificationOutcomeManual = "manual"
+	// notificationOutcomeFailed means the remediation failed
+	notificationOutcomeFailed = "failed"
+
+	// maxBatchSummaryItems limits the number of objects listed in a summary message
+	// (Slack recommends max 50 blocks per message)
+	maxBatchSummaryItems = 20
+	// maxBatchItemMessageLength limits the result message length per listed object
+	maxBatchItemMessageLength = 200
+)
+
+// NotificationBatchItem represents a single remediation outcome in a batch
+type NotificationBatchItem struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Reason    string
+	Outcome   string
+	Message   string
+	Link      string
+	Timestamp time.Time
+}
+
+// NotificationBatch holds buffered notifications for one policy and channel
+type NotificationBatch struct {
+	// Channel is the notification channel (slack or googleChat)
+	Channel string
+	// Policy is a snapshot of the policy taken when the latest item was added
+	Policy *dotaiv1alpha1.RemediationPolicy
+	// Items are the buffered remediation outcomes
+	Items []NotificationBatchItem
+	// FirstQueued is when the first item was added to the batch
+	FirstQueued time.Time
+	// Window is how long the batch is held before being flushed
+	Window time.Duration
+}
+
+// CountByOutcome returns the number of items with the given outcome
+func (b *NotificationBatch) CountByOutcome(outcome string) int {
+	count := 0
+	for _, item := range b.Items {
+		if item.Outcome == outcome {
+			count++
+		}
+	}
+	return count
+}
+
+// NotificationBatchSender sends a flushed batch to its notification channel
+type NotificationBatchSender func(ctx context.Context, batch *NotificationBatch) error
+
+// NotificationBatcher collects completion notifications and flushes them as summaries
+type NotificationBatcher struct {
+	// batches holds pending batches keyed by policy namespace/name/channel
+	batches map[string]*NotificationBatch
+	mu      sync.Mutex
+
+	// tickInterval is how often batches are checked for expired windows
+	tickInterval time.Duration
+
+	// sender delivers flushed batches
+	sender NotificationBatchSender
+
+	// metrics for observability
+	totalFlushes  int64
+	totalItems    int64
+	totalFailures int64
+	metricsMu     sync.RWMutex
+}
+
+// NotificationBatcherConfig holds configuration for creating a NotificationBatcher
+type NotificationBatcherConfig struct {
+	TickInterval time.Duration
+	Sender       NotificationBatchSender
+}
+
+// NewNotificationBatcher creates a new notification batcher
+func NewNotificationBatcher(cfg NotificationBatcherConfig) *NotificationBatcher {
+	if cfg.TickInterval <= 0 {
+		cfg.TickInterval = 5 * time.Second // Default 5-second check interval
+	}
+
+	return &NotificationBatcher{
+		batches:      make(map[string]*NotificationBatch),
+		tickInterval: cfg.TickInterval,
+		sender:       cfg.Sender,
+	}
+}
+
+// Add records a notification item in the batch for the given policy and channel
+func (b *NotificationBatcher) Add(policy *dotaiv1alpha1.RemediationPolicy, channel string, item NotificationBatchItem) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := fmt.Sprintf("%s/%s/%s", policy.Namespace, policy.Name, channel)
+	batch, exists := b.batches[key]
+	if !exists {
+		batch = &NotificationBatch{
+			Channel:     channel,
+			FirstQueued: time.Now(),
+		}
+		b.batches[key] = batch
+	}
+
+	// Always use the latest policy snapshot so configuration changes apply on flush
+	batch.Policy = policy.DeepCopy()
+	batch.Window = time.Duration(policy.GetBatchingWindowSeconds()) * time.Second
+	batch.Items = append(batch.Items, item)
+}
+
+// Run starts the batcher processing loop
+// It periodically flushes batches whose window has elapsed
+func (b *NotificationBatcher) Run(ctx context.Context) {
+	logger := logf.FromContext(ctx).WithName("notification-batcher")
+	logger.Info("Starting notification batcher", "tickInterval", b.tickInterval)
+
+	ticker := time.NewTicker(b.tickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Notification batcher stopping, performing final flush")
+			// Use a fresh context with timeout for final flush since the original
+			// context is cancelled. This ensures buffered summaries are not lost on shutdown.
+			flushCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			b.flush(flushCtx, true)
+			cancel()
+			return
+
+		case <-ticker.C:
+			b.flush(ctx, false)
+		}
+	}
+}
+
+// flush sends all batches whose window has elapsed (or all batches when force is true)
+func (b *NotificationBatcher) flush(ctx context.Context, force bool) {
+	logger := logf.FromContext(ctx).WithName("notification-batcher")
+
+	now := time.Now()
+	var due []*NotificationBatch
+
+	b.mu.Lock()
+	for key, batch := range b.batches {
+		if force || now.Sub(batch.FirstQueued) >= batch.Window {
+			due = append(due, batch)
+			delete(b.batches, key)
+		}
+	}
+	b.mu.Unlock()
+
+	if len(due) == 0 {
+		return
+	}
+	ctx, span := tracing.Start(ctx, "RemediationPolicy.flushNotificationBatches", attribute.Int("notification.batches", len(due)))
+	defer span.End()
+
+	for _, batch := range due {
+		if len(batch.Items) == 0 {
+			continue
+		}
+
+		logger.Info("Flushing notification batch",
+			"policy", fmt.Sprintf("%s/%s", batch.Policy.Namespace, batch.Policy.Name),
+			"channel", batch.Channel,
+			"items", len(batch.Items),
+		)
+
+		if b.sender == nil {
+			logger.V(1).Info("Notification batch sender not configured, skipping flush")
+			continue
+		}
+
+		// Notifications are best-effort: failed summaries are logged, not re-queued
+		err := b.sender(ctx, batch)
+		b.updateMetrics(len(batch.Items), err)
+		if err != nil {
+			logger.Error(err, "Failed to send notification batch",
+				"channel", batch.Channel,
+				"items", len(batch.Items),
+			)
+		}
+	}
+}
+
+// updateMetrics updates the batcher metrics after a flush
+func (b *NotificationBatcher) updateMetrics(items int, err error) {
+	b.metricsMu.Lock()
+	defer b.metricsMu.Unlock()
+
+	b.totalFlushes++
+	b.totalItems += int64(items)
+	if err != nil {
+		b.totalFailures++
+	}
+}
+
+// PendingCount returns the number of buffered notification items across all batches
+func (b *NotificationBatcher) PendingCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	count := 0
+	for _, batch := range b.batches {
+		count += len(batch.Items)
+	}
+	return count
+}
+
+// GetMetrics returns current batcher metrics
+func (b *NotificationBatcher) GetMetrics() NotificationBatcherMetrics {
+	b.metricsMu.RLock()
+	defer b.metricsMu.RUnlock()
+
+	return NotificationBatcherMetrics{
+		TotalFlushes:  b.totalFlushes,
+		TotalItems:    b.totalItems,
+		TotalFailures: b.totalFailures,
+		PendingItems:  b.PendingCount(),
+	}
+}
+
+// NotificationBatcherMetrics holds metrics about the notification batcher
+type NotificationBatcherMetrics struct {
+	TotalFlushes  int64
+	TotalItems    int64
+	TotalFailures int64
+	PendingItems  int
+}
+
+// getNotificationOutcome determines the remediation outcome from an MCP response
+func (r *RemediationPolicyReconciler) getNotificationOutcome(mcpResponse *McpResponse) string {
+	if mcpResponse == nil || !mcpResponse.Success {
+		return notificationOutcomeFailed
+	}
+	if r.getMcpExecutedStatus(mcpResponse) {
+		return notificationOutcomeExecuted
+	}
+	return notificationOutcomeManual
+}
+
+// batchNotification buffers a notification when batching is enabled.
+// It returns true if an individual notification should still be sent.
+func (r *RemediationPolicyReconciler) batchNotification(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy, event *corev1.Event, channel string, notificationType string, mcpResponse *McpResponse) bool {
+	logger := logf.FromContext(ctx)
+
+	if !policy.IsBatchingEnabled() || r.notificationBatcher == nil {
+		return true
+	}
+
+	// Start notifications would defeat the purpose of batching during event storms
+	if notificationType == "start" {
+		logger.V(1).Info("Start notification suppressed by batching", "channel", channel)
+		return false
+	}
+
+	outcome := r.getNotificationOutcome(mcpResponse)
+	message := "no result data"
+	if mcpResponse != nil {
+		if mcpResponse.Success {
+			message = mcpResponse.GetResultMessage()
+		} else {
+			message = mcpResponse.GetErrorMessage()
+		}
+	}
+
+	r.notificationBatcher.Add(policy, channel, NotificationBatchItem{
+		Kind:      event.InvolvedObject.Kind,
+		Name:      event.InvolvedObject.Name,
+		Namespace: event.InvolvedObject.Namespace,
+		Reason:    event.Reason,
+		Outcome:   outcome,
+		Message:   message,
+		Link:      buildBatchItemLink(policy, event),
+		Timestamp: time.Now(),
+	})
+
+	logger.V(1).Info("Notification added to batch",
+		"channel", channel,
+		"outcome", outcome,
+		"window", policy.GetBatchingWindowSeconds())
+
+	// Optionally still send failures individually so they are not delayed
+	return outcome == notificationOutcomeFailed && policy.Spec.Notifications.Batching.GetSendFailuresImmediately()
+}
+
+// buildBatchItemLink renders the policy link template for an event's involved object
+func buildBatchItemLink(policy *dotaiv1alpha1.RemediationPolicy, event *corev1.Event) string {
+	if policy.Spec.Notifications.Batching == nil || policy.Spec.Notifications.Batching.LinkTemplate == "" {
+		return ""
+	}
+	replacer := strings.NewReplacer(
+		"{namespace}", event.InvolvedObject.Namespace,
+		"{kind}", event.InvolvedObject.Kind,
+		"{name}", event.InvolvedObject.Name,
+		"{policy}", policy.Name,
+	)
+	return replacer.Replace(policy.Spec.Notifications.Batching.LinkTemplate)
+}
+
+// sendNotificationBatch resolves the channel webhook and sends a summary message for a batch
+func (r *RemediationPolicyReconciler) sendNotificationBatch(ctx context.Context, batch *NotificationBatch) error {
+	logger := logf.FromContext(ctx)
+	policy := batch.Policy
+
+	// Batches for NotificationChannel references are delivered through the channel
+	if channelName, ok := notificationChannelNameFromBatchKey(batch.Channel); ok {
+		return r.deliverToNotificationChannel(ctx, policy, channelName,
+			func() SlackMessage { return r.createSlackBatchMessage(batch) },
+			func() GoogleChatMessage { return r.createGoogleChatBatchMessage(batch) },
+		)
+	}
+
+	var delivery notificationDelivery
+	var serviceType string
+	switch batch.Channel {
+	case notificationChannelSlack:
+		delivery = newSlackDelivery(r.createSlackBatchMessage(batch))
+		serviceType = "Slack"
+	case notificationChannelGoogleChat:
+		delivery = newGoogleChatDelivery(r.createGoogleChatBatchMessage(batch))
+		serviceType = "Google Chat"
+	default:
+		return fmt.Errorf("unknown notification channel: %s", batch.Channel)
+	}
+
+	if err := r.deliverNotification(ctx, policy, delivery); err != nil {
+		return err
+	}
+
+	logger.Info("📦 Notification summary sent successfully",
+		"service", serviceType,
+		"policy", fmt.Sprintf("%s/%s", policy.Namespace, policy.Name),
+		"items", len(batch.Items))
+	return nil
+}
+
+// batchOutcomeEmoji returns the emoji used for an outcome in summary messages
+func batchOutcomeEmoji(outcome string) string {
+	switch outcome {
+	case notificationOutcomeExecuted:
+		return "✅"
+	case notificationOutcomeManual:
+		return "📋"
+	default:
+		return "❌"
+	}
+}
+
+// truncateBatchMessage truncates a result message for display in a summary
+func truncateBatchMessage(msg string) string {
+	if len(msg) > maxBatchItemMessageLength {
+		return msg[:maxBatchItemMessageLength-3] + "..."
+	}
+	return msg
+}
+
+// createSlackBatchMessage creates a Slack summary message for a batch using Block Kit
+func (r *RemediationPolicyReconciler) createSlackBatchMessage(batch *NotificationBatch) SlackMessage {
+	policy := batch.Policy
+	executed := batch.CountByOutcome(notificationOutcomeExecuted)
+	manual := batch.CountByOutcome(notificationOutcomeManual)
+	failed := batch.CountByOutcome(notificationOutcomeFailed)
+
+	color := "#2eb67d" // Green vertical bar (all executed)
+	if failed > 0 {
+		color = "#e01e5a" // Red vertical bar
+	} else if manual > 0 {
+		color = "#0073e6" // Blue vertical bar (manual action required)
+	}
+
+	blocks := []SlackBlock{
+		// Header
+		{
+			Type: "header",
+			Text: &SlackBlockText{
+				Type: "plain_text",
+				Text: fmt.Sprintf("📦 Remediation Summary (%d events)", len(batch.Items)),
+			},
+		},
+		// Outcome counts
+		{
+			Type: "section",
+			Fields: []SlackBlockText{
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Executed:*\n%d", executed)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Manual Action Required:*\n%d", manual)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Failed:*\n%d", failed)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Window:*\n%s", batch.Window)},
+			},
+		},
+		{
+			Type: "section",
+			Text: &SlackBlockText{
+				Type: "mrkdwn",
+				Text: "*Affected Objects:*",
+			},
+		},
+	}
+
+	// One block per object
+	for i, item := range batch.Items {
+		if i >= maxBatchSummaryItems {
+			blocks = append(blocks, SlackBlock{
+				Type: "section",
+				Text: &SlackBlockText{
+					Type: "mrkdwn",
+					Text: fmt.Sprintf("_... and %d more objects_", len(batch.Items)-maxBatchSummaryItems),
+				},
+			})
+			break
+		}
+
+		resource := fmt.Sprintf("%s/%s", item.Kind, item.Name)
+		if item.Link != "" {
+			resource = fmt.Sprintf("<%s|%s>", item.Link, resource)
+		} else {
+			resource = fmt.Sprintf("`%s`", resource)
+		}
+		blocks = append(blocks, SlackBlock{
+			Type: "section",
+			Text: &SlackBlockText{
+				Type: "mrkdwn",
+				Text: fmt.Sprintf("%s %s in `%s` (%s)\n%s",
+					batchOutcomeEmoji(item.Outcome), resource, item.Namespace, item.Reason,
+					truncateBatchMessage(item.Message)),
+			},
+		})
+	}
+
+	// Divider and footer
+	blocks = append(blocks,
+		SlackBlock{
+			Type: "divider",
+		},
+		SlackBlock{
+			Type: "context",
+			Elements: []SlackBlockElement{
+				{
+					Type: "mrkdwn",
+					Text: fmt.Sprintf("Policy: `%s` | dot-ai Kubernetes Event Controller", policy.Name),
+				},
+			},
+		},
+	)
+
+	message := SlackMessage{
+		Username:  "dot-ai-controller",
+		IconEmoji: ":robot_face:",
+		Attachments: []SlackAttachment{
+			{
+				Color:  color,
+				Blocks: blocks,
+			},
+		},
+	}
+
+	// Set channel if configured
+	if policy.Spec.Notifications.Slack.Channel != "" {
+		message.Channel = policy.Spec.Notifications.Slack.Channel
+	}
+
+	return message
+}
+
+// createGoogleChatBatchMessage creates a Google Chat summary message for a batch using Card v2 API
+func (r *RemediationPolicyReconciler) createGoogleChatBatchMessage(batch *NotificationBatch) GoogleChatMessage {
+	policy := batch.Policy
+
+	var objectWidgets []GoogleChatWidget
+	for i, item := range batch.Items {
+		if i >= maxBatchSummaryItems {
+			objectWidgets = append(objectWidgets, GoogleChatWidget{
+				TextParagraph: &GoogleChatTextParagraph{
+					Text: fmt.Sprintf("<i>... and %d more objects</i>", len(batch.Items)-maxBatchSummaryItems),
+				},
+			})
+			break
+		}
+
+		resource := html.EscapeString(fmt.Sprintf("%s/%s", item.Kind, item.Name))
+		if item.Link != "" {
+			resource = fmt.Sprintf("<a href=\"%s\">%s</a>", html.EscapeString(item.Link), resource)
+		} else {
+			resource = fmt.Sprintf("<b>%s</b>", resource)
+		}
+		objectWidgets = append(objectWidgets, GoogleChatWidget{
+			TextParagraph: &GoogleChatTextParagraph{
+				Text: fmt.Sprintf("%s %s in %s (%s)<br>%s",
+					batchOutcomeEmoji(item.Outcome), resource,
+					html.EscapeString(item.Namespace), html.EscapeString(item.Reason),
+					html.EscapeString(truncateBatchMessage(item.Message))),
+			},
+		})
+	}
+
+	sections := []GoogleChatSection{
+		{
+			Header: "Summary",
+			Widgets: []GoogleChatWidget{
+				{
+					DecoratedText: &GoogleChatDecoratedText{
+						TopLabel: "Executed",
+						Text:     fmt.Sprintf("%d", batch.CountByOutcome(notificationOutcomeExecuted)),
+						Icon:     &GoogleChatIcon{KnownIcon: "STAR"},
+					},
+				},
+				{
+					DecoratedText: &GoogleChatDecoratedText{
+						TopLabel: "Manual Action Required",
+						Text:     fmt.Sprintf("%d", batch.CountByOutcome(notificationOutcomeManual)),
+						Icon:     &GoogleChatIcon{KnownIcon: "TICKET"},
+					},
+				},
+				{
+					DecoratedText: &GoogleChatDecoratedText{
+						TopLabel: "Failed",
+						Text:     fmt.Sprintf("%d", batch.CountByOutcome(notificationOutcomeFailed)),
+						Icon:     &GoogleChatIcon{KnownIcon: "BUG_REPORT"},
+					},
+				},
+				{
+					DecoratedText: &GoogleChatDecoratedText{
+						TopLabel: "Window",
+						Text:     batch.Window.String(),
+						Icon:     &GoogleChatIcon{KnownIcon: "CLOCK"},
+					},
+				},
+			},
+		},
+		{
+			Header:  "Affected Objects",
+			Widgets: objectWidgets,
+		},
+		// Footer
+		{
+			Widgets: []GoogleChatWidget{
+				{
+					TextParagraph: &GoogleChatTextParagraph{
+						Text: "<i>dot-ai Kubernetes Event Controller</i>",
+					},
+				},
+			},
+		},
+	}
+
+	return GoogleChatMessage{
+		CardsV2: []GoogleChatCardV2{
+			{
+				CardId: "remediation-summary",
+				Card: GoogleChatCard{
+					Header: &GoogleChatCardHeader{
+						Title:     fmt.Sprintf("📦 Remediation Summary (%d events)", len(batch.Items)),
+						Subtitle:  fmt.Sprintf("Policy: %s", policy.Name),
+						ImageType: "CIRCLE",
+					},
+					Sections: sections,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,320 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+func newBatchingTestPolicy(windowSeconds int, sendFailuresImmediately bool) *dotaiv1alpha1.RemediationPolicy {
+	return &dotaiv1alpha1.RemediationPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "batch-policy",
+			Namespace: "default",
+		},
+		Spec: dotaiv1alpha1.RemediationPolicySpec{
+			McpEndpoint: "http://mcp:3456/api/v1/tools/remediate",
+			Notifications: dotaiv1alpha1.NotificationConfig{
+				Slack: dotaiv1alpha1.SlackConfig{
+					Enabled:          true,
+					WebhookUrl:       "https://hooks.slack.com/services/test",
+					Channel:          "#alerts",
+					NotifyOnStart:    true,
+					NotifyOnComplete: true,
+				},
+				Batching: &dotaiv1alpha1.NotificationBatchingConfig{
+					Enabled:                 true,
+					WindowSeconds:           windowSeconds,
+					SendFailuresImmediately: &sendFailuresImmediately,
+					LinkTemplate:            "https://console.example.com/{namespace}/{kind}/{name}",
+				},
+			},
+		},
+	}
+}
+
+func newBatchingTestEvent(name string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: name + ".event", Namespace: "apps"},
+		Type:       corev1.EventTypeWarning,
+		Reason:     "BackOff",
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Name:      name,
+			Namespace: "apps",
+		},
+	}
+}
+
+func TestNotificationBatcher_AddAndPendingCount(t *testing.T) {
+	batcher := NewNotificationBatcher(NotificationBatcherConfig{})
+	policy := newBatchingTestPolicy(60, false)
+
+	batcher.Add(policy, notificationChannelSlack, NotificationBatchItem{Name: "a", Outcome: notificationOutcomeExecuted})
+	batcher.Add(policy, notificationChannelSlack, NotificationBatchItem{Name: "b", Outcome: notificationOutcomeFailed})
+	batcher.Add(policy, notificationChannelGoogleChat, NotificationBatchItem{Name: "a", Outcome: notificationOutcomeExecuted})
+
+	assert.Equal(t, 3, batcher.PendingCount())
+	assert.Len(t, batcher.batches, 2, "batches should be kept per policy and channel")
+
+	batch := batcher.batches["default/batch-policy/slack"]
+	require.NotNil(t, batch)
+	assert.Equal(t, 60*time.Second, batch.Window)
+	assert.Equal(t, 1, batch.CountByOutcome(notificationOutcomeFailed))
+}
+
+func TestNotificationBatcher_FlushRespectsWindow(t *testing.T) {
+	var mu sync.Mutex
+	var sent []*NotificationBatch
+	batcher := NewNotificationBatcher(NotificationBatcherConfig{
+		Sender: func(ctx context.Context, batch *NotificationBatch) error {
+			mu.Lock()
+			defer mu.Unlock()
+			sent = append(sent, batch)
+			return nil
+		},
+	})
+	policy := newBatchingTestPolicy(60, false)
+
+	batcher.Add(policy, notificationChannelSlack, NotificationBatchItem{Name: "a"})
+
+	// Window has not elapsed - nothing should be sent
+	batcher.flush(context.Background(), false)
+	assert.Empty(t, sent)
+	assert.Equal(t, 1, batcher.PendingCount())
+
+	// Simulate elapsed window
+	batcher.batches["default/batch-policy/slack"].FirstQueued = time.Now().Add(-2 * time.Minute)
+	batcher.flush(context.Background(), false)
+	require.Len(t, sent, 1)
+	assert.Len(t, sent[0].Items, 1)
+	assert.Equal(t, 0, batcher.PendingCount())
+
+	metrics := batcher.GetMetrics()
+	assert.Equal(t, int64(1), metrics.TotalFlushes)
+	assert.Equal(t, int64(1), metrics.TotalItems)
+	assert.Equal(t, int64(0), metrics.TotalFailures)
+}
+
+func TestNotificationBatcher_SendErrorIsCounted(t *testing.T) {
+	batcher := NewNotificationBatcher(NotificationBatcherConfig{
+		Sender: func(ctx context.Context, batch *NotificationBatch) error {
+			return fmt.Errorf("webhook unavailable")
+		},
+	})
+	batcher.Add(newBatchingTestPolicy(60, false), notificationChannelSlack, NotificationBatchItem{Name: "a"})
+
+	batcher.flush(context.Background(), true)
+
+	metrics := batcher.GetMetrics()
+	assert.Equal(t, int64(1), metrics.TotalFailures)
+	assert.Equal(t, 0, metrics.PendingItems, "failed batches are not re-queued")
+}
+
+func TestNotificationBatcher_RunFlushesOnShutdown(t *testing.T) {
+	done := make(chan *NotificationBatch, 1)
+	batcher := NewNotificationBatcher(NotificationBatcherConfig{
+		TickInterval: time.Hour,
+		Sender: func(ctx context.Context, batch *NotificationBatch) error {
+			done <- batch
+			return nil
+		},
+	})
+	batcher.Add(newBatchingTestPolicy(3600, false), notificationChannelSlack, NotificationBatchItem{Name: "a"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go batcher.Run(ctx)
+	cancel()
+
+	select {
+	case batch := <-done:
+		assert.Len(t, batch.Items, 1)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected final flush on shutdown")
+	}
+}
+
+func TestRemediationPolicyReconciler_BatchNotification(t *testing.T) {
+	r := &RemediationPolicyReconciler{
+		notificationBatcher: NewNotificationBatcher(NotificationBatcherConfig{}),
+	}
+	ctx := context.Background()
+	event := newBatchingTestEvent("web-1")
+
+	t.Run("start notifications are suppressed", func(t *testing.T) {
+		policy := newBatchingTestPolicy(60, true)
+		assert.False(t, r.batchNotification(ctx, policy, event, notificationChannelSlack, "start", nil))
+		assert.Equal(t, 0, r.notificationBatcher.PendingCount())
+	})
+
+	t.Run("successful completion is only batched", func(t *testing.T) {
+		policy := newBatchingTestPolicy(60, true)
+		response := createSuccessfulMcpResponse("fixed", 1000)
+		assert.False(t, r.batchNotification(ctx, policy, event, notificationChannelSlack, "complete", &response))
+		assert.Equal(t, 1, r.notificationBatcher.PendingCount())
+
+		item := r.notificationBatcher.batches["default/batch-policy/slack"].Items[0]
+		assert.Equal(t, notificationOutcomeExecuted, item.Outcome)
+		assert.Equal(t, "https://console.example.com/apps/Pod/web-1", item.Link)
+	})
+
+	t.Run("failure is batched and sent immediately when configured", func(t *testing.T) {
+		policy := newBatchingTestPolicy(60, true)
+		response := createFailedMcpResponse("boom")
+		assert.True(t, r.batchNotification(ctx, policy, event, notificationChannelSlack, "complete", &response))
+		assert.Equal(t, 2, r.notificationBatcher.PendingCount())
+	})
+
+	t.Run("failure is only batched when immediate sending is disabled", func(t *testing.T) {
+		policy := newBatchingTestPolicy(60, false)
+		response := createFailedMcpResponse("boom")
+		assert.False(t, r.batchNotification(ctx, policy, event, notificationChannelSlack, "complete", &response))
+	})
+
+	t.Run("failure is sent immediately by default", func(t *testing.T) {
+		policy := newBatchingTestPolicy(60, false)
+		policy.Spec.Notifications.Batching.SendFailuresImmediately = nil
+		response := createFailedMcpResponse("boom")
+		assert.True(t, r.batchNotification(ctx, policy, event, notificationChannelSlack, "complete", &response))
+	})
+
+	t.Run("batching disabled sends individually", func(t *testing.T) {
+		policy := newBatchingTestPolicy(60, false)
+		policy.Spec.Notifications.Batching.Enabled = false
+		assert.True(t, r.batchNotification(ctx, policy, event, notificationChannelSlack, "start", nil))
+	})
+}
+
+func TestRemediationPolicyReconciler_CreateSlackBatchMessage(t *testing.T) {
+	r := &RemediationPolicyReconciler{}
+	policy := newBatchingTestPolicy(60, false)
+
+	batch := &NotificationBatch{
+		Channel: notificationChannelSlack,
+		Policy:  policy,
+		Window:  time.Minute,
+	}
+	for i := 0; i < maxBatchSummaryItems+5; i++ {
+		batch.Items = append(batch.Items, NotificationBatchItem{
+			Kind:      "Pod",
+			Name:      fmt.Sprintf("web-%d", i),
+			Namespace: "apps",
+			Reason:    "BackOff",
+			Outcome:   notificationOutcomeManual,
+			Message:   strings.Repeat("x", 500),
+			Link:      fmt.Sprintf("https://console.example.com/apps/Pod/web-%d", i),
+		})
+	}
+	batch.Items[0].Outcome = notificationOutcomeFailed
+
+	message := r.createSlackBatchMessage(batch)
+
+	assert.Equal(t, "#alerts", message.Channel)
+	require.Len(t, message.Attachments, 1)
+	assert.Equal(t, "#e01e5a", message.Attachments[0].Color)
+
+	blocks := message.Attachments[0].Blocks
+	assert.Contains(t, blocks[0].Text.Text, "25 events")
+	assert.Contains(t, blocks[1].Fields[1].Text, "24")
+	assert.Contains(t, blocks[1].Fields[2].Text, "1")
+
+	var itemTexts []string
+	for _, block := range blocks {
+		if block.Text != nil {
+			itemTexts = append(itemTexts, block.Text.Text)
+		}
+	}
+	joined := strings.Join(itemTexts, "\n")
+	assert.Contains(t, joined, "<https://console.example.com/apps/Pod/web-0|Pod/web-0>")
+	assert.Contains(t, joined, "... and 5 more objects")
+	assert.NotContains(t, joined, strings.Repeat("x", 300), "long messages should be truncated")
+}
+
+func TestRemediationPolicyReconciler_CreateGoogleChatBatchMessage(t *testing.T) {
+	r := &RemediationPolicyReconciler{}
+	batch := &NotificationBatch{
+		Channel: notificationChannelGoogleChat,
+		Policy:  newBatchingTestPolicy(60, false),
+		Window:  time.Minute,
+		Items: []NotificationBatchItem{
+			{Kind: "Pod", Name: "web-0", Namespace: "apps", Reason: "BackOff", Outcome: notificationOutcomeExecuted, Message: "<fixed>"},
+		},
+	}
+
+	message := r.createGoogleChatBatchMessage(batch)
+
+	require.Len(t, message.CardsV2, 1)
+	card := message.CardsV2[0].Card
+	assert.Contains(t, card.Header.Title, "1 events")
+	assert.Equal(t, "Policy: batch-policy", card.Header.Subtitle)
+	require.Len(t, card.Sections, 3)
+	assert.Equal(t, "1", card.Sections[0].Widgets[0].DecoratedText.Text)
+	assert.Contains(t, card.Sections[1].Widgets[0].TextParagraph.Text, "&lt;fixed&gt;")
+}
+
+func TestRemediationPolicyReconciler_SendNotificationBatch(t *testing.T) {
+	var received SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = dotaiv1alpha1.AddToScheme(scheme)
+
+	policy := newBatchingTestPolicy(60, false)
+	policy.Spec.Notifications.Slack.WebhookUrl = server.URL
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(policy).
+		WithStatusSubresource(policy).
+		Build()
+
+	r := &RemediationPolicyReconciler{
+		Client:     fakeClient,
+		Scheme:     scheme,
+		Recorder:   record.NewFakeRecorder(10),
+		HttpClient: server.Client(),
+	}
+
+	batch := &NotificationBatch{
+		Channel: notificationChannelSlack,
+		Policy:  policy,
+		Window:  time.Minute,
+		Items: []NotificationBatchItem{
+			{Kind: "Pod", Name: "web-0", Namespace: "apps", Reason: "BackOff", Outcome: notificationOutcomeExecuted, Message: "fixed"},
+		},
+	}
+
+	err := r.sendNotificationBatch(context.Background(), batch)
+	require.NoError(t, err)
+	require.Len(t, received.Attachments, 1)
+	assert.Equal(t, "#2eb67d", received.Attachments[0].Color)
+
+	// Notification health condition should be recorded
+	updated := &dotaiv1alpha1.RemediationPolicy{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(policy), updated))
+	require.Len(t, updated.Status.Conditions, 1)
+	assert.Equal(t, "NotificationsHealthy", updated.Status.Conditions[0].Type)
+	assert.Equal(t, metav1.ConditionTrue, updated.Status.Conditions[0].Status)
+}
@@ -0,0 +1,132 @@
+// remediationpolicy_channels.go contains delivery of RemediationPolicy notifications
+// through reusable NotificationChannel resources referenced by name. Each delivery
+// is recorded in the NotificationChannel status so that channels aggregate
+// success and failure counts across all policies that reference them.
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// notificationChannelRefPrefix prefixes batch channel keys for NotificationChannel references
+const notificationChannelRefPrefix = "channel/"
+
+// validateNotificationChannelRefs validates NotificationChannel references
+func (r *RemediationPolicyReconciler) validateNotificationChannelRefs(policy *dotaiv1alpha1.RemediationPolicy) error {
+	names := make(map[string]bool)
+	for i, ref := range policy.Spec.Notifications.Channels {
+		if ref.Name == "" {
+			return fmt.Errorf("notification channel reference %d: name cannot be empty", i)
+		}
+		if names[ref.Name] {
+			return fmt.Errorf("notification channel '%s' is referenced more than once", ref.Name)
+		}
+		names[ref.Name] = true
+	}
+	return nil
+}
+
+// sendChannelNotifications sends a notification to all referenced NotificationChannels.
+// Errors are logged and do not fail event processing.
+func (r *RemediationPolicyReconciler) sendChannelNotifications(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy, event *corev1.Event, notificationType string, mcpRequest *dotaiv1alpha1.McpRequest, mcpResponse *McpResponse) {
+	logger := logf.FromContext(ctx)
+
+	for _, ref := range policy.Spec.Notifications.Channels {
+		// Check notification type against reference configuration
+		if notificationType == "start" && !ref.NotifyOnStart {
+			continue
+		}
+		if notificationType == "complete" && !ref.NotifyOnComplete {
+			continue
+		}
+
+		// Buffer notification for a summary message if batching is enabled
+		if !r.batchNotification(ctx, policy, event, notificationChannelRefPrefix+ref.Name, notificationType, mcpResponse) {
+			continue
+		}
+
+		err := r.deliverToNotificationChannel(ctx, policy, ref.Name,
+			func() SlackMessage {
+				return r.createSlackMessage(policy, event, notificationType, mcpRequest, mcpResponse)
+			},
+			func() GoogleChatMessage {
+				return r.createGoogleChatMessage(policy, event, notificationType, mcpRequest, mcpResponse)
+			},
+		)
+		if err != nil {
+			logger.Error(err, "failed to send notification through NotificationChannel",
+				"channel", ref.Name,
+				"notificationType", notificationType)
+		}
+	}
+}
+
+// deliverToNotificationChannel resolves a NotificationChannel and sends the message matching its type.
+// The delivery result is recorded in the channel status and the policy's notification delivery status.
+func (r *RemediationPolicyReconciler) deliverToNotificationChannel(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy, channelName string, slackMessage func() SlackMessage, googleChatMessage func() GoogleChatMessage) error {
+	return r.deliverNotification(ctx, policy, newNotificationChannelDelivery(channelName, slackMessage(), googleChatMessage()))
+}
+
+// sendToNotificationChannel resolves a NotificationChannel and sends the message matching its type.
+// The delivery result is recorded in the channel status. It is shared by all controllers
+// that notify through NotificationChannels.
+func sendToNotificationChannel(ctx context.Context, c client.Client, httpClient *http.Client, namespace, channelName string, slackMessage func() SlackMessage, googleChatMessage func() GoogleChatMessage) error {
+	logger := logf.FromContext(ctx)
+
+	channel := &dotaiv1alpha1.NotificationChannel{}
+	key := client.ObjectKey{Namespace: namespace, Name: channelName}
+	if err := c.Get(ctx, key, channel); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("NotificationChannel '%s' not found in namespace '%s'", channelName, namespace)
+		}
+		return fmt.Errorf("failed to fetch NotificationChannel '%s': %w", channelName, err)
+	}
+
+	webhookUrl, err := resolveNotificationChannelWebhook(ctx, c, channel)
+	if err == nil {
+		httpClient, err = webhookHTTPClient(ctx, c, namespace, channel.Spec.TLS, channel.Spec.ProxyURL, httpClient)
+	}
+	if err == nil {
+		switch channel.Spec.Type {
+		case dotaiv1alpha1.NotificationChannelTypeSlack:
+			message := slackMessage()
+			message.Channel = channel.Spec.Channel
+			err = postSlackWebhook(ctx, httpClient, webhookUrl, message)
+		case dotaiv1alpha1.NotificationChannelTypeGoogleChat:
+			err = postGoogleChatWebhook(ctx, httpClient, webhookUrl, googleChatMessage())
+		default:
+			err = fmt.Errorf("unsupported notification channel type '%s'", channel.Spec.Type)
+		}
+	}
+
+	// Aggregate delivery statistics on the channel
+	if recordErr := recordNotificationChannelDelivery(ctx, c, key, err); recordErr != nil {
+		logger.Error(recordErr, "failed to record NotificationChannel delivery", "channel", channelName)
+	}
+	if err != nil {
+		return fmt.Errorf("NotificationChannel '%s': %w", channelName, err)
+	}
+
+	logger.Info("📣 Notification sent through NotificationChannel",
+		"channel", channelName,
+		"type", channel.Spec.Type)
+	return nil
+}
+
+// notificationChannelNameFromBatchKey returns the NotificationChannel name for a batch channel key
+func notificationChannelNameFromBatchKey(batchChannel string) (string, bool) {
+	if !strings.HasPrefix(batchChannel, notificationChannelRefPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(batchChannel, notificationChannelRefPrefix), true
+}
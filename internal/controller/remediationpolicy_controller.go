@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -18,12 +19,15 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+	"github.com/vfarcic/dot-ai-controller/internal/tracing"
 )
 
 // DefaultObjectCooldownMinutes is the default cooldown period for object-level deduplication.
@@ -43,6 +47,10 @@ type RemediationPolicyReconciler struct {
 	// to survive pod restarts
 	CooldownPersistence *CooldownPersistence
 
+	// DeadLetterQueue stores notifications that could not be delivered because of
+	// transient errors and redelivers them periodically. Nil disables the queue.
+	DeadLetterQueue *NotificationDeadLetterQueue
+
 	// startupTime records when the controller started.
 	// Events with lastTimestamp before this time are ignored to prevent
 	// notification storms on controller restart.
@@ -66,14 +74,22 @@ type RemediationPolicyReconciler struct {
 	// Key format: policy-namespace/policy-name/involved-object-namespace/involved-object-name
 	objectCooldowns   map[string]time.Time
 	objectCooldownsMu sync.RWMutex
+
+	// notificationBatcher buffers completion notifications for policies with
+	// batching enabled and sends them as periodic summary messages
+	notificationBatcher *NotificationBatcher
 }
 
 // +kubebuilder:rbac:groups=dot-ai.devopstoolkit.live,resources=remediationpolicies,verbs=get;list;watch
 // +kubebuilder:rbac:groups=dot-ai.devopstoolkit.live,resources=remediationpolicies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=dot-ai.devopstoolkit.live,resources=notificationchannels,verbs=get;list;watch
+// +kubebuilder:rbac:groups=dot-ai.devopstoolkit.live,resources=notificationchannels/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts/token,verbs=create
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
-// +kubebuilder:rbac:groups="",resources=configmaps,verbs=create
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;create;update
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch
 // +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch
@@ -262,7 +278,7 @@ func (r *RemediationPolicyReconciler) matchesSelector(event *corev1.Event, selec
 }
 
 // processEvent handles processing of a single event
-func (r *RemediationPolicyReconciler) processEvent(ctx context.Context, event *corev1.Event, policy *dotaiv1alpha1.RemediationPolicy, selector dotaiv1alpha1.EventSelector) error {
+func (r *RemediationPolicyReconciler) processEvent(ctx context.Context, event *corev1.Event, policy *dotaiv1alpha1.RemediationPolicy, selector dotaiv1alpha1.EventSelector) (err error) {
 	effectiveMode := r.getEffectiveMode(selector, policy)
 	logger := logf.FromContext(ctx).WithValues(
 		"event", fmt.Sprintf("%s/%s", event.Namespace, event.Name),
@@ -273,6 +289,14 @@ func (r *RemediationPolicyReconciler) processEvent(ctx context.Context, event *c
 		"effectiveMode", effectiveMode,
 	)
 
+	ctx, span := tracing.Start(ctx, "RemediationPolicy.processEvent",
+		attribute.String("k8s.namespace.name", event.InvolvedObject.Namespace),
+		attribute.String("remediation.object", fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name)),
+		attribute.String("remediation.reason", event.Reason),
+		attribute.String("remediation.mode", effectiveMode),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	logger.Info("Processing event that matches policy",
 		"eventMessage", event.Message,
 		"firstTimestamp", event.FirstTimestamp,
@@ -314,23 +338,26 @@ func (r *RemediationPolicyReconciler) processEvent(ctx context.Context, event *c
 		logger.Error(err, "failed to send Google Chat start notification")
 		// Don't fail the entire process for notification errors, just log and continue
 	}
+	r.sendChannelNotifications(ctx, policy, event, "start", mcpRequest, nil)
 
-	// Resolve MCP auth token from Secret (if configured)
-	authToken, err := r.getMcpAuthToken(ctx, policy)
+	// Resolve MCP endpoint and credentials for the configured auth mode
+	connection, err := r.getMcpConnection(ctx, policy)
 	if err != nil {
-		logger.Error(err, "failed to resolve MCP auth token from Secret")
+		logger.Error(err, "failed to resolve MCP credentials")
 		r.Recorder.Eventf(policy, corev1.EventTypeWarning, "McpAuthSecretError",
 			"Failed to resolve MCP auth token: %v", err)
 		return err
 	}
 
 	// MILESTONE 4B: Send HTTP request to MCP endpoint
-	mcpResponse, err := r.sendMcpRequest(ctx, mcpRequest, policy.Spec.McpEndpoint, authToken)
+	remediationStart := time.Now()
+	mcpResponse, err := r.callMcpRemediate(ctx, policy, event, mcpRequest, connection)
+	recordRemediation(policy, mcpRequest.Mode, mcpResponse, err, time.Since(remediationStart))
 	if err != nil {
 		logger.Error(err, "failed to send MCP request")
 		// Generate error event
 		r.Recorder.Eventf(policy, corev1.EventTypeWarning, "McpRequestFailed",
-			"Failed to send MCP request to %s: %v", policy.Spec.McpEndpoint, err)
+			"Failed to send MCP request to %s: %v", connection.Endpoint, err)
 		// Update policy status with failure
 		if statusErr := r.updatePolicyStatus(ctx, policy, false, false); statusErr != nil {
 			logger.Error(statusErr, "failed to update policy status after MCP request failure")
@@ -338,12 +365,14 @@ func (r *RemediationPolicyReconciler) processEvent(ctx context.Context, event *c
 		return err
 	}
 
+	span.SetAttributes(attribute.Bool("remediation.success", mcpResponse.Success))
+
 	// Check MCP response
 	var mcpSuccess bool
 	if mcpResponse.Success {
 		mcpSuccess = true
 		logger.Info("🎉 MCP request successful",
-			"endpoint", policy.Spec.McpEndpoint,
+			"endpoint", connection.Endpoint,
 			"response", mcpResponse.GetResultMessage())
 		// Generate success event
 		r.Recorder.Eventf(policy, corev1.EventTypeNormal, "McpRequestSucceeded",
@@ -366,6 +395,12 @@ func (r *RemediationPolicyReconciler) processEvent(ctx context.Context, event *c
 		return err
 	}
 
+	// Keep the typed result in status for tooling and dashboards
+	if err := r.recordRemediationResult(ctx, policy, event, mcpResponse); err != nil {
+		logger.Error(err, "failed to record remediation result")
+		// Don't fail the entire process for result history errors, just log and continue
+	}
+
 	// MILESTONE 4C: Send mandatory "complete" notification
 	if err := r.sendSlackNotification(ctx, policy, event, "complete", mcpRequest, mcpResponse); err != nil {
 		logger.Error(err, "failed to send Slack complete notification")
@@ -375,6 +410,10 @@ func (r *RemediationPolicyReconciler) processEvent(ctx context.Context, event *c
 		logger.Error(err, "failed to send Google Chat complete notification")
 		// Don't fail the entire process for notification errors, just log and continue
 	}
+	r.sendChannelNotifications(ctx, policy, event, "complete", mcpRequest, mcpResponse)
+
+	// Send notifications to matching routes now that the MCP outcome is known
+	r.sendRoutedNotifications(ctx, policy, event, mcpRequest, mcpResponse)
 
 	// Log final success
 	if mcpSuccess {
@@ -386,7 +425,10 @@ func (r *RemediationPolicyReconciler) processEvent(ctx context.Context, event *c
 }
 
 // updatePolicyStatus updates the RemediationPolicy status with processing statistics with retry logic
-func (r *RemediationPolicyReconciler) updatePolicyStatus(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy, success bool, mcpMessageGenerated ...bool) error {
+func (r *RemediationPolicyReconciler) updatePolicyStatus(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy, success bool, mcpMessageGenerated ...bool) (err error) {
+	ctx, span := tracing.Start(ctx, "RemediationPolicy.updateStatus")
+	defer func() { tracing.End(span, err) }()
+
 	// Retry configuration for status updates
 	maxRetries := 3
 	baseDelay := 100 * time.Millisecond
@@ -531,7 +573,10 @@ func (r *RemediationPolicyReconciler) initializePolicyStatus(ctx context.Context
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
-func (r *RemediationPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *RemediationPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	ctx, span := tracing.StartReconcile(ctx, "RemediationPolicy", req.NamespacedName)
+	defer func() { tracing.End(span, err) }()
+
 	logger := logf.FromContext(ctx)
 
 	// Try to fetch as Event first
@@ -546,6 +591,9 @@ func (r *RemediationPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	if err := r.Get(ctx, req.NamespacedName, &policy); err == nil {
 		// This is a RemediationPolicy - initialize/update its status
 		return r.reconcilePolicy(ctx, &policy)
+	} else if apierrors.IsNotFound(err) {
+		// No policy has this name, so none of its series are reported any longer
+		forgetRemediationPolicyMetrics(req.Namespace, req.Name)
 	}
 
 	// Neither found - resource was probably deleted
@@ -560,6 +608,7 @@ func (r *RemediationPolicyReconciler) reconcilePolicy(ctx context.Context, polic
 	logger.Info("Reconciling RemediationPolicy",
 		"eventSelectors", len(policy.Spec.EventSelectors),
 		"mcpEndpoint", policy.Spec.McpEndpoint,
+		"mcpServerRef", policy.Spec.McpServerRef,
 		"mode", policy.Spec.Mode,
 	)
 
@@ -579,6 +628,22 @@ func (r *RemediationPolicyReconciler) reconcilePolicy(ctx context.Context, polic
 		return ctrl.Result{}, err
 	}
 
+	// Validate NotificationChannel references
+	if err := r.validateNotificationChannelRefs(policy); err != nil {
+		logger.Error(err, "invalid notification channel references")
+		r.Recorder.Eventf(policy, corev1.EventTypeWarning, "InvalidNotificationChannels",
+			"Invalid notification channel references: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	// Validate notification routes
+	if err := r.validateNotificationRoutes(policy); err != nil {
+		logger.Error(err, "invalid notification routes")
+		r.Recorder.Eventf(policy, corev1.EventTypeWarning, "InvalidNotificationRoutes",
+			"Invalid notification routes: %v", err)
+		return ctrl.Result{}, err
+	}
+
 	// Initialize status if this is a new policy (no status yet)
 	needsStatusUpdate := false
 	if policy.Status.TotalEventsProcessed == 0 && policy.Status.LastProcessedEvent == nil && len(policy.Status.Conditions) == 0 {
@@ -603,6 +668,9 @@ func (r *RemediationPolicyReconciler) reconcilePolicy(ctx context.Context, polic
 		logger.Info("✅ RemediationPolicy status initialized successfully")
 	}
 
+	// Surface the readiness of the referenced MCPServer and the referenced Secrets
+	r.updateReferenceConditions(ctx, policy)
+
 	// Periodic cleanup of processed events cache
 	r.cleanupProcessedEvents(10 * time.Minute)
 
@@ -673,6 +741,7 @@ func (r *RemediationPolicyReconciler) reconcileEvent(ctx context.Context, event
 	for _, policy := range policies.Items {
 		if matches, matchingSelector := r.matchesPolicyWithSelector(event, &policy); matches {
 			effectiveMode := r.getEffectiveMode(matchingSelector, &policy)
+			remediationEventsMatched.WithLabelValues(policy.Namespace, policy.Name).Inc()
 
 			// Check object-level cooldown first (independent of rate limiting)
 			// This prevents notification storms from multiple events for the same object
@@ -683,6 +752,8 @@ func (r *RemediationPolicyReconciler) reconcileEvent(ctx context.Context, event
 					"involvedObject", fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name),
 				)
 
+				remediationEventsBlocked.WithLabelValues(policy.Namespace, policy.Name, "cooldown").Inc()
+
 				// Update rate limit status to track blocked events
 				// (object cooldown is a form of rate limiting)
 				if err := r.updateRateLimitStatus(ctx, &policy); err != nil {
@@ -703,6 +774,8 @@ func (r *RemediationPolicyReconciler) reconcileEvent(ctx context.Context, event
 					"cooldownMinutes", policy.Spec.RateLimiting.CooldownMinutes,
 				)
 
+				remediationEventsBlocked.WithLabelValues(policy.Namespace, policy.Name, "rate_limit").Inc()
+
 				// Update policy status with rate limiting statistics
 				if err := r.updateRateLimitStatus(ctx, &policy); err != nil {
 					logger.Error(err, "failed to update rate limit status")
@@ -791,16 +864,100 @@ func (r *RemediationPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		}
 	}
 
+	// Add a Runnable that redelivers notifications from the dead-letter queue
+	if r.DeadLetterQueue != nil {
+		if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			r.runDeadLetterRedelivery(ctx)
+			return nil
+		})); err != nil {
+			return fmt.Errorf("failed to add notification dead-letter queue runnable: %w", err)
+		}
+	}
+
+	// Add a Runnable that flushes batched notification summaries
+	r.notificationBatcher = NewNotificationBatcher(NotificationBatcherConfig{
+		Sender: r.sendNotificationBatch,
+	})
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		r.notificationBatcher.Run(ctx)
+		return nil
+	})); err != nil {
+		return fmt.Errorf("failed to add notification batcher runnable: %w", err)
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Event{}).
 		Watches(
 			&dotaiv1alpha1.RemediationPolicy{},
 			&handler.EnqueueRequestForObject{},
 		).
+		Watches(
+			&dotaiv1alpha1.MCPServer{},
+			handler.EnqueueRequestsFromMapFunc(r.mapMCPServerToPolicies),
+		).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.mapSecretToPolicies),
+			builder.WithPredicates(secretDataChangedPredicate()),
+		).
 		Named("remediationpolicy").
 		Complete(r)
 }
 
+// mapMCPServerToPolicies enqueues the RemediationPolicies that reference an MCPServer,
+// so that they surface its readiness
+func (r *RemediationPolicyReconciler) mapMCPServerToPolicies(ctx context.Context, obj client.Object) []reconcile.Request {
+	var policies dotaiv1alpha1.RemediationPolicyList
+	if err := r.List(ctx, &policies); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to list RemediationPolicies for MCPServer", "mcpserver", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, policy := range policies.Items {
+		if policy.Spec.McpServerRef == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&policy)})
+		}
+	}
+	return requests
+}
+
+// mapSecretToPolicies enqueues the RemediationPolicies that reference a Secret,
+// so that they validate it again when it is created, rotated or deleted
+func (r *RemediationPolicyReconciler) mapSecretToPolicies(ctx context.Context, obj client.Object) []reconcile.Request {
+	var policies dotaiv1alpha1.RemediationPolicyList
+	if err := r.List(ctx, &policies, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to list RemediationPolicies for Secret", "secret", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, policy := range policies.Items {
+		if referencesSecret(remediationPolicySecretReferences(&policy), obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&policy)})
+		}
+	}
+	return requests
+}
+
+// updateReferenceConditions refreshes the readiness of the referenced MCPServer and the
+// CredentialsResolved condition in the policy status
+func (r *RemediationPolicyReconciler) updateReferenceConditions(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy) {
+	fresh := &dotaiv1alpha1.RemediationPolicy{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(policy), fresh); err != nil {
+		return
+	}
+	serverChanged := setMCPServerReadyCondition(ctx, r.Client, &fresh.Status.Conditions, fresh.Spec.McpServerRef, fresh.Generation)
+	credentialsChanged := setCredentialsResolvedCondition(ctx, r.Client, &fresh.Status.Conditions, fresh.Namespace,
+		remediationPolicySecretReferences(fresh), fresh.Generation)
+	if !serverChanged && !credentialsChanged {
+		return
+	}
+	if err := r.Status().Update(ctx, fresh); err != nil && !apierrors.IsConflict(err) {
+		logf.FromContext(ctx).Error(err, "failed to update reference conditions")
+	}
+}
+
 // getCooldownsForPersistence returns a copy of cooldown tracking map for persistence
 func (r *RemediationPolicyReconciler) getCooldownsForPersistence() map[string]time.Time {
 	r.rateLimitMu.RLock()
@@ -0,0 +1,453 @@
+// remediationpolicy_deadletter.go implements the notification dead-letter queue.
+// Notifications that still fail with a transient error after in-line retries are stored
+// in a per-policy ConfigMap (with an ownerReference for automatic cleanup) and redelivered
+// periodically with exponential backoff, so that they survive controller restarts.
+// Queued notifications are dropped, and counted as failed, when they exceed the maximum
+// age, when they fail with a permanent error, or when the queue overflows its entry or size limit.
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+const (
+	// DefaultDeadLetterRetryInterval is the default interval between redelivery passes
+	DefaultDeadLetterRetryInterval = 1 * time.Minute
+
+	// DefaultDeadLetterMaxBackoff is the default maximum delay between redelivery attempts
+	DefaultDeadLetterMaxBackoff = 30 * time.Minute
+
+	// DefaultDeadLetterMaxAge is the default maximum age of a queued notification
+	DefaultDeadLetterMaxAge = 24 * time.Hour
+
+	// DefaultDeadLetterMaxEntries is the default maximum number of queued notifications per policy
+	DefaultDeadLetterMaxEntries = 100
+
+	// DefaultDeadLetterMaxBytes is the default maximum size of the queued notifications per policy
+	// It leaves room for the metadata of the ConfigMap below the 1 MiB object size limit of etcd
+	DefaultDeadLetterMaxBytes = 900 * 1024
+
+	// deadLetterConfigMapSuffix is appended to the policy name for the dead-letter ConfigMap
+	deadLetterConfigMapSuffix = "-notification-dlq"
+
+	// deadLetterDataKey is the key used for queued notifications in the ConfigMap
+	deadLetterDataKey = "entries"
+)
+
+// deadLetterEntry is a queued notification waiting for redelivery
+type deadLetterEntry struct {
+	// ID uniquely identifies the entry within the queue
+	ID string `json:"id"`
+	// Delivery is the notification to redeliver
+	Delivery notificationDelivery `json:"delivery"`
+	// Attempts is the number of failed delivery attempts
+	Attempts int `json:"attempts"`
+	// FirstFailure is when the first delivery attempt failed
+	FirstFailure time.Time `json:"firstFailure"`
+	// NextAttempt is when the next redelivery is due
+	NextAttempt time.Time `json:"nextAttempt"`
+	// LastError is the error of the last delivery attempt
+	LastError string `json:"lastError"`
+}
+
+// deadLetterResult is the outcome of a redelivery attempt or of dropping an entry
+type deadLetterResult struct {
+	// Channel is the delivery status key of the entry
+	Channel string
+	// Outcome is the effect on the delivery statistics
+	Outcome deliveryOutcome
+	// Err is the delivery error, nil on successful redelivery
+	Err error
+}
+
+// NotificationDeadLetterQueueConfig configures the notification dead-letter queue
+type NotificationDeadLetterQueueConfig struct {
+	// RetryInterval is the interval between redelivery passes and the initial redelivery backoff
+	RetryInterval time.Duration
+	// MaxBackoff is the maximum delay between redelivery attempts
+	MaxBackoff time.Duration
+	// MaxAge is how long a notification is retried before it is dropped
+	MaxAge time.Duration
+	// MaxEntries is the maximum number of queued notifications per policy; the oldest are dropped first
+	MaxEntries int
+	// MaxBytes is the maximum size of the serialized queue per policy; the oldest are dropped first
+	MaxBytes int
+}
+
+// NotificationDeadLetterQueue stores undeliverable notifications in per-policy ConfigMaps
+type NotificationDeadLetterQueue struct {
+	client client.Client
+	scheme *runtime.Scheme
+	config NotificationDeadLetterQueueConfig
+
+	// mu serializes read-modify-write cycles on the queue ConfigMaps
+	mu sync.Mutex
+}
+
+// NewNotificationDeadLetterQueue creates a new NotificationDeadLetterQueue, applying defaults for unset values
+func NewNotificationDeadLetterQueue(c client.Client, scheme *runtime.Scheme, config NotificationDeadLetterQueueConfig) *NotificationDeadLetterQueue {
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = DefaultDeadLetterRetryInterval
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultDeadLetterMaxBackoff
+	}
+	if config.MaxAge <= 0 {
+		config.MaxAge = DefaultDeadLetterMaxAge
+	}
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = DefaultDeadLetterMaxEntries
+	}
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = DefaultDeadLetterMaxBytes
+	}
+	return &NotificationDeadLetterQueue{
+		client: c,
+		scheme: scheme,
+		config: config,
+	}
+}
+
+// getDeadLetterConfigMapName returns the dead-letter ConfigMap name for a policy
+func getDeadLetterConfigMapName(policyName string) string {
+	return policyName + deadLetterConfigMapSuffix
+}
+
+// Enqueue adds a failed notification to the policy's queue.
+// It returns the results for entries dropped because the queue overflowed.
+func (q *NotificationDeadLetterQueue) Enqueue(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy, delivery notificationDelivery, deliveryErr error) ([]deadLetterResult, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	cm, entries, err := q.load(ctx, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entry := deadLetterEntry{
+		ID:           strconv.FormatInt(now.UnixNano(), 36),
+		Delivery:     delivery,
+		Attempts:     1,
+		FirstFailure: now,
+		LastError:    deliveryErr.Error(),
+	}
+	entry.NextAttempt = now.Add(q.backoff(entry.Attempts, deliveryErr))
+	entries = append(entries, entry)
+
+	entries, dropped, err := q.trim(entries)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.save(ctx, policy, cm, entries); err != nil {
+		return nil, err
+	}
+	return dropped, nil
+}
+
+// Len returns the number of queued notifications for a policy
+func (q *NotificationDeadLetterQueue) Len(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	_, entries, err := q.load(ctx, policy)
+	return len(entries), err
+}
+
+// Redeliver attempts to deliver all due notifications of a policy using the given send function.
+// Delivered, expired and permanently failing entries are removed from the queue; entries
+// that fail again with a transient error are rescheduled with exponential backoff.
+func (q *NotificationDeadLetterQueue) Redeliver(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy, send func(notificationDelivery) error) ([]deadLetterResult, error) {
+	// Select due entries without holding the lock while sending
+	q.mu.Lock()
+	_, entries, err := q.load(ctx, policy)
+	q.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var results []deadLetterResult
+	done := make(map[string]bool)
+	retried := make(map[string]deadLetterEntry)
+	for _, entry := range entries {
+		if now.Sub(entry.FirstFailure) > q.config.MaxAge {
+			results = append(results, deadLetterResult{
+				Channel: entry.Delivery.Channel,
+				Outcome: deliveryDropped,
+				Err:     fmt.Errorf("notification expired after %d attempts: %s", entry.Attempts, entry.LastError),
+			})
+			done[entry.ID] = true
+			continue
+		}
+		if now.Before(entry.NextAttempt) {
+			continue
+		}
+
+		sendErr := send(entry.Delivery)
+		switch {
+		case sendErr == nil:
+			results = append(results, deadLetterResult{Channel: entry.Delivery.Channel, Outcome: deliveryRedelivered})
+			done[entry.ID] = true
+		case !isRetryableDeliveryError(sendErr):
+			results = append(results, deadLetterResult{Channel: entry.Delivery.Channel, Outcome: deliveryDropped, Err: sendErr})
+			done[entry.ID] = true
+		default:
+			entry.Attempts++
+			entry.LastError = sendErr.Error()
+			entry.NextAttempt = time.Now().Add(q.backoff(entry.Attempts, sendErr))
+			retried[entry.ID] = entry
+			results = append(results, deadLetterResult{Channel: entry.Delivery.Channel, Outcome: deliveryRequeued, Err: sendErr})
+		}
+	}
+	if len(done) == 0 && len(retried) == 0 {
+		return results, nil
+	}
+
+	// Apply the results to the current queue, which may have received new entries meanwhile
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	cm, current, err := q.load(ctx, policy)
+	if err != nil {
+		return results, err
+	}
+	remaining := make([]deadLetterEntry, 0, len(current))
+	for _, entry := range current {
+		if done[entry.ID] {
+			continue
+		}
+		if updated, ok := retried[entry.ID]; ok {
+			entry = updated
+		}
+		remaining = append(remaining, entry)
+	}
+
+	// Rescheduled entries carry new errors, which may grow the queue beyond its size limit
+	remaining, dropped, err := q.trim(remaining)
+	if err != nil {
+		return results, err
+	}
+	results = append(results, dropped...)
+	return results, q.save(ctx, policy, cm, remaining)
+}
+
+// trim drops the oldest entries until the queue fits its entry and size limits
+// The size limit keeps the ConfigMap below the object size limit, since rendered messages
+// with long analysis results can be large enough for 100 entries to exceed it.
+func (q *NotificationDeadLetterQueue) trim(entries []deadLetterEntry) ([]deadLetterEntry, []deadLetterResult, error) {
+	var dropped []deadLetterResult
+	drop := func(reason string) {
+		dropped = append(dropped, deadLetterResult{
+			Channel: entries[0].Delivery.Channel,
+			Outcome: deliveryDropped,
+			Err:     fmt.Errorf("dead-letter queue full (%s), dropped notification: %s", reason, entries[0].LastError),
+		})
+		entries = entries[1:]
+	}
+
+	for len(entries) > q.config.MaxEntries {
+		drop(fmt.Sprintf("%d entries", q.config.MaxEntries))
+	}
+
+	// The serialized queue is a JSON array: brackets, entries and the commas between them
+	sizes := make([]int, len(entries))
+	size := 1
+	for i, entry := range entries {
+		entryJSON, err := json.Marshal(entry)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to serialize dead-letter entry: %w", err)
+		}
+		sizes[i] = len(entryJSON) + 1
+		size += sizes[i]
+	}
+	for len(entries) > 0 && size > q.config.MaxBytes {
+		size -= sizes[0]
+		sizes = sizes[1:]
+		drop(fmt.Sprintf("%d bytes", q.config.MaxBytes))
+	}
+	return entries, dropped, nil
+}
+
+// backoff returns the delay before the next redelivery attempt, honoring Retry-After
+func (q *NotificationDeadLetterQueue) backoff(attempts int, deliveryErr error) time.Duration {
+	delay := time.Duration(float64(q.config.RetryInterval) * math.Pow(2, float64(attempts-1)))
+	var webhookErr *WebhookError
+	if errors.As(deliveryErr, &webhookErr) && webhookErr.RetryAfter > delay {
+		delay = webhookErr.RetryAfter
+	}
+	if delay > q.config.MaxBackoff {
+		delay = q.config.MaxBackoff
+	}
+	return delay
+}
+
+// load reads the queue ConfigMap of a policy; a missing ConfigMap is an empty queue
+func (q *NotificationDeadLetterQueue) load(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy) (*corev1.ConfigMap, []deadLetterEntry, error) {
+	cm := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: policy.Namespace, Name: getDeadLetterConfigMapName(policy.Name)}
+	if err := q.client.Get(ctx, key, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to get dead-letter ConfigMap: %w", err)
+	}
+
+	var entries []deadLetterEntry
+	if data := cm.Data[deadLetterDataKey]; data != "" {
+		if err := json.Unmarshal([]byte(data), &entries); err != nil {
+			logf.FromContext(ctx).Error(err, "failed to parse dead-letter ConfigMap, discarding entries",
+				"configmap", cm.Name,
+				"namespace", cm.Namespace)
+			entries = nil
+		}
+	}
+	return cm, entries, nil
+}
+
+// save writes the queue of a policy, deleting the ConfigMap when the queue is empty
+func (q *NotificationDeadLetterQueue) save(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy, cm *corev1.ConfigMap, entries []deadLetterEntry) error {
+	if len(entries) == 0 {
+		if cm == nil {
+			return nil
+		}
+		if err := q.client.Delete(ctx, cm); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete dead-letter ConfigMap: %w", err)
+		}
+		return nil
+	}
+
+	entriesJSON, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to serialize dead-letter entries: %w", err)
+	}
+
+	if cm == nil {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      getDeadLetterConfigMapName(policy.Name),
+				Namespace: policy.Namespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/component":  "notification-dlq",
+					"app.kubernetes.io/managed-by": "dot-ai-controller",
+				},
+			},
+			Data: map[string]string{
+				deadLetterDataKey: string(entriesJSON),
+			},
+		}
+
+		// Set ownerReference for automatic cleanup
+		if err := controllerutil.SetControllerReference(policy, cm, q.scheme); err != nil {
+			return fmt.Errorf("failed to set owner reference: %w", err)
+		}
+
+		if err := q.client.Create(ctx, cm); err != nil {
+			return fmt.Errorf("failed to create dead-letter ConfigMap: %w", err)
+		}
+		return nil
+	}
+
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	cm.Data[deadLetterDataKey] = string(entriesJSON)
+	if err := q.client.Update(ctx, cm); err != nil {
+		return fmt.Errorf("failed to update dead-letter ConfigMap: %w", err)
+	}
+	return nil
+}
+
+// runDeadLetterRedelivery periodically redelivers queued notifications until the context is done
+func (r *RemediationPolicyReconciler) runDeadLetterRedelivery(ctx context.Context) {
+	ticker := time.NewTicker(r.DeadLetterQueue.config.RetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.redeliverDeadLetters(ctx)
+		}
+	}
+}
+
+// redeliverDeadLetters redelivers due notifications for all policies and records the results
+func (r *RemediationPolicyReconciler) redeliverDeadLetters(ctx context.Context) {
+	logger := logf.FromContext(ctx).WithName("notification-dlq")
+
+	var policies dotaiv1alpha1.RemediationPolicyList
+	if err := r.List(ctx, &policies); err != nil {
+		logger.Error(err, "failed to list RemediationPolicies for notification redelivery")
+		return
+	}
+
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		results, err := r.DeadLetterQueue.Redeliver(ctx, policy, func(delivery notificationDelivery) error {
+			return r.sendNotificationDelivery(ctx, policy, delivery)
+		})
+		if err != nil {
+			logger.Error(err, "failed to redeliver queued notifications",
+				"policy", fmt.Sprintf("%s/%s", policy.Namespace, policy.Name))
+		}
+
+		for _, result := range results {
+			switch result.Outcome {
+			case deliveryRedelivered:
+				logger.Info("📤 Queued notification delivered",
+					"policy", fmt.Sprintf("%s/%s", policy.Namespace, policy.Name),
+					"channel", result.Channel)
+			case deliveryRequeued:
+				logger.V(1).Info("Queued notification failed again, rescheduled",
+					"policy", fmt.Sprintf("%s/%s", policy.Namespace, policy.Name),
+					"channel", result.Channel,
+					"error", result.Err.Error())
+			}
+			r.recordDeadLetterResult(ctx, policy, result)
+		}
+	}
+}
+
+// recordDroppedDeadLetters records entries dropped from the dead-letter queue on overflow
+func (r *RemediationPolicyReconciler) recordDroppedDeadLetters(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy, dropped []deadLetterResult) {
+	for _, result := range dropped {
+		r.recordDeadLetterResult(ctx, policy, result)
+	}
+}
+
+// recordDeadLetterResult updates the delivery statistics for a dead-letter result
+// and emits a Warning event when a notification is dropped
+func (r *RemediationPolicyReconciler) recordDeadLetterResult(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy, result deadLetterResult) {
+	if result.Outcome == deliveryDropped {
+		logf.FromContext(ctx).Error(result.Err, "notification dropped",
+			"policy", fmt.Sprintf("%s/%s", policy.Namespace, policy.Name),
+			"channel", result.Channel)
+		if r.Recorder != nil {
+			r.Recorder.Eventf(policy, corev1.EventTypeWarning, "NotificationDropped",
+				"Notification for %s dropped: %v", result.Channel, result.Err)
+		}
+	}
+
+	if err := r.recordNotificationDelivery(ctx, policy, result.Channel, result.Outcome, result.Err); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to record notification delivery", "channel", result.Channel)
+	}
+}
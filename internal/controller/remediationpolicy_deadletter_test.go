@@ -0,0 +1,227 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// newDeadLetterTestReconciler creates a reconciler with a dead-letter queue and a Slack-enabled policy
+func newDeadLetterTestReconciler(t *testing.T, webhookUrl string, config NotificationDeadLetterQueueConfig) (*RemediationPolicyReconciler, *dotaiv1alpha1.RemediationPolicy, *record.FakeRecorder) {
+	t.Helper()
+
+	previous := defaultWebhookRetryConfig
+	defaultWebhookRetryConfig = WebhookRetryConfig{MaxAttempts: 1}
+	t.Cleanup(func() { defaultWebhookRetryConfig = previous })
+
+	scheme := newNotificationChannelTestScheme()
+	policy := &dotaiv1alpha1.RemediationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "dlq-policy", Namespace: "default", UID: "dlq-policy-uid"},
+		Spec: dotaiv1alpha1.RemediationPolicySpec{
+			Notifications: dotaiv1alpha1.NotificationConfig{
+				Slack: dotaiv1alpha1.SlackConfig{
+					Enabled:             true,
+					NotifyOnComplete:    true,
+					WebhookUrlSecretRef: &dotaiv1alpha1.SecretReference{Name: "webhooks", Key: "url"},
+				},
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(policy, newWebhookSecret(webhookUrl)).
+		WithStatusSubresource(policy).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	r := &RemediationPolicyReconciler{
+		Client:          fakeClient,
+		Scheme:          scheme,
+		Recorder:        recorder,
+		HttpClient:      http.DefaultClient,
+		DeadLetterQueue: NewNotificationDeadLetterQueue(fakeClient, scheme, config),
+	}
+	return r, policy, recorder
+}
+
+// getDeliveryStatus returns the delivery statistics of a channel from the stored policy
+func getDeliveryStatus(t *testing.T, r *RemediationPolicyReconciler, policy *dotaiv1alpha1.RemediationPolicy, channel string) (*dotaiv1alpha1.RemediationPolicy, dotaiv1alpha1.NotificationDeliveryStatus) {
+	t.Helper()
+
+	updated := &dotaiv1alpha1.RemediationPolicy{}
+	require.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(policy), updated))
+	for _, stats := range updated.Status.NotificationDelivery {
+		if stats.Channel == channel {
+			return updated, stats
+		}
+	}
+	t.Fatalf("no delivery status for channel %s", channel)
+	return nil, dotaiv1alpha1.NotificationDeliveryStatus{}
+}
+
+func TestRemediationPolicyReconciler_DeliverNotification(t *testing.T) {
+	t.Run("transient failure is queued and redelivered", func(t *testing.T) {
+		var failing atomic.Bool
+		failing.Store(true)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if failing.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		r, policy, _ := newDeadLetterTestReconciler(t, server.URL, NotificationDeadLetterQueueConfig{RetryInterval: time.Millisecond})
+		ctx := context.Background()
+
+		err := r.deliverNotification(ctx, policy, newSlackDelivery(SlackMessage{Channel: "#complete"}))
+		require.Error(t, err)
+
+		updated, stats := getDeliveryStatus(t, r, policy, notificationChannelSlack)
+		assert.Equal(t, 1, stats.Pending)
+		assert.Equal(t, int64(0), stats.Failed)
+		assert.Contains(t, stats.LastError, "503")
+		assert.True(t, meta.IsStatusConditionFalse(updated.Status.Conditions, "NotificationsHealthy"))
+
+		cm := &corev1.ConfigMap{}
+		require.NoError(t, r.Get(ctx, client.ObjectKey{Namespace: "default", Name: "dlq-policy-notification-dlq"}, cm))
+		require.Len(t, cm.OwnerReferences, 1)
+		assert.Equal(t, "dlq-policy", cm.OwnerReferences[0].Name)
+
+		// Redeliver once the webhook recovers
+		failing.Store(false)
+		time.Sleep(5 * time.Millisecond)
+		r.redeliverDeadLetters(ctx)
+
+		updated, stats = getDeliveryStatus(t, r, policy, notificationChannelSlack)
+		assert.Equal(t, 0, stats.Pending)
+		assert.Equal(t, int64(1), stats.Delivered)
+		assert.Empty(t, stats.LastError)
+		assert.NotNil(t, stats.LastDeliveryTime)
+		assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, "NotificationsHealthy"))
+
+		err = r.Get(ctx, client.ObjectKeyFromObject(cm), &corev1.ConfigMap{})
+		assert.True(t, apierrors.IsNotFound(err), "empty queue should delete its ConfigMap")
+	})
+
+	t.Run("permanent failure is counted as failed", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		r, policy, _ := newDeadLetterTestReconciler(t, server.URL, NotificationDeadLetterQueueConfig{})
+		err := r.deliverNotification(context.Background(), policy, newSlackDelivery(SlackMessage{Channel: "#complete"}))
+		require.Error(t, err)
+
+		_, stats := getDeliveryStatus(t, r, policy, notificationChannelSlack)
+		assert.Equal(t, int64(1), stats.Failed)
+		assert.Equal(t, 0, stats.Pending)
+
+		length, err := r.DeadLetterQueue.Len(context.Background(), policy)
+		require.NoError(t, err)
+		assert.Equal(t, 0, length)
+	})
+
+	t.Run("queue overflow drops the oldest notification", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		r, policy, recorder := newDeadLetterTestReconciler(t, server.URL, NotificationDeadLetterQueueConfig{MaxEntries: 1})
+		ctx := context.Background()
+		_ = r.deliverNotification(ctx, policy, newSlackDelivery(SlackMessage{Channel: "#first"}))
+		_ = r.deliverNotification(ctx, policy, newSlackDelivery(SlackMessage{Channel: "#second"}))
+
+		_, stats := getDeliveryStatus(t, r, policy, notificationChannelSlack)
+		assert.Equal(t, 1, stats.Pending)
+		assert.Equal(t, int64(1), stats.Failed)
+
+		length, err := r.DeadLetterQueue.Len(ctx, policy)
+		require.NoError(t, err)
+		assert.Equal(t, 1, length)
+
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "NotificationDropped")
+	})
+
+	t.Run("expired notifications are dropped", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		r, policy, recorder := newDeadLetterTestReconciler(t, server.URL, NotificationDeadLetterQueueConfig{MaxAge: time.Millisecond})
+		ctx := context.Background()
+		_ = r.deliverNotification(ctx, policy, newSlackDelivery(SlackMessage{Channel: "#complete"}))
+
+		time.Sleep(5 * time.Millisecond)
+		r.redeliverDeadLetters(ctx)
+
+		_, stats := getDeliveryStatus(t, r, policy, notificationChannelSlack)
+		assert.Equal(t, 0, stats.Pending)
+		assert.Equal(t, int64(1), stats.Failed)
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "NotificationDropped")
+	})
+}
+
+func TestNotificationDeadLetterQueue_BackoffHonorsRetryAfter(t *testing.T) {
+	q := NewNotificationDeadLetterQueue(nil, nil, NotificationDeadLetterQueueConfig{RetryInterval: time.Minute, MaxBackoff: time.Hour})
+
+	assert.Equal(t, time.Minute, q.backoff(1, &WebhookError{StatusCode: http.StatusServiceUnavailable}))
+	assert.Equal(t, 4*time.Minute, q.backoff(3, &WebhookError{StatusCode: http.StatusServiceUnavailable}))
+	assert.Equal(t, 10*time.Minute, q.backoff(1, &WebhookError{StatusCode: http.StatusTooManyRequests, RetryAfter: 10 * time.Minute}))
+	assert.Equal(t, time.Hour, q.backoff(20, &WebhookError{StatusCode: http.StatusServiceUnavailable}))
+}
+
+func TestNotificationDeadLetterQueue_DropsOldestOverSizeLimit(t *testing.T) {
+	r, policy, _ := newDeadLetterTestReconciler(t, "https://hooks.slack.com/services/test", NotificationDeadLetterQueueConfig{MaxBytes: 2048})
+	q := r.DeadLetterQueue
+	ctx := context.Background()
+	deliveryErr := &WebhookError{StatusCode: http.StatusServiceUnavailable}
+
+	var dropped []deadLetterResult
+	for _, channel := range []string{"#first", "#second", "#third"} {
+		results, err := q.Enqueue(ctx, policy, newSlackDelivery(SlackMessage{Channel: channel, Username: strings.Repeat("x", 700)}), deliveryErr)
+		require.NoError(t, err)
+		dropped = append(dropped, results...)
+	}
+
+	require.Len(t, dropped, 1, "the oldest notification is dropped once the queue exceeds its size limit")
+	assert.Equal(t, deliveryDropped, dropped[0].Outcome)
+	assert.ErrorContains(t, dropped[0].Err, "2048 bytes")
+
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, r.Get(ctx, client.ObjectKey{Namespace: "default", Name: "dlq-policy-notification-dlq"}, cm))
+	assert.LessOrEqual(t, len(cm.Data[deadLetterDataKey]), 2048)
+	assert.Contains(t, cm.Data[deadLetterDataKey], "#third")
+	assert.NotContains(t, cm.Data[deadLetterDataKey], "#first")
+
+	// A notification larger than the whole queue cannot be stored
+	results, err := q.Enqueue(ctx, policy, newSlackDelivery(SlackMessage{Channel: "#huge", Username: strings.Repeat("x", 4096)}), deliveryErr)
+	require.NoError(t, err)
+	assert.Len(t, results, 3)
+	length, err := q.Len(ctx, policy)
+	require.NoError(t, err)
+	assert.Equal(t, 0, length)
+}
@@ -0,0 +1,256 @@
+// remediationpolicy_delivery.go contains the central delivery path for RemediationPolicy
+// notifications. Every notification (inline Slack and Google Chat, routes, NotificationChannel
+// references and batch summaries) is sent through deliverNotification, which retries transient
+// failures, hands undeliverable notifications to the dead-letter queue, and records
+// per-target delivered/failed/pending counts in the policy status.
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+const (
+	// notificationRouteKeyPrefix prefixes delivery status keys for notification routes
+	notificationRouteKeyPrefix = "route/"
+
+	// maxDeliveryStatusRetries is the number of attempts for delivery status updates on conflict
+	maxDeliveryStatusRetries = 5
+)
+
+// notificationDelivery describes a single notification for a single target.
+// It is self-contained so that it can be persisted in the dead-letter queue and
+// redelivered later: webhook URLs are never stored, they are resolved at send time.
+type notificationDelivery struct {
+	// Channel is the delivery status key (slack, googleChat, channel/<name> or route/<name>/<service>)
+	Channel string `json:"channel"`
+	// Service is the webhook service (slack or googleChat); empty for NotificationChannel targets
+	Service string `json:"service,omitempty"`
+	// SecretRef references the webhook URL for route targets.
+	// Inline targets without SecretRef are resolved from the policy notification settings.
+	SecretRef *dotaiv1alpha1.SecretReference `json:"secretRef,omitempty"`
+	// TLS and ProxyURL are the connection settings of route targets
+	TLS      *dotaiv1alpha1.TLSConfig `json:"tls,omitempty"`
+	ProxyURL string                   `json:"proxyURL,omitempty"`
+	// NotificationChannel is the name of the NotificationChannel target
+	NotificationChannel string `json:"notificationChannel,omitempty"`
+	// SlackMessage is the message sent to Slack targets
+	SlackMessage *SlackMessage `json:"slackMessage,omitempty"`
+	// GoogleChatMessage is the message sent to Google Chat targets
+	GoogleChatMessage *GoogleChatMessage `json:"googleChatMessage,omitempty"`
+}
+
+// deliveryOutcome describes how a delivery affects the per-target delivery statistics
+type deliveryOutcome int
+
+const (
+	// deliveryDelivered means the notification was delivered
+	deliveryDelivered deliveryOutcome = iota
+	// deliveryFailed means the notification was dropped without being delivered
+	deliveryFailed
+	// deliveryQueued means the notification was added to the dead-letter queue
+	deliveryQueued
+	// deliveryRedelivered means a queued notification was delivered
+	deliveryRedelivered
+	// deliveryDropped means a queued notification was dropped without being delivered
+	deliveryDropped
+	// deliveryRequeued means a queued notification failed again and stays queued
+	deliveryRequeued
+)
+
+// notificationRouteKey returns the delivery status key for a route target
+func notificationRouteKey(routeName, service string) string {
+	return fmt.Sprintf("%s%s/%s", notificationRouteKeyPrefix, routeName, service)
+}
+
+// newSlackDelivery creates a delivery for the inline Slack configuration of a policy
+func newSlackDelivery(message SlackMessage) notificationDelivery {
+	return notificationDelivery{Channel: notificationChannelSlack, Service: notificationChannelSlack, SlackMessage: &message}
+}
+
+// newGoogleChatDelivery creates a delivery for the inline Google Chat configuration of a policy
+func newGoogleChatDelivery(message GoogleChatMessage) notificationDelivery {
+	return notificationDelivery{Channel: notificationChannelGoogleChat, Service: notificationChannelGoogleChat, GoogleChatMessage: &message}
+}
+
+// newNotificationChannelDelivery creates a delivery for a NotificationChannel.
+// Both messages are included because the channel type is only known at send time.
+func newNotificationChannelDelivery(channelName string, slackMessage SlackMessage, googleChatMessage GoogleChatMessage) notificationDelivery {
+	return notificationDelivery{
+		Channel:             notificationChannelRefPrefix + channelName,
+		NotificationChannel: channelName,
+		SlackMessage:        &slackMessage,
+		GoogleChatMessage:   &googleChatMessage,
+	}
+}
+
+// deliverNotification sends a notification with retries. Notifications that still fail with
+// a transient error are added to the dead-letter queue for later redelivery. The result is
+// recorded in the NotificationsHealthy condition and the per-target delivery statistics.
+func (r *RemediationPolicyReconciler) deliverNotification(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy, delivery notificationDelivery) error {
+	logger := logf.FromContext(ctx)
+
+	err := r.sendNotificationDelivery(ctx, policy, delivery)
+
+	outcome := deliveryDelivered
+	if err != nil {
+		outcome = deliveryFailed
+		if isRetryableDeliveryError(err) && r.DeadLetterQueue != nil {
+			dropped, queueErr := r.DeadLetterQueue.Enqueue(ctx, policy, delivery, err)
+			if queueErr != nil {
+				logger.Error(queueErr, "failed to add notification to dead-letter queue", "channel", delivery.Channel)
+			} else {
+				outcome = deliveryQueued
+				logger.Info("📥 Notification queued for redelivery",
+					"channel", delivery.Channel,
+					"error", err.Error())
+				r.recordDroppedDeadLetters(ctx, policy, dropped)
+			}
+		}
+	}
+
+	if statusErr := r.recordNotificationDelivery(ctx, policy, delivery.Channel, outcome, err); statusErr != nil {
+		logger.Error(statusErr, "failed to record notification delivery", "channel", delivery.Channel)
+	}
+	return err
+}
+
+// sendNotificationDelivery resolves the delivery target and sends the notification
+func (r *RemediationPolicyReconciler) sendNotificationDelivery(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy, delivery notificationDelivery) error {
+	if delivery.NotificationChannel != "" {
+		return sendToNotificationChannel(ctx, r.Client, r.HttpClient, policy.Namespace, delivery.NotificationChannel,
+			func() SlackMessage { return derefSlackMessage(delivery.SlackMessage) },
+			func() GoogleChatMessage { return derefGoogleChatMessage(delivery.GoogleChatMessage) },
+		)
+	}
+
+	// Route targets carry their own TLS and proxy settings; inline targets read them from the policy
+	switch delivery.Service {
+	case notificationChannelSlack:
+		plainUrl, secretRef, tlsConfig, proxyURL := "", delivery.SecretRef, delivery.TLS, delivery.ProxyURL
+		if secretRef == nil {
+			slack := policy.Spec.Notifications.Slack
+			plainUrl, secretRef, tlsConfig, proxyURL = slack.WebhookUrl, slack.WebhookUrlSecretRef, slack.TLS, slack.ProxyURL
+		}
+		httpClient, err := webhookHTTPClient(ctx, r.Client, policy.Namespace, tlsConfig, proxyURL, r.HttpClient)
+		if err != nil {
+			return fmt.Errorf("invalid Slack TLS settings: %w", err)
+		}
+		webhookUrl, err := r.resolveWebhookUrl(ctx, policy.Namespace, plainUrl, secretRef, "Slack")
+		if err != nil {
+			return fmt.Errorf("failed to resolve Slack webhook URL: %w", err)
+		}
+		return postSlackWebhook(ctx, httpClient, webhookUrl, derefSlackMessage(delivery.SlackMessage))
+	case notificationChannelGoogleChat:
+		plainUrl, secretRef, tlsConfig, proxyURL := "", delivery.SecretRef, delivery.TLS, delivery.ProxyURL
+		if secretRef == nil {
+			googleChat := policy.Spec.Notifications.GoogleChat
+			plainUrl, secretRef, tlsConfig, proxyURL = googleChat.WebhookUrl, googleChat.WebhookUrlSecretRef, googleChat.TLS, googleChat.ProxyURL
+		}
+		httpClient, err := webhookHTTPClient(ctx, r.Client, policy.Namespace, tlsConfig, proxyURL, r.HttpClient)
+		if err != nil {
+			return fmt.Errorf("invalid Google Chat TLS settings: %w", err)
+		}
+		webhookUrl, err := r.resolveWebhookUrl(ctx, policy.Namespace, plainUrl, secretRef, "Google Chat")
+		if err != nil {
+			return fmt.Errorf("failed to resolve Google Chat webhook URL: %w", err)
+		}
+		return postGoogleChatWebhook(ctx, httpClient, webhookUrl, derefGoogleChatMessage(delivery.GoogleChatMessage))
+	default:
+		return fmt.Errorf("unknown notification service: %s", delivery.Service)
+	}
+}
+
+// recordNotificationDelivery updates the NotificationsHealthy condition and the delivery
+// statistics of a notification target in a single status update, retrying on conflicts
+func (r *RemediationPolicyReconciler) recordNotificationDelivery(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy, channel string, outcome deliveryOutcome, notificationError error) error {
+	notificationDeliveries.WithLabelValues(policy.Namespace, policy.Name, channel, deliveryOutcomeResults[outcome]).Inc()
+
+	var lastErr error
+	for attempt := 0; attempt < maxDeliveryStatusRetries; attempt++ {
+		fresh := &dotaiv1alpha1.RemediationPolicy{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(policy), fresh); err != nil {
+			return fmt.Errorf("failed to fetch fresh policy: %w", err)
+		}
+
+		// Requeued redeliveries do not change the health condition on every retry
+		if outcome != deliveryRequeued {
+			setNotificationHealthCondition(fresh, notificationError)
+		}
+		applyDeliveryOutcome(deliveryStatusFor(&fresh.Status, channel), outcome, notificationError)
+
+		if err := r.Status().Update(ctx, fresh); err != nil {
+			if apierrors.IsConflict(err) {
+				lastErr = err
+				continue
+			}
+			return fmt.Errorf("failed to update notification delivery status: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("failed to update notification delivery status after %d attempts: %w", maxDeliveryStatusRetries, lastErr)
+}
+
+// deliveryStatusFor returns the delivery statistics of a notification target, adding them if missing
+func deliveryStatusFor(status *dotaiv1alpha1.RemediationPolicyStatus, channel string) *dotaiv1alpha1.NotificationDeliveryStatus {
+	for i := range status.NotificationDelivery {
+		if status.NotificationDelivery[i].Channel == channel {
+			return &status.NotificationDelivery[i]
+		}
+	}
+	status.NotificationDelivery = append(status.NotificationDelivery, dotaiv1alpha1.NotificationDeliveryStatus{Channel: channel})
+	return &status.NotificationDelivery[len(status.NotificationDelivery)-1]
+}
+
+// applyDeliveryOutcome updates delivery statistics with the outcome of a delivery
+func applyDeliveryOutcome(stats *dotaiv1alpha1.NotificationDeliveryStatus, outcome deliveryOutcome, notificationError error) {
+	switch outcome {
+	case deliveryDelivered:
+		stats.Delivered++
+	case deliveryFailed:
+		stats.Failed++
+	case deliveryQueued:
+		stats.Pending++
+	case deliveryRedelivered:
+		stats.Delivered++
+		stats.Pending--
+	case deliveryDropped:
+		stats.Failed++
+		stats.Pending--
+	}
+	if stats.Pending < 0 {
+		stats.Pending = 0
+	}
+
+	if notificationError != nil {
+		stats.LastError = truncateStatusNotificationMessage(notificationError.Error())
+		return
+	}
+	now := metav1.NewTime(time.Now())
+	stats.LastDeliveryTime = &now
+	stats.LastError = ""
+}
+
+// derefSlackMessage returns the Slack message or an empty message
+func derefSlackMessage(message *SlackMessage) SlackMessage {
+	if message == nil {
+		return SlackMessage{}
+	}
+	return *message
+}
+
+// derefGoogleChatMessage returns the Google Chat message or an empty message
+func derefGoogleChatMessage(message *GoogleChatMessage) GoogleChatMessage {
+	if message == nil {
+		return GoogleChatMessage{}
+	}
+	return *message
+}
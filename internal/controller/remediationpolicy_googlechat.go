@@ -4,13 +4,12 @@
 package controller
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"html"
-	"io"
 	"net/http"
+	"net/url"
 
 	corev1 "k8s.io/api/core/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -21,6 +20,12 @@ import (
 // GoogleChatMessage represents the structure of a Google Chat webhook message using Card v2 API
 type GoogleChatMessage struct {
 	CardsV2 []GoogleChatCardV2 `json:"cardsV2,omitempty"`
+	Thread  *GoogleChatThread  `json:"thread,omitempty"`
+}
+
+// GoogleChatThread groups messages with the same thread key into a single thread
+type GoogleChatThread struct {
+	ThreadKey string `json:"threadKey,omitempty"`
 }
 
 // GoogleChatCardV2 represents a Card v2 structure
@@ -96,47 +101,21 @@ func (r *RemediationPolicyReconciler) sendGoogleChatNotification(ctx context.Con
 		return nil
 	}
 
-	// Resolve webhook URL from Secret or plain text
-	webhookUrl, err := r.resolveWebhookUrl(
-		ctx,
-		policy.Namespace,
-		policy.Spec.Notifications.GoogleChat.WebhookUrl,
-		policy.Spec.Notifications.GoogleChat.WebhookUrlSecretRef,
-		"Google Chat",
-	)
-	if err != nil {
-		logger.Error(err, "failed to resolve Google Chat webhook URL")
-		// Update notification health condition
-		if updateErr := r.updateNotificationHealthCondition(ctx, policy, err); updateErr != nil {
-			logger.Error(updateErr, "failed to update notification health condition")
-		}
-		return fmt.Errorf("failed to resolve Google Chat webhook URL: %w", err)
-	}
-	if webhookUrl == "" {
-		logger.V(1).Info("Google Chat webhook URL not configured, skipping notification")
+	// Buffer notification for a summary message if batching is enabled
+	if !r.batchNotification(ctx, policy, event, notificationChannelGoogleChat, notificationType, mcpResponse) {
 		return nil
 	}
 
 	// Create Google Chat message
 	message := r.createGoogleChatMessage(policy, event, notificationType, mcpRequest, mcpResponse)
 
-	// Send the message
-	if err := r.sendGoogleChatWebhook(ctx, webhookUrl, message); err != nil {
+	// Send the message, queueing it for redelivery on transient failures
+	if err := r.deliverNotification(ctx, policy, newGoogleChatDelivery(message)); err != nil {
 		logger.Error(err, "failed to send Google Chat notification",
 			"notificationType", notificationType)
-		// Update notification health condition with HTTP error
-		if updateErr := r.updateNotificationHealthCondition(ctx, policy, err); updateErr != nil {
-			logger.Error(updateErr, "failed to update notification health condition")
-		}
 		return err
 	}
 
-	// Update notification health condition - success
-	if err := r.updateNotificationHealthCondition(ctx, policy, nil); err != nil {
-		logger.Error(err, "failed to update notification health condition")
-		// Don't fail notification on status update error
-	}
-
 	logger.Info("💬 Google Chat notification sent successfully",
 		"notificationType", notificationType)
 	return nil
@@ -337,11 +316,7 @@ func (r *RemediationPolicyReconciler) createGoogleChatCompleteSections(policy *d
 // createGoogleChatMcpDetailSections extracts detailed information from MCP response
 func (r *RemediationPolicyReconciler) createGoogleChatMcpDetailSections(mcpResponse *McpResponse) []GoogleChatSection {
 	sections := []GoogleChatSection{}
-	executed := r.getMcpExecutedStatus(mcpResponse)
-
-	if mcpResponse.Data != nil && mcpResponse.Data.Result != nil {
-		result := mcpResponse.Data.Result
-
+	if result := mcpResponse.RemediationResult(); result != nil {
 		// Execution time and confidence
 		var metaWidgets []GoogleChatWidget
 		if mcpResponse.Data.ExecutionTime > 0 {
@@ -355,11 +330,11 @@ func (r *RemediationPolicyReconciler) createGoogleChatMcpDetailSections(mcpRespo
 			})
 		}
 
-		if confidence, ok := result["confidence"].(float64); ok {
+		if result.Confidence != nil {
 			metaWidgets = append(metaWidgets, GoogleChatWidget{
 				DecoratedText: &GoogleChatDecoratedText{
 					TopLabel: "Confidence",
-					Text:     fmt.Sprintf("%.0f%%", confidence*100),
+					Text:     fmt.Sprintf("%.0f%%", *result.Confidence*100),
 					Icon:     &GoogleChatIcon{KnownIcon: "CONFIRMATION_NUMBER_ICON"},
 				},
 			})
@@ -373,99 +348,91 @@ func (r *RemediationPolicyReconciler) createGoogleChatMcpDetailSections(mcpRespo
 		}
 
 		// Root cause analysis
-		if analysis, ok := result["analysis"].(map[string]interface{}); ok {
-			var analysisWidgets []GoogleChatWidget
-			if rootCause, ok := analysis["rootCause"].(string); ok && rootCause != "" {
-				analysisWidgets = append(analysisWidgets, GoogleChatWidget{
-					TextParagraph: &GoogleChatTextParagraph{
-						Text: fmt.Sprintf("<b>Root Cause:</b> %s", html.EscapeString(rootCause)),
-					},
-				})
-			}
-			if confLevel, ok := analysis["confidence"].(float64); ok {
-				analysisWidgets = append(analysisWidgets, GoogleChatWidget{
-					DecoratedText: &GoogleChatDecoratedText{
-						TopLabel: "Analysis Confidence",
-						Text:     fmt.Sprintf("%.0f%%", confLevel*100),
-					},
-				})
-			}
-			if len(analysisWidgets) > 0 {
-				sections = append(sections, GoogleChatSection{
-					Header:  "Analysis",
-					Widgets: analysisWidgets,
-				})
-			}
+		var analysisWidgets []GoogleChatWidget
+		if result.RootCause != "" {
+			analysisWidgets = append(analysisWidgets, GoogleChatWidget{
+				TextParagraph: &GoogleChatTextParagraph{
+					Text: fmt.Sprintf("<b>Root Cause:</b> %s", html.EscapeString(result.RootCause)),
+				},
+			})
+		}
+		if result.AnalysisConfidence != nil {
+			analysisWidgets = append(analysisWidgets, GoogleChatWidget{
+				DecoratedText: &GoogleChatDecoratedText{
+					TopLabel: "Analysis Confidence",
+					Text:     fmt.Sprintf("%.0f%%", *result.AnalysisConfidence*100),
+				},
+			})
+		}
+		if len(analysisWidgets) > 0 {
+			sections = append(sections, GoogleChatSection{
+				Header:  "Analysis",
+				Widgets: analysisWidgets,
+			})
 		}
 
 		// Remediation commands
-		if remediation, ok := result["remediation"].(map[string]interface{}); ok {
-			if actions, ok := remediation["actions"].([]interface{}); ok && len(actions) > 0 {
-				commandsTitle := "Commands Executed"
-				if !executed {
-					commandsTitle = "Recommended Commands"
-				}
+		if len(result.Actions) > 0 {
+			commandsTitle := "Commands Executed"
+			if !result.Executed {
+				commandsTitle = "Recommended Commands"
+			}
 
-				var cmdWidgets []GoogleChatWidget
-				for i, action := range actions {
-					// Limit to 10 commands
-					if i >= 10 {
-						cmdWidgets = append(cmdWidgets, GoogleChatWidget{
-							TextParagraph: &GoogleChatTextParagraph{
-								Text: fmt.Sprintf("<i>... and %d more commands</i>", len(actions)-10),
-							},
-						})
-						break
-					}
-
-					if actionMap, ok := action.(map[string]interface{}); ok {
-						if cmd, ok := actionMap["command"].(string); ok && cmd != "" {
-							cmdWidgets = append(cmdWidgets, GoogleChatWidget{
-								TextParagraph: &GoogleChatTextParagraph{
-									Text: fmt.Sprintf("<code>%s</code>", html.EscapeString(cmd)),
-								},
-							})
-						}
-					}
+			var cmdWidgets []GoogleChatWidget
+			for i, action := range result.Actions {
+				// Limit to 10 commands
+				if i >= 10 {
+					cmdWidgets = append(cmdWidgets, GoogleChatWidget{
+						TextParagraph: &GoogleChatTextParagraph{
+							Text: fmt.Sprintf("<i>... and %d more commands</i>", len(result.Actions)-10),
+						},
+					})
+					break
 				}
 
-				if len(cmdWidgets) > 0 {
-					sections = append(sections, GoogleChatSection{
-						Header:  commandsTitle,
-						Widgets: cmdWidgets,
+				if action.Command != "" {
+					cmdWidgets = append(cmdWidgets, GoogleChatWidget{
+						TextParagraph: &GoogleChatTextParagraph{
+							Text: fmt.Sprintf("<code>%s</code>", html.EscapeString(action.Command)),
+						},
 					})
 				}
 			}
+
+			if len(cmdWidgets) > 0 {
+				sections = append(sections, GoogleChatSection{
+					Header:  commandsTitle,
+					Widgets: cmdWidgets,
+				})
+			}
 		}
 
 		// Validation results
-		if validation, ok := result["validation"].(map[string]interface{}); ok {
-			if success, ok := validation["success"].(bool); ok {
-				status := "❌ Failed"
-				if success {
-					status = "✅ Passed"
-				}
-				sections = append(sections, GoogleChatSection{
-					Header: "Validation",
-					Widgets: []GoogleChatWidget{
-						{
-							TextParagraph: &GoogleChatTextParagraph{
-								Text: status,
-							},
+		if result.Validation != nil {
+			status := "❌ Failed"
+			if result.Validation.Success {
+				status = "✅ Passed"
+			}
+			sections = append(sections, GoogleChatSection{
+				Header: "Validation",
+				Widgets: []GoogleChatWidget{
+					{
+						TextParagraph: &GoogleChatTextParagraph{
+							Text: status,
 						},
 					},
-				})
-			}
+				},
+			})
 		}
 
 		// Action count summary
-		if results, ok := result["results"].([]interface{}); ok && len(results) > 0 {
+		if result.ActionsTaken > 0 {
 			sections = append(sections, GoogleChatSection{
 				Widgets: []GoogleChatWidget{
 					{
 						DecoratedText: &GoogleChatDecoratedText{
 							TopLabel: "Actions Taken",
-							Text:     fmt.Sprintf("%d remediation actions", len(results)),
+							Text:     fmt.Sprintf("%d remediation actions", result.ActionsTaken),
 							Icon:     &GoogleChatIcon{KnownIcon: "STAR"},
 						},
 					},
@@ -506,40 +473,26 @@ func (r *RemediationPolicyReconciler) createGoogleChatMcpDetailSections(mcpRespo
 	return sections
 }
 
-// sendGoogleChatWebhook sends the actual HTTP request to Google Chat webhook
-func (r *RemediationPolicyReconciler) sendGoogleChatWebhook(ctx context.Context, webhookUrl string, message GoogleChatMessage) error {
-	logger := logf.FromContext(ctx)
-
+// postGoogleChatWebhook posts a Google Chat message to a webhook URL
+// It is shared by all controllers that send Google Chat notifications and retries transient failures
+func postGoogleChatWebhook(ctx context.Context, httpClient *http.Client, webhookUrl string, message GoogleChatMessage) error {
 	// Marshal message to JSON
 	payload, err := json.Marshal(message)
 	if err != nil {
 		return fmt.Errorf("failed to marshal Google Chat message: %w", err)
 	}
 
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "POST", webhookUrl, bytes.NewBuffer(payload))
-	if err != nil {
-		return fmt.Errorf("failed to create Google Chat webhook request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	// Send request with timeout
-	response, err := r.HttpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send Google Chat webhook: %w", err)
-	}
-	defer response.Body.Close()
-
-	// Check response
-	if response.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(response.Body)
-		return fmt.Errorf("Google Chat webhook returned status %d: %s", response.StatusCode, string(body))
+	// Threaded messages reply to the thread with the same key, or start it
+	if message.Thread != nil && message.Thread.ThreadKey != "" {
+		parsed, err := url.Parse(webhookUrl)
+		if err != nil {
+			return fmt.Errorf("invalid Google Chat webhook URL: %w", err)
+		}
+		query := parsed.Query()
+		query.Set("messageReplyOption", "REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD")
+		parsed.RawQuery = query.Encode()
+		webhookUrl = parsed.String()
 	}
 
-	logger.V(1).Info("Google Chat webhook sent successfully",
-		"statusCode", response.StatusCode,
-		"payloadSize", len(payload))
-
-	return nil
+	return postWebhook(ctx, httpClient, webhookUrl, "Google Chat", payload)
 }
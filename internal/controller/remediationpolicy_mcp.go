@@ -4,20 +4,21 @@
 package controller
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+	"github.com/vfarcic/dot-ai-controller/internal/mcp"
+	"github.com/vfarcic/dot-ai-controller/internal/tracing"
 )
 
 // McpResponse represents the response from MCP remediate endpoint
@@ -39,20 +40,30 @@ type McpResponse struct {
 		RequestId string `json:"requestId"`
 		Version   string `json:"version"`
 	} `json:"meta,omitempty"`
+
+	// remediationResult caches the typed result decoded from Data.Result
+	remediationResult *dotaiv1alpha1.RemediationResult
+}
+
+// RemediationResult returns the typed remediation result, or nil when the response has no result data.
+// The raw result is decoded on first use and cached.
+func (r *McpResponse) RemediationResult() *dotaiv1alpha1.RemediationResult {
+	if r == nil || r.Data == nil || r.Data.Result == nil {
+		return nil
+	}
+	if r.remediationResult == nil {
+		result := decodeRemediationResult(r.Data.Result)
+		r.remediationResult = &result
+	}
+	return r.remediationResult
 }
 
 // GetResultMessage extracts a meaningful message from the MCP response
 func (r *McpResponse) GetResultMessage() string {
-	if r.Data != nil && r.Data.Result != nil {
-		// Try to extract a message from the result data
-		if msg, ok := r.Data.Result["message"].(string); ok && msg != "" {
-			return msg
-		}
-		if msg, ok := r.Data.Result["summary"].(string); ok && msg != "" {
-			return msg
-		}
-		if msg, ok := r.Data.Result["output"].(string); ok && msg != "" {
-			return msg
+	if result := r.RemediationResult(); result != nil {
+		// The typed message is taken from the message, summary or output of the result
+		if result.Message != "" {
+			return result.Message
 		}
 		// If no specific message field, return a generic success message with execution time
 		if r.Data.ExecutionTime > 0 {
@@ -211,9 +222,55 @@ func (r *RemediationPolicyReconciler) getMcpAuthToken(ctx context.Context, polic
 	return string(tokenBytes), nil
 }
 
-// sendMcpRequest sends MCP request to the specified endpoint (single attempt, no retries)
-func (r *RemediationPolicyReconciler) sendMcpRequest(ctx context.Context, mcpRequest *dotaiv1alpha1.McpRequest, endpoint string, authToken string) (*McpResponse, error) {
+// getMcpConnection resolves the MCP endpoint and credentials of a policy, either from the referenced
+// MCPServer or from the inline settings. The inline bearer mode (default) reads the static token from
+// the auth Secret; other modes are resolved by internal/mcp.
+func (r *RemediationPolicyReconciler) getMcpConnection(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy) (*mcpConnection, error) {
+	if policy.Spec.McpServerRef != "" {
+		connection, err := resolveMCPServerConnection(ctx, r.Client, policy.Spec.McpServerRef)
+		if err != nil {
+			return nil, err
+		}
+		path := "/api/v1/tools/" + getMcpTool(policy)
+		if policy.Spec.McpProtocol == mcpProtocolJsonRpc {
+			path = "/mcp"
+		}
+		connection.Endpoint = connection.URL(path)
+		return connection, nil
+	}
+	if policy.Spec.McpEndpoint == "" {
+		return nil, fmt.Errorf("either mcpEndpoint or mcpServerRef is required")
+	}
+
+	settings, err := mcp.ResolveTLSSettings(ctx, r.Client, policy.Namespace, policy.Spec.McpTLS, policy.Spec.McpProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MCP TLS settings: %w", err)
+	}
+	if policy.Spec.McpAuth.GetMode() != dotaiv1alpha1.McpAuthModeBearer {
+		credentials, err := mcp.ResolveEndpointCredentials(ctx, r.Client, policy.Namespace, policy.Spec.McpAuthSecretRef, policy.Spec.McpAuth, settings)
+		if err != nil {
+			return nil, err
+		}
+		return &mcpConnection{Endpoint: policy.Spec.McpEndpoint, Credentials: credentials}, nil
+	}
+
+	token, err := r.getMcpAuthToken(ctx, policy)
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := mcp.Credentials{Token: token}.WithTLS(settings)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MCP TLS settings: %w", err)
+	}
+	return &mcpConnection{Endpoint: policy.Spec.McpEndpoint, Credentials: credentials}, nil
+}
+
+// sendMcpRequest sends MCP request to the specified endpoint (single attempt unless remediation retries are configured)
+func (r *RemediationPolicyReconciler) sendMcpRequest(ctx context.Context, mcpRequest *dotaiv1alpha1.McpRequest, connection *mcpConnection) (_ *McpResponse, err error) {
+	ctx, span := tracing.Start(ctx, "RemediationPolicy.sendMcpRequest", attribute.String("remediation.mode", mcpRequest.Mode))
+	defer func() { tracing.End(span, err) }()
 	logger := logf.FromContext(ctx)
+	endpoint := connection.Endpoint
 
 	startTime := time.Now()
 	logger.Info("🚀 Starting MCP request", "endpoint", endpoint, "startTime", startTime.Format(time.RFC3339Nano))
@@ -226,56 +283,44 @@ func (r *RemediationPolicyReconciler) sendMcpRequest(ctx context.Context, mcpReq
 
 	logger.Info("📄 MCP request prepared", "contentLength", len(requestBody), "requestBody", string(requestBody))
 
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(requestBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
-	}
-
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json, text/event-stream")
-	req.Header.Set("User-Agent", "dot-ai-controller/v1.0.0")
-
-	// Add Authorization header if auth token is configured
-	if authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+authToken)
+	logger.Info("🌐 Sending HTTP request", "method", "POST", "endpoint", endpoint)
+	if connection.Credentials.Token != "" {
 		logger.V(1).Info("Authorization header set for MCP request")
 	}
 
-	logger.Info("🌐 Sending HTTP request", "method", "POST", "endpoint", endpoint)
-
-	// Make the HTTP request
-	resp, err := r.HttpClient.Do(req)
-	requestDuration := time.Since(startTime)
+	// Remediation requests are not retried unless configured, because they are long-running
+	// and not idempotent; the idempotency key lets the MCP server detect retried requests
+	mcpClient := mcp.NewClient(mcp.Config{
+		Component:  "remediation",
+		HTTPClient: connection.HTTPClient(r.HttpClient),
+		Retry:      mcp.RemediationRetryPolicy(),
+	})
+	resp, err := mcpClient.Do(ctx, mcp.Request{
+		URL:    endpoint,
+		Body:   json.RawMessage(requestBody),
+		Token:  connection.Credentials.Token,
+		Accept: "application/json, text/event-stream",
+	})
+	totalDuration := time.Since(startTime)
 
-	if err != nil {
-		logger.Error(err, "❌ HTTP request failed", "duration", requestDuration, "error", err.Error())
-		return nil, fmt.Errorf("HTTP request failed after %v: %w", requestDuration, err)
+	var statusErr *mcp.StatusError
+	if err != nil && !errors.As(err, &statusErr) {
+		logger.Error(err, "❌ HTTP request failed", "duration", totalDuration, "error", err.Error())
+		return nil, fmt.Errorf("HTTP request failed after %v: %w", totalDuration, err)
 	}
 
+	responseBody := resp.Body
 	logger.Info("📡 HTTP response received",
 		"statusCode", resp.StatusCode,
-		"duration", requestDuration,
+		"duration", totalDuration,
+		"attempts", resp.Attempts,
+		"requestId", resp.RequestID,
 		"contentType", resp.Header.Get("Content-Type"),
-		"contentLength", resp.Header.Get("Content-Length"))
-
-	// Read response body
-	defer resp.Body.Close()
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		logger.Error(err, "❌ Failed to read response body", "duration", requestDuration)
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	totalDuration := time.Since(startTime)
-	logger.Info("📄 Response body read",
 		"bodyLength", len(responseBody),
-		"totalDuration", totalDuration,
 		"responseBody", string(responseBody))
 
 	// Check HTTP status
-	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+	if statusErr != nil || resp.StatusCode < 200 {
 		logger.Error(nil, "❌ HTTP error status",
 			"statusCode", resp.StatusCode,
 			"responseBody", string(responseBody),
@@ -316,6 +361,9 @@ func (r *RemediationPolicyReconciler) sendMcpRequest(ctx context.Context, mcpReq
 		}, nil
 	}
 
+	// Decode the result once, before it is shared by notifications and status updates
+	mcpResponse.RemediationResult()
+
 	// Return response
 	logger.Info("✅ MCP request completed",
 		"success", mcpResponse.Success,
@@ -325,3 +373,128 @@ func (r *RemediationPolicyReconciler) sendMcpRequest(ctx context.Context, mcpReq
 
 	return &mcpResponse, nil
 }
+
+// callMcpRemediate sends the MCP request using the protocol configured in the policy.
+// With the JSON-RPC protocol, progress notifications are forwarded to the policy status
+// and, when enabled, to progress notifications.
+func (r *RemediationPolicyReconciler) callMcpRemediate(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy, event *corev1.Event, mcpRequest *dotaiv1alpha1.McpRequest, connection *mcpConnection) (*McpResponse, error) {
+	if policy.Spec.McpProtocol != mcpProtocolJsonRpc {
+		return r.sendMcpRequest(ctx, mcpRequest, connection)
+	}
+
+	tracker := r.newRemediationProgressTracker(ctx, policy, event)
+	tracker.Start()
+	defer tracker.Finish()
+
+	return r.sendMcpJsonRpcRequest(ctx, mcpRequest, connection, getMcpTool(policy), tracker.Update)
+}
+
+// getMcpTool returns the MCP tool name for a policy
+func getMcpTool(policy *dotaiv1alpha1.RemediationPolicy) string {
+	if policy.Spec.McpTool == "" {
+		return "remediate"
+	}
+	return policy.Spec.McpTool
+}
+
+// sendMcpJsonRpcRequest calls the MCP tool with JSON-RPC over Streamable HTTP (single attempt, no retries).
+// HTTP, JSON-RPC and tool errors are returned as failed responses; transport errors are returned as errors.
+func (r *RemediationPolicyReconciler) sendMcpJsonRpcRequest(ctx context.Context, mcpRequest *dotaiv1alpha1.McpRequest, connection *mcpConnection, tool string, onProgress func(McpProgress)) (_ *McpResponse, err error) {
+	ctx, span := tracing.Start(ctx, "RemediationPolicy.sendMcpJsonRpcRequest",
+		attribute.String("remediation.mode", mcpRequest.Mode),
+		attribute.String("mcp.tool", tool),
+	)
+	defer func() { tracing.End(span, err) }()
+	logger := logf.FromContext(ctx)
+	endpoint := connection.Endpoint
+
+	startTime := time.Now()
+	logger.Info("🚀 Starting MCP JSON-RPC tool call", "endpoint", endpoint, "tool", tool)
+
+	mcpClient := NewMcpJsonRpcClient(connection.HTTPClient(r.HttpClient), endpoint, connection.Credentials.Token)
+	defer mcpClient.Close(ctx)
+
+	err = mcpClient.Initialize(ctx)
+	var result *McpToolResult
+	if err == nil {
+		result, err = mcpClient.CallTool(ctx, tool, mcpRequest, onProgress)
+	}
+	totalDuration := time.Since(startTime)
+
+	if err != nil {
+		var httpErr *mcpHttpError
+		if errors.As(err, &httpErr) {
+			logger.Error(nil, "❌ HTTP error status",
+				"statusCode", httpErr.StatusCode,
+				"responseBody", httpErr.Body,
+				"totalDuration", totalDuration)
+			return newMcpErrorResponse(fmt.Sprintf("%d", httpErr.StatusCode), httpErr.Error()), nil
+		}
+		var rpcErr *jsonRpcError
+		if errors.As(err, &rpcErr) {
+			logger.Error(nil, "❌ JSON-RPC error",
+				"code", rpcErr.Code,
+				"message", rpcErr.Message,
+				"totalDuration", totalDuration)
+			return newMcpErrorResponse(fmt.Sprintf("%d", rpcErr.Code), rpcErr.Message), nil
+		}
+		logger.Error(err, "❌ MCP JSON-RPC request failed", "duration", totalDuration)
+		return nil, fmt.Errorf("MCP JSON-RPC request failed after %v: %w", totalDuration, err)
+	}
+
+	mcpResponse := newMcpResponseFromToolResult(tool, result, totalDuration)
+	logger.Info("✅ MCP request completed",
+		"success", mcpResponse.Success,
+		"message", mcpResponse.GetResultMessage(),
+		"error", mcpResponse.GetErrorMessage(),
+		"totalDuration", totalDuration)
+
+	return mcpResponse, nil
+}
+
+// newMcpResponseFromToolResult converts an MCP tool result into the REST-style McpResponse.
+// Structured content is used as the result when present; otherwise text content is parsed
+// as a JSON object and falls back to a plain message.
+func newMcpResponseFromToolResult(tool string, result *McpToolResult, duration time.Duration) *McpResponse {
+	text := result.Text()
+	if result.IsError {
+		return newMcpErrorResponse("TOOL_ERROR", text)
+	}
+
+	data := result.StructuredContent
+	if data == nil {
+		if err := json.Unmarshal([]byte(text), &data); err != nil || data == nil {
+			data = map[string]interface{}{"message": text}
+		}
+	}
+
+	response := &McpResponse{
+		Success: true,
+		Data: &struct {
+			Result        map[string]interface{} `json:"result"`
+			Tool          string                 `json:"tool"`
+			ExecutionTime float64                `json:"executionTime"`
+		}{
+			Result:        data,
+			Tool:          tool,
+			ExecutionTime: float64(duration.Milliseconds()),
+		},
+	}
+	response.RemediationResult()
+	return response
+}
+
+// newMcpErrorResponse creates a failed McpResponse with the given error code and message
+func newMcpErrorResponse(code, message string) *McpResponse {
+	return &McpResponse{
+		Success: false,
+		Error: &struct {
+			Code    string                 `json:"code"`
+			Message string                 `json:"message"`
+			Details map[string]interface{} `json:"details,omitempty"`
+		}{
+			Code:    code,
+			Message: message,
+		},
+	}
+}
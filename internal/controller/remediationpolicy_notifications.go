@@ -169,6 +169,18 @@ func (r *RemediationPolicyReconciler) updateNotificationHealthCondition(ctx cont
 		return fmt.Errorf("failed to fetch fresh policy: %w", err)
 	}
 
+	setNotificationHealthCondition(fresh, notificationError)
+
+	// Update status subresource
+	if err := r.Status().Update(ctx, fresh); err != nil {
+		return fmt.Errorf("failed to update notification health condition: %w", err)
+	}
+
+	return nil
+}
+
+// setNotificationHealthCondition sets the NotificationsHealthy condition on a policy based on notification errors
+func setNotificationHealthCondition(policy *dotaiv1alpha1.RemediationPolicy, notificationError error) {
 	now := metav1.NewTime(time.Now())
 	var healthCondition metav1.Condition
 
@@ -194,28 +206,21 @@ func (r *RemediationPolicyReconciler) updateNotificationHealthCondition(ctx cont
 
 	// Find and update existing condition or append new one
 	updated := false
-	for i, condition := range fresh.Status.Conditions {
+	for i, condition := range policy.Status.Conditions {
 		if condition.Type == "NotificationsHealthy" {
 			// Only update if status changed (to preserve LastTransitionTime)
 			if condition.Status != healthCondition.Status {
-				fresh.Status.Conditions[i] = healthCondition
+				policy.Status.Conditions[i] = healthCondition
 				updated = true
 			} else {
 				// Update message but keep transition time
-				fresh.Status.Conditions[i].Message = healthCondition.Message
+				policy.Status.Conditions[i].Message = healthCondition.Message
 				updated = true
 			}
 			break
 		}
 	}
 	if !updated {
-		fresh.Status.Conditions = append(fresh.Status.Conditions, healthCondition)
-	}
-
-	// Update status subresource
-	if err := r.Status().Update(ctx, fresh); err != nil {
-		return fmt.Errorf("failed to update notification health condition: %w", err)
+		policy.Status.Conditions = append(policy.Status.Conditions, healthCondition)
 	}
-
-	return nil
 }
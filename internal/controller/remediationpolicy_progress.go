@@ -0,0 +1,235 @@
+// remediationpolicy_progress.go forwards progress notifications reported by the MCP server
+// during a JSON-RPC tool call into the RemediationPolicy status (activeRemediations) and,
+// when notifyOnProgress is enabled, into Slack and Google Chat progress messages.
+// Status updates and notifications are throttled so that chatty servers cannot flood
+// the API server or chat channels.
+package controller
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+const (
+	// remediationProgressStatusInterval is the minimum interval between progress status updates
+	remediationProgressStatusInterval = 5 * time.Second
+
+	// remediationProgressNotifyInterval is the minimum interval between progress notifications
+	remediationProgressNotifyInterval = 30 * time.Second
+)
+
+// remediationProgressTracker tracks a single remediation while the MCP tool call runs
+type remediationProgressTracker struct {
+	r         *RemediationPolicyReconciler
+	ctx       context.Context
+	policy    *dotaiv1alpha1.RemediationPolicy
+	event     *corev1.Event
+	object    string
+	startTime time.Time
+
+	lastStatusUpdate    time.Time
+	lastNotification    time.Time
+	lastNotifiedMessage string
+}
+
+// newRemediationProgressTracker creates a progress tracker for the object of an event
+func (r *RemediationPolicyReconciler) newRemediationProgressTracker(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy, event *corev1.Event) *remediationProgressTracker {
+	return &remediationProgressTracker{
+		r:      r,
+		ctx:    ctx,
+		policy: policy,
+		event:  event,
+		object: fmt.Sprintf("%s/%s/%s",
+			event.InvolvedObject.Kind, event.InvolvedObject.Namespace, event.InvolvedObject.Name),
+		startTime: time.Now(),
+	}
+}
+
+// Start records the remediation as active in the policy status
+func (t *remediationProgressTracker) Start() {
+	start := metav1.NewTime(t.startTime)
+	t.updateStatus(func(remediations []dotaiv1alpha1.RemediationProgress) []dotaiv1alpha1.RemediationProgress {
+		remediations = removeRemediationProgress(remediations, t.object)
+		return append(remediations, dotaiv1alpha1.RemediationProgress{
+			Object:         t.object,
+			StartTime:      &start,
+			LastUpdateTime: &start,
+		})
+	})
+}
+
+// Update forwards a progress notification to the status and notification channels
+func (t *remediationProgressTracker) Update(progress McpProgress) {
+	logf.FromContext(t.ctx).V(1).Info("MCP progress",
+		"object", t.object,
+		"progress", progress.Progress,
+		"message", progress.Message)
+
+	now := time.Now()
+	if now.Sub(t.lastStatusUpdate) >= remediationProgressStatusInterval {
+		t.lastStatusUpdate = now
+		update := metav1.NewTime(now)
+		t.updateStatus(func(remediations []dotaiv1alpha1.RemediationProgress) []dotaiv1alpha1.RemediationProgress {
+			for i := range remediations {
+				if remediations[i].Object == t.object {
+					remediations[i].Percent = progress.Percent()
+					remediations[i].Message = truncateStatusNotificationMessage(progress.Message)
+					remediations[i].LastUpdateTime = &update
+				}
+			}
+			return remediations
+		})
+	}
+
+	if progress.Message != "" && progress.Message != t.lastNotifiedMessage &&
+		now.Sub(t.lastNotification) >= remediationProgressNotifyInterval {
+		t.lastNotification = now
+		t.lastNotifiedMessage = progress.Message
+		t.notify(progress)
+	}
+}
+
+// Finish removes the remediation from the active remediations in the policy status
+func (t *remediationProgressTracker) Finish() {
+	t.updateStatus(func(remediations []dotaiv1alpha1.RemediationProgress) []dotaiv1alpha1.RemediationProgress {
+		return removeRemediationProgress(remediations, t.object)
+	})
+}
+
+// notify sends a progress notification to the Slack and Google Chat targets that enable it.
+// Progress notifications are informational: they are not queued for redelivery.
+func (t *remediationProgressTracker) notify(progress McpProgress) {
+	logger := logf.FromContext(t.ctx)
+	notifications := t.policy.Spec.Notifications
+
+	if notifications.Slack.Enabled && notifications.Slack.NotifyOnProgress {
+		message := t.r.createSlackProgressMessage(t.policy, t.event, progress)
+		if err := t.r.sendNotificationDelivery(t.ctx, t.policy, newSlackDelivery(message)); err != nil {
+			logger.Error(err, "failed to send Slack progress notification")
+		}
+	}
+	if notifications.GoogleChat.Enabled && notifications.GoogleChat.NotifyOnProgress {
+		message := t.r.createGoogleChatProgressMessage(t.policy, t.event, progress)
+		message.Thread = &GoogleChatThread{ThreadKey: t.threadKey()}
+		if err := t.r.sendNotificationDelivery(t.ctx, t.policy, newGoogleChatDelivery(message)); err != nil {
+			logger.Error(err, "failed to send Google Chat progress notification")
+		}
+	}
+}
+
+// threadKey returns the chat thread key grouping progress messages of this remediation
+func (t *remediationProgressTracker) threadKey() string {
+	return fmt.Sprintf("dot-ai-%s-%s-%d", t.policy.Name, t.event.InvolvedObject.UID, t.startTime.Unix())
+}
+
+// updateStatus applies a change to the active remediations in the policy status, retrying on conflicts.
+// Progress is informational, so errors are logged and never fail remediation.
+func (t *remediationProgressTracker) updateStatus(mutate func([]dotaiv1alpha1.RemediationProgress) []dotaiv1alpha1.RemediationProgress) {
+	logger := logf.FromContext(t.ctx)
+
+	for attempt := 0; attempt < maxDeliveryStatusRetries; attempt++ {
+		fresh := &dotaiv1alpha1.RemediationPolicy{}
+		if err := t.r.Get(t.ctx, client.ObjectKeyFromObject(t.policy), fresh); err != nil {
+			logger.Error(err, "failed to fetch policy for progress update")
+			return
+		}
+
+		fresh.Status.ActiveRemediations = mutate(fresh.Status.ActiveRemediations)
+		if err := t.r.Status().Update(t.ctx, fresh); err != nil {
+			if apierrors.IsConflict(err) {
+				continue
+			}
+			logger.Error(err, "failed to update remediation progress")
+			return
+		}
+		return
+	}
+	logger.Info("⚠️ Gave up updating remediation progress after repeated conflicts", "object", t.object)
+}
+
+// removeRemediationProgress removes the entry for an object from the active remediations
+func removeRemediationProgress(remediations []dotaiv1alpha1.RemediationProgress, object string) []dotaiv1alpha1.RemediationProgress {
+	filtered := remediations[:0]
+	for _, remediation := range remediations {
+		if remediation.Object != object {
+			filtered = append(filtered, remediation)
+		}
+	}
+	return filtered
+}
+
+// formatProgress returns a human-readable progress description
+func formatProgress(progress McpProgress) string {
+	message := progress.Message
+	if message == "" {
+		message = "Remediation in progress"
+	}
+	if percent := progress.Percent(); percent != nil {
+		return fmt.Sprintf("%s (%d%%)", message, *percent)
+	}
+	return message
+}
+
+// createSlackProgressMessage creates a compact Slack message for a progress update
+func (r *RemediationPolicyReconciler) createSlackProgressMessage(policy *dotaiv1alpha1.RemediationPolicy, event *corev1.Event, progress McpProgress) SlackMessage {
+	return SlackMessage{
+		Channel:   policy.Spec.Notifications.Slack.Channel,
+		Username:  "dot-ai-controller",
+		IconEmoji: ":robot_face:",
+		Attachments: []SlackAttachment{
+			{
+				Color: "#1d9bd1", // Blue vertical bar
+				Blocks: []SlackBlock{
+					{
+						Type: "section",
+						Text: &SlackBlockText{
+							Type: "mrkdwn",
+							Text: fmt.Sprintf("⏳ *Remediation in progress:* `%s/%s` in `%s`\n%s",
+								event.InvolvedObject.Kind, event.InvolvedObject.Name, event.InvolvedObject.Namespace,
+								truncateStatusNotificationMessage(formatProgress(progress))),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// createGoogleChatProgressMessage creates a compact Google Chat message for a progress update
+func (r *RemediationPolicyReconciler) createGoogleChatProgressMessage(policy *dotaiv1alpha1.RemediationPolicy, event *corev1.Event, progress McpProgress) GoogleChatMessage {
+	return GoogleChatMessage{
+		CardsV2: []GoogleChatCardV2{
+			{
+				CardId: "remediation-progress",
+				Card: GoogleChatCard{
+					Header: &GoogleChatCardHeader{
+						Title:     "⏳ Remediation in progress",
+						Subtitle:  fmt.Sprintf("%s/%s in %s", event.InvolvedObject.Kind, event.InvolvedObject.Name, event.InvolvedObject.Namespace),
+						ImageType: "CIRCLE",
+					},
+					Sections: []GoogleChatSection{
+						{
+							Widgets: []GoogleChatWidget{
+								{
+									TextParagraph: &GoogleChatTextParagraph{
+										Text: html.EscapeString(truncateStatusNotificationMessage(formatProgress(progress))),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
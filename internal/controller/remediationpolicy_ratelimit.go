@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -17,6 +18,7 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+	"github.com/vfarcic/dot-ai-controller/internal/tracing"
 )
 
 // parseCronJobNameFromPodName attempts to extract a CronJob name from a pod name
@@ -67,6 +69,8 @@ func parseCronJobNameFromPodName(podName string) (string, bool) {
 //   - Pod (no Job owner): returns ("", pod-name)
 //   - Non-Pod resources: returns ("", object-name)
 func (r *RemediationPolicyReconciler) resolveOwnerForRateLimiting(ctx context.Context, involvedObject corev1.ObjectReference) (kind string, name string) {
+	ctx, span := tracing.Start(ctx, "RemediationPolicy.resolveOwner", attribute.String("k8s.object.kind", involvedObject.Kind))
+	defer span.End()
 	logger := logf.FromContext(ctx)
 
 	// Default: use original object name with no kind prefix
@@ -0,0 +1,225 @@
+// remediationpolicy_routing.go contains conditional notification routing for the
+// RemediationPolicy controller. Routes are evaluated after the MCP response is known
+// and send completion notifications to additional Slack or Google Chat targets.
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// riskLevelOrder ranks MCP risk levels for comparison
+var riskLevelOrder = map[string]int{
+	"low":    1,
+	"medium": 2,
+	"high":   3,
+}
+
+// validateNotificationRoutes validates notification route settings
+func (r *RemediationPolicyReconciler) validateNotificationRoutes(policy *dotaiv1alpha1.RemediationPolicy) error {
+	names := make(map[string]bool)
+	for i, route := range policy.Spec.Notifications.Routes {
+		if route.Name == "" {
+			return fmt.Errorf("notification route %d: name cannot be empty", i)
+		}
+		if names[route.Name] {
+			return fmt.Errorf("notification route '%s': duplicate route name", route.Name)
+		}
+		names[route.Name] = true
+
+		if route.Slack == nil && route.GoogleChat == nil && route.ChannelRef == "" {
+			return fmt.Errorf("notification route '%s': at least one of slack, googleChat or channelRef is required", route.Name)
+		}
+		if route.Slack != nil {
+			if route.Slack.WebhookUrlSecretRef.Name == "" || route.Slack.WebhookUrlSecretRef.Key == "" {
+				return fmt.Errorf("notification route '%s': slack webhookUrlSecretRef name and key are required", route.Name)
+			}
+		}
+		if route.GoogleChat != nil {
+			if route.GoogleChat.WebhookUrlSecretRef.Name == "" || route.GoogleChat.WebhookUrlSecretRef.Key == "" {
+				return fmt.Errorf("notification route '%s': googleChat webhookUrlSecretRef name and key are required", route.Name)
+			}
+		}
+
+		for _, pattern := range route.Match.Namespaces {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("notification route '%s': invalid namespace pattern '%s': %w", route.Name, pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// getMcpRiskLevel extracts the risk level of the remediation from the MCP response.
+// The overall remediation risk is preferred; otherwise the highest action risk is used.
+// Returns an empty string when MCP did not report a risk level.
+func (r *RemediationPolicyReconciler) getMcpRiskLevel(mcpResponse *McpResponse) string {
+	result := mcpResponse.RemediationResult()
+	if result == nil {
+		return ""
+	}
+	if result.Risk != "" {
+		return result.Risk
+	}
+	return highestActionRisk(result)
+}
+
+// matchesNotificationRoute checks if a completed remediation matches a route's criteria
+func (r *RemediationPolicyReconciler) matchesNotificationRoute(route dotaiv1alpha1.NotificationRoute, event *corev1.Event, mcpRequest *dotaiv1alpha1.McpRequest, mcpResponse *McpResponse) bool {
+	match := route.Match
+
+	// Check namespace patterns
+	if len(match.Namespaces) > 0 {
+		matched := false
+		for _, pattern := range match.Namespaces {
+			if ok, err := path.Match(pattern, event.InvolvedObject.Namespace); err == nil && ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	// Check outcome
+	if len(match.Outcomes) > 0 {
+		outcome := "failure"
+		if mcpResponse != nil && mcpResponse.Success {
+			outcome = "success"
+		}
+		if !containsString(match.Outcomes, outcome) {
+			return false
+		}
+	}
+
+	// Check mode
+	if len(match.Modes) > 0 && !containsString(match.Modes, mcpRequest.Mode) {
+		return false
+	}
+
+	// Check risk level (unknown risk never matches)
+	if len(match.RiskLevels) > 0 && !containsString(match.RiskLevels, r.getMcpRiskLevel(mcpResponse)) {
+		return false
+	}
+
+	// Check executed vs recommended
+	if match.Executed != nil {
+		executed := mcpResponse != nil && r.getMcpExecutedStatus(mcpResponse)
+		if executed != *match.Executed {
+			return false
+		}
+	}
+
+	// Check event reason
+	if len(match.Reasons) > 0 && !containsString(match.Reasons, event.Reason) {
+		return false
+	}
+
+	return true
+}
+
+// sendRoutedNotifications sends completion notifications to all matching routes.
+// Errors are logged and do not fail event processing.
+func (r *RemediationPolicyReconciler) sendRoutedNotifications(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy, event *corev1.Event, mcpRequest *dotaiv1alpha1.McpRequest, mcpResponse *McpResponse) {
+	logger := logf.FromContext(ctx)
+
+	for _, route := range policy.Spec.Notifications.Routes {
+		if !r.matchesNotificationRoute(route, event, mcpRequest, mcpResponse) {
+			continue
+		}
+
+		logger.Info("🔀 Notification route matched", "route", route.Name)
+
+		if route.Slack != nil {
+			if err := r.sendRouteSlackNotification(ctx, policy, event, route, mcpRequest, mcpResponse); err != nil {
+				logger.Error(err, "failed to send routed Slack notification", "route", route.Name)
+			}
+		}
+		if route.GoogleChat != nil {
+			if err := r.sendRouteGoogleChatNotification(ctx, policy, event, route, mcpRequest, mcpResponse); err != nil {
+				logger.Error(err, "failed to send routed Google Chat notification", "route", route.Name)
+			}
+		}
+
+		if route.ChannelRef != "" {
+			err := r.deliverToNotificationChannel(ctx, policy, route.ChannelRef,
+				func() SlackMessage {
+					return r.createSlackMessage(policy, event, "complete", mcpRequest, mcpResponse)
+				},
+				func() GoogleChatMessage {
+					return r.createGoogleChatMessage(policy, event, "complete", mcpRequest, mcpResponse)
+				},
+			)
+			if err != nil {
+				logger.Error(err, "failed to send routed notification through NotificationChannel", "route", route.Name)
+			}
+		}
+
+		if !route.Continue {
+			break
+		}
+	}
+}
+
+// sendRouteSlackNotification sends a completion notification to a route's Slack target
+func (r *RemediationPolicyReconciler) sendRouteSlackNotification(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy, event *corev1.Event, route dotaiv1alpha1.NotificationRoute, mcpRequest *dotaiv1alpha1.McpRequest, mcpResponse *McpResponse) error {
+	secretRef := route.Slack.WebhookUrlSecretRef
+	message := r.createSlackMessage(policy, event, "complete", mcpRequest, mcpResponse)
+	message.Channel = route.Slack.Channel
+
+	err := r.deliverNotification(ctx, policy, notificationDelivery{
+		Channel:      notificationRouteKey(route.Name, notificationChannelSlack),
+		Service:      notificationChannelSlack,
+		SecretRef:    &secretRef,
+		TLS:          route.Slack.TLS,
+		ProxyURL:     route.Slack.ProxyURL,
+		SlackMessage: &message,
+	})
+	if err != nil {
+		return fmt.Errorf("route '%s': %w", route.Name, err)
+	}
+
+	logf.FromContext(ctx).Info("📱 Routed Slack notification sent successfully",
+		"route", route.Name,
+		"channel", route.Slack.Channel)
+	return nil
+}
+
+// sendRouteGoogleChatNotification sends a completion notification to a route's Google Chat target
+func (r *RemediationPolicyReconciler) sendRouteGoogleChatNotification(ctx context.Context, policy *dotaiv1alpha1.RemediationPolicy, event *corev1.Event, route dotaiv1alpha1.NotificationRoute, mcpRequest *dotaiv1alpha1.McpRequest, mcpResponse *McpResponse) error {
+	secretRef := route.GoogleChat.WebhookUrlSecretRef
+	message := r.createGoogleChatMessage(policy, event, "complete", mcpRequest, mcpResponse)
+
+	err := r.deliverNotification(ctx, policy, notificationDelivery{
+		Channel:           notificationRouteKey(route.Name, notificationChannelGoogleChat),
+		Service:           notificationChannelGoogleChat,
+		SecretRef:         &secretRef,
+		TLS:               route.GoogleChat.TLS,
+		ProxyURL:          route.GoogleChat.ProxyURL,
+		GoogleChatMessage: &message,
+	})
+	if err != nil {
+		return fmt.Errorf("route '%s': %w", route.Name, err)
+	}
+
+	logf.FromContext(ctx).Info("💬 Routed Google Chat notification sent successfully",
+		"route", route.Name)
+	return nil
+}
+
+// containsString checks if a string slice contains a value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
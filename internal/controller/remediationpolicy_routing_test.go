@@ -0,0 +1,356 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+func newRoutingTestEvent(namespace, reason string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: "web.event", Namespace: namespace},
+		Type:       corev1.EventTypeWarning,
+		Reason:     reason,
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Name:      "web",
+			Namespace: namespace,
+		},
+	}
+}
+
+func newRiskMcpResponse(executed bool, risk string) *McpResponse {
+	response := createSuccessfulMcpResponse("done", 1000)
+	response.Data.Result["executed"] = executed
+	response.Data.Result["remediation"] = map[string]interface{}{
+		"actions": []interface{}{
+			map[string]interface{}{"command": "kubectl get pods", "risk": "low"},
+			map[string]interface{}{"command": "kubectl delete pod web", "risk": risk},
+		},
+	}
+	return &response
+}
+
+func TestRemediationPolicyReconciler_GetMcpRiskLevel(t *testing.T) {
+	r := &RemediationPolicyReconciler{}
+
+	assert.Equal(t, "", r.getMcpRiskLevel(nil))
+
+	response := createSuccessfulMcpResponse("done", 1000)
+	assert.Equal(t, "", r.getMcpRiskLevel(&response), "no remediation data means unknown risk")
+
+	assert.Equal(t, "high", r.getMcpRiskLevel(newRiskMcpResponse(true, "high")), "highest action risk is used")
+
+	overall := newRiskMcpResponse(true, "high")
+	overall.Data.Result["remediation"].(map[string]interface{})["risk"] = "medium"
+	assert.Equal(t, "medium", r.getMcpRiskLevel(overall), "overall remediation risk is preferred")
+}
+
+func TestRemediationPolicyReconciler_MatchesNotificationRoute(t *testing.T) {
+	r := &RemediationPolicyReconciler{}
+	failed := createFailedMcpResponse("boom")
+	automatic := &dotaiv1alpha1.McpRequest{Mode: "automatic"}
+	manual := &dotaiv1alpha1.McpRequest{Mode: "manual"}
+
+	tests := []struct {
+		name     string
+		match    dotaiv1alpha1.NotificationRouteMatch
+		event    *corev1.Event
+		request  *dotaiv1alpha1.McpRequest
+		response *McpResponse
+		expected bool
+	}{
+		{
+			name:     "empty criteria match everything",
+			event:    newRoutingTestEvent("dev", "BackOff"),
+			request:  manual,
+			response: &failed,
+			expected: true,
+		},
+		{
+			name:     "prod failures match glob and outcome",
+			match:    dotaiv1alpha1.NotificationRouteMatch{Namespaces: []string{"prod-*"}, Outcomes: []string{"failure"}},
+			event:    newRoutingTestEvent("prod-payments", "BackOff"),
+			request:  manual,
+			response: &failed,
+			expected: true,
+		},
+		{
+			name:     "non-prod namespace does not match glob",
+			match:    dotaiv1alpha1.NotificationRouteMatch{Namespaces: []string{"prod-*"}},
+			event:    newRoutingTestEvent("staging", "BackOff"),
+			request:  manual,
+			response: &failed,
+			expected: false,
+		},
+		{
+			name:     "success does not match failure outcome",
+			match:    dotaiv1alpha1.NotificationRouteMatch{Outcomes: []string{"failure"}},
+			event:    newRoutingTestEvent("prod-payments", "BackOff"),
+			request:  automatic,
+			response: newRiskMcpResponse(true, "low"),
+			expected: false,
+		},
+		{
+			name: "high-risk automatic execution matches",
+			match: dotaiv1alpha1.NotificationRouteMatch{
+				Modes:      []string{"automatic"},
+				RiskLevels: []string{"high"},
+				Executed:   ptr.To(true),
+			},
+			event:    newRoutingTestEvent("dev", "BackOff"),
+			request:  automatic,
+			response: newRiskMcpResponse(true, "high"),
+			expected: true,
+		},
+		{
+			name:     "recommended-only does not match executed",
+			match:    dotaiv1alpha1.NotificationRouteMatch{Executed: ptr.To(true)},
+			event:    newRoutingTestEvent("dev", "BackOff"),
+			request:  manual,
+			response: newRiskMcpResponse(false, "high"),
+			expected: false,
+		},
+		{
+			name:     "unknown risk level does not match",
+			match:    dotaiv1alpha1.NotificationRouteMatch{RiskLevels: []string{"low", "medium", "high"}},
+			event:    newRoutingTestEvent("dev", "BackOff"),
+			request:  manual,
+			response: &failed,
+			expected: false,
+		},
+		{
+			name:     "event reason must match",
+			match:    dotaiv1alpha1.NotificationRouteMatch{Reasons: []string{"FailedScheduling"}},
+			event:    newRoutingTestEvent("dev", "BackOff"),
+			request:  manual,
+			response: &failed,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := dotaiv1alpha1.NotificationRoute{Name: "test", Match: tt.match}
+			assert.Equal(t, tt.expected, r.matchesNotificationRoute(route, tt.event, tt.request, tt.response))
+		})
+	}
+}
+
+func TestRemediationPolicyReconciler_ValidateNotificationRoutes(t *testing.T) {
+	r := &RemediationPolicyReconciler{}
+	secretRef := dotaiv1alpha1.SecretReference{Name: "slack", Key: "url"}
+
+	tests := []struct {
+		name        string
+		routes      []dotaiv1alpha1.NotificationRoute
+		expectedErr string
+	}{
+		{
+			name:   "valid route",
+			routes: []dotaiv1alpha1.NotificationRoute{{Name: "prod", Slack: &dotaiv1alpha1.SlackRouteTarget{WebhookUrlSecretRef: secretRef}}},
+		},
+		{
+			name:        "missing name",
+			routes:      []dotaiv1alpha1.NotificationRoute{{Slack: &dotaiv1alpha1.SlackRouteTarget{WebhookUrlSecretRef: secretRef}}},
+			expectedErr: "name cannot be empty",
+		},
+		{
+			name: "duplicate name",
+			routes: []dotaiv1alpha1.NotificationRoute{
+				{Name: "prod", Slack: &dotaiv1alpha1.SlackRouteTarget{WebhookUrlSecretRef: secretRef}},
+				{Name: "prod", Slack: &dotaiv1alpha1.SlackRouteTarget{WebhookUrlSecretRef: secretRef}},
+			},
+			expectedErr: "duplicate route name",
+		},
+		{
+			name:        "missing target",
+			routes:      []dotaiv1alpha1.NotificationRoute{{Name: "prod"}},
+			expectedErr: "at least one of slack, googleChat or channelRef is required",
+		},
+		{
+			name:        "incomplete secret reference",
+			routes:      []dotaiv1alpha1.NotificationRoute{{Name: "prod", GoogleChat: &dotaiv1alpha1.GoogleChatRouteTarget{WebhookUrlSecretRef: dotaiv1alpha1.SecretReference{Name: "gchat"}}}},
+			expectedErr: "googleChat webhookUrlSecretRef name and key are required",
+		},
+		{
+			name: "invalid namespace pattern",
+			routes: []dotaiv1alpha1.NotificationRoute{{
+				Name:  "prod",
+				Match: dotaiv1alpha1.NotificationRouteMatch{Namespaces: []string{"prod-["}},
+				Slack: &dotaiv1alpha1.SlackRouteTarget{WebhookUrlSecretRef: secretRef},
+			}},
+			expectedErr: "invalid namespace pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &dotaiv1alpha1.RemediationPolicy{}
+			policy.Spec.Notifications.Routes = tt.routes
+			err := r.validateNotificationRoutes(policy)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestRemediationPolicyReconciler_SendRoutedNotifications(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string][]SlackMessage{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message SlackMessage
+		_ = json.NewDecoder(r.Body).Decode(&message)
+		mu.Lock()
+		received[r.URL.Path] = append(received[r.URL.Path], message)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = dotaiv1alpha1.AddToScheme(scheme)
+
+	policy := &dotaiv1alpha1.RemediationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "routed-policy", Namespace: "default"},
+		Spec: dotaiv1alpha1.RemediationPolicySpec{
+			Notifications: dotaiv1alpha1.NotificationConfig{
+				Routes: []dotaiv1alpha1.NotificationRoute{
+					{
+						Name:  "prod-incidents",
+						Match: dotaiv1alpha1.NotificationRouteMatch{Namespaces: []string{"prod-*"}, Outcomes: []string{"failure"}},
+						Slack: &dotaiv1alpha1.SlackRouteTarget{
+							WebhookUrlSecretRef: dotaiv1alpha1.SecretReference{Name: "webhooks", Key: "incidents"},
+							Channel:             "#prod-incidents",
+						},
+						Continue: true,
+					},
+					{
+						Name:  "all",
+						Slack: &dotaiv1alpha1.SlackRouteTarget{WebhookUrlSecretRef: dotaiv1alpha1.SecretReference{Name: "webhooks", Key: "log"}},
+					},
+					{
+						Name:  "never-reached",
+						Slack: &dotaiv1alpha1.SlackRouteTarget{WebhookUrlSecretRef: dotaiv1alpha1.SecretReference{Name: "webhooks", Key: "log"}},
+					},
+				},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhooks", Namespace: "default"},
+		Data: map[string][]byte{
+			"incidents": []byte(server.URL + "/incidents"),
+			"log":       []byte(server.URL + "/log"),
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(policy, secret).
+		WithStatusSubresource(policy).
+		Build()
+
+	r := &RemediationPolicyReconciler{
+		Client:     fakeClient,
+		Scheme:     scheme,
+		Recorder:   record.NewFakeRecorder(10),
+		HttpClient: server.Client(),
+	}
+
+	failed := createFailedMcpResponse("boom")
+	r.sendRoutedNotifications(context.Background(), policy, newRoutingTestEvent("prod-payments", "BackOff"),
+		&dotaiv1alpha1.McpRequest{Issue: "pod failing", Mode: "manual"}, &failed)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received["/incidents"], 1)
+	assert.Equal(t, "#prod-incidents", received["/incidents"][0].Channel)
+	assert.Len(t, received["/log"], 1, "continue should evaluate the next route, which stops evaluation")
+}
+
+func TestRemediationPolicyReconciler_SendRoutedNotifications_TLS(t *testing.T) {
+	var received sync.WaitGroup
+	received.Add(1)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Done()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = dotaiv1alpha1.AddToScheme(scheme)
+
+	policy := &dotaiv1alpha1.RemediationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "routed-policy", Namespace: "default"},
+		Spec: dotaiv1alpha1.RemediationPolicySpec{
+			Notifications: dotaiv1alpha1.NotificationConfig{
+				Routes: []dotaiv1alpha1.NotificationRoute{{
+					Name: "internal",
+					Slack: &dotaiv1alpha1.SlackRouteTarget{
+						WebhookUrlSecretRef: dotaiv1alpha1.SecretReference{Name: "webhooks", Key: "internal"},
+						TLS: &dotaiv1alpha1.TLSConfig{
+							CAConfigMapRef: &dotaiv1alpha1.ConfigMapReference{Name: "internal-ca", Key: "ca.crt"},
+						},
+					},
+				}},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhooks", Namespace: "default"},
+		Data:       map[string][]byte{"internal": []byte(server.URL)},
+	}
+	caBundle := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "internal-ca", Namespace: "default"},
+		Data: map[string]string{
+			"ca.crt": string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})),
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(policy, secret, caBundle).
+		WithStatusSubresource(policy).
+		Build()
+
+	// The shared client only trusts the system roots
+	r := &RemediationPolicyReconciler{
+		Client:     fakeClient,
+		Scheme:     scheme,
+		Recorder:   record.NewFakeRecorder(10),
+		HttpClient: http.DefaultClient,
+	}
+
+	failed := createFailedMcpResponse("boom")
+	r.sendRoutedNotifications(context.Background(), policy, newRoutingTestEvent("default", "BackOff"),
+		&dotaiv1alpha1.McpRequest{Issue: "pod failing", Mode: "manual"}, &failed)
+	received.Wait()
+
+	updated := &dotaiv1alpha1.RemediationPolicy{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(policy), updated))
+	stats := deliveryStatusFor(&updated.Status, notificationRouteKey("internal", notificationChannelSlack))
+	assert.Equal(t, int64(1), stats.Delivered, "the route target is verified with its own CA bundle")
+}
@@ -4,11 +4,9 @@
 package controller
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 
 	corev1 "k8s.io/api/core/v1"
@@ -85,47 +83,21 @@ func (r *RemediationPolicyReconciler) sendSlackNotification(ctx context.Context,
 		return nil
 	}
 
-	// Resolve webhook URL from Secret or plain text
-	webhookUrl, err := r.resolveWebhookUrl(
-		ctx,
-		policy.Namespace,
-		policy.Spec.Notifications.Slack.WebhookUrl,
-		policy.Spec.Notifications.Slack.WebhookUrlSecretRef,
-		"Slack",
-	)
-	if err != nil {
-		logger.Error(err, "failed to resolve Slack webhook URL")
-		// Update notification health condition
-		if updateErr := r.updateNotificationHealthCondition(ctx, policy, err); updateErr != nil {
-			logger.Error(updateErr, "failed to update notification health condition")
-		}
-		return fmt.Errorf("failed to resolve Slack webhook URL: %w", err)
-	}
-	if webhookUrl == "" {
-		logger.V(1).Info("Slack webhook URL not configured, skipping notification")
+	// Buffer notification for a summary message if batching is enabled
+	if !r.batchNotification(ctx, policy, event, notificationChannelSlack, notificationType, mcpResponse) {
 		return nil
 	}
 
 	// Create Slack message
 	message := r.createSlackMessage(policy, event, notificationType, mcpRequest, mcpResponse)
 
-	// Send the message
-	if err := r.sendSlackWebhook(ctx, webhookUrl, message); err != nil {
+	// Send the message, queueing it for redelivery on transient failures
+	if err := r.deliverNotification(ctx, policy, newSlackDelivery(message)); err != nil {
 		logger.Error(err, "failed to send Slack notification",
 			"notificationType", notificationType)
-		// Update notification health condition with HTTP error
-		if updateErr := r.updateNotificationHealthCondition(ctx, policy, err); updateErr != nil {
-			logger.Error(updateErr, "failed to update notification health condition")
-		}
 		return err
 	}
 
-	// Update notification health condition - success
-	if err := r.updateNotificationHealthCondition(ctx, policy, nil); err != nil {
-		logger.Error(err, "failed to update notification health condition")
-		// Don't fail notification on status update error
-	}
-
 	logger.Info("📱 Slack notification sent successfully",
 		"notificationType", notificationType,
 		"channel", policy.Spec.Notifications.Slack.Channel)
@@ -310,11 +282,7 @@ func (r *RemediationPolicyReconciler) createCompleteBlocks(emoji, title string,
 // createMcpDetailBlocks extracts detailed information from MCP response and creates Block Kit blocks
 func (r *RemediationPolicyReconciler) createMcpDetailBlocks(mcpResponse *McpResponse) []SlackBlock {
 	blocks := []SlackBlock{}
-	executed := r.getMcpExecutedStatus(mcpResponse)
-
-	if mcpResponse.Data != nil && mcpResponse.Data.Result != nil {
-		result := mcpResponse.Data.Result
-
+	if result := mcpResponse.RemediationResult(); result != nil {
 		// Execution time and confidence as fields
 		var metaFields []SlackBlockText
 		if mcpResponse.Data.ExecutionTime > 0 {
@@ -325,10 +293,10 @@ func (r *RemediationPolicyReconciler) createMcpDetailBlocks(mcpResponse *McpResp
 			})
 		}
 
-		if confidence, ok := result["confidence"].(float64); ok {
+		if result.Confidence != nil {
 			metaFields = append(metaFields, SlackBlockText{
 				Type: "mrkdwn",
-				Text: fmt.Sprintf("*Confidence:*\n%.0f%%", confidence*100),
+				Text: fmt.Sprintf("*Confidence:*\n%.0f%%", *result.Confidence*100),
 			})
 		}
 
@@ -340,96 +308,88 @@ func (r *RemediationPolicyReconciler) createMcpDetailBlocks(mcpResponse *McpResp
 		}
 
 		// Root cause analysis
-		if analysis, ok := result["analysis"].(map[string]interface{}); ok {
-			if rootCause, ok := analysis["rootCause"].(string); ok && rootCause != "" {
-				blocks = append(blocks, SlackBlock{
-					Type: "section",
-					Text: &SlackBlockText{
-						Type: "mrkdwn",
-						Text: fmt.Sprintf("*Root Cause:*\n%s", rootCause),
-					},
-				})
-			}
-			if confLevel, ok := analysis["confidence"].(float64); ok {
-				blocks = append(blocks, SlackBlock{
-					Type: "section",
-					Text: &SlackBlockText{
-						Type: "mrkdwn",
-						Text: fmt.Sprintf("*Analysis Confidence:* %.0f%%", confLevel*100),
-					},
-				})
-			}
+		if result.RootCause != "" {
+			blocks = append(blocks, SlackBlock{
+				Type: "section",
+				Text: &SlackBlockText{
+					Type: "mrkdwn",
+					Text: fmt.Sprintf("*Root Cause:*\n%s", result.RootCause),
+				},
+			})
+		}
+		if result.AnalysisConfidence != nil {
+			blocks = append(blocks, SlackBlock{
+				Type: "section",
+				Text: &SlackBlockText{
+					Type: "mrkdwn",
+					Text: fmt.Sprintf("*Analysis Confidence:* %.0f%%", *result.AnalysisConfidence*100),
+				},
+			})
 		}
 
 		// Remediation commands - NO TRUNCATION, use code blocks
-		if remediation, ok := result["remediation"].(map[string]interface{}); ok {
-			if actions, ok := remediation["actions"].([]interface{}); ok && len(actions) > 0 {
-				commandsTitle := "Commands Executed"
-				if !executed {
-					commandsTitle = "Recommended Commands"
+		if len(result.Actions) > 0 {
+			commandsTitle := "Commands Executed"
+			if !result.Executed {
+				commandsTitle = "Recommended Commands"
+			}
+
+			blocks = append(blocks, SlackBlock{
+				Type: "section",
+				Text: &SlackBlockText{
+					Type: "mrkdwn",
+					Text: fmt.Sprintf("*%s:*", commandsTitle),
+				},
+			})
+
+			// Add each command in its own code block - NO TRUNCATION
+			for i, action := range result.Actions {
+				// Slack recommends max 50 blocks per message - enforce 10 command limit
+				if i >= 10 {
+					blocks = append(blocks, SlackBlock{
+						Type: "section",
+						Text: &SlackBlockText{
+							Type: "mrkdwn",
+							Text: fmt.Sprintf("_... and %d more commands_", len(result.Actions)-10),
+						},
+					})
+					break
 				}
 
-				blocks = append(blocks, SlackBlock{
-					Type: "section",
-					Text: &SlackBlockText{
-						Type: "mrkdwn",
-						Text: fmt.Sprintf("*%s:*", commandsTitle),
-					},
-				})
-
-				// Add each command in its own code block - NO TRUNCATION
-				for i, action := range actions {
-					// Slack recommends max 50 blocks per message - enforce 10 command limit
-					if i >= 10 {
-						blocks = append(blocks, SlackBlock{
-							Type: "section",
-							Text: &SlackBlockText{
-								Type: "mrkdwn",
-								Text: fmt.Sprintf("_... and %d more commands_", len(actions)-10),
-							},
-						})
-						break
-					}
-
-					if actionMap, ok := action.(map[string]interface{}); ok {
-						if cmd, ok := actionMap["command"].(string); ok && cmd != "" {
-							blocks = append(blocks, SlackBlock{
-								Type: "section",
-								Text: &SlackBlockText{
-									Type: "mrkdwn",
-									Text: fmt.Sprintf("```\n%s\n```", cmd), // Full command in code block
-								},
-							})
-						}
-					}
+				if action.Command != "" {
+					blocks = append(blocks, SlackBlock{
+						Type: "section",
+						Text: &SlackBlockText{
+							Type: "mrkdwn",
+							Text: fmt.Sprintf("```\n%s\n```", action.Command), // Full command in code block
+						},
+					})
 				}
 			}
 		}
 
 		// Validation results
-		if validation, ok := result["validation"].(map[string]interface{}); ok {
-			if success, ok := validation["success"].(bool); ok {
-				status := "❌ Failed"
-				if success {
-					status = "✅ Passed"
-				}
-				blocks = append(blocks, SlackBlock{
-					Type: "section",
-					Text: &SlackBlockText{
-						Type: "mrkdwn",
-						Text: fmt.Sprintf("*Validation:* %s", status),
-					},
-				})
+		if result.Validation != nil {
+			status := "❌ Failed"
+			if result.Validation.Success {
+				status = "✅ Passed"
 			}
+			blocks = append(blocks, SlackBlock{
+				Type: "section",
+				Text: &SlackBlockText{
+					Type: "mrkdwn",
+					Text: fmt.Sprintf("*Validation:* %s", status),
+				},
+			})
 		}
 
 		// Action count summary
-		if results, ok := result["results"].([]interface{}); ok && len(results) > 0 {
+		if result.ActionsTaken > 0 {
 			blocks = append(blocks, SlackBlock{
 				Type: "section",
 				Text: &SlackBlockText{
 					Type: "mrkdwn",
-					Text: fmt.Sprintf("*Actions Taken:* %d remediation actions", len(results)),
+					Text: fmt.Sprintf("*Actions Taken:* %d remediation actions", result.ActionsTaken),
 				},
 			})
 		}
@@ -462,48 +422,18 @@ func (r *RemediationPolicyReconciler) createMcpDetailBlocks(mcpResponse *McpResp
 
 // getMcpExecutedStatus checks if MCP actually executed commands or just provided recommendations
 func (r *RemediationPolicyReconciler) getMcpExecutedStatus(mcpResponse *McpResponse) bool {
-	if mcpResponse.Data != nil && mcpResponse.Data.Result != nil {
-		if executed, ok := mcpResponse.Data.Result["executed"].(bool); ok {
-			return executed
-		}
-	}
-	return false
+	result := mcpResponse.RemediationResult()
+	return result != nil && result.Executed
 }
 
-// sendSlackWebhook sends the actual HTTP request to Slack webhook
-func (r *RemediationPolicyReconciler) sendSlackWebhook(ctx context.Context, webhookUrl string, message SlackMessage) error {
-	logger := logf.FromContext(ctx)
-
+// postSlackWebhook posts a Slack message to a webhook URL
+// It is shared by all controllers that send Slack notifications and retries transient failures
+func postSlackWebhook(ctx context.Context, httpClient *http.Client, webhookUrl string, message SlackMessage) error {
 	// Marshal message to JSON
 	payload, err := json.Marshal(message)
 	if err != nil {
 		return fmt.Errorf("failed to marshal Slack message: %w", err)
 	}
 
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "POST", webhookUrl, bytes.NewBuffer(payload))
-	if err != nil {
-		return fmt.Errorf("failed to create Slack webhook request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	// Send request with timeout
-	response, err := r.HttpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send Slack webhook: %w", err)
-	}
-	defer response.Body.Close()
-
-	// Check response
-	if response.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(response.Body)
-		return fmt.Errorf("Slack webhook returned status %d: %s", response.StatusCode, string(body))
-	}
-
-	logger.V(1).Info("Slack webhook sent successfully",
-		"statusCode", response.StatusCode,
-		"payloadSize", len(payload))
-
-	return nil
+	return postWebhook(ctx, httpClient, webhookUrl, "Slack", payload)
 }
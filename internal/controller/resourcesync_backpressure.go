@@ -0,0 +1,259 @@
+// resourcesync_backpressure.go recovers resource changes dropped because the change queue was full.
+// Informer handlers never block for long, so bursts beyond the queue capacity drop changes. The
+// namespaces and resource types of dropped changes are recorded as digest buckets, and a targeted
+// resync compares just those buckets with MCP instead of waiting for the next full resync, so
+// dropped deletes do not leave ghost resources in search results for an hour.
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// dropRecoveryRetryInterval is the delay before a failed recovery of dropped changes is retried
+const dropRecoveryRetryInterval = 30 * time.Second
+
+// changeBucket returns the digest bucket of a change: its namespace and resource type
+func changeBucket(change *ResourceChange) string {
+	if change.Data != nil {
+		return resourceBucket(change.Data)
+	}
+	if id := change.DeleteIdentifier; id != nil {
+		return fmt.Sprintf("%s/%s/%s", id.Namespace, id.APIVersion, id.Kind)
+	}
+	return ""
+}
+
+// parseBucket splits a digest bucket into its namespace, apiVersion and kind
+func parseBucket(bucket string) (namespace, apiVersion, kind string, ok bool) {
+	first, last := strings.Index(bucket, "/"), strings.LastIndex(bucket, "/")
+	if first < 0 || last <= first {
+		return "", "", "", false
+	}
+	return bucket[:first], bucket[first+1 : last], bucket[last+1:], true
+}
+
+// recordDroppedChange counts a change dropped because the change queue was full and
+// requests a recovery of its bucket
+func (s *activeConfigState) recordDroppedChange(change *ResourceChange) {
+	s.droppedChanges.Add(1)
+
+	bucket := changeBucket(change)
+	if bucket == "" {
+		return
+	}
+	s.droppedMu.Lock()
+	if s.droppedBuckets == nil {
+		s.droppedBuckets = make(map[string]bool)
+	}
+	s.droppedBuckets[bucket] = true
+	s.droppedMu.Unlock()
+	s.requestDropRecovery()
+}
+
+// requestDropRecovery triggers a recovery of dropped changes unless one is already pending
+func (s *activeConfigState) requestDropRecovery() {
+	select {
+	case s.dropRecoveryRequests <- struct{}{}:
+	default:
+	}
+}
+
+// takeDroppedBuckets returns the buckets with dropped changes and clears them
+func (s *activeConfigState) takeDroppedBuckets() []string {
+	s.droppedMu.Lock()
+	defer s.droppedMu.Unlock()
+
+	buckets := make([]string, 0, len(s.droppedBuckets))
+	for bucket := range s.droppedBuckets {
+		buckets = append(buckets, bucket)
+	}
+	s.droppedBuckets = nil
+	return buckets
+}
+
+// restoreDroppedBuckets records buckets again after their recovery failed
+func (s *activeConfigState) restoreDroppedBuckets(buckets []string) {
+	s.droppedMu.Lock()
+	defer s.droppedMu.Unlock()
+
+	if s.droppedBuckets == nil {
+		s.droppedBuckets = make(map[string]bool, len(buckets))
+	}
+	for _, bucket := range buckets {
+		s.droppedBuckets[bucket] = true
+	}
+}
+
+// lastDropRecoverySnapshot returns a copy of the last recovery result for status updates
+func (s *activeConfigState) lastDropRecoverySnapshot() *dotaiv1alpha1.DropRecoveryResult {
+	s.droppedMu.Lock()
+	defer s.droppedMu.Unlock()
+	return s.lastDropRecovery.DeepCopy()
+}
+
+// dropRecoveryLoop runs a targeted resync whenever changes are dropped
+// Drops come in bursts, so the loop waits one debounce window for the burst to end and the
+// queued changes to be flushed before comparing the affected buckets with MCP.
+func (r *ResourceSyncReconciler) dropRecoveryLoop(ctx context.Context, state *activeConfigState, configName string) {
+	logger := logf.FromContext(ctx).WithName("resourcesync")
+	window := time.Duration(state.config.GetDebounceWindow()) * time.Second
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-state.dropRecoveryRequests:
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(window):
+		}
+
+		buckets := state.takeDroppedBuckets()
+		if len(buckets) == 0 {
+			continue
+		}
+		logger.Info("Recovering changes dropped because the change queue was full",
+			"config", configName,
+			"buckets", len(buckets),
+			"droppedChanges", state.droppedChanges.Load(),
+		)
+
+		if err := r.recoverDroppedChanges(ctx, state, buckets); err != nil {
+			logger.Error(err, "Failed to recover dropped changes, retrying", "config", configName,
+				"retryAfter", dropRecoveryRetryInterval)
+			state.restoreDroppedBuckets(buckets)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(dropRecoveryRetryInterval):
+			}
+			state.requestDropRecovery()
+		}
+	}
+}
+
+// listBucketResources extracts the resource data of the cached resources in the given buckets
+// Only the informers of the bucket resource types are read, through their namespace index, so a
+// recovery does not build the resource data of every cached object like a full resync.
+func (r *ResourceSyncReconciler) listBucketResources(state *activeConfigState, buckets []string) []*ResourceData {
+	type bucketKey struct{ apiVersion, kind string }
+	namespaces := make(map[bucketKey][]string)
+	for _, bucket := range buckets {
+		namespace, apiVersion, kind, ok := parseBucket(bucket)
+		if !ok {
+			continue
+		}
+		if namespace == clusterScopeNamespace {
+			namespace = ""
+		}
+		key := bucketKey{apiVersion: apiVersion, kind: kind}
+		namespaces[key] = append(namespaces[key], namespace)
+	}
+
+	state.informersMu.RLock()
+	informers := make(map[schema.GroupVersionResource]cache.SharedIndexInformer)
+	for gvr, informer := range state.activeInformers {
+		if gvr == crdGVR {
+			continue
+		}
+		for key := range namespaces {
+			if gvr.GroupVersion().String() == key.apiVersion {
+				informers[gvr] = informer
+				break
+			}
+		}
+	}
+	state.informersMu.RUnlock()
+
+	var resources []*ResourceData
+	for gvr, informer := range informers {
+		for key, bucketNamespaces := range namespaces {
+			if gvr.GroupVersion().String() != key.apiVersion {
+				continue
+			}
+			for _, namespace := range bucketNamespaces {
+				items, err := namespaceItems(informer, namespace)
+				if err != nil {
+					continue
+				}
+				for _, item := range items {
+					u, ok := cachedUnstructured(item)
+					if !ok || u.GetKind() != key.kind || u.GetNamespace() != namespace ||
+						!r.selectsObject(context.Background(), state.filter, u) {
+						continue
+					}
+					resources = append(resources, state.resourceData(u))
+				}
+			}
+		}
+	}
+	return resources
+}
+
+// namespaceItems returns the cached objects of a namespace, through the namespace index of the
+// informer if it has one
+func namespaceItems(informer cache.SharedIndexInformer, namespace string) ([]interface{}, error) {
+	if indexer := informer.GetIndexer(); indexer != nil {
+		if _, indexed := indexer.GetIndexers()[cache.NamespaceIndex]; indexed {
+			return indexer.ByIndex(cache.NamespaceIndex, namespace)
+		}
+	}
+	return informer.GetStore().List(), nil
+}
+
+// recoverDroppedChanges compares the buckets with dropped changes with MCP and uploads the
+// differences. Without digest support in MCP, stale resources can only be found by a full
+// resync, which is requested instead. The other sinks receive the resources of the buckets as upserts.
+func (r *ResourceSyncReconciler) recoverDroppedChanges(ctx context.Context, state *activeConfigState, buckets []string) error {
+	logger := logf.FromContext(ctx).WithName("resourcesync")
+
+	if state.mcpClient == nil && !state.debounceBuffer.hasSinks() {
+		logger.V(1).Info("No sink configured, skipping recovery of dropped changes")
+		return nil
+	}
+
+	resources := r.listBucketResources(state, buckets)
+	state.debounceBuffer.ResyncSinks(ctx, resources)
+	if state.mcpClient == nil {
+		return nil
+	}
+
+	result := &dotaiv1alpha1.DropRecoveryResult{
+		Time:    metav1.NewTime(time.Now()),
+		Buckets: len(buckets),
+	}
+	var err error
+	result.Upserted, result.Deleted, err = r.reconcileBuckets(ctx, state, newResyncDigest(resources), buckets)
+	if isUnsupportedEndpoint(err) {
+		logger.Info("MCP does not support resync digests, requesting a full resync to recover dropped changes")
+		state.requestResync()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	state.droppedMu.Lock()
+	state.lastDropRecovery = result
+	state.droppedMu.Unlock()
+
+	logger.Info("Recovered dropped changes",
+		"buckets", result.Buckets,
+		"upserted", result.Upserted,
+		"deleted", result.Deleted,
+	)
+	return nil
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// newBackpressureTestDeployment creates a Deployment as listed by an informer
+func newBackpressureTestDeployment(namespace, name, version string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+			"labels":    map[string]interface{}{"version": version},
+		},
+	}}
+}
+
+func TestTrySendChange_RecordsDroppedBuckets(t *testing.T) {
+	state := &activeConfigState{
+		changeQueue:          make(chan *ResourceChange, 1),
+		dropRecoveryRequests: make(chan struct{}, 1),
+	}
+	require.True(t, trySendChange(state, &ResourceChange{ID: "queued", Action: ActionUpsert}))
+
+	assert.False(t, trySendChange(state, &ResourceChange{
+		Action: ActionUpsert,
+		Data:   &ResourceData{Namespace: "default", Name: "web", Kind: "Deployment", APIVersion: "apps/v1"},
+	}))
+	assert.False(t, trySendChange(state, &ResourceChange{
+		Action:           ActionDelete,
+		DeleteIdentifier: &ResourceIdentifier{Namespace: "team-a", Name: "db", Kind: "StatefulSet", APIVersion: "apps/v1"},
+	}))
+
+	assert.Equal(t, int64(2), state.droppedChanges.Load())
+	assert.Len(t, state.dropRecoveryRequests, 1, "a recovery is requested")
+	assert.ElementsMatch(t, []string{"default/apps/v1/Deployment", "team-a/apps/v1/StatefulSet"}, state.takeDroppedBuckets())
+	assert.Empty(t, state.takeDroppedBuckets(), "taking buckets clears them")
+}
+
+func TestTrySendChange_WaitsForRoom(t *testing.T) {
+	state := &activeConfigState{
+		changeQueue:     make(chan *ResourceChange, 1),
+		changeQueueWait: 5 * time.Second,
+	}
+	require.True(t, trySendChange(state, &ResourceChange{ID: "queued", Action: ActionUpsert}))
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		<-state.changeQueue
+	}()
+	assert.True(t, trySendChange(state, &ResourceChange{ID: "waiting", Action: ActionUpsert}))
+	assert.Zero(t, state.droppedChanges.Load())
+
+	state.changeQueueWait = 10 * time.Millisecond
+	assert.False(t, trySendChange(state, &ResourceChange{ID: "dropped", Action: ActionUpsert}))
+	assert.Equal(t, int64(1), state.droppedChanges.Load(), "changes are dropped once the wait times out")
+}
+
+func TestTrySendChange_WaitDoesNotBlockClosing(t *testing.T) {
+	state := &activeConfigState{
+		changeQueue:     make(chan *ResourceChange, 1),
+		changeQueueWait: 5 * time.Second,
+	}
+	require.True(t, trySendChange(state, &ResourceChange{ID: "queued", Action: ActionUpsert}))
+
+	result := make(chan bool, 1)
+	go func() {
+		result <- trySendChange(state, &ResourceChange{ID: "waiting", Action: ActionUpsert})
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// Stopping the watcher takes the write lock while a handler waits for room
+	locked := make(chan struct{})
+	go func() {
+		state.changeQueueMu.Lock()
+		state.changeQueueClosed = true
+		state.changeQueueMu.Unlock()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Fatal("closing the queue waited for the handler")
+	}
+	select {
+	case sent := <-result:
+		assert.False(t, sent)
+	case <-time.After(time.Second):
+		t.Fatal("the waiting handler did not notice the closed queue")
+	}
+	assert.Zero(t, state.droppedChanges.Load(), "changes for a closed queue are not recovered")
+}
+
+func TestResourceSyncReconciler_RecoverDroppedChanges(t *testing.T) {
+	maxRetries := 0
+	state := &activeConfigState{
+		config: &dotaiv1alpha1.ResourceSyncConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "sync", Namespace: "dot-ai"},
+		},
+		activeInformers: map[schema.GroupVersionResource]cache.SharedIndexInformer{
+			{Group: "apps", Version: "v1", Resource: "deployments"}: &mockInformer{store: &mockStore{items: []interface{}{
+				newBackpressureTestDeployment("default", "web", "1"),
+				newBackpressureTestDeployment("default", "api", "2"),
+				newBackpressureTestDeployment("team-a", "worker", "2"),
+			}}},
+		},
+	}
+
+	// The delete of default/cache and the update of default/api were dropped; team-a is out of date
+	// as well but had no dropped changes, so it is left to the periodic resync
+	web := state.resourceData(newBackpressureTestDeployment("default", "web", "1"))
+	server := &digestTestServer{stored: map[string]*ResourceData{
+		resourceDataID(web):                   web,
+		"default:apps/v1:Deployment:api":      newDigestTestResource("default", "Deployment", "api", "1"),
+		"default:apps/v1:Deployment:cache":    newDigestTestResource("default", "Deployment", "cache", "1"),
+		"team-a:apps/v1:Deployment:worker":    newDigestTestResource("team-a", "Deployment", "worker", "1"),
+		"team-a:apps/v1:Deployment:collector": newDigestTestResource("team-a", "Deployment", "collector", "1"),
+	}}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	state.mcpClient = NewMCPResourceSyncClient(MCPResourceSyncClientConfig{
+		Endpoint:   httpServer.URL + ResourceSyncPath,
+		HTTPClient: httpServer.Client(),
+		MaxRetries: &maxRetries,
+	})
+
+	reconciler := &ResourceSyncReconciler{}
+	require.NoError(t, reconciler.recoverDroppedChanges(t.Context(), state, []string{"default/apps/v1/Deployment"}))
+
+	assert.Equal(t, 1, server.upserted, "only the changed resource is uploaded")
+	assert.Equal(t, 1, server.deletedCount, "the resource deleted while its change was dropped is removed")
+	assert.Contains(t, server.stored, "team-a:apps/v1:Deployment:collector", "buckets without dropped changes are not compared")
+	assert.Equal(t, &dotaiv1alpha1.DropRecoveryResult{
+		Time:     state.lastDropRecovery.Time,
+		Buckets:  1,
+		Upserted: 1,
+		Deleted:  1,
+	}, state.lastDropRecoverySnapshot())
+}
+
+func TestResourceSyncReconciler_RecoverDroppedChanges_Unsupported(t *testing.T) {
+	server := &resyncTestServer{chunks: map[int]int{}, failChunk: -1, unsupported: true}
+	reconciler, state := newResyncTestState(t, server, 10)
+
+	require.NoError(t, reconciler.recoverDroppedChanges(t.Context(), state, []string{"default/apps/v1/Deployment"}))
+	assert.Len(t, state.resyncRequests, 1, "a full resync is requested instead")
+	assert.Nil(t, state.lastDropRecoverySnapshot())
+}
@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// cacheModeMetadata caches only the metadata of objects
+	cacheModeMetadata = "metadata"
+	// cacheModeFull caches full objects, for resource types with field rules that read spec or status
+	cacheModeFull = "full"
+)
+
+// resourceCacheSize is the number of objects cached by the informer of a resource type
+type resourceCacheSize struct {
+	resource string
+	mode     string
+	objects  int
+}
+
+// recordResourceKind remembers the kind of a discovered resource type
+func (r *ResourceSyncReconciler) recordResourceKind(gvr schema.GroupVersionResource, kind string) {
+	r.kindsMu.Lock()
+	defer r.kindsMu.Unlock()
+	if r.resourceKinds == nil {
+		r.resourceKinds = make(map[schema.GroupResource]string)
+	}
+	r.resourceKinds[gvr.GroupResource()] = kind
+}
+
+// resourceKind returns the kind of a discovered resource type
+func (r *ResourceSyncReconciler) resourceKind(gvr schema.GroupVersionResource) string {
+	r.kindsMu.RLock()
+	defer r.kindsMu.RUnlock()
+	return r.resourceKinds[gvr.GroupResource()]
+}
+
+// needsFullObjects checks if field rules read the spec or status of a resource type
+// Other resource types only need metadata, which keeps the informer caches small
+func (s *activeConfigState) needsFullObjects(groupKind schema.GroupKind) bool {
+	return len(s.fields.rulesForKind(groupKind)) > 0
+}
+
+// newResourceCacheTransform creates the transform applied to objects before they are cached
+// Metadata-only objects stay typed, which takes far less memory than unstructured maps, and are
+// labelled with their own kind so cachedUnstructured converts them when they are read. Managed
+// fields and the last applied configuration are never synced and are dropped to save memory, and
+// full objects are pruned by the optional prune function.
+func newResourceCacheTransform(gvk schema.GroupVersionKind, prune func(obj *unstructured.Unstructured)) cache.TransformFunc {
+	return func(obj interface{}) (interface{}, error) {
+		switch t := obj.(type) {
+		case *metav1.PartialObjectMetadata:
+			t.SetManagedFields(nil)
+			dropLastAppliedConfig(t)
+			t.SetGroupVersionKind(gvk)
+			return t, nil
+		case *unstructured.Unstructured:
+			t.SetManagedFields(nil)
+			dropLastAppliedConfig(t)
+			if prune != nil {
+				prune(t)
+			}
+			return t, nil
+		default:
+			// DeletedFinalStateUnknown tombstones carry already transformed objects
+			return obj, nil
+		}
+	}
+}
+
+// cachedUnstructured returns a cached object, or the object of a deletion tombstone, as an
+// unstructured object. Metadata-only objects are converted to a copy, so callers may modify it.
+func cachedUnstructured(obj interface{}) (*unstructured.Unstructured, bool) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	switch t := obj.(type) {
+	case *unstructured.Unstructured:
+		return t, true
+	case *metav1.PartialObjectMetadata:
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(t)
+		if err != nil {
+			return nil, false
+		}
+		return &unstructured.Unstructured{Object: content}, true
+	default:
+		return nil, false
+	}
+}
+
+// cachedObject returns the current version of an object from the informer cache of its resource type
+func (s *activeConfigState) cachedObject(gvk schema.GroupVersionKind, key string) (*unstructured.Unstructured, bool) {
+	s.informersMu.RLock()
+	var stores []cache.Store
+	for gvr, informer := range s.activeInformers {
+		if gvr != crdGVR && gvr.GroupVersion() == gvk.GroupVersion() {
+			stores = append(stores, informer.GetStore())
+		}
+	}
+	s.informersMu.RUnlock()
+
+	// Resource types of a group version can share keys, so the kind of the object is checked
+	for _, store := range stores {
+		item, exists, err := store.GetByKey(key)
+		if err != nil || !exists {
+			continue
+		}
+		if u, ok := cachedUnstructured(item); ok && u.GetKind() == gvk.Kind {
+			return u, true
+		}
+	}
+	return nil, false
+}
+
+// dropLastAppliedConfig removes the last applied configuration annotation from an object
+func dropLastAppliedConfig(obj metav1.Object) {
+	annotations := obj.GetAnnotations()
+	if _, ok := annotations[lastAppliedConfigAnnotation]; !ok {
+		return
+	}
+	delete(annotations, lastAppliedConfigAnnotation)
+	obj.SetAnnotations(annotations)
+}
+
+// cacheSizes returns the number of cached objects of each watched resource type
+// Stores are counted by their keys after releasing informersMu, so metrics scrapes neither copy
+// the cached objects nor block informers from being started or stopped.
+func (s *activeConfigState) cacheSizes() []resourceCacheSize {
+	s.informersMu.RLock()
+	sizes := make([]resourceCacheSize, 0, len(s.activeInformers))
+	stores := make([]cache.Store, 0, len(s.activeInformers))
+	for gvr, informer := range s.activeInformers {
+		if gvr == crdGVR {
+			continue
+		}
+		mode := cacheModeMetadata
+		if s.fullObjectResources[gvr] {
+			mode = cacheModeFull
+		}
+		sizes = append(sizes, resourceCacheSize{resource: formatGVR(gvr), mode: mode})
+		stores = append(stores, informer.GetStore())
+	}
+	s.informersMu.RUnlock()
+
+	for i, store := range stores {
+		sizes[i].objects = len(store.ListKeys())
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i].resource < sizes[j].resource })
+	return sizes
+}
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestNewResourceCacheTransform(t *testing.T) {
+	transform := newResourceCacheTransform(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, nil)
+
+	partial := &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{APIVersion: "meta.k8s.io/v1", Kind: "PartialObjectMetadata"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "web",
+			Namespace:     "default",
+			Labels:        map[string]string{"app": "web"},
+			Annotations:   map[string]string{"description": "Frontend", lastAppliedConfigAnnotation: "{}"},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+		},
+	}
+	transformed, err := transform(partial)
+	require.NoError(t, err)
+
+	cached, ok := transformed.(*metav1.PartialObjectMetadata)
+	require.True(t, ok, "metadata is cached as typed objects")
+	assert.Empty(t, cached.GetManagedFields())
+
+	u, ok := cachedUnstructured(cached)
+	require.True(t, ok, "metadata is converted when it is read")
+	assert.Equal(t, "apps/v1", u.GetAPIVersion())
+	assert.Equal(t, "Deployment", u.GetKind())
+	assert.Equal(t, map[string]string{"app": "web"}, u.GetLabels())
+	assert.Equal(t, map[string]string{"description": "Frontend"}, u.GetAnnotations())
+	assert.Empty(t, u.GetManagedFields())
+	assert.Equal(t, "default:apps/v1:Deployment:web", buildResourceID(u))
+
+	full := newFieldsTestDeployment(3, 3)
+	full.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl"}})
+	transformed, err = transform(full)
+	require.NoError(t, err)
+	assert.Empty(t, transformed.(*unstructured.Unstructured).GetManagedFields())
+	_, found, _ := unstructured.NestedFieldNoCopy(full.Object, "spec", "replicas")
+	assert.True(t, found, "full objects keep spec and status")
+}
+
+func TestResourceSyncReconciler_WatchResource_CacheModes(t *testing.T) {
+	extractor, err := newResourceFieldExtractor(deploymentFieldExtractions)
+	require.NoError(t, err)
+	state := &activeConfigState{
+		clients: &clusterClients{
+			dynamic:  newVersionsTestDynamicClient(),
+			metadata: metadatafake.NewSimpleMetadataClient(metadatafake.NewTestScheme()),
+		},
+		activeInformers: make(map[schema.GroupVersionResource]cache.SharedIndexInformer),
+		fields:          extractor,
+	}
+	reconciler := &ResourceSyncReconciler{}
+
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	_, err = reconciler.watchResource(state, deployments, "Deployment")
+	require.NoError(t, err)
+	_, err = reconciler.watchResource(state, configMaps, "ConfigMap")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[schema.GroupVersionResource]bool{deployments: true}, state.fullObjectResources,
+		"only resource types with field rules cache full objects")
+
+	// Cache sizes are reported per resource type and cache mode
+	require.NoError(t, state.activeInformers[configMaps].GetStore().Add(&unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "settings", "namespace": "default"},
+	}}))
+	assert.Equal(t, []resourceCacheSize{
+		{resource: "apps/v1/deployments", mode: cacheModeFull, objects: 0},
+		{resource: "v1/configmaps", mode: cacheModeMetadata, objects: 1},
+	}, state.cacheSizes())
+
+	state.changeQueue = make(chan *ResourceChange, 1)
+	state.debounceBuffer = NewDebounceBuffer(DebounceBufferConfig{ConfigName: "default/sync", ChangeQueue: state.changeQueue})
+	collector := newResourceSyncCollector()
+	collector.track("default/sync", state)
+	expected := `
+# HELP dot_ai_resourcesync_cached_objects Number of objects in the informer cache of a watched resource type, by cache mode (metadata or full)
+# TYPE dot_ai_resourcesync_cached_objects gauge
+dot_ai_resourcesync_cached_objects{cache="full",config="default/sync",resource="apps/v1/deployments"} 0
+dot_ai_resourcesync_cached_objects{cache="metadata",config="default/sync",resource="v1/configmaps"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected), "dot_ai_resourcesync_cached_objects"))
+}
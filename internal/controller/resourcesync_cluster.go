@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+const (
+	// clusterAPIKubeconfigKey is the key of the kubeconfig in the Secrets maintained by Cluster API
+	clusterAPIKubeconfigKey = "value"
+
+	// remoteDiscoveryTimeout bounds discovery requests and health checks against remote clusters
+	// Dynamic and metadata clients have no timeout, since their watches are long-lived
+	remoteDiscoveryTimeout = 30 * time.Second
+)
+
+// namespacesGVR is the GVR of the namespaces read by namespace selectors of remote clusters
+var namespacesGVR = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+// clusterClients are the clients of the cluster a ResourceSyncConfig syncs
+type clusterClients struct {
+	dynamic   dynamic.Interface
+	discovery discovery.DiscoveryInterface
+	metadata  metadata.Interface
+	// server is the API server URL, reported in the status of remote clusters
+	server string
+}
+
+// newClusterClients creates the dynamic, discovery and metadata clients of a cluster
+func newClusterClients(restConfig *rest.Config, discoveryTimeout time.Duration) (*clusterClients, error) {
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	discoveryConfig := rest.CopyConfig(restConfig)
+	discoveryConfig.Timeout = discoveryTimeout
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(discoveryConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	metadataClient, err := metadata.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metadata client: %w", err)
+	}
+
+	return &clusterClients{
+		dynamic:   dynamicClient,
+		discovery: discoveryClient,
+		metadata:  metadataClient,
+		server:    restConfig.Host,
+	}, nil
+}
+
+// localClusterClients returns the clients of the cluster the controller runs in
+func (r *ResourceSyncReconciler) localClusterClients() *clusterClients {
+	return &clusterClients{
+		dynamic:   r.dynamicClient,
+		discovery: r.discoveryClient,
+		metadata:  r.metadataClient,
+	}
+}
+
+// clusterSecretReferences returns the kubeconfig Secret of the remote cluster of a ResourceSyncConfig
+func clusterSecretReferences(config *dotaiv1alpha1.ResourceSyncConfig) []secretKeyReference {
+	cluster := config.Spec.Cluster
+	if cluster == nil {
+		return nil
+	}
+	if cluster.ClusterAPIClusterName != "" {
+		return []secretKeyReference{{
+			Field: "cluster.clusterAPIClusterName",
+			Name:  cluster.ClusterAPIClusterName + "-kubeconfig",
+			Key:   clusterAPIKubeconfigKey,
+		}}
+	}
+	return appendSecretRef(nil, "cluster.kubeconfigSecretRef", cluster.KubeconfigSecretRef)
+}
+
+// validateRemoteCluster checks that a remote cluster has exactly one kubeconfig source and a name
+func validateRemoteCluster(config *dotaiv1alpha1.ResourceSyncConfig) error {
+	cluster := config.Spec.Cluster
+	if cluster == nil {
+		return nil
+	}
+	hasSecret := cluster.KubeconfigSecretRef != nil && cluster.KubeconfigSecretRef.Name != ""
+	hasClusterAPI := cluster.ClusterAPIClusterName != ""
+	switch {
+	case hasSecret == hasClusterAPI:
+		return errors.New("cluster requires exactly one of kubeconfigSecretRef and clusterAPIClusterName")
+	case config.GetClusterName() == "":
+		return errors.New("clusterName is required with cluster.kubeconfigSecretRef")
+	}
+	return nil
+}
+
+// clusterClientsFor returns the clients of the cluster a ResourceSyncConfig syncs
+// Remote clusters get their own clients from their kubeconfig Secret
+func (r *ResourceSyncReconciler) clusterClientsFor(ctx context.Context, config *dotaiv1alpha1.ResourceSyncConfig) (*clusterClients, error) {
+	if config.Spec.Cluster == nil {
+		return r.localClusterClients(), nil
+	}
+	if err := validateRemoteCluster(config); err != nil {
+		return nil, err
+	}
+
+	ref := clusterSecretReferences(config)[0]
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: config.Namespace, Name: ref.Name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get kubeconfig Secret '%s' referenced by %s: %w", ref.Name, ref.Field, err)
+	}
+	kubeconfig := secret.Data[ref.Key]
+	if len(kubeconfig) == 0 {
+		return nil, fmt.Errorf("kubeconfig Secret '%s' does not contain a value for key '%s'", ref.Name, ref.Key)
+	}
+
+	restConfig, err := restConfigFromKubeconfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig from Secret '%s': %w", ref.Name, err)
+	}
+	return newClusterClients(restConfig, remoteDiscoveryTimeout)
+}
+
+// restConfigFromKubeconfig builds a rest.Config from a kubeconfig read from a Secret
+// Kubeconfigs are written by namespace users, so settings that make the controller run commands or
+// read its own files, such as exec plugins or a tokenFile pointing at its ServiceAccount token, are
+// rejected. Credentials and CAs must be embedded in the kubeconfig.
+func restConfigFromKubeconfig(kubeconfig []byte) (*rest.Config, error) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, authInfo := range config.AuthInfos {
+		switch {
+		case authInfo.Exec != nil:
+			return nil, fmt.Errorf("user '%s' uses an exec credential plugin, which is not allowed", name)
+		case authInfo.AuthProvider != nil:
+			return nil, fmt.Errorf("user '%s' uses an auth provider, which is not allowed", name)
+		case authInfo.TokenFile != "":
+			return nil, fmt.Errorf("user '%s' reads its token from a file, which is not allowed; embed the token", name)
+		case authInfo.ClientCertificate != "" || authInfo.ClientKey != "":
+			return nil, fmt.Errorf("user '%s' reads its client certificate from a file, which is not allowed; embed the certificate data", name)
+		}
+	}
+	for name, cluster := range config.Clusters {
+		if cluster.CertificateAuthority != "" {
+			return nil, fmt.Errorf("cluster '%s' reads its CA from a file, which is not allowed; embed the CA data", name)
+		}
+	}
+
+	return clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{}).ClientConfig()
+}
+
+// clusterCredentialsChanged checks if the kubeconfig Secret of a remote cluster changed since the
+// watcher started; informers cannot switch clients, so the watcher is restarted with new ones
+func (r *ResourceSyncReconciler) clusterCredentialsChanged(ctx context.Context, config *dotaiv1alpha1.ResourceSyncConfig, state *activeConfigState) bool {
+	version := secretReferencesVersion(ctx, r.Client, config.Namespace, clusterSecretReferences(config))
+	return version != state.clusterCredentialsVersion
+}
+
+// checkClusterHealth checks that the API server of a remote cluster is reachable
+// Returns nil for the local cluster, whose health is not reported
+func (s *activeConfigState) checkClusterHealth() *dotaiv1alpha1.ClusterHealth {
+	if s.config.Spec.Cluster == nil {
+		return nil
+	}
+
+	health := &dotaiv1alpha1.ClusterHealth{
+		Name:          s.config.GetClusterName(),
+		Server:        s.clients.server,
+		LastCheckTime: metav1.NewTime(time.Now()),
+	}
+	version, err := s.clients.discovery.ServerVersion()
+	if err != nil {
+		health.Error = truncateErrorMessage(err.Error())
+	} else {
+		health.Reachable = true
+		health.KubernetesVersion = version.GitVersion
+	}
+
+	s.clusterMu.Lock()
+	s.clusterHealth = health
+	s.clusterMu.Unlock()
+	return health.DeepCopy()
+}
+
+// clusterHealthSnapshot returns a copy of the last health check for status updates
+func (s *activeConfigState) clusterHealthSnapshot() *dotaiv1alpha1.ClusterHealth {
+	s.clusterMu.Lock()
+	defer s.clusterMu.Unlock()
+	return s.clusterHealth.DeepCopy()
+}
+
+// clusterName returns the name of the synced cluster, added to synced resources
+func (s *activeConfigState) clusterName() string {
+	if s.config == nil {
+		return ""
+	}
+	return s.config.GetClusterName()
+}
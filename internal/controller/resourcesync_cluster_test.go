@@ -0,0 +1,260 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// newClusterTestKubeconfig returns a kubeconfig for an API server
+func newClusterTestKubeconfig(server string) []byte {
+	return []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: remote
+  cluster:
+    server: %s
+contexts:
+- name: remote
+  context:
+    cluster: remote
+    user: remote
+users:
+- name: remote
+  user:
+    token: test-token
+current-context: remote
+`, server))
+}
+
+// newClusterTestAPIServer serves the version endpoint of a remote API server
+func newClusterTestAPIServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/version" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"major": "1", "minor": "33", "gitVersion": "v1.33.0"})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestValidateRemoteCluster(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    dotaiv1alpha1.ResourceSyncConfigSpec
+		wantErr string
+	}{
+		{name: "local cluster"},
+		{
+			name: "kubeconfig Secret with cluster name",
+			spec: dotaiv1alpha1.ResourceSyncConfigSpec{
+				ClusterName: "prod",
+				Cluster:     &dotaiv1alpha1.RemoteClusterConfig{KubeconfigSecretRef: &dotaiv1alpha1.SecretReference{Name: "prod", Key: "kubeconfig"}},
+			},
+		},
+		{
+			name:    "kubeconfig Secret without cluster name",
+			spec:    dotaiv1alpha1.ResourceSyncConfigSpec{Cluster: &dotaiv1alpha1.RemoteClusterConfig{KubeconfigSecretRef: &dotaiv1alpha1.SecretReference{Name: "prod", Key: "kubeconfig"}}},
+			wantErr: "clusterName is required",
+		},
+		{
+			name: "Cluster API cluster defaults the cluster name",
+			spec: dotaiv1alpha1.ResourceSyncConfigSpec{Cluster: &dotaiv1alpha1.RemoteClusterConfig{ClusterAPIClusterName: "workload-1"}},
+		},
+		{
+			name:    "no kubeconfig source",
+			spec:    dotaiv1alpha1.ResourceSyncConfigSpec{ClusterName: "prod", Cluster: &dotaiv1alpha1.RemoteClusterConfig{}},
+			wantErr: "exactly one of",
+		},
+		{
+			name: "both kubeconfig sources",
+			spec: dotaiv1alpha1.ResourceSyncConfigSpec{Cluster: &dotaiv1alpha1.RemoteClusterConfig{
+				KubeconfigSecretRef:   &dotaiv1alpha1.SecretReference{Name: "prod", Key: "kubeconfig"},
+				ClusterAPIClusterName: "workload-1",
+			}},
+			wantErr: "exactly one of",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRemoteCluster(&dotaiv1alpha1.ResourceSyncConfig{Spec: tt.spec})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClusterSecretReferences(t *testing.T) {
+	config := &dotaiv1alpha1.ResourceSyncConfig{Spec: dotaiv1alpha1.ResourceSyncConfigSpec{
+		McpServerRef: "dot-ai",
+		Cluster:      &dotaiv1alpha1.RemoteClusterConfig{ClusterAPIClusterName: "workload-1"},
+	}}
+	assert.Equal(t, []secretKeyReference{
+		{Field: "cluster.clusterAPIClusterName", Name: "workload-1-kubeconfig", Key: "value"},
+	}, resourceSyncSecretReferences(config), "Cluster API kubeconfig Secrets are validated and watched")
+
+	config.Spec.Cluster = &dotaiv1alpha1.RemoteClusterConfig{KubeconfigSecretRef: &dotaiv1alpha1.SecretReference{Name: "prod", Key: "kubeconfig"}}
+	assert.Equal(t, []secretKeyReference{
+		{Field: "cluster.kubeconfigSecretRef", Name: "prod", Key: "kubeconfig"},
+	}, clusterSecretReferences(config))
+}
+
+func TestResourceSyncReconciler_ClusterClientsFor(t *testing.T) {
+	apiServer := newClusterTestAPIServer(t)
+	config := &dotaiv1alpha1.ResourceSyncConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "workload-1", Namespace: "fleet"},
+		Spec: dotaiv1alpha1.ResourceSyncConfigSpec{
+			Cluster: &dotaiv1alpha1.RemoteClusterConfig{ClusterAPIClusterName: "workload-1"},
+		},
+	}
+	reconciler := &ResourceSyncReconciler{Client: fake.NewClientBuilder().
+		WithScheme(newNotificationChannelTestScheme()).
+		WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "workload-1-kubeconfig", Namespace: "fleet"},
+			Data:       map[string][]byte{clusterAPIKubeconfigKey: newClusterTestKubeconfig(apiServer.URL)},
+		}).
+		Build()}
+
+	clients, err := reconciler.clusterClientsFor(t.Context(), config)
+	require.NoError(t, err)
+	assert.Equal(t, apiServer.URL, clients.server)
+
+	state := &activeConfigState{config: config, clients: clients}
+	health := state.checkClusterHealth()
+	require.NotNil(t, health)
+	assert.Equal(t, "workload-1", health.Name)
+	assert.True(t, health.Reachable)
+	assert.Equal(t, "v1.33.0", health.KubernetesVersion)
+	assert.Equal(t, health, state.clusterHealthSnapshot())
+
+	apiServer.Close()
+	health = state.checkClusterHealth()
+	assert.False(t, health.Reachable)
+	assert.NotEmpty(t, health.Error)
+
+	// Missing kubeconfig Secrets fail the watcher start
+	config.Spec.Cluster.ClusterAPIClusterName = "workload-2"
+	_, err = reconciler.clusterClientsFor(t.Context(), config)
+	assert.ErrorContains(t, err, "workload-2-kubeconfig")
+
+	// The local cluster reports no health
+	local := &activeConfigState{config: &dotaiv1alpha1.ResourceSyncConfig{}}
+	assert.Nil(t, local.checkClusterHealth())
+}
+
+func TestActiveConfigState_ClusterName(t *testing.T) {
+	state := &activeConfigState{config: &dotaiv1alpha1.ResourceSyncConfig{
+		Spec: dotaiv1alpha1.ResourceSyncConfigSpec{ClusterName: "prod"},
+	}}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "settings", "namespace": "default"},
+	}}
+
+	assert.Equal(t, "prod", state.resourceData(obj).ClusterName)
+	assert.Equal(t, "prod", newDeleteChange(obj, state.clusterName()).DeleteIdentifier.ClusterName)
+
+	var received SyncRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success":true,"data":{"upserted":1,"deleted":0}}`))
+	}))
+	defer server.Close()
+	mcpClient := NewMCPResourceSyncClient(MCPResourceSyncClientConfig{
+		Endpoint:    server.URL + ResourceSyncPath,
+		HTTPClient:  server.Client(),
+		ClusterName: "prod",
+	})
+	_, err := mcpClient.Resync(t.Context(), []*ResourceData{state.resourceData(obj)})
+	require.NoError(t, err)
+	assert.Equal(t, "prod", received.ClusterName, "resyncs only replace the resources of their cluster")
+}
+
+func TestRestConfigFromKubeconfig(t *testing.T) {
+	restConfig, err := restConfigFromKubeconfig(newClusterTestKubeconfig("https://remote.example.com"))
+	require.NoError(t, err)
+	assert.Equal(t, "https://remote.example.com", restConfig.Host)
+	assert.Equal(t, "test-token", restConfig.BearerToken)
+
+	tests := []struct {
+		name    string
+		user    string
+		cluster string
+		wantErr string
+	}{
+		{
+			name:    "exec credential plugin",
+			user:    "exec:\n      apiVersion: client.authentication.k8s.io/v1\n      command: /bin/sh",
+			wantErr: "exec credential plugin",
+		},
+		{
+			name:    "auth provider",
+			user:    "auth-provider:\n      name: oidc",
+			wantErr: "auth provider",
+		},
+		{
+			name:    "token file",
+			user:    "tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token",
+			wantErr: "reads its token from a file",
+		},
+		{
+			name:    "client certificate file",
+			user:    "client-certificate: /etc/controller/tls.crt",
+			wantErr: "reads its client certificate from a file",
+		},
+		{
+			name:    "client key file",
+			user:    "client-key: /etc/controller/tls.key",
+			wantErr: "reads its client certificate from a file",
+		},
+		{
+			name:    "CA file",
+			user:    "token: test-token",
+			cluster: "\n    certificate-authority: /var/run/secrets/kubernetes.io/serviceaccount/ca.crt",
+			wantErr: "reads its CA from a file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: remote
+  cluster:
+    server: https://attacker.example.com%s
+contexts:
+- name: remote
+  context:
+    cluster: remote
+    user: remote
+users:
+- name: remote
+  user:
+    %s
+current-context: remote
+`, tt.cluster, tt.user)
+			_, err := restConfigFromKubeconfig([]byte(kubeconfig))
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
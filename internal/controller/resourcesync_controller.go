@@ -5,7 +5,8 @@
 //
 // Note: Status and spec are NOT synced - they are fetched on-demand from the
 // Kubernetes API when needed. This reduces sync traffic since labels rarely
-// change after resource creation.
+// change after resource creation. Only the fields selected by the config's
+// field rules (JSONPath or CEL) are extracted from spec and status.
 //
 // Key responsibilities:
 // - Watch ResourceSyncConfig CRs to enable/disable resource syncing
@@ -19,11 +20,13 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -35,14 +38,20 @@ import (
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+	"github.com/vfarcic/dot-ai-controller/internal/tracing"
 )
 
 // ChangeAction represents the type of change detected for a resource
@@ -66,10 +75,18 @@ type ResourceData struct {
 	Kind string `json:"kind"`
 	// APIVersion including group (e.g., "apps/v1", "v1")
 	APIVersion string `json:"apiVersion"`
+	// ClusterName identifies the cluster of the resource when several clusters sync to MCP
+	ClusterName string `json:"clusterName,omitempty"`
 	// Labels from the resource
 	Labels map[string]string `json:"labels,omitempty"`
 	// Annotations from the resource (selected ones, not all)
 	Annotations map[string]string `json:"annotations,omitempty"`
+	// Fields extracted by the ResourceSyncConfig field rules (e.g., images, replicas, health)
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	// Relationships are the edges to owners and other related resources
+	Relationships []ResourceRelationship `json:"relationships,omitempty"`
+	// ContentHash is the hash of labels, annotations, fields and relationships, compared by incremental resyncs
+	ContentHash string `json:"contentHash,omitempty"`
 	// CreatedAt is when the resource was created
 	CreatedAt time.Time `json:"createdAt"`
 	// UpdatedAt is when this data was last updated (now)
@@ -87,6 +104,8 @@ type ResourceIdentifier struct {
 	Kind string `json:"kind"`
 	// APIVersion including group (e.g., "apps/v1", "v1")
 	APIVersion string `json:"apiVersion"`
+	// ClusterName identifies the cluster of the resource when several clusters sync to MCP
+	ClusterName string `json:"clusterName,omitempty"`
 }
 
 // ResourceChange represents a change to be synced to MCP
@@ -108,6 +127,12 @@ const (
 	// Large enough to handle startup bursts and rolling updates
 	changeQueueBufferSize = 10000
 
+	// changeQueueRetryInterval is how often event handlers retry sending to a full change queue
+	changeQueueRetryInterval = 10 * time.Millisecond
+
+	// informerResyncPeriod is how often informers replay their cache to the event handlers
+	informerResyncPeriod = 30 * time.Minute
+
 	// clusterScopeNamespace is used in resource IDs for cluster-scoped resources
 	clusterScopeNamespace = "_cluster"
 
@@ -197,15 +222,29 @@ type ResourceSyncReconciler struct {
 	Recorder   record.EventRecorder
 	RestConfig *rest.Config
 
-	// HttpClient for MCP communication
+	// HttpClient for MCP communication (optional, defaults to the shared MCP connection pool)
 	HttpClient *http.Client
 
-	// dynamicClient for fetching arbitrary resources
+	// Notifier sends notifications when syncing starts failing or recovers
+	Notifier *StatusNotifier
+
+	// SinkDir is the directory NDJSON sinks write to, in a subdirectory per namespace
+	// NDJSON sinks are rejected when it is empty
+	SinkDir string
+
+	// dynamicClient for fetching arbitrary resources in the local cluster
 	dynamicClient dynamic.Interface
 
 	// discoveryClient for finding all resource types
 	discoveryClient discovery.DiscoveryInterface
 
+	// metadataClient for watching the metadata of resource types without field rules
+	metadataClient metadata.Interface
+
+	// resourceKinds caches the kind of each discovered resource type
+	resourceKinds map[schema.GroupResource]string
+	kindsMu       sync.RWMutex
+
 	// activeConfigs tracks which ResourceSyncConfig CRs have active watchers
 	// Key is namespace/name of the CR
 	activeConfigs map[string]*activeConfigState
@@ -214,22 +253,68 @@ type ResourceSyncReconciler struct {
 
 // activeConfigState holds the state for an active ResourceSyncConfig
 type activeConfigState struct {
-	config          *dotaiv1alpha1.ResourceSyncConfig
+	config *dotaiv1alpha1.ResourceSyncConfig
+	// clients are the clients of the synced cluster, the local cluster or a remote one
+	clients *clusterClients
+	// clusterCredentialsVersion fingerprints the kubeconfig Secret of a remote cluster
+	clusterCredentialsVersion string
+	// redactionKeyVersion fingerprints the HMAC key of hash redactions; a change restarts the watcher
+	redactionKeyVersion string
+	// clusterHealth is the last health check of a remote cluster
+	clusterHealth *dotaiv1alpha1.ClusterHealth
+	clusterMu     sync.Mutex // protects clusterHealth
+	// informerFactory creates the CRD informer, which runs as long as the watcher
 	informerFactory dynamicinformer.DynamicSharedInformerFactory
-	activeInformers map[schema.GroupVersionResource]cache.SharedIndexInformer
-	informersMu     sync.RWMutex // protects activeInformers
-	stopCh          chan struct{}
-	cancel          context.CancelFunc
+	// metadataInformerFactory creates the namespace informer of remote clusters
+	metadataInformerFactory metadatainformer.SharedInformerFactory
+	activeInformers         map[schema.GroupVersionResource]cache.SharedIndexInformer
+	// fullObjectResources are the activeInformers caching full objects instead of metadata
+	fullObjectResources map[schema.GroupVersionResource]bool
+	// handlerRegistrations are the sync event handlers of activeInformers, removed when a
+	// resource type is no longer watched (CRD deleted or its version changed)
+	handlerRegistrations map[schema.GroupVersionResource]cache.ResourceEventHandlerRegistration
+	// informerStops stop the informers of resource types individually; unlike informers of the
+	// shared factories, they are stopped when their resource type is no longer watched
+	informerStops map[schema.GroupVersionResource]chan struct{}
+	informersMu   sync.RWMutex // protects activeInformers, fullObjectResources, handlerRegistrations and informerStops
+	stopCh        chan struct{}
+	cancel        context.CancelFunc
+	// filter selects the resource types and objects that are synced
+	filter *resourceSyncFilter
+	// fields extracts the configured fields from synced resources
+	fields *resourceFieldExtractor
+	// metadata selects the synced annotations and labels and redacts synced values
+	metadata *resourceMetadataRules
 	// changeQueue receives resource changes from informer event handlers
 	// Buffered to prevent blocking informers during bursts
 	changeQueue chan *ResourceChange
 	// changeQueueClosed indicates the changeQueue has been closed
 	changeQueueClosed bool
 	changeQueueMu     sync.RWMutex
+	// changeQueueWait is how long event handlers wait for room in a full change queue
+	changeQueueWait time.Duration
 	// debounceBuffer collects and batches changes before sending to MCP
 	debounceBuffer *DebounceBuffer
 	// mcpClient handles communication with the MCP endpoint
 	mcpClient *MCPResourceSyncClient
+	// credentialsVersion fingerprints the referenced Secrets; a change requests a resync
+	credentialsVersion string
+	// resyncRequests triggers a resync before the next periodic resync is due
+	resyncRequests chan struct{}
+	// droppedChanges counts changes dropped because the change queue was full
+	droppedChanges atomic.Int64
+	// droppedBuckets are the namespace and resource type buckets with dropped changes
+	droppedBuckets map[string]bool
+	// dropRecoveryRequests triggers a targeted resync of the buckets with dropped changes
+	dropRecoveryRequests chan struct{}
+	// lastDropRecovery is the outcome of the last targeted resync of dropped changes
+	lastDropRecovery *dotaiv1alpha1.DropRecoveryResult
+	droppedMu        sync.Mutex // protects droppedBuckets and lastDropRecovery
+	// resyncProgress is the current or last chunked resync session, resumed when incomplete
+	resyncProgress *dotaiv1alpha1.ResyncProgress
+	// lastIncrementalResync is the outcome of the last incremental resync
+	lastIncrementalResync *dotaiv1alpha1.IncrementalResyncResult
+	resyncMu              sync.Mutex // protects resyncProgress and lastIncrementalResync
 
 	// statusUpdateFailures tracks consecutive status update failures
 	// Used to apply backoff when updates repeatedly fail (e.g., entity too large)
@@ -240,6 +325,14 @@ type activeConfigState struct {
 	statusUpdateMu          sync.Mutex
 }
 
+// requestResync triggers a resync unless one is already pending
+func (s *activeConfigState) requestResync() {
+	select {
+	case s.resyncRequests <- struct{}{}:
+	default:
+	}
+}
+
 // configKey returns a unique key for a ResourceSyncConfig (namespace/name)
 func configKey(config *dotaiv1alpha1.ResourceSyncConfig) string {
 	return config.Namespace + "/" + config.Name
@@ -260,9 +353,15 @@ func parseConfigKey(key string) (namespace, name string) {
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
 // +kubebuilder:rbac:groups=*,resources=*,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts/token,verbs=create
 
 // Reconcile handles ResourceSyncConfig CR changes
-func (r *ResourceSyncReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *ResourceSyncReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	ctx, span := tracing.StartReconcile(ctx, "ResourceSyncConfig", req.NamespacedName)
+	defer func() { tracing.End(span, err) }()
+
 	logger := logf.FromContext(ctx).WithValues("resourcesyncconfig", req.Name)
 
 	// Fetch the ResourceSyncConfig
@@ -272,6 +371,7 @@ func (r *ResourceSyncReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			// CR was deleted - stop watching resources for this config
 			logger.Info("ResourceSyncConfig deleted, stopping resource watcher")
 			r.stopWatcher(req.Namespace + "/" + req.Name)
+			forgetResourceSyncMetrics(req.Namespace + "/" + req.Name)
 			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "Failed to get ResourceSyncConfig")
@@ -280,6 +380,7 @@ func (r *ResourceSyncReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	logger.Info("Reconciling ResourceSyncConfig",
 		"mcpEndpoint", config.Spec.McpEndpoint,
+		"mcpServerRef", config.Spec.McpServerRef,
 		"debounceWindow", config.GetDebounceWindow(),
 		"resyncInterval", config.GetResyncInterval(),
 	)
@@ -298,10 +399,27 @@ func (r *ResourceSyncReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	if exists {
 		// Check if config changed (endpoint, timing, etc.)
-		if r.configChanged(existingState.config, &config) {
+		restart := r.configChanged(existingState.config, &config)
+		if restart {
 			logger.Info("ResourceSyncConfig changed, restarting watcher")
+		} else if r.clusterCredentialsChanged(ctx, &config, existingState) {
+			logger.Info("🔑 Remote cluster kubeconfig changed, restarting watcher")
+			restart = true
+		} else if r.redactionKeyChanged(ctx, &config, existingState) {
+			logger.Info("🔑 Redaction key changed, restarting watcher")
+			restart = true
+		}
+		if restart {
 			r.stopWatcher(configKey(&config))
 		} else {
+			// Rotated credentials may fix failed syncs, so resync with the new Secrets
+			credentialsVersion := secretReferencesVersion(ctx, r.Client, config.Namespace, resourceSyncSecretReferences(&config))
+			if credentialsVersion != existingState.credentialsVersion {
+				existingState.credentialsVersion = credentialsVersion
+				logger.Info("🔑 Referenced Secrets changed, requesting resync")
+				existingState.requestResync()
+			}
+
 			// Config unchanged, just update status with current state
 			existingState.informersMu.RLock()
 			watchedCount := len(existingState.activeInformers)
@@ -319,6 +437,9 @@ func (r *ResourceSyncReconciler) Reconcile(ctx context.Context, req ctrl.Request
 					r.updateSyncErrorCount(ctx, &config, existingState)
 				}
 			}
+			if health := existingState.checkClusterHealth(); health != nil && !health.Reachable && lastError == "" {
+				lastError = "Remote cluster unreachable: " + health.Error
+			}
 			r.updateStatus(ctx, &config, existingState, true, watchedCount, lastError, lastFlushTime)
 			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 		}
@@ -343,40 +464,73 @@ func (r *ResourceSyncReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 // ensureClients initializes the dynamic and discovery clients if needed
 func (r *ResourceSyncReconciler) ensureClients() error {
-	if r.dynamicClient != nil && r.discoveryClient != nil {
+	if r.dynamicClient != nil && r.discoveryClient != nil && r.metadataClient != nil {
 		return nil
 	}
 
-	var err error
-	r.dynamicClient, err = dynamic.NewForConfig(r.RestConfig)
-	if err != nil {
-		return fmt.Errorf("failed to create dynamic client: %w", err)
-	}
-
-	r.discoveryClient, err = discovery.NewDiscoveryClientForConfig(r.RestConfig)
+	clients, err := newClusterClients(r.RestConfig, r.RestConfig.Timeout)
 	if err != nil {
-		return fmt.Errorf("failed to create discovery client: %w", err)
+		return err
 	}
-
+	r.dynamicClient = clients.dynamic
+	r.discoveryClient = clients.discovery
+	r.metadataClient = clients.metadata
 	return nil
 }
 
 // configChanged checks if the relevant config fields have changed
 func (r *ResourceSyncReconciler) configChanged(old, new *dotaiv1alpha1.ResourceSyncConfig) bool {
-	if old.Spec.McpEndpoint != new.Spec.McpEndpoint {
+	if old.Spec.McpEndpoint != new.Spec.McpEndpoint || old.Spec.McpServerRef != new.Spec.McpServerRef {
 		return true
 	}
-	if old.GetDebounceWindow() != new.GetDebounceWindow() {
+	if old.GetDebounceWindow() != new.GetDebounceWindow() || old.Spec.ChangeQueueWaitSeconds != new.Spec.ChangeQueueWaitSeconds {
 		return true
 	}
 	if old.GetResyncInterval() != new.GetResyncInterval() {
 		return true
 	}
+	if old.GetResyncChunkSize() != new.GetResyncChunkSize() || old.GetResyncMode() != new.GetResyncMode() {
+		return true
+	}
+	if old.GetRelationshipMode() != new.GetRelationshipMode() {
+		return true
+	}
+	if !reflect.DeepEqual(old.Spec.Sinks, new.Spec.Sinks) {
+		return true
+	}
+	// A different cluster needs new clients and informers
+	if !reflect.DeepEqual(old.Spec.Cluster, new.Spec.Cluster) || old.GetClusterName() != new.GetClusterName() {
+		return true
+	}
 	// Check auth secret ref changes
 	if old.Spec.McpAuthSecretRef.Name != new.Spec.McpAuthSecretRef.Name ||
 		old.Spec.McpAuthSecretRef.Key != new.Spec.McpAuthSecretRef.Key {
 		return true
 	}
+	if !reflect.DeepEqual(old.Spec.McpAuth, new.Spec.McpAuth) {
+		return true
+	}
+	if !reflect.DeepEqual(old.Spec.McpTLS, new.Spec.McpTLS) || old.Spec.McpProxyURL != new.Spec.McpProxyURL {
+		return true
+	}
+	// Filter changes require new informers and a resync to remove deselected resources
+	if !stringSlicesEqual(old.Spec.IncludeResources, new.Spec.IncludeResources) ||
+		!stringSlicesEqual(old.Spec.ExcludeResources, new.Spec.ExcludeResources) {
+		return true
+	}
+	if !reflect.DeepEqual(old.Spec.NamespaceSelector, new.Spec.NamespaceSelector) ||
+		!reflect.DeepEqual(old.Spec.ObjectSelector, new.Spec.ObjectSelector) {
+		return true
+	}
+	if !reflect.DeepEqual(old.Spec.Fields, new.Spec.Fields) {
+		return true
+	}
+	if !stringSlicesEqual(old.Spec.AnnotationAllowlist, new.Spec.AnnotationAllowlist) ||
+		!stringSlicesEqual(old.Spec.LabelDenylist, new.Spec.LabelDenylist) ||
+		!reflect.DeepEqual(old.Spec.Redactions, new.Spec.Redactions) ||
+		!reflect.DeepEqual(old.Spec.RedactionKeySecretRef, new.Spec.RedactionKeySecretRef) {
+		return true
+	}
 	return false
 }
 
@@ -384,56 +538,104 @@ func (r *ResourceSyncReconciler) configChanged(old, new *dotaiv1alpha1.ResourceS
 func (r *ResourceSyncReconciler) startWatcher(ctx context.Context, config *dotaiv1alpha1.ResourceSyncConfig) error {
 	logger := logf.FromContext(ctx).WithName("resourcesync")
 
+	filter, err := newResourceSyncFilter(config)
+	if err != nil {
+		return err
+	}
+	fields, err := newResourceFieldExtractor(config.Spec.Fields)
+	if err != nil {
+		return err
+	}
+	hashKey, err := redactionKey(ctx, r.Client, config)
+	if err != nil {
+		return err
+	}
+	metadata, err := newResourceMetadataRules(config.Spec, hashKey)
+	if err != nil {
+		return err
+	}
+	clients, err := r.clusterClientsFor(ctx, config)
+	if err != nil {
+		return err
+	}
+	sinks, err := r.newResourceSyncSinks(config)
+	if err != nil {
+		return err
+	}
+
 	// Create a cancellable context for this watcher
 	watcherCtx, cancel := context.WithCancel(context.Background())
 
-	// Create informer factory
-	informerFactory := dynamicinformer.NewDynamicSharedInformerFactory(
-		r.dynamicClient,
-		30*time.Minute, // Cache resync period
-	)
+	// Create informer factories for CRDs and namespaces; resource types get informers of their own
+	informerFactory := dynamicinformer.NewDynamicSharedInformerFactory(clients.dynamic, informerResyncPeriod)
+	metadataInformerFactory := metadatainformer.NewSharedInformerFactory(clients.metadata, informerResyncPeriod)
+
+	// Namespace selectors read namespace labels from the informer that reports their label changes
+	if filter.namespaceSelector != nil {
+		filter.namespaces = metadataInformerFactory.ForResource(namespacesGVR).Lister()
+	}
 
 	// Create change queue
 	changeQueue := make(chan *ResourceChange, changeQueueBufferSize)
 
-	// Create MCP client if endpoint is configured
+	// Create MCP client if endpoint or MCPServer is configured
 	var mcpClient *MCPResourceSyncClient
-	if config.Spec.McpEndpoint != "" {
-		httpClient := r.HttpClient
-		if httpClient == nil {
-			httpClient = &http.Client{
-				Timeout: 60 * time.Second,
-			}
-		}
-
+	if config.Spec.McpEndpoint != "" || config.Spec.McpServerRef != "" {
+		// A nil HttpClient uses the shared MCP connection pool and timeout
 		mcpClient = NewMCPResourceSyncClient(MCPResourceSyncClientConfig{
 			Endpoint:            config.Spec.McpEndpoint,
-			HTTPClient:          httpClient,
+			HTTPClient:          r.HttpClient,
 			K8sClient:           r.Client,
 			AuthSecretRef:       config.Spec.McpAuthSecretRef,
 			AuthSecretNamespace: config.Namespace,
+			Auth:                config.Spec.McpAuth,
+			TLS:                 config.Spec.McpTLS,
+			ProxyURL:            config.Spec.McpProxyURL,
+			ServerRef:           config.Spec.McpServerRef,
+			ClusterName:         config.GetClusterName(),
 		})
-		logger.Info("MCP client created", "endpoint", config.Spec.McpEndpoint)
-	} else {
+		logger.Info("MCP client created", "endpoint", config.Spec.McpEndpoint, "mcpServerRef", config.Spec.McpServerRef)
+	} else if len(sinks) == 0 {
 		logger.Info("MCP endpoint not configured, resource sync will be disabled")
 	}
 
 	// Create debounce buffer
 	debounceBuffer := NewDebounceBuffer(DebounceBufferConfig{
+		ConfigName:  configKey(config),
 		Window:      time.Duration(config.GetDebounceWindow()) * time.Second,
 		MCPClient:   mcpClient,
 		ChangeQueue: changeQueue,
+		Sinks:       sinks,
 	})
 
 	state := &activeConfigState{
-		config:          config.DeepCopy(),
-		informerFactory: informerFactory,
-		activeInformers: make(map[schema.GroupVersionResource]cache.SharedIndexInformer),
-		stopCh:          make(chan struct{}),
-		cancel:          cancel,
-		changeQueue:     changeQueue,
-		debounceBuffer:  debounceBuffer,
-		mcpClient:       mcpClient,
+		config:                  config.DeepCopy(),
+		clients:                 clients,
+		informerFactory:         informerFactory,
+		metadataInformerFactory: metadataInformerFactory,
+		activeInformers:         make(map[schema.GroupVersionResource]cache.SharedIndexInformer),
+		fullObjectResources:     make(map[schema.GroupVersionResource]bool),
+		handlerRegistrations:    make(map[schema.GroupVersionResource]cache.ResourceEventHandlerRegistration),
+		filter:                  filter,
+		fields:                  fields,
+		metadata:                metadata,
+		stopCh:                  make(chan struct{}),
+		cancel:                  cancel,
+		changeQueue:             changeQueue,
+		debounceBuffer:          debounceBuffer,
+		mcpClient:               mcpClient,
+		resyncRequests:          make(chan struct{}, 1),
+		changeQueueWait:         time.Duration(config.Spec.ChangeQueueWaitSeconds) * time.Second,
+		dropRecoveryRequests:    make(chan struct{}, 1),
+		// An incomplete resync session is resumed after a restart
+		resyncProgress:        config.Status.Resync.DeepCopy(),
+		lastIncrementalResync: config.Status.LastIncrementalResync.DeepCopy(),
+		credentialsVersion: secretReferencesVersion(ctx, r.Client, config.Namespace,
+			resourceSyncSecretReferences(config)),
+		clusterCredentialsVersion: secretReferencesVersion(ctx, r.Client, config.Namespace,
+			clusterSecretReferences(config)),
+		redactionKeyVersion: secretReferencesVersion(ctx, r.Client, config.Namespace,
+			redactionKeySecretReferences(config)),
 	}
 
 	// Discover existing resources and setup informers
@@ -450,6 +652,15 @@ func (r *ResourceSyncReconciler) startWatcher(ctx context.Context, config *dotai
 		return fmt.Errorf("failed to setup CRD watcher: %w", err)
 	}
 
+	// Setup namespace watcher for namespaces that start or stop matching the namespace selector
+	if filter.namespaceSelector != nil {
+		if err := r.setupNamespaceWatcher(state); err != nil {
+			cancel()
+			close(changeQueue)
+			return fmt.Errorf("failed to setup namespace watcher: %w", err)
+		}
+	}
+
 	// Store the state
 	r.configsMu.Lock()
 	if r.activeConfigs == nil {
@@ -457,9 +668,16 @@ func (r *ResourceSyncReconciler) startWatcher(ctx context.Context, config *dotai
 	}
 	r.activeConfigs[configKey(config)] = state
 	r.configsMu.Unlock()
+	resourceSyncBuffers.track(configKey(config), state)
 
 	// Start informers
 	informerFactory.Start(state.stopCh)
+	metadataInformerFactory.Start(state.stopCh)
+	state.informersMu.Lock()
+	for gvr := range state.informerStops {
+		state.runInformer(gvr)
+	}
+	state.informersMu.Unlock()
 
 	// Start debounce buffer in background
 	go func() {
@@ -472,6 +690,8 @@ func (r *ResourceSyncReconciler) startWatcher(ctx context.Context, config *dotai
 	go func() {
 		logger.Info("Waiting for informer caches to sync", "config", config.Name)
 		informerFactory.WaitForCacheSync(state.stopCh)
+		metadataInformerFactory.WaitForCacheSync(state.stopCh)
+		cache.WaitForCacheSync(state.stopCh, state.informersSynced()...)
 		logger.Info("Informer caches synced", "config", config.Name)
 
 		// Check if context is still valid
@@ -488,12 +708,14 @@ func (r *ResourceSyncReconciler) startWatcher(ctx context.Context, config *dotai
 		// Start periodic resync loop
 		resyncInterval := time.Duration(config.GetResyncInterval()) * time.Minute
 		go r.periodicResyncLoop(watcherCtx, state, configKey(config), resyncInterval)
+		go r.dropRecoveryLoop(watcherCtx, state, configKey(config))
 	}()
 
 	// Update status
 	state.informersMu.RLock()
 	watchedCount := len(state.activeInformers)
 	state.informersMu.RUnlock()
+	state.checkClusterHealth()
 	r.updateStatus(ctx, config, state, true, watchedCount, "", time.Time{})
 
 	return nil
@@ -509,10 +731,16 @@ func (r *ResourceSyncReconciler) setupCRDWatcher(ctx context.Context, state *act
 		AddFunc: func(obj interface{}) {
 			r.onCRDAdd(state, obj)
 		},
+		// Version changes (e.g., a new storage version) switch the informer to the new version
+		// Status writes update CRDs as well, so other updates return before taking the informers lock
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if r.crdVersionChanged(oldObj, newObj) {
+				r.onCRDAdd(state, newObj)
+			}
+		},
 		DeleteFunc: func(obj interface{}) {
 			r.onCRDDelete(state, obj)
 		},
-		// We don't need UpdateFunc - CRD spec changes don't affect our GVR watching
 	})
 	if err != nil {
 		return fmt.Errorf("failed to add CRD event handler: %w", err)
@@ -526,7 +754,8 @@ func (r *ResourceSyncReconciler) setupCRDWatcher(ctx context.Context, state *act
 	return nil
 }
 
-// onCRDAdd handles new CRD installations
+// onCRDAdd handles new CRD installations and CRD version changes
+// Each resource type is watched under a single version, so a version change replaces the informer
 func (r *ResourceSyncReconciler) onCRDAdd(state *activeConfigState, obj interface{}) {
 	logger := logf.Log.WithName("resourcesync")
 
@@ -543,43 +772,197 @@ func (r *ResourceSyncReconciler) onCRDAdd(state *activeConfigState, obj interfac
 	}
 
 	// Check if we should skip this resource
-	if r.shouldSkipResource(gvr.Group, gvr.Resource) {
+	kind, _, _ := unstructured.NestedString(u.Object, "spec", "names", "kind")
+	if r.shouldSkipResource(gvr.Group, gvr.Resource) || !state.filter.matchesResource(kind, gvr.Group) {
 		logger.V(2).Info("Skipping CRD resource", "gvr", gvr.String())
 		return
 	}
 
-	// Check if we already have an informer for this GVR
-	state.informersMu.RLock()
-	_, exists := state.activeInformers[gvr]
-	state.informersMu.RUnlock()
+	r.recordResourceKind(gvr, kind)
 
-	if exists {
+	state.informersMu.Lock()
+	current, watched := state.watchedGVR(gvr.GroupResource())
+	if watched && current == gvr {
+		state.informersMu.Unlock()
 		return // Already watching this resource type
 	}
 
-	// Create informer for the new CRD
-	logger.Info("New CRD detected, creating informer", "crd", u.GetName(), "gvr", gvr.String())
-
-	informer := state.informerFactory.ForResource(gvr).Informer()
+	if watched {
+		// The version changed, so stop syncing the resource under the old apiVersion
+		logger.Info("CRD version changed, switching informer", "crd", u.GetName(),
+			"from", current.Version, "to", gvr.Version)
+		state.unwatchResource(current)
+	} else {
+		logger.Info("New CRD detected, creating informer", "crd", u.GetName(), "gvr", gvr.String())
+	}
 
-	_, err = informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc:    r.makeOnAdd(state),
-		UpdateFunc: r.makeOnUpdate(state),
-		DeleteFunc: r.makeOnDelete(state),
-	})
+	_, err = r.watchResource(state, gvr, kind)
+	if err == nil {
+		state.runInformer(gvr)
+	}
+	state.informersMu.Unlock()
 	if err != nil {
 		logger.Error(err, "Failed to add event handler for new CRD", "gvr", gvr.String())
 		return
 	}
 
-	state.informersMu.Lock()
+	// Resync so MCP replaces the resources synced under the old apiVersion
+	if watched {
+		state.requestResync()
+	}
+
+	logger.Info("Informer created for new CRD", "gvr", gvr.String())
+}
+
+// watchResource creates the informer of a GVR with the sync event handlers; runInformer starts it
+// Only resource types with field rules cache full objects; the others cache metadata only
+// The caller must hold state.informersMu
+func (r *ResourceSyncReconciler) watchResource(state *activeConfigState, gvr schema.GroupVersionResource, kind string) (cache.SharedIndexInformer, error) {
+	gvk := gvr.GroupVersion().WithKind(kind)
+	fullObjects := state.needsFullObjects(gvk.GroupKind())
+
+	// Relationship sources keep the spec fields their edges are computed from
+	var prune func(obj *unstructured.Unstructured)
+	if !fullObjects && state.computesEdges(gvk.GroupKind()) {
+		prune = pruneForRelationships(relationshipSources[gvk.GroupKind()])
+		fullObjects = true
+	}
+
+	// Informers are created for each watch instead of by the shared factories, which return the
+	// informer of an earlier watch and cannot stop it when the resource type is no longer watched
+	indexers := cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}
+	var informer cache.SharedIndexInformer
+	if fullObjects {
+		informer = dynamicinformer.NewFilteredDynamicInformer(state.clients.dynamic, gvr, metav1.NamespaceAll,
+			informerResyncPeriod, indexers, nil).Informer()
+	} else {
+		informer = metadatainformer.NewFilteredMetadataInformer(state.clients.metadata, gvr, metav1.NamespaceAll,
+			informerResyncPeriod, indexers, nil).Informer()
+	}
+	if err := informer.SetTransform(newResourceCacheTransform(gvk, prune)); err != nil {
+		return nil, err
+	}
+
+	onAdd, onUpdate, onDelete := r.makeOnAdd(state), r.makeOnUpdate(state), r.makeOnDelete(state)
+	registration, err := informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			onAdd(obj)
+			// The initial sync includes the edges of Services to existing Pods
+			if !isInInitialList {
+				r.onPodEvent(state, nil, obj)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			onUpdate(oldObj, newObj)
+			r.onPodEvent(state, oldObj, newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			onDelete(obj)
+			r.onPodEvent(state, obj, nil)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	state.activeInformers[gvr] = informer
-	state.informersMu.Unlock()
+	if state.fullObjectResources == nil {
+		state.fullObjectResources = make(map[schema.GroupVersionResource]bool)
+	}
+	if fullObjects {
+		state.fullObjectResources[gvr] = true
+	}
+	if state.handlerRegistrations == nil {
+		state.handlerRegistrations = make(map[schema.GroupVersionResource]cache.ResourceEventHandlerRegistration)
+	}
+	state.handlerRegistrations[gvr] = registration
+	if state.informerStops == nil {
+		state.informerStops = make(map[schema.GroupVersionResource]chan struct{})
+	}
+	state.informerStops[gvr] = make(chan struct{})
+	return informer, nil
+}
 
-	// Start the informer (the factory handles this for new informers)
-	go informer.Run(state.stopCh)
+// runInformer starts the informer of a watched GVR, which runs until the GVR is unwatched or the
+// watcher stops
+// The caller must hold state.informersMu
+func (s *activeConfigState) runInformer(gvr schema.GroupVersionResource) {
+	informer, watched := s.activeInformers[gvr]
+	stop, owned := s.informerStops[gvr]
+	if !watched || !owned {
+		return
+	}
 
-	logger.Info("Informer created for new CRD", "gvr", gvr.String())
+	watcherStop := s.stopCh
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-watcherStop:
+		case <-stop:
+		}
+		close(done)
+	}()
+	go informer.Run(done)
+}
+
+// informersSynced returns the sync checks of the informers of the watched resource types
+func (s *activeConfigState) informersSynced() []cache.InformerSynced {
+	s.informersMu.RLock()
+	defer s.informersMu.RUnlock()
+
+	synced := make([]cache.InformerSynced, 0, len(s.informerStops))
+	for gvr := range s.informerStops {
+		if informer, ok := s.activeInformers[gvr]; ok {
+			synced = append(synced, informer.HasSynced)
+		}
+	}
+	return synced
+}
+
+// unwatchResource stops syncing a GVR by removing its event handlers and stopping its informer,
+// which releases the cached objects
+// The caller must hold state.informersMu
+func (s *activeConfigState) unwatchResource(gvr schema.GroupVersionResource) {
+	informer, exists := s.activeInformers[gvr]
+	if !exists {
+		return
+	}
+	if registration, ok := s.handlerRegistrations[gvr]; ok {
+		_ = informer.RemoveEventHandler(registration)
+		delete(s.handlerRegistrations, gvr)
+	}
+	if stop, ok := s.informerStops[gvr]; ok {
+		close(stop)
+		delete(s.informerStops, gvr)
+	}
+	delete(s.activeInformers, gvr)
+	delete(s.fullObjectResources, gvr)
+}
+
+// watchedGVR returns the version of a resource type that is watched
+// The caller must hold state.informersMu
+func (s *activeConfigState) watchedGVR(groupResource schema.GroupResource) (schema.GroupVersionResource, bool) {
+	for gvr := range s.activeInformers {
+		if gvr.GroupResource() == groupResource {
+			return gvr, true
+		}
+	}
+	return schema.GroupVersionResource{}, false
+}
+
+// crdVersionChanged checks if a CRD update changed the version its resources are watched under
+func (r *ResourceSyncReconciler) crdVersionChanged(oldObj, newObj interface{}) bool {
+	oldCRD, oldOK := oldObj.(*unstructured.Unstructured)
+	newCRD, newOK := newObj.(*unstructured.Unstructured)
+	if !oldOK || !newOK {
+		return false
+	}
+	newGVR, err := r.gvrFromCRD(newCRD)
+	if err != nil {
+		return false
+	}
+	oldGVR, err := r.gvrFromCRD(oldCRD)
+	return err != nil || oldGVR != newGVR
 }
 
 // onCRDDelete handles CRD removals
@@ -607,11 +990,11 @@ func (r *ResourceSyncReconciler) onCRDDelete(state *activeConfigState, obj inter
 		return
 	}
 
-	// Remove the informer for this GVR
+	// Remove the informer for this resource type, whichever version is watched
 	state.informersMu.Lock()
-	if _, exists := state.activeInformers[gvr]; exists {
-		delete(state.activeInformers, gvr)
-		logger.Info("CRD deleted, removed informer", "crd", u.GetName(), "gvr", gvr.String())
+	if current, watched := state.watchedGVR(gvr.GroupResource()); watched {
+		state.unwatchResource(current)
+		logger.Info("CRD deleted, removed informer", "crd", u.GetName(), "gvr", current.String())
 	}
 	state.informersMu.Unlock()
 }
@@ -690,11 +1073,14 @@ func (r *ResourceSyncReconciler) stopWatcher(name string) {
 
 	// Stop the watcher - cancel context first to stop debounce buffer
 	state.cancel()
+	// Cancel the trailing syncs of throttled field changes
+	state.fields.stop()
 	// Close stopCh to stop informers
 	close(state.stopCh)
 	// Close change queue to signal debounce buffer to exit
 	close(state.changeQueue)
 	delete(r.activeConfigs, name)
+	resourceSyncBuffers.untrack(name)
 }
 
 // discoverAndSetupInformers discovers all built-in resource types and creates informers
@@ -703,7 +1089,7 @@ func (r *ResourceSyncReconciler) discoverAndSetupInformers(ctx context.Context,
 	logger := logf.FromContext(ctx).WithName("resourcesync")
 
 	// Discover all resource types (built-in + existing CRDs)
-	gvrs, err := r.discoverResources(ctx)
+	gvrs, err := r.discoverResources(ctx, state.clients, state.filter)
 	if err != nil {
 		return fmt.Errorf("failed to discover resources: %w", err)
 	}
@@ -719,34 +1105,30 @@ func (r *ResourceSyncReconciler) discoverAndSetupInformers(ctx context.Context,
 			continue // Already have an informer for this GVR
 		}
 
-		// Create informer for this GVR
-		informer := state.informerFactory.ForResource(gvr).Informer()
-
-		// Add event handlers (will be fully implemented in M2)
-		_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-			AddFunc:    r.makeOnAdd(state),
-			UpdateFunc: r.makeOnUpdate(state),
-			DeleteFunc: r.makeOnDelete(state),
-		})
-		if err != nil {
+		// Create informer for this GVR with the sync event handlers
+		if _, err := r.watchResource(state, gvr, r.resourceKind(gvr)); err != nil {
 			logger.Error(err, "Failed to add event handler", "gvr", gvr.String())
 			continue
 		}
 
-		state.activeInformers[gvr] = informer
-		logger.V(1).Info("Created informer", "gvr", gvr.String())
+		logger.V(1).Info("Created informer", "gvr", gvr.String(), "fullObjects", state.fullObjectResources[gvr])
 	}
 
 	logger.Info("Informer setup complete", "activeInformers", len(state.activeInformers))
 	return nil
 }
 
-// discoverResources discovers all watchable resource types in the cluster
-func (r *ResourceSyncReconciler) discoverResources(ctx context.Context) ([]schema.GroupVersionResource, error) {
+// discoverResources discovers all watchable resource types in the cluster that pass the filter
+// Each resource type is returned once, under its preferred version, or the storage version for CRDs,
+// so resources served under multiple versions are not synced multiple times
+func (r *ResourceSyncReconciler) discoverResources(ctx context.Context, clients *clusterClients, filter *resourceSyncFilter) ([]schema.GroupVersionResource, error) {
 	logger := logf.FromContext(ctx).WithName("resourcesync")
 
-	// Get all API resources
-	_, resources, err := r.discoveryClient.ServerGroupsAndResources()
+	// CRD resources use the same version as the CRD watcher, so it does not switch informers on startup
+	crdVersions := r.crdResourceVersions(ctx, clients)
+
+	// Get the API resources under their preferred versions
+	resources, err := clients.discovery.ServerPreferredResources()
 	if err != nil {
 		// Discovery can return partial results with errors for unavailable API groups
 		if !discovery.IsGroupDiscoveryFailedError(err) {
@@ -756,6 +1138,7 @@ func (r *ResourceSyncReconciler) discoverResources(ctx context.Context) ([]schem
 	}
 
 	var gvrs []schema.GroupVersionResource
+	seen := make(map[schema.GroupResource]bool)
 	for _, resourceList := range resources {
 		if resourceList == nil {
 			continue
@@ -784,18 +1167,55 @@ func (r *ResourceSyncReconciler) discoverResources(ctx context.Context) ([]schem
 				continue
 			}
 
+			// Apply include/exclude filters
+			if !filter.matchesResource(resource.Kind, gv.Group) {
+				logger.V(2).Info("Resource excluded by filters", "group", gv.Group, "resource", resource.Name)
+				continue
+			}
+
 			gvr := schema.GroupVersionResource{
 				Group:    gv.Group,
 				Version:  gv.Version,
 				Resource: resource.Name,
 			}
+			if crdGVR, isCRD := crdVersions[gvr.GroupResource()]; isCRD {
+				gvr = crdGVR
+			}
+
+			// Deduplicate (same resource may appear in multiple versions)
+			if seen[gvr.GroupResource()] {
+				continue
+			}
+			seen[gvr.GroupResource()] = true
 			gvrs = append(gvrs, gvr)
+			r.recordResourceKind(gvr, resource.Kind)
 		}
 	}
 
 	return gvrs, nil
 }
 
+// crdResourceVersions returns the GVR of each CRD resource, as selected by gvrFromCRD
+// Returns an empty map when CRDs cannot be listed; the CRD watcher then switches versions if needed
+func (r *ResourceSyncReconciler) crdResourceVersions(ctx context.Context, clients *clusterClients) map[schema.GroupResource]schema.GroupVersionResource {
+	logger := logf.FromContext(ctx).WithName("resourcesync")
+
+	versions := make(map[schema.GroupResource]schema.GroupVersionResource)
+	crds, err := clients.dynamic.Resource(crdGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		logger.V(1).Info("Failed to list CRDs, using preferred versions", "error", err)
+		return versions
+	}
+	for i := range crds.Items {
+		gvr, err := r.gvrFromCRD(&crds.Items[i])
+		if err != nil {
+			continue
+		}
+		versions[gvr.GroupResource()] = gvr
+	}
+	return versions
+}
+
 // shouldSkipResource returns true if the resource should not be watched
 func (r *ResourceSyncReconciler) shouldSkipResource(group, resource string) bool {
 	// Skip Kubernetes Events (high volume, low signal for resource visibility)
@@ -848,7 +1268,7 @@ func extractResourceData(obj *unstructured.Unstructured) *ResourceData {
 	annotations := make(map[string]string)
 	for k, v := range obj.GetAnnotations() {
 		// Skip kubectl's last-applied-configuration (can be very large)
-		if k == "kubectl.kubernetes.io/last-applied-configuration" {
+		if k == lastAppliedConfigAnnotation {
 			continue
 		}
 		// Skip managed fields annotation
@@ -880,32 +1300,69 @@ func extractResourceData(obj *unstructured.Unstructured) *ResourceData {
 	}
 }
 
+// resourceData extracts the resource data synced for a resource, including the configured
+// fields, with the annotation, label and redaction rules of the config applied
+func (s *activeConfigState) resourceData(obj *unstructured.Unstructured) *ResourceData {
+	data := extractResourceData(obj)
+	data.ClusterName = s.clusterName()
+	data.Fields = s.fields.extract(obj)
+	data.Relationships = s.relationships(obj)
+	s.metadata.apply(obj, data)
+	data.ContentHash = contentHash(data)
+	return data
+}
+
 // hasRelevantChanges checks if the resource has changes worth syncing
-// Only compares labels - status is fetched on-demand from K8s API
-func hasRelevantChanges(oldObj, newObj *unstructured.Unstructured) bool {
-	// Compare labels only - status changes don't trigger sync
-	return !reflect.DeepEqual(oldObj.GetLabels(), newObj.GetLabels())
+// Compares the synced labels and annotations, after the allowlist, denylist and redactions of
+// rules - status is fetched on-demand from K8s API, and extracted fields are throttled separately
+func hasRelevantChanges(oldObj, newObj *unstructured.Unstructured, rules *resourceMetadataRules) bool {
+	oldData, newData := extractResourceData(oldObj), extractResourceData(newObj)
+	rules.apply(oldObj, oldData)
+	rules.apply(newObj, newData)
+	return !reflect.DeepEqual(oldData.Labels, newData.Labels) || !reflect.DeepEqual(oldData.Annotations, newData.Annotations)
 }
 
 // Event handler factories
 
 // trySendChange attempts to send a change to the queue, returning false if queue is closed or full
+// A full queue is retried for up to changeQueueWait; changes dropped after that are recovered
+// by a targeted resync of their namespace and resource type
 func trySendChange(state *activeConfigState, change *ResourceChange) bool {
-	// Check if queue is closed first
-	state.changeQueueMu.RLock()
-	closed := state.changeQueueClosed
-	state.changeQueueMu.RUnlock()
-
-	if closed {
-		return false
+	deadline := time.Now().Add(state.changeQueueWait)
+	for {
+		sent, closed := state.offerChange(change)
+		if sent {
+			return true
+		}
+		if closed {
+			return false
+		}
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			break
+		}
+		time.Sleep(min(wait, changeQueueRetryInterval))
 	}
 
-	// Try to send (non-blocking)
+	state.recordDroppedChange(change)
+	return false
+}
+
+// offerChange sends a change to the queue unless it is full or closed
+// The read lock is only held for the non-blocking send, so waiting handlers do not delay
+// stopping the watcher.
+func (s *activeConfigState) offerChange(change *ResourceChange) (sent, closed bool) {
+	s.changeQueueMu.RLock()
+	defer s.changeQueueMu.RUnlock()
+
+	if s.changeQueueClosed {
+		return false, true
+	}
 	select {
-	case state.changeQueue <- change:
-		return true
+	case s.changeQueue <- change:
+		return true, false
 	default:
-		return false
+		return false, false
 	}
 }
 
@@ -914,15 +1371,21 @@ func (r *ResourceSyncReconciler) makeOnAdd(state *activeConfigState) func(obj in
 	logger := logf.Log.WithName("resourcesync")
 
 	return func(obj interface{}) {
-		u, ok := obj.(*unstructured.Unstructured)
+		u, ok := cachedUnstructured(obj)
 		if !ok {
-			logger.V(2).Info("OnAdd received non-unstructured object", "type", fmt.Sprintf("%T", obj))
+			logger.V(2).Info("OnAdd received unexpected object type", "type", fmt.Sprintf("%T", obj))
+			return
+		}
+
+		if !r.selectsObject(context.Background(), state.filter, u) {
+			logger.V(3).Info("Resource not selected, skipping add event", "id", buildResourceID(u))
 			return
 		}
 
 		// Extract resource data and build internal ID for deduplication
-		data := extractResourceData(u)
+		data := state.resourceData(u)
 		id := buildResourceID(u)
+		state.fields.synced(id, time.Now())
 
 		// Queue the change (non-blocking with select to handle full queue)
 		change := &ResourceChange{
@@ -934,7 +1397,7 @@ func (r *ResourceSyncReconciler) makeOnAdd(state *activeConfigState) func(obj in
 		if trySendChange(state, change) {
 			logger.V(2).Info("Queued resource add", "id", id)
 		} else {
-			// Queue is full or closed - log and drop (debounce buffer will catch this on resync)
+			// Queue is full or closed - dropped changes are recovered by a targeted resync
 			logger.V(1).Info("Change queue full or closed, dropping add event", "id", id)
 		}
 	}
@@ -945,27 +1408,45 @@ func (r *ResourceSyncReconciler) makeOnUpdate(state *activeConfigState) func(old
 	logger := logf.Log.WithName("resourcesync")
 
 	return func(oldObj, newObj interface{}) {
-		oldU, ok := oldObj.(*unstructured.Unstructured)
+		oldU, ok := cachedUnstructured(oldObj)
 		if !ok {
-			logger.V(2).Info("OnUpdate received non-unstructured old object", "type", fmt.Sprintf("%T", oldObj))
+			logger.V(2).Info("OnUpdate received unexpected old object type", "type", fmt.Sprintf("%T", oldObj))
 			return
 		}
 
-		newU, ok := newObj.(*unstructured.Unstructured)
+		newU, ok := cachedUnstructured(newObj)
 		if !ok {
-			logger.V(2).Info("OnUpdate received non-unstructured new object", "type", fmt.Sprintf("%T", newObj))
+			logger.V(2).Info("OnUpdate received unexpected new object type", "type", fmt.Sprintf("%T", newObj))
 			return
 		}
 
-		// Check if there are relevant changes
+		// Check if there are relevant changes (labels and annotations, or extracted fields outside their throttle window)
 		id := buildResourceID(newU)
-		if !hasRelevantChanges(oldU, newU) {
+		if !hasRelevantChanges(oldU, newU, state.metadata) && !state.relationshipsChanged(oldU, newU) &&
+			!state.fields.changedFieldsAllowed(id, oldU, newU, time.Now(), r.makeDeferredFieldSync(state, newU)) {
 			logger.V(3).Info("No relevant changes detected", "id", id)
 			return
 		}
 
-		// Extract resource data
-		data := extractResourceData(newU)
+		// Remove resources whose labels stopped matching the object selector
+		if !r.selectsObject(context.Background(), state.filter, newU) {
+			if !r.selectsObject(context.Background(), state.filter, oldU) {
+				logger.V(3).Info("Resource not selected, skipping update event", "id", id)
+				return
+			}
+			state.fields.forget(id)
+			if trySendChange(state, newDeleteChange(newU, state.clusterName())) {
+				logger.V(2).Info("Queued delete of deselected resource", "id", id)
+			} else {
+				logger.V(1).Info("Change queue full or closed, dropping delete of deselected resource", "id", id)
+			}
+			return
+		}
+
+		// Extract resource data; the upsert includes the current fields, so a trailing sync of
+		// throttled fields is no longer needed
+		data := state.resourceData(newU)
+		state.fields.synced(id, time.Now())
 
 		// Queue the change
 		change := &ResourceChange{
@@ -977,70 +1458,100 @@ func (r *ResourceSyncReconciler) makeOnUpdate(state *activeConfigState) func(old
 		if trySendChange(state, change) {
 			logger.V(2).Info("Queued resource update", "id", id)
 		} else {
-			// Queue is full or closed - log and drop
+			// Queue is full or closed - dropped changes are recovered by a targeted resync
 			logger.V(1).Info("Change queue full or closed, dropping update event", "id", id)
 		}
 	}
 }
 
+// makeDeferredFieldSync creates the handler of throttled field changes of a resource, which queues
+// an upsert of its current version in the informer cache when its throttle window ends
+func (r *ResourceSyncReconciler) makeDeferredFieldSync(state *activeConfigState, changed *unstructured.Unstructured) func() {
+	logger := logf.Log.WithName("resourcesync")
+	gvk := changed.GroupVersionKind()
+	key, _ := cache.MetaNamespaceKeyFunc(changed)
+
+	return func() {
+		obj, exists := state.cachedObject(gvk, key)
+		if !exists {
+			// Deleted or no longer watched resources are synced by their delete events
+			logger.V(3).Info("Resource no longer cached, skipping throttled field change", "key", key, "kind", gvk.Kind)
+			return
+		}
+		id := buildResourceID(obj)
+		if !r.selectsObject(context.Background(), state.filter, obj) {
+			logger.V(3).Info("Resource not selected, skipping throttled field change", "id", id)
+			return
+		}
+
+		change := &ResourceChange{
+			Action: ActionUpsert,
+			Data:   state.resourceData(obj),
+			ID:     id,
+		}
+		if trySendChange(state, change) {
+			logger.V(2).Info("Queued throttled field change", "id", id)
+		} else {
+			logger.V(1).Info("Change queue full or closed, dropping throttled field change", "id", id)
+		}
+	}
+}
+
 // makeOnDelete creates an OnDelete handler that queues resource deletions for syncing
 func (r *ResourceSyncReconciler) makeOnDelete(state *activeConfigState) func(obj interface{}) {
 	logger := logf.Log.WithName("resourcesync")
 
 	return func(obj interface{}) {
-		var u *unstructured.Unstructured
-
-		switch t := obj.(type) {
-		case *unstructured.Unstructured:
-			u = t
-		case cache.DeletedFinalStateUnknown:
-			// Object was deleted before we could process it
-			var ok bool
-			u, ok = t.Obj.(*unstructured.Unstructured)
-			if !ok {
-				logger.V(2).Info("OnDelete DeletedFinalStateUnknown contains non-unstructured object",
-					"type", fmt.Sprintf("%T", t.Obj))
-				return
-			}
-		default:
+		// Tombstones of objects deleted before we could process them are unwrapped
+		u, ok := cachedUnstructured(obj)
+		if !ok {
 			logger.V(2).Info("OnDelete received unexpected object type", "type", fmt.Sprintf("%T", obj))
 			return
 		}
 
-		// Build the resource ID for internal deduplication
+		// Namespace labels are not checked since the namespace may already be gone
 		id := buildResourceID(u)
-
-		// Build the identifier for MCP to construct the ID
-		// For cluster-scoped resources, use "_cluster" as the namespace
-		namespace := u.GetNamespace()
-		if namespace == "" {
-			namespace = clusterScopeNamespace
-		}
-
-		deleteIdentifier := &ResourceIdentifier{
-			Namespace:  namespace,
-			Name:       u.GetName(),
-			Kind:       u.GetKind(),
-			APIVersion: u.GetAPIVersion(),
+		state.fields.forget(id)
+		if !state.filter.matchesLabels(u.GetLabels()) {
+			logger.V(3).Info("Resource not selected, skipping delete event", "id", id)
+			return
 		}
 
 		// Queue the deletion
-		change := &ResourceChange{
-			Action:           ActionDelete,
-			Data:             nil, // No data needed for deletes
-			ID:               id,
-			DeleteIdentifier: deleteIdentifier,
-		}
+		change := newDeleteChange(u, state.clusterName())
 
 		if trySendChange(state, change) {
 			logger.V(2).Info("Queued resource delete", "id", id)
 		} else {
-			// Queue is full or closed - log and drop (resync will catch orphaned records)
+			// Queue is full or closed - dropped deletes are recovered by a targeted resync
 			logger.V(1).Info("Change queue full or closed, dropping delete event", "id", id)
 		}
 	}
 }
 
+// newDeleteChange builds the change that removes a resource from MCP
+func newDeleteChange(u *unstructured.Unstructured, clusterName string) *ResourceChange {
+	// Build the identifier for MCP to construct the ID
+	// For cluster-scoped resources, use "_cluster" as the namespace
+	namespace := u.GetNamespace()
+	if namespace == "" {
+		namespace = clusterScopeNamespace
+	}
+
+	return &ResourceChange{
+		Action: ActionDelete,
+		Data:   nil, // No data needed for deletes
+		ID:     buildResourceID(u),
+		DeleteIdentifier: &ResourceIdentifier{
+			Namespace:   namespace,
+			Name:        u.GetName(),
+			Kind:        u.GetKind(),
+			APIVersion:  u.GetAPIVersion(),
+			ClusterName: clusterName,
+		},
+	}
+}
+
 // updateSyncErrorCount increments the sync error count for a ResourceSyncConfig
 func (r *ResourceSyncReconciler) updateSyncErrorCount(ctx context.Context, config *dotaiv1alpha1.ResourceSyncConfig, state *activeConfigState) {
 	logger := logf.FromContext(ctx)
@@ -1129,7 +1640,24 @@ func (r *ResourceSyncReconciler) updateStatus(ctx context.Context, config *dotai
 	// Update status fields
 	fresh.Status.Active = active
 	fresh.Status.WatchedResourceTypes = watchedTypes
+	fresh.Status.WatchedResources = nil
+	if state != nil && active {
+		fresh.Status.WatchedResources = state.watchedResources()
+	}
 	fresh.Status.LastError = truncateErrorMessage(lastError)
+	// The last health of a remote cluster is kept while its watcher is restarted
+	if state != nil {
+		fresh.Status.Cluster = state.clusterHealthSnapshot()
+	} else if fresh.Spec.Cluster == nil {
+		fresh.Status.Cluster = nil
+	}
+	if state != nil && state.debounceBuffer != nil {
+		fresh.Status.Sinks = state.debounceBuffer.SinkStatuses()
+	}
+	if state != nil {
+		fresh.Status.DroppedChanges = state.droppedChanges.Load()
+		fresh.Status.LastDropRecovery = state.lastDropRecoverySnapshot()
+	}
 
 	// Update LastSyncTime from debounce buffer's lastFlushTime if it's more recent
 	if !lastFlushTime.IsZero() {
@@ -1185,6 +1713,20 @@ func (r *ResourceSyncReconciler) updateStatus(ctx context.Context, config *dotai
 	if !updated {
 		fresh.Status.Conditions = append(fresh.Status.Conditions, readyCondition)
 	}
+	setMCPServerReadyCondition(ctx, r.Client, &fresh.Status.Conditions, fresh.Spec.McpServerRef, fresh.Generation)
+	setCredentialsResolvedCondition(ctx, r.Client, &fresh.Status.Conditions, fresh.Namespace,
+		resourceSyncSecretReferences(fresh), fresh.Generation)
+
+	// Record health transitions for notifications
+	// Sync errors make the config unhealthy even while the watcher is active
+	healthy := active && lastError == ""
+	reason := readyCondition.Reason
+	message := readyCondition.Message
+	if active && lastError != "" {
+		reason = "SyncError"
+		message = conditionMessage
+	}
+	transition := r.Notifier.Prepare(fresh.Spec.Notifications, &fresh.Status.Notifications, healthy, reason, message)
 
 	// Sanitize status before update to prevent entity too large errors
 	sanitizeStatus(&fresh.Status)
@@ -1213,6 +1755,7 @@ func (r *ResourceSyncReconciler) updateStatus(ctx context.Context, config *dotai
 		state.statusUpdateFailures = 0
 		state.statusUpdateMu.Unlock()
 	}
+	r.Notifier.Send(ctx, fresh, "ResourceSyncConfig", fresh.Spec.Notifications, transition)
 }
 
 // Resync functions
@@ -1244,16 +1787,21 @@ func (r *ResourceSyncReconciler) listAllResources(state *activeConfigState) []*R
 		)
 
 		for _, item := range items {
-			u, ok := item.(*unstructured.Unstructured)
+			u, ok := cachedUnstructured(item)
 			if !ok {
-				logger.V(2).Info("Skipping non-unstructured item",
+				logger.V(2).Info("Skipping item of unexpected type",
 					"gvr", gvr.String(),
 					"type", fmt.Sprintf("%T", item),
 				)
 				continue
 			}
 
-			data := extractResourceData(u)
+			// Deselected resources are left out so MCP removes them
+			if !r.selectsObject(context.Background(), state.filter, u) {
+				continue
+			}
+
+			data := state.resourceData(u)
 			allResources = append(allResources, data)
 		}
 	}
@@ -1268,12 +1816,13 @@ func (r *ResourceSyncReconciler) listAllResources(state *activeConfigState) []*R
 
 // performResync sends all current resources to MCP for reconciliation
 // MCP will diff against Qdrant and handle any drift (insert new, update changed, delete missing)
-// performResync syncs all resources to MCP and returns the resource count
+// Resources are uploaded in chunks within a resync session, or in a single request when MCP
+// does not support sessions. The other sinks receive all resources as upserts. Returns the resource count
 func (r *ResourceSyncReconciler) performResync(ctx context.Context, state *activeConfigState) (int, error) {
 	logger := logf.FromContext(ctx).WithName("resourcesync")
 
-	if state.mcpClient == nil {
-		logger.V(1).Info("MCP client not configured, skipping resync")
+	if state.mcpClient == nil && !state.debounceBuffer.hasSinks() {
+		logger.V(1).Info("No sink configured, skipping resync")
 		return 0, nil
 	}
 
@@ -1286,10 +1835,37 @@ func (r *ResourceSyncReconciler) performResync(ctx context.Context, state *activ
 		return 0, nil
 	}
 
+	state.debounceBuffer.ResyncSinks(ctx, allResources)
+	if state.mcpClient == nil {
+		return resourceCount, nil
+	}
+
 	logger.Info("Performing resync with MCP",
 		"resourceCount", resourceCount,
 	)
 
+	if state.config.GetResyncMode() == dotaiv1alpha1.ResyncModeIncremental {
+		err := r.performIncrementalResync(ctx, state, allResources)
+		if !errors.Is(err, ErrResyncDigestsUnsupported) {
+			if err != nil {
+				logger.Error(err, "Failed to resync resources to MCP incrementally")
+				return resourceCount, fmt.Errorf("incremental resync failed: %w", err)
+			}
+			return resourceCount, nil
+		}
+		logger.V(1).Info("MCP does not support resync digests, uploading all resources")
+	}
+
+	err := r.performChunkedResync(ctx, state, allResources)
+	if !errors.Is(err, ErrResyncSessionsUnsupported) {
+		if err != nil {
+			logger.Error(err, "Failed to resync resources to MCP")
+			return resourceCount, fmt.Errorf("resync failed: %w", err)
+		}
+		return resourceCount, nil
+	}
+	logger.V(1).Info("MCP does not support resync sessions, sending all resources in a single request")
+
 	// Send to MCP with IsResync flag
 	resp, err := state.mcpClient.Resync(ctx, allResources)
 	if err != nil {
@@ -1355,6 +1931,8 @@ func (r *ResourceSyncReconciler) performInitialSync(ctx context.Context, state *
 	now := metav1.NewTime(time.Now())
 	config.Status.LastResyncTime = &now
 	config.Status.LastSyncTime = &now
+	config.Status.Resync = state.resyncProgressSnapshot()
+	config.Status.LastIncrementalResync = state.lastIncrementalResyncSnapshot()
 
 	if err != nil {
 		// Use capped increment and truncated error message
@@ -1409,79 +1987,83 @@ func (r *ResourceSyncReconciler) periodicResyncLoop(ctx context.Context, state *
 		case <-ctx.Done():
 			logger.Info("Periodic resync loop stopping", "config", configName)
 			return
-
 		case <-ticker.C:
-			// Check if this config is still active
-			r.configsMu.RLock()
-			currentState, exists := r.activeConfigs[configName]
-			r.configsMu.RUnlock()
-
-			if !exists || currentState != state {
-				logger.Info("Config no longer active, stopping resync loop", "config", configName)
-				return
-			}
+		case <-state.resyncRequests:
+			logger.Info("Resync requested", "config", configName)
+		}
 
-			// Check if we're in backoff mode
-			state.statusUpdateMu.Lock()
-			if state.statusUpdateFailures > 0 && time.Since(state.lastStatusUpdateFailure) < statusUpdateBackoffDuration {
-				state.statusUpdateMu.Unlock()
-				logger.V(1).Info("Skipping periodic resync status update due to backoff",
-					"failures", state.statusUpdateFailures)
-				continue
-			}
-			state.statusUpdateMu.Unlock()
+		// Check if this config is still active
+		r.configsMu.RLock()
+		currentState, exists := r.activeConfigs[configName]
+		r.configsMu.RUnlock()
 
-			logger.Info("Starting periodic resync", "config", configName)
+		if !exists || currentState != state {
+			logger.Info("Config no longer active, stopping resync loop", "config", configName)
+			return
+		}
 
-			resourceCount, err := r.performResync(ctx, state)
+		// Check if we're in backoff mode
+		state.statusUpdateMu.Lock()
+		if state.statusUpdateFailures > 0 && time.Since(state.lastStatusUpdateFailure) < statusUpdateBackoffDuration {
+			state.statusUpdateMu.Unlock()
+			logger.V(1).Info("Skipping periodic resync status update due to backoff",
+				"failures", state.statusUpdateFailures)
+			continue
+		}
+		state.statusUpdateMu.Unlock()
 
-			// Update status
-			configNamespace, configNameOnly := parseConfigKey(configName)
-			var config dotaiv1alpha1.ResourceSyncConfig
-			if getErr := r.Get(ctx, client.ObjectKey{Namespace: configNamespace, Name: configNameOnly}, &config); getErr != nil {
-				logger.Error(getErr, "Failed to fetch ResourceSyncConfig for status update")
-				continue
-			}
+		logger.Info("Starting periodic resync", "config", configName)
 
-			now := metav1.NewTime(time.Now())
-			config.Status.LastResyncTime = &now
-			config.Status.LastSyncTime = &now
+		resourceCount, err := r.performResync(ctx, state)
 
-			if err != nil {
-				// Use capped increment and truncated error message
-				config.Status.SyncErrors = incrementSyncErrors(config.Status.SyncErrors)
-				config.Status.LastError = truncateErrorMessage(err.Error())
-				logger.Error(err, "Periodic resync failed", "config", configName)
-			} else {
-				config.Status.LastError = ""
-				// Use count from performResync (avoids redundant listAllResources call)
-				config.Status.TotalResourcesSynced = int64(resourceCount)
-				logger.Info("Periodic resync completed", "config", configName, "resourceCount", resourceCount)
-			}
+		// Update status
+		configNamespace, configNameOnly := parseConfigKey(configName)
+		var config dotaiv1alpha1.ResourceSyncConfig
+		if getErr := r.Get(ctx, client.ObjectKey{Namespace: configNamespace, Name: configNameOnly}, &config); getErr != nil {
+			logger.Error(getErr, "Failed to fetch ResourceSyncConfig for status update")
+			continue
+		}
 
-			// Sanitize status before update
-			sanitizeStatus(&config.Status)
+		now := metav1.NewTime(time.Now())
+		config.Status.LastResyncTime = &now
+		config.Status.LastSyncTime = &now
+		config.Status.Resync = state.resyncProgressSnapshot()
+		config.Status.LastIncrementalResync = state.lastIncrementalResyncSnapshot()
 
-			if statusErr := r.Status().Update(ctx, &config); statusErr != nil {
-				// Track failure for backoff
-				state.statusUpdateMu.Lock()
-				state.statusUpdateFailures++
-				state.lastStatusUpdateFailure = time.Now()
-				state.statusUpdateMu.Unlock()
+		if err != nil {
+			// Use capped increment and truncated error message
+			config.Status.SyncErrors = incrementSyncErrors(config.Status.SyncErrors)
+			config.Status.LastError = truncateErrorMessage(err.Error())
+			logger.Error(err, "Periodic resync failed", "config", configName)
+		} else {
+			config.Status.LastError = ""
+			// Use count from performResync (avoids redundant listAllResources call)
+			config.Status.TotalResourcesSynced = int64(resourceCount)
+			logger.Info("Periodic resync completed", "config", configName, "resourceCount", resourceCount)
+		}
 
-				if isEntityTooLargeError(statusErr) {
-					logger.Error(statusErr, "Periodic resync status update failed due to entity size limit, entering backoff mode")
-				} else {
-					logger.Error(statusErr, "Failed to update status after periodic resync")
-				}
-				continue
-			}
+		// Sanitize status before update
+		sanitizeStatus(&config.Status)
 
-			// Success - reset failure tracking
+		if statusErr := r.Status().Update(ctx, &config); statusErr != nil {
+			// Track failure for backoff
 			state.statusUpdateMu.Lock()
-			state.statusUpdateFailures = 0
+			state.statusUpdateFailures++
+			state.lastStatusUpdateFailure = time.Now()
 			state.statusUpdateMu.Unlock()
+
+			if isEntityTooLargeError(statusErr) {
+				logger.Error(statusErr, "Periodic resync status update failed due to entity size limit, entering backoff mode")
+			} else {
+				logger.Error(statusErr, "Failed to update status after periodic resync")
+			}
+			continue
 		}
+
+		// Success - reset failure tracking
+		state.statusUpdateMu.Lock()
+		state.statusUpdateFailures = 0
+		state.statusUpdateMu.Unlock()
 	}
 }
 
@@ -1527,6 +2109,51 @@ func (r *ResourceSyncReconciler) GetWatchedGVRs(configName string) []schema.Grou
 func (r *ResourceSyncReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&dotaiv1alpha1.ResourceSyncConfig{}).
+		Watches(
+			&dotaiv1alpha1.MCPServer{},
+			handler.EnqueueRequestsFromMapFunc(r.mapMCPServerToRequests),
+		).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.mapSecretToRequests),
+			builder.WithPredicates(secretDataChangedPredicate()),
+		).
 		Named("resourcesync").
 		Complete(r)
 }
+
+// mapMCPServerToRequests enqueues the ResourceSyncConfigs that reference an MCPServer,
+// so that they surface its readiness
+func (r *ResourceSyncReconciler) mapMCPServerToRequests(ctx context.Context, obj client.Object) []reconcile.Request {
+	var configs dotaiv1alpha1.ResourceSyncConfigList
+	if err := r.List(ctx, &configs); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list ResourceSyncConfigs for MCPServer", "mcpserver", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, config := range configs.Items {
+		if config.Spec.McpServerRef == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&config)})
+		}
+	}
+	return requests
+}
+
+// mapSecretToRequests enqueues the ResourceSyncConfigs that reference a Secret,
+// so that they validate it again and resync when it is rotated
+func (r *ResourceSyncReconciler) mapSecretToRequests(ctx context.Context, obj client.Object) []reconcile.Request {
+	var configs dotaiv1alpha1.ResourceSyncConfigList
+	if err := r.List(ctx, &configs, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list ResourceSyncConfigs for Secret", "secret", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, config := range configs.Items {
+		if referencesSecret(resourceSyncSecretReferences(&config), obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&config)})
+		}
+	}
+	return requests
+}
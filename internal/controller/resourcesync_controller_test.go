@@ -730,7 +730,7 @@ var _ = Describe("ResourceSync Controller", func() {
 			Expect(reconciler.ensureClients()).To(Succeed())
 
 			// Discover resources
-			gvrs, err := reconciler.discoverResources(testCtx)
+			gvrs, err := reconciler.discoverResources(testCtx, reconciler.localClusterClients(), nil)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(len(gvrs)).To(BeNumerically(">", 0))
 
@@ -759,7 +759,7 @@ var _ = Describe("ResourceSync Controller", func() {
 			Expect(reconciler.ensureClients()).To(Succeed())
 
 			// Discover resources
-			gvrs, err := reconciler.discoverResources(testCtx)
+			gvrs, err := reconciler.discoverResources(testCtx, reconciler.localClusterClients(), nil)
 			Expect(err).NotTo(HaveOccurred())
 
 			// Check that skipped resources are not included
@@ -775,7 +775,7 @@ var _ = Describe("ResourceSync Controller", func() {
 			Expect(reconciler.ensureClients()).To(Succeed())
 
 			// Discover resources
-			gvrs, err := reconciler.discoverResources(testCtx)
+			gvrs, err := reconciler.discoverResources(testCtx, reconciler.localClusterClients(), nil)
 			Expect(err).NotTo(HaveOccurred())
 
 			// Check that no subresources are included
@@ -1097,7 +1097,7 @@ var _ = Describe("ResourceSync Controller", func() {
 				},
 			}
 
-			Expect(hasRelevantChanges(oldObj, newObj)).To(BeTrue())
+			Expect(hasRelevantChanges(oldObj, newObj, nil)).To(BeTrue())
 		})
 
 		It("should detect label removal", func() {
@@ -1130,7 +1130,7 @@ var _ = Describe("ResourceSync Controller", func() {
 				},
 			}
 
-			Expect(hasRelevantChanges(oldObj, newObj)).To(BeTrue())
+			Expect(hasRelevantChanges(oldObj, newObj, nil)).To(BeTrue())
 		})
 
 		It("should detect label value changes", func() {
@@ -1161,7 +1161,7 @@ var _ = Describe("ResourceSync Controller", func() {
 				},
 			}
 
-			Expect(hasRelevantChanges(oldObj, newObj)).To(BeTrue())
+			Expect(hasRelevantChanges(oldObj, newObj, nil)).To(BeTrue())
 		})
 
 		It("should NOT detect status changes (status not synced)", func() {
@@ -1192,7 +1192,7 @@ var _ = Describe("ResourceSync Controller", func() {
 				},
 			}
 
-			Expect(hasRelevantChanges(oldObj, newObj)).To(BeFalse())
+			Expect(hasRelevantChanges(oldObj, newObj, nil)).To(BeFalse())
 		})
 
 		It("should return false when nothing changed", func() {
@@ -1229,7 +1229,7 @@ var _ = Describe("ResourceSync Controller", func() {
 				},
 			}
 
-			Expect(hasRelevantChanges(oldObj, newObj)).To(BeFalse())
+			Expect(hasRelevantChanges(oldObj, newObj, nil)).To(BeFalse())
 		})
 
 		It("should ignore resourceVersion changes", func() {
@@ -1256,7 +1256,7 @@ var _ = Describe("ResourceSync Controller", func() {
 				},
 			}
 
-			Expect(hasRelevantChanges(oldObj, newObj)).To(BeFalse())
+			Expect(hasRelevantChanges(oldObj, newObj, nil)).To(BeFalse())
 		})
 
 		It("should ignore annotation changes (except description)", func() {
@@ -1288,7 +1288,7 @@ var _ = Describe("ResourceSync Controller", func() {
 			}
 
 			// Annotations are not part of change detection (only labels and status)
-			Expect(hasRelevantChanges(oldObj, newObj)).To(BeFalse())
+			Expect(hasRelevantChanges(oldObj, newObj, nil)).To(BeFalse())
 		})
 
 		It("should handle nil labels", func() {
@@ -1313,7 +1313,7 @@ var _ = Describe("ResourceSync Controller", func() {
 				},
 			}
 
-			Expect(hasRelevantChanges(oldObj, newObj)).To(BeFalse())
+			Expect(hasRelevantChanges(oldObj, newObj, nil)).To(BeFalse())
 		})
 
 		It("should detect when labels added to previously nil labels", func() {
@@ -1341,7 +1341,7 @@ var _ = Describe("ResourceSync Controller", func() {
 				},
 			}
 
-			Expect(hasRelevantChanges(oldObj, newObj)).To(BeTrue())
+			Expect(hasRelevantChanges(oldObj, newObj, nil)).To(BeTrue())
 		})
 
 		It("should handle nil status", func() {
@@ -1366,7 +1366,7 @@ var _ = Describe("ResourceSync Controller", func() {
 				},
 			}
 
-			Expect(hasRelevantChanges(oldObj, newObj)).To(BeFalse())
+			Expect(hasRelevantChanges(oldObj, newObj, nil)).To(BeFalse())
 		})
 
 		It("should NOT detect when status added to previously nil status (status not synced)", func() {
@@ -1395,7 +1395,7 @@ var _ = Describe("ResourceSync Controller", func() {
 			}
 
 			// Status changes should NOT trigger sync - only labels matter
-			Expect(hasRelevantChanges(oldObj, newObj)).To(BeFalse())
+			Expect(hasRelevantChanges(oldObj, newObj, nil)).To(BeFalse())
 		})
 	})
 
@@ -1,14 +1,22 @@
 // resourcesync_debounce.go implements the debounce buffer for resource sync.
 // The buffer collects changes over a configurable time window, deduplicates them
-// (last-state-wins), and sends batched updates to the MCP endpoint.
+// (last-state-wins), and sends batched updates to the MCP endpoint and the other
+// configured sinks.
 package controller
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+	"github.com/vfarcic/dot-ai-controller/internal/tracing"
 )
 
 // DebounceBuffer collects resource changes and flushes them in batches
@@ -23,9 +31,15 @@ type DebounceBuffer struct {
 	// mcpClient sends batched changes to MCP
 	mcpClient *MCPResourceSyncClient
 
+	// deliveries track the retries and status of each sink; the MCP sink comes first
+	deliveries []*sinkDelivery
+
 	// changeQueue is the input channel for changes from informer handlers
 	changeQueue <-chan *ResourceChange
 
+	// configName labels the flush metrics of the buffer
+	configName string
+
 	// metrics for observability
 	totalUpserts   int64
 	totalDeletes   int64
@@ -41,9 +55,26 @@ type DebounceBuffer struct {
 
 // DebounceBufferConfig holds configuration for creating a DebounceBuffer
 type DebounceBufferConfig struct {
+	ConfigName  string
 	Window      time.Duration
 	MCPClient   *MCPResourceSyncClient
 	ChangeQueue <-chan *ResourceChange
+	// Sinks receive the changes in addition to MCP
+	Sinks []ResourceSyncSink
+}
+
+// sinkDelivery tracks the failed changes and delivery status of a sink
+type sinkDelivery struct {
+	sink ResourceSyncSink
+
+	// pending holds the changes of failed batches, retried with the next flush (guarded by mu)
+	pending map[string]*ResourceChange
+
+	// status fields (guarded by metricsMu)
+	totalDelivered   int64
+	lastDeliveryTime time.Time
+	lastError        string
+	lastErrorTime    time.Time
 }
 
 // NewDebounceBuffer creates a new debounce buffer
@@ -52,12 +83,17 @@ func NewDebounceBuffer(cfg DebounceBufferConfig) *DebounceBuffer {
 		cfg.Window = 10 * time.Second // Default 10-second window
 	}
 
-	return &DebounceBuffer{
+	b := &DebounceBuffer{
 		changes:     make(map[string]*ResourceChange),
 		window:      cfg.Window,
-		mcpClient:   cfg.MCPClient,
 		changeQueue: cfg.ChangeQueue,
+		configName:  cfg.ConfigName,
 	}
+	b.SetMCPClient(cfg.MCPClient)
+	for _, sink := range cfg.Sinks {
+		b.deliveries = append(b.deliveries, &sinkDelivery{sink: sink})
+	}
+	return b
 }
 
 // Run starts the debounce buffer processing loop
@@ -133,110 +169,262 @@ func (b *DebounceBuffer) record(change *ResourceChange) {
 	}
 }
 
-// flush sends all pending changes to MCP
+// flush sends all pending changes to MCP and the other sinks
+// Each sink receives its own failed changes from previous flushes together with the new
+// ones, so a failing sink retries independently of the others.
 func (b *DebounceBuffer) flush(ctx context.Context) {
 	logger := logf.FromContext(ctx).WithName("debounce-buffer")
 
 	b.mu.Lock()
-	if len(b.changes) == 0 {
+	pending := len(b.changes) > 0
+	for _, delivery := range b.deliveries {
+		pending = pending || len(delivery.pending) > 0
+	}
+	if !pending {
+		b.mu.Unlock()
+		return
+	}
+
+	changes := b.changes
+	b.changes = make(map[string]*ResourceChange)
+	if len(b.deliveries) == 0 {
 		b.mu.Unlock()
+		logger.V(1).Info("No sink configured, skipping flush", "changes", len(changes))
 		return
 	}
 
-	// Collect changes - store both ID (for requeue) and full change data
+	// Newer changes replace the failed ones of the same resources
+	deliveries := b.deliveries
+	batches := make([]map[string]*ResourceChange, len(deliveries))
+	for i, delivery := range deliveries {
+		batch := delivery.pending
+		if batch == nil {
+			batch = make(map[string]*ResourceChange, len(changes))
+		}
+		for id, change := range changes {
+			batch[id] = change
+		}
+		batches[i] = batch
+		delivery.pending = nil
+	}
+	b.mu.Unlock()
+
+	ctx, span := tracing.Start(ctx, "ResourceSyncConfig.flush",
+		attribute.Int("resourcesync.changes", len(changes)),
+		attribute.Int("resourcesync.sinks", len(deliveries)),
+	)
+	defer span.End()
+
+	// Sinks are independent, so a slow sink does not delay the others
+	results := make([]*SinkResult, len(deliveries))
+	var wg sync.WaitGroup
+	for i, delivery := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = b.deliver(ctx, delivery, batches[i])
+		}()
+	}
+	wg.Wait()
+
+	// The buffer reports the errors of all failing sinks
+	var errs []string
+	delivered := false
+	var mcpResult *SinkResult
+	b.metricsMu.RLock()
+	for i, delivery := range deliveries {
+		if results[i] != nil {
+			delivered = true
+			if delivery.sink.Name() == mcpSinkName {
+				mcpResult = results[i]
+			}
+		}
+		if delivery.lastError == "" {
+			continue
+		}
+		if len(deliveries) == 1 {
+			errs = append(errs, delivery.lastError)
+		} else {
+			errs = append(errs, fmt.Sprintf("sink '%s': %s", delivery.sink.Name(), delivery.lastError))
+		}
+	}
+	b.metricsMu.RUnlock()
+
+	if delivered {
+		b.updateMetrics(mcpResult, len(changes))
+	}
+	if len(errs) > 0 {
+		b.recordError(strings.Join(errs, "; "))
+	} else {
+		b.clearError()
+	}
+}
+
+// hasSinks checks if changes are delivered to sinks other than MCP
+func (b *DebounceBuffer) hasSinks() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, delivery := range b.deliveries {
+		if delivery.sink.Name() != mcpSinkName {
+			return true
+		}
+	}
+	return false
+}
+
+// ResyncSinks sends the current version of resources to the sinks other than MCP, which
+// reconciles through its own resync protocol. Resources are sent as upserts in batches of up
+// to maxSinkPendingChanges, so resyncs repair changes a sink rejected or never received.
+// A failed batch is kept for the next flush and the remaining batches of that sink are skipped
+// until the next resync. Deletes a sink missed cannot be derived from the current resources.
+func (b *DebounceBuffer) ResyncSinks(ctx context.Context, resources []*ResourceData) {
+	if b == nil || len(resources) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	var deliveries []*sinkDelivery
+	for _, delivery := range b.deliveries {
+		if delivery.sink.Name() != mcpSinkName {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	b.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := 0; start < len(resources); start += maxSinkPendingChanges {
+				end := min(start+maxSinkPendingChanges, len(resources))
+				batch := make(map[string]*ResourceChange, end-start)
+				for _, data := range resources[start:end] {
+					id := resourceDataID(data)
+					batch[id] = &ResourceChange{Action: ActionUpsert, Data: data, ID: id}
+				}
+				if b.deliver(ctx, delivery, batch) == nil {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// deliver sends a batch to a sink and records the outcome
+// Returns nil if the batch failed; its changes are then kept for the next flush.
+func (b *DebounceBuffer) deliver(ctx context.Context, delivery *sinkDelivery, batch map[string]*ResourceChange) *SinkResult {
+	logger := logf.FromContext(ctx).WithName("debounce-buffer")
+	name := delivery.sink.Name()
+
 	var upserts []*ResourceData
 	var deletes []*ResourceIdentifier
-	// Keep track of changes for potential requeue
-	changesToRequeue := make(map[string]*ResourceChange)
-
-	for id, change := range b.changes {
+	for _, change := range batch {
 		if change.Action == ActionDelete {
 			if change.DeleteIdentifier != nil {
 				deletes = append(deletes, change.DeleteIdentifier)
-				changesToRequeue[id] = change
 			}
 		} else if change.Data != nil {
 			upserts = append(upserts, change.Data)
-			changesToRequeue[id] = change
 		}
 	}
-
-	// Clear the buffer
-	b.changes = make(map[string]*ResourceChange)
-	b.mu.Unlock()
-
-	// Skip if nothing to send
 	if len(upserts) == 0 && len(deletes) == 0 {
-		return
+		return nil
 	}
 
-	logger.Info("Flushing changes to MCP",
+	logger.Info("Flushing changes",
+		"sink", name,
 		"upserts", len(upserts),
 		"deletes", len(deletes),
 	)
 
-	// Send to MCP
-	if b.mcpClient == nil {
-		logger.V(1).Info("MCP client not configured, skipping flush")
-		return
+	// The flush metrics keep reporting the MCP endpoint
+	isMCP := name == mcpSinkName
+	if isMCP {
+		resourceSyncFlushSize.WithLabelValues(b.configName).Observe(float64(len(upserts) + len(deletes)))
+	}
+	flushStart := time.Now()
+	result, err := delivery.sink.Send(ctx, upserts, deletes)
+	if isMCP {
+		flushResult := "success"
+		if err != nil || result.Error != "" {
+			flushResult = "error"
+		}
+		resourceSyncFlushDuration.WithLabelValues(b.configName, flushResult).Observe(time.Since(flushStart).Seconds())
 	}
 
-	resp, err := b.mcpClient.SyncResources(ctx, upserts, deletes)
 	if err != nil {
-		logger.Error(err, "Failed to sync resources to MCP",
+		logger.Error(err, "Failed to deliver changes",
+			"sink", name,
 			"upserts", len(upserts),
 			"deletes", len(deletes),
 		)
-		// Record the error for status reporting
-		b.recordError(err.Error())
-		// Re-queue failed changes for next flush
-		b.requeueChangesFromMap(changesToRequeue)
-		return
+		b.recordSinkError(delivery, err.Error())
+		b.requeueForSink(delivery, batch)
+		return nil
 	}
 
-	// Update metrics
-	b.updateMetrics(resp, len(upserts), len(deletes))
-
-	if !resp.Success {
-		errMsg := resp.GetErrorMessage()
-		logger.Error(nil, "MCP sync returned error",
-			"error", errMsg,
-			"failures", resp.GetFailures(),
-		)
-		// Record the error for status reporting
-		b.recordError(errMsg)
-		// For partial failures, we don't re-queue - the next resync will catch them
+	b.metricsMu.Lock()
+	delivery.totalDelivered += int64(result.Upserted + result.Deleted)
+	delivery.lastDeliveryTime = time.Now()
+	if result.Error != "" {
+		// Partial failures are not retried - the next resync sends the resources again
+		delivery.lastError = result.Error
+		delivery.lastErrorTime = time.Now()
 	} else {
-		// Clear error on success
-		b.clearError()
+		delivery.lastError = ""
 	}
+	b.metricsMu.Unlock()
+	return result
 }
 
-// requeueChangesFromMap adds failed changes back to the buffer for retry
-func (b *DebounceBuffer) requeueChangesFromMap(changes map[string]*ResourceChange) {
+// recordSinkError stores the last delivery error of a sink
+func (b *DebounceBuffer) recordSinkError(delivery *sinkDelivery, errMsg string) {
+	b.metricsMu.Lock()
+	defer b.metricsMu.Unlock()
+	delivery.lastError = errMsg
+	delivery.lastErrorTime = time.Now()
+}
+
+// requeueForSink keeps the changes of a failed batch for the next flush of a sink
+// Changes recorded since the flush replace them; beyond maxSinkPendingChanges they are
+// dropped, so an unavailable sink cannot grow the buffer without bound.
+func (b *DebounceBuffer) requeueForSink(delivery *sinkDelivery, batch map[string]*ResourceChange) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	for id, change := range changes {
-		if _, exists := b.changes[id]; !exists {
-			b.changes[id] = change
+	if delivery.pending == nil {
+		delivery.pending = make(map[string]*ResourceChange, len(batch))
+	}
+	for id, change := range batch {
+		if _, exists := delivery.pending[id]; exists {
+			continue
+		}
+		if len(delivery.pending) >= maxSinkPendingChanges {
+			b.incrementDropped()
+			continue
 		}
+		delivery.pending[id] = change
 	}
 }
 
 // updateMetrics updates the buffer metrics after a flush
-func (b *DebounceBuffer) updateMetrics(resp *SyncResponse, attemptedUpserts, attemptedDeletes int) {
+// Upserts and deletes are counted from the result of the MCP sink, if any
+func (b *DebounceBuffer) updateMetrics(result *SinkResult, attempted int) {
 	b.metricsMu.Lock()
 	defer b.metricsMu.Unlock()
 
 	b.totalFlushes++
 	b.lastFlushTime = time.Now()
-	b.lastFlushCount = attemptedUpserts + attemptedDeletes
+	b.lastFlushCount = attempted
 
-	if resp != nil && resp.Success {
-		upserted, deleted := resp.GetSuccessCounts()
-		b.totalUpserts += int64(upserted)
-		b.totalDeletes += int64(deleted)
+	if result != nil && result.Error == "" {
+		b.totalUpserts += int64(result.Upserted)
+		b.totalDeletes += int64(result.Deleted)
 	}
 }
 
@@ -264,13 +452,13 @@ func (b *DebounceBuffer) clearError() {
 
 // GetMetrics returns current buffer metrics
 func (b *DebounceBuffer) GetMetrics() DebounceBufferMetrics {
+	// Pending changes are counted before locking the metrics, since record and requeueForSink
+	// update metrics while holding the buffer lock
+	pendingCount := b.PendingCount()
+
 	b.metricsMu.RLock()
 	defer b.metricsMu.RUnlock()
 
-	b.mu.Lock()
-	pendingCount := len(b.changes)
-	b.mu.Unlock()
-
 	return DebounceBufferMetrics{
 		TotalUpserts:   b.totalUpserts,
 		TotalDeletes:   b.totalDeletes,
@@ -299,16 +487,86 @@ type DebounceBufferMetrics struct {
 	LastErrorTime time.Time
 }
 
-// PendingCount returns the number of pending changes in the buffer
+// PendingCount returns the number of resources with pending changes in the buffer,
+// including the failed changes waiting for the next flush of any sink
 func (b *DebounceBuffer) PendingCount() int {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	return len(b.changes)
+
+	count := len(b.changes)
+	var seen map[string]bool
+	for _, delivery := range b.deliveries {
+		for id := range delivery.pending {
+			if _, recorded := b.changes[id]; recorded || seen[id] {
+				continue
+			}
+			if seen == nil {
+				seen = make(map[string]bool)
+			}
+			seen[id] = true
+			count++
+		}
+	}
+	return count
 }
 
 // SetMCPClient sets the MCP client (useful for late initialization or testing)
+// The MCP endpoint is the first sink; a nil client removes it
 func (b *DebounceBuffer) SetMCPClient(client *MCPResourceSyncClient) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	b.mcpClient = client
+	deliveries := make([]*sinkDelivery, 0, len(b.deliveries)+1)
+	if client != nil {
+		delivery := &sinkDelivery{}
+		if len(b.deliveries) > 0 && b.deliveries[0].sink.Name() == mcpSinkName {
+			delivery = b.deliveries[0]
+		}
+		delivery.sink = &mcpSink{client: client}
+		deliveries = append(deliveries, delivery)
+	}
+	for _, delivery := range b.deliveries {
+		if delivery.sink.Name() != mcpSinkName {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	b.deliveries = deliveries
+}
+
+// SinkStatuses returns the delivery status of each sink for the ResourceSyncConfig status
+func (b *DebounceBuffer) SinkStatuses() []dotaiv1alpha1.SinkStatus {
+	b.mu.Lock()
+	deliveries := b.deliveries
+	pending := make([]int, len(deliveries))
+	for i, delivery := range deliveries {
+		pending[i] = len(delivery.pending)
+	}
+	b.mu.Unlock()
+
+	b.metricsMu.RLock()
+	defer b.metricsMu.RUnlock()
+
+	statuses := make([]dotaiv1alpha1.SinkStatus, 0, len(deliveries))
+	for i, delivery := range deliveries {
+		status := dotaiv1alpha1.SinkStatus{
+			Name:           delivery.sink.Name(),
+			Type:           delivery.sink.Type(),
+			TotalDelivered: delivery.totalDelivered,
+			PendingChanges: pending[i],
+			LastError:      truncateErrorMessage(delivery.lastError),
+		}
+		if !delivery.lastDeliveryTime.IsZero() {
+			t := metav1.NewTime(delivery.lastDeliveryTime)
+			status.LastDeliveryTime = &t
+		}
+		if delivery.lastError != "" {
+			t := metav1.NewTime(delivery.lastErrorTime)
+			status.LastErrorTime = &t
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
 }
 
 // SetLastFlushTimeForTesting sets the lastFlushTime for testing purposes
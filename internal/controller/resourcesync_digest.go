@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// resourceContent is the synced content of a resource covered by its content hash
+type resourceContent struct {
+	Labels        map[string]string      `json:"labels,omitempty"`
+	Annotations   map[string]string      `json:"annotations,omitempty"`
+	Fields        map[string]interface{} `json:"fields,omitempty"`
+	Relationships []ResourceRelationship `json:"relationships,omitempty"`
+}
+
+// contentHash returns the hash of the labels, annotations, extracted fields and relationships of resource data
+// MCP stores the hash of each upsert, so incremental resyncs can compare hashes instead of content
+func contentHash(data *ResourceData) string {
+	// Maps are marshalled with sorted keys, so equal content has the same hash
+	content, err := json.Marshal(resourceContent{
+		Labels:        data.Labels,
+		Annotations:   data.Annotations,
+		Fields:        data.Fields,
+		Relationships: data.Relationships,
+	})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// resourceDataID returns the ID of resource data, in the format of buildResourceID
+func resourceDataID(data *ResourceData) string {
+	return fmt.Sprintf("%s:%s:%s:%s", data.Namespace, data.APIVersion, data.Kind, data.Name)
+}
+
+// resourceBucket returns the digest bucket of resource data: its namespace and resource type
+func resourceBucket(data *ResourceData) string {
+	return fmt.Sprintf("%s/%s/%s", data.Namespace, data.APIVersion, data.Kind)
+}
+
+// resyncDigest is a two-level hash tree of resources: a root hash over bucket hashes,
+// and bucket hashes over the content hashes of the resources in each bucket
+type resyncDigest struct {
+	root    string
+	buckets map[string]string
+	// resources holds the content hash of each resource by ID, per bucket
+	resources map[string]map[string]string
+	// byID indexes the resources for uploading the ones MCP lacks
+	byID map[string]*ResourceData
+}
+
+// newResyncDigest builds the hash tree of resources
+func newResyncDigest(resources []*ResourceData) *resyncDigest {
+	digest := &resyncDigest{
+		buckets:   make(map[string]string),
+		resources: make(map[string]map[string]string),
+		byID:      make(map[string]*ResourceData, len(resources)),
+	}
+	for _, data := range resources {
+		bucket := resourceBucket(data)
+		if digest.resources[bucket] == nil {
+			digest.resources[bucket] = make(map[string]string)
+		}
+		id := resourceDataID(data)
+		hash := data.ContentHash
+		if hash == "" {
+			hash = contentHash(data)
+		}
+		digest.resources[bucket][id] = hash
+		digest.byID[id] = data
+	}
+	for bucket, hashes := range digest.resources {
+		digest.buckets[bucket] = hashEntries(hashes)
+	}
+	digest.root = hashEntries(digest.buckets)
+	return digest
+}
+
+// hashEntries hashes key/hash pairs in key order
+func hashEntries(entries map[string]string) string {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(h, "%s=%s\n", key, entries[key])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// performIncrementalResync reconciles MCP by comparing hashes and uploading only differing resources
+// The root and bucket hashes are compared first; resource hashes are exchanged only for differing
+// buckets, in requests of up to the resync chunk size. Returns ErrResyncDigestsUnsupported when MCP
+// does not compare hashes
+func (r *ResourceSyncReconciler) performIncrementalResync(ctx context.Context, state *activeConfigState, allResources []*ResourceData) error {
+	logger := logf.FromContext(ctx).WithName("resourcesync")
+
+	digest := newResyncDigest(allResources)
+	result := &dotaiv1alpha1.IncrementalResyncResult{
+		Time:         metav1.NewTime(time.Now()),
+		TotalBuckets: len(digest.buckets),
+	}
+
+	differing, err := state.mcpClient.CompareDigest(ctx, digest.root, digest.buckets)
+	if err != nil {
+		return err
+	}
+	result.DifferingBuckets = len(differing)
+
+	result.Upserted, result.Deleted, err = r.reconcileBuckets(ctx, state, digest, differing)
+	if err != nil {
+		return err
+	}
+
+	state.resyncMu.Lock()
+	state.lastIncrementalResync = result
+	state.resyncMu.Unlock()
+
+	logger.Info("Incremental resync completed",
+		"buckets", result.TotalBuckets,
+		"differingBuckets", result.DifferingBuckets,
+		"upserted", result.Upserted,
+		"deleted", result.Deleted,
+	)
+	return nil
+}
+
+// reconcileBuckets exchanges the resource hashes of buckets with MCP and uploads the differences,
+// in requests of up to the resync chunk size
+func (r *ResourceSyncReconciler) reconcileBuckets(ctx context.Context, state *activeConfigState, digest *resyncDigest, buckets []string) (upserted, deleted int, err error) {
+	chunkSize := state.config.GetResyncChunkSize()
+	request := make(map[string]map[string]string)
+	entries := 0
+	compare := func() error {
+		if len(request) == 0 {
+			return nil
+		}
+		missing, stale, err := state.mcpClient.CompareBucketDigests(ctx, request)
+		if err != nil {
+			return fmt.Errorf("failed to compare bucket digests: %w", err)
+		}
+		chunkUpserted, chunkDeleted, err := r.uploadDifferences(ctx, state, digest, missing, stale)
+		upserted += chunkUpserted
+		deleted += chunkDeleted
+		request = make(map[string]map[string]string)
+		entries = 0
+		return err
+	}
+
+	for _, bucket := range buckets {
+		// Buckets only MCP has are sent empty, so MCP reports their resources as stale
+		hashes := digest.resources[bucket]
+		if hashes == nil {
+			hashes = map[string]string{}
+		}
+		request[bucket] = hashes
+		entries += len(hashes)
+		if entries >= chunkSize {
+			if err := compare(); err != nil {
+				return upserted, deleted, err
+			}
+		}
+	}
+	return upserted, deleted, compare()
+}
+
+// uploadDifferences upserts the resources MCP lacks and deletes the stale ones, in chunks
+func (r *ResourceSyncReconciler) uploadDifferences(ctx context.Context, state *activeConfigState, digest *resyncDigest, missing []string, stale []*ResourceIdentifier) (upserted, deleted int, err error) {
+	var upserts []*ResourceData
+	for _, id := range missing {
+		if data, ok := digest.byID[id]; ok {
+			upserts = append(upserts, data)
+		}
+	}
+
+	chunkSize := state.config.GetResyncChunkSize()
+	for len(upserts) > 0 || len(stale) > 0 {
+		upsertChunk := upserts[:min(chunkSize, len(upserts))]
+		upserts = upserts[len(upsertChunk):]
+		deleteChunk := stale[:min(chunkSize-len(upsertChunk), len(stale))]
+		stale = stale[len(deleteChunk):]
+
+		resp, err := state.mcpClient.SyncResources(ctx, upsertChunk, deleteChunk)
+		if err != nil {
+			return upserted, deleted, fmt.Errorf("failed to upload differing resources: %w", err)
+		}
+		chunkUpserted, chunkDeleted := resp.GetSuccessCounts()
+		upserted += chunkUpserted
+		deleted += chunkDeleted
+	}
+	return upserted, deleted, nil
+}
+
+// lastIncrementalResyncSnapshot returns a copy of the last incremental resync result for status updates
+func (s *activeConfigState) lastIncrementalResyncSnapshot() *dotaiv1alpha1.IncrementalResyncResult {
+	s.resyncMu.Lock()
+	defer s.resyncMu.Unlock()
+	return s.lastIncrementalResync.DeepCopy()
+}
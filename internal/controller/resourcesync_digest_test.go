@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// digestTestServer is an MCP server that stores synced resources and compares digests against them
+type digestTestServer struct {
+	mu     sync.Mutex
+	stored map[string]*ResourceData
+
+	digestRequests         int
+	bucketDigestRequests   int
+	upserted, deletedCount int
+}
+
+func (s *digestTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := make([]*ResourceData, 0, len(s.stored))
+	for _, data := range s.stored {
+		stored = append(stored, data)
+	}
+	digest := newResyncDigest(stored)
+
+	data := map[string]interface{}{}
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/digest"):
+		s.digestRequests++
+		var req ResyncDigestRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		data["match"] = req.Root == digest.root
+		var differing []string
+		for bucket, hash := range req.Buckets {
+			if digest.buckets[bucket] != hash {
+				differing = append(differing, bucket)
+			}
+		}
+		for bucket := range digest.buckets {
+			if _, ok := req.Buckets[bucket]; !ok {
+				differing = append(differing, bucket)
+			}
+		}
+		data["differingBuckets"] = differing
+	case strings.HasSuffix(r.URL.Path, "/digest/resources"):
+		s.bucketDigestRequests++
+		var req ResyncBucketDigestRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		var missing []string
+		var stale []*ResourceIdentifier
+		for bucket, hashes := range req.Buckets {
+			for id, hash := range hashes {
+				if digest.resources[bucket][id] != hash {
+					missing = append(missing, id)
+				}
+			}
+			for id := range digest.resources[bucket] {
+				if _, ok := hashes[id]; !ok {
+					d := s.stored[id]
+					stale = append(stale, &ResourceIdentifier{Namespace: d.Namespace, Name: d.Name, Kind: d.Kind, APIVersion: d.APIVersion})
+				}
+			}
+		}
+		data["missing"] = missing
+		data["stale"] = stale
+	case strings.HasSuffix(r.URL.Path, "/sync"):
+		var req SyncRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		for _, upsert := range req.Upserts {
+			s.stored[resourceDataID(upsert)] = upsert
+		}
+		for _, d := range req.Deletes {
+			delete(s.stored, resourceDataID(&ResourceData{Namespace: d.Namespace, Name: d.Name, Kind: d.Kind, APIVersion: d.APIVersion}))
+		}
+		s.upserted += len(req.Upserts)
+		s.deletedCount += len(req.Deletes)
+		data["upserted"] = len(req.Upserts)
+		data["deleted"] = len(req.Deletes)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": data})
+}
+
+// newDigestTestResource creates resource data with its content hash
+func newDigestTestResource(namespace, kind, name, version string) *ResourceData {
+	data := &ResourceData{
+		Namespace:  namespace,
+		Name:       name,
+		Kind:       kind,
+		APIVersion: "apps/v1",
+		Labels:     map[string]string{"version": version},
+	}
+	data.ContentHash = contentHash(data)
+	return data
+}
+
+func TestContentHash(t *testing.T) {
+	a := &ResourceData{Labels: map[string]string{"app": "web", "tier": "frontend"}, Fields: map[string]interface{}{"replicas": int64(3)}}
+	b := &ResourceData{Labels: map[string]string{"tier": "frontend", "app": "web"}, Fields: map[string]interface{}{"replicas": int64(3)}}
+	assert.Equal(t, contentHash(a), contentHash(b), "equal content has the same hash")
+
+	b.Fields["replicas"] = int64(4)
+	assert.NotEqual(t, contentHash(a), contentHash(b))
+}
+
+func TestResourceSyncReconciler_PerformIncrementalResync(t *testing.T) {
+	unchanged := newDigestTestResource("default", "Deployment", "web", "1")
+	server := &digestTestServer{stored: map[string]*ResourceData{
+		resourceDataID(unchanged):            unchanged,
+		"default:apps/v1:Deployment:api":     newDigestTestResource("default", "Deployment", "api", "1"),
+		"team-a:apps/v1:StatefulSet:db":      newDigestTestResource("team-a", "StatefulSet", "db", "1"),
+		"team-b:apps/v1:DaemonSet:collector": newDigestTestResource("team-b", "DaemonSet", "collector", "1"),
+	}}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	maxRetries := 0
+	state := &activeConfigState{
+		config: &dotaiv1alpha1.ResourceSyncConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "sync", Namespace: "dot-ai"},
+			Spec:       dotaiv1alpha1.ResourceSyncConfigSpec{ResyncMode: dotaiv1alpha1.ResyncModeIncremental},
+		},
+		mcpClient: NewMCPResourceSyncClient(MCPResourceSyncClientConfig{
+			Endpoint:   httpServer.URL + ResourceSyncPath,
+			HTTPClient: httpServer.Client(),
+			MaxRetries: &maxRetries,
+		}),
+	}
+	reconciler := &ResourceSyncReconciler{}
+
+	// api changed, worker is new, the StatefulSet is unchanged and the DaemonSet bucket is gone
+	resources := []*ResourceData{
+		unchanged,
+		newDigestTestResource("default", "Deployment", "api", "2"),
+		newDigestTestResource("default", "Deployment", "worker", "1"),
+		newDigestTestResource("team-a", "StatefulSet", "db", "1"),
+	}
+	require.NoError(t, reconciler.performIncrementalResync(t.Context(), state, resources))
+
+	assert.Equal(t, 2, server.upserted, "only the changed and new resources are uploaded")
+	assert.Equal(t, 1, server.deletedCount, "resources of buckets only MCP has are deleted")
+	assert.Equal(t, 1, server.bucketDigestRequests)
+	assert.Equal(t, &dotaiv1alpha1.IncrementalResyncResult{
+		Time:             state.lastIncrementalResync.Time,
+		TotalBuckets:     2,
+		DifferingBuckets: 2,
+		Upserted:         2,
+		Deleted:          1,
+	}, state.lastIncrementalResyncSnapshot())
+
+	// Once in sync, only the root hash is compared
+	require.NoError(t, reconciler.performIncrementalResync(t.Context(), state, resources))
+	assert.Equal(t, 2, server.digestRequests)
+	assert.Equal(t, 1, server.bucketDigestRequests)
+	assert.Equal(t, 2, server.upserted)
+	assert.Zero(t, state.lastIncrementalResyncSnapshot().DifferingBuckets)
+}
+
+func TestResourceSyncReconciler_PerformIncrementalResync_Unsupported(t *testing.T) {
+	server := &resyncTestServer{chunks: map[int]int{}, failChunk: -1, unsupported: true}
+	reconciler, state := newResyncTestState(t, server, 10)
+
+	err := reconciler.performIncrementalResync(t.Context(), state, newResyncTestResources(3))
+	assert.ErrorIs(t, err, ErrResyncDigestsUnsupported)
+}
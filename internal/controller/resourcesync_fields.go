@@ -0,0 +1,331 @@
+package controller
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"google.golang.org/protobuf/types/known/structpb"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// celFieldCostLimit bounds the evaluation cost of a CEL field expression
+const celFieldCostLimit = 1000000
+
+// celValueType is the native type CEL results are converted to before syncing
+var celValueType = reflect.TypeOf(&structpb.Value{})
+
+// compiledFieldRule is a field extraction rule ready for evaluation
+type compiledFieldRule struct {
+	name     string
+	throttle time.Duration
+
+	// jsonPath is not safe for concurrent use, so evaluations are serialized
+	jsonPath   *jsonpath.JSONPath
+	jsonPathMu sync.Mutex
+
+	program cel.Program
+}
+
+// compiledFieldExtraction holds the compiled rules of a ResourceFieldExtraction
+type compiledFieldExtraction struct {
+	resources []string
+	rules     []*compiledFieldRule
+}
+
+// resourceFieldExtractor extracts the configured fields from resources and throttles
+// syncs triggered by frequently changing fields. A nil extractor extracts nothing.
+type resourceFieldExtractor struct {
+	extractions []compiledFieldExtraction
+
+	// rulesByKind caches the rules that apply to each resource kind
+	rulesByKind map[schema.GroupKind][]*compiledFieldRule
+	rulesMu     sync.RWMutex
+
+	// lastFieldSync records when a field change last triggered a sync of a resource, by resource ID
+	lastFieldSync map[string]time.Time
+	// pendingFieldSyncs are the trailing syncs of throttled field changes, by resource ID
+	pendingFieldSyncs map[string]*pendingFieldSync
+	lastSyncMu        sync.Mutex
+}
+
+// pendingFieldSync is a sync of a throttled field change, run when the throttle window ends
+type pendingFieldSync struct {
+	timer *time.Timer
+	// sync queues an upsert of the current version of the resource
+	sync func()
+}
+
+// newResourceFieldExtractor compiles the field extraction rules of a ResourceSyncConfig
+// Returns nil when no fields are configured
+func newResourceFieldExtractor(extractions []dotaiv1alpha1.ResourceFieldExtraction) (*resourceFieldExtractor, error) {
+	if len(extractions) == 0 {
+		return nil, nil
+	}
+
+	env, err := cel.NewEnv(cel.Variable("object", cel.DynType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	extractor := &resourceFieldExtractor{
+		rulesByKind:       make(map[schema.GroupKind][]*compiledFieldRule),
+		lastFieldSync:     make(map[string]time.Time),
+		pendingFieldSyncs: make(map[string]*pendingFieldSync),
+	}
+	for _, extraction := range extractions {
+		compiled := compiledFieldExtraction{resources: extraction.Resources}
+		for _, field := range extraction.Fields {
+			rule, err := compileFieldRule(env, field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid field %q: %w", field.Name, err)
+			}
+			compiled.rules = append(compiled.rules, rule)
+		}
+		extractor.extractions = append(extractor.extractions, compiled)
+	}
+	return extractor, nil
+}
+
+// compileFieldRule parses the JSONPath or compiles the CEL expression of a field
+func compileFieldRule(env *cel.Env, field dotaiv1alpha1.ResourceFieldRule) (*compiledFieldRule, error) {
+	rule := &compiledFieldRule{
+		name:     field.Name,
+		throttle: time.Duration(field.ThrottleSeconds) * time.Second,
+	}
+
+	switch {
+	case field.JSONPath != "" && field.CEL != "":
+		return nil, fmt.Errorf("only one of jsonPath or cel can be set")
+	case field.JSONPath != "":
+		expression := field.JSONPath
+		if !strings.HasPrefix(expression, "{") {
+			expression = "{" + expression + "}"
+		}
+		rule.jsonPath = jsonpath.New(field.Name).AllowMissingKeys(true)
+		if err := rule.jsonPath.Parse(expression); err != nil {
+			return nil, fmt.Errorf("failed to parse jsonPath: %w", err)
+		}
+	case field.CEL != "":
+		ast, issues := env.Compile(field.CEL)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("failed to compile cel: %w", issues.Err())
+		}
+		program, err := env.Program(ast, cel.CostLimit(celFieldCostLimit))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build cel program: %w", err)
+		}
+		rule.program = program
+	default:
+		return nil, fmt.Errorf("one of jsonPath or cel must be set")
+	}
+	return rule, nil
+}
+
+// evaluate returns the field value of a resource, or false when it has none
+// Missing fields and failed evaluations leave the field out
+func (rule *compiledFieldRule) evaluate(obj *unstructured.Unstructured) (interface{}, bool) {
+	if rule.program != nil {
+		out, _, err := rule.program.Eval(map[string]interface{}{"object": obj.Object})
+		if err != nil {
+			return nil, false
+		}
+		native, err := out.ConvertToNative(celValueType)
+		if err != nil {
+			return nil, false
+		}
+		return native.(*structpb.Value).AsInterface(), true
+	}
+
+	rule.jsonPathMu.Lock()
+	results, err := rule.jsonPath.FindResults(obj.Object)
+	rule.jsonPathMu.Unlock()
+	if err != nil {
+		return nil, false
+	}
+
+	var values []interface{}
+	for _, result := range results {
+		for _, value := range result {
+			if value.IsValid() && value.CanInterface() {
+				values = append(values, value.Interface())
+			}
+		}
+	}
+	switch len(values) {
+	case 0:
+		return nil, false
+	case 1:
+		return values[0], true
+	default:
+		return values, true
+	}
+}
+
+// rulesFor returns the rules that apply to the kind of a resource
+func (e *resourceFieldExtractor) rulesFor(obj *unstructured.Unstructured) []*compiledFieldRule {
+	return e.rulesForKind(obj.GroupVersionKind().GroupKind())
+}
+
+// rulesForKind returns the rules that apply to a resource kind
+func (e *resourceFieldExtractor) rulesForKind(groupKind schema.GroupKind) []*compiledFieldRule {
+	if e == nil {
+		return nil
+	}
+
+	e.rulesMu.RLock()
+	rules, cached := e.rulesByKind[groupKind]
+	e.rulesMu.RUnlock()
+	if cached {
+		return rules
+	}
+
+	resourceID := resourceTypeID(groupKind.Kind, groupKind.Group)
+	for _, extraction := range e.extractions {
+		if matchesResourcePatterns(resourceID, extraction.resources, nil) {
+			rules = append(rules, extraction.rules...)
+		}
+	}
+
+	e.rulesMu.Lock()
+	e.rulesByKind[groupKind] = rules
+	e.rulesMu.Unlock()
+	return rules
+}
+
+// extract returns the configured fields of a resource, or nil when it has none
+func (e *resourceFieldExtractor) extract(obj *unstructured.Unstructured) map[string]interface{} {
+	if e == nil {
+		return nil
+	}
+
+	var fields map[string]interface{}
+	for _, rule := range e.rulesFor(obj) {
+		value, ok := rule.evaluate(obj)
+		if !ok {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]interface{})
+		}
+		fields[rule.name] = value
+	}
+	return fields
+}
+
+// changedFieldsAllowed checks if changed fields should trigger a sync of a resource and
+// records the sync. Changes of fields without a throttle are always synced; changes of
+// throttled fields only once per throttle window, using the shortest window of the changed fields.
+// Throttled changes are deferred, not discarded: deferred is called when the window ends, unless
+// another sync of the resource happened before, and reads the then current version of the resource.
+func (e *resourceFieldExtractor) changedFieldsAllowed(id string, oldObj, newObj *unstructured.Unstructured, now time.Time,
+	deferred func()) bool {
+	if e == nil {
+		return false
+	}
+
+	changed := false
+	var throttle time.Duration
+	for _, rule := range e.rulesFor(newObj) {
+		oldValue, oldOK := rule.evaluate(oldObj)
+		newValue, newOK := rule.evaluate(newObj)
+		if oldOK == newOK && reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		if !changed || rule.throttle < throttle {
+			throttle = rule.throttle
+		}
+		changed = true
+	}
+	if !changed {
+		return false
+	}
+
+	e.lastSyncMu.Lock()
+	defer e.lastSyncMu.Unlock()
+	if last, ok := e.lastFieldSync[id]; ok && throttle > 0 && now.Sub(last) < throttle {
+		e.deferFieldSync(id, throttle-now.Sub(last), deferred)
+		return false
+	}
+	e.lastFieldSync[id] = now
+	if pending, ok := e.pendingFieldSyncs[id]; ok {
+		pending.timer.Stop()
+		delete(e.pendingFieldSyncs, id)
+	}
+	return true
+}
+
+// deferFieldSync schedules the trailing sync of a throttled change, unless one is already
+// scheduled. Must be called with lastSyncMu held.
+func (e *resourceFieldExtractor) deferFieldSync(id string, delay time.Duration, sync func()) {
+	if sync == nil {
+		return
+	}
+	if _, ok := e.pendingFieldSyncs[id]; ok {
+		return
+	}
+
+	pending := &pendingFieldSync{sync: sync}
+	pending.timer = time.AfterFunc(delay, func() {
+		e.lastSyncMu.Lock()
+		if e.pendingFieldSyncs[id] != pending {
+			e.lastSyncMu.Unlock()
+			return
+		}
+		delete(e.pendingFieldSyncs, id)
+		e.lastFieldSync[id] = time.Now()
+		e.lastSyncMu.Unlock()
+
+		pending.sync()
+	})
+	e.pendingFieldSyncs[id] = pending
+}
+
+// synced cancels the trailing sync of a resource that was synced with its current fields by
+// another change, such as a label or annotation change
+func (e *resourceFieldExtractor) synced(id string, now time.Time) {
+	if e == nil {
+		return
+	}
+	e.lastSyncMu.Lock()
+	if pending, ok := e.pendingFieldSyncs[id]; ok {
+		pending.timer.Stop()
+		delete(e.pendingFieldSyncs, id)
+		e.lastFieldSync[id] = now
+	}
+	e.lastSyncMu.Unlock()
+}
+
+// stop cancels all trailing syncs, when the watcher of the config stops
+func (e *resourceFieldExtractor) stop() {
+	if e == nil {
+		return
+	}
+	e.lastSyncMu.Lock()
+	for id, pending := range e.pendingFieldSyncs {
+		pending.timer.Stop()
+		delete(e.pendingFieldSyncs, id)
+	}
+	e.lastSyncMu.Unlock()
+}
+
+// forget removes the throttle state of a deleted resource and cancels its trailing sync
+func (e *resourceFieldExtractor) forget(id string) {
+	if e == nil {
+		return
+	}
+	e.lastSyncMu.Lock()
+	delete(e.lastFieldSync, id)
+	if pending, ok := e.pendingFieldSyncs[id]; ok {
+		pending.timer.Stop()
+		delete(e.pendingFieldSyncs, id)
+	}
+	e.lastSyncMu.Unlock()
+}
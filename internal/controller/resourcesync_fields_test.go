@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// newFieldsTestDeployment creates a Deployment with two containers and the given replica counts
+func newFieldsTestDeployment(replicas, readyReplicas int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "default"},
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+			"template": map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "nginx:1.27"},
+				map[string]interface{}{"name": "sidecar", "image": "envoy:1.31"},
+			}}},
+		},
+		"status": map[string]interface{}{"readyReplicas": readyReplicas},
+	}}
+}
+
+// deploymentFieldExtractions extracts images, a throttled replica count and a computed health
+var deploymentFieldExtractions = []dotaiv1alpha1.ResourceFieldExtraction{{
+	Resources: []string{"Deployment.apps"},
+	Fields: []dotaiv1alpha1.ResourceFieldRule{
+		{Name: "images", JSONPath: ".spec.template.spec.containers[*].image"},
+		{Name: "readyReplicas", JSONPath: "{.status.readyReplicas}", ThrottleSeconds: 60},
+		{Name: "health", CEL: "object.status.readyReplicas == object.spec.replicas ? 'healthy' : 'degraded'"},
+		{Name: "missing", JSONPath: "{.status.phase}"},
+	},
+}}
+
+func TestNewResourceFieldExtractor_InvalidRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   dotaiv1alpha1.ResourceFieldRule
+		wantErr string
+	}{
+		{name: "no expression", field: dotaiv1alpha1.ResourceFieldRule{Name: "f"}, wantErr: "one of jsonPath or cel must be set"},
+		{name: "both expressions", field: dotaiv1alpha1.ResourceFieldRule{Name: "f", JSONPath: ".a", CEL: "1"}, wantErr: "only one of"},
+		{name: "invalid jsonPath", field: dotaiv1alpha1.ResourceFieldRule{Name: "f", JSONPath: "{.a[}"}, wantErr: "failed to parse jsonPath"},
+		{name: "invalid cel", field: dotaiv1alpha1.ResourceFieldRule{Name: "f", CEL: "object.("}, wantErr: "failed to compile cel"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newResourceFieldExtractor([]dotaiv1alpha1.ResourceFieldExtraction{
+				{Resources: []string{"*"}, Fields: []dotaiv1alpha1.ResourceFieldRule{tt.field}},
+			})
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	extractor, err := newResourceFieldExtractor(nil)
+	require.NoError(t, err)
+	assert.Nil(t, extractor.extract(newFieldsTestDeployment(1, 1)), "a nil extractor extracts nothing")
+}
+
+func TestResourceFieldExtractor_Extract(t *testing.T) {
+	extractor, err := newResourceFieldExtractor(deploymentFieldExtractions)
+	require.NoError(t, err)
+
+	fields := extractor.extract(newFieldsTestDeployment(3, 2))
+	assert.Equal(t, map[string]interface{}{
+		"images":        []interface{}{"nginx:1.27", "envoy:1.31"},
+		"readyReplicas": int64(2),
+		"health":        "degraded",
+	}, fields, "missing fields are left out")
+
+	service := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "default"},
+	}}
+	assert.Nil(t, extractor.extract(service), "rules apply only to matching resource types")
+}
+
+func TestResourceFieldExtractor_ChangedFieldsAllowed(t *testing.T) {
+	extractor, err := newResourceFieldExtractor(deploymentFieldExtractions)
+	require.NoError(t, err)
+	id := "default:apps/v1:Deployment:web"
+	now := time.Now()
+
+	assert.False(t, extractor.changedFieldsAllowed(id, newFieldsTestDeployment(3, 2), newFieldsTestDeployment(3, 2), now, nil),
+		"unchanged fields do not trigger a sync")
+
+	// readyReplicas is throttled, but the first change is synced
+	assert.True(t, extractor.changedFieldsAllowed(id, newFieldsTestDeployment(5, 1), newFieldsTestDeployment(5, 2), now, nil))
+	assert.False(t, extractor.changedFieldsAllowed(id, newFieldsTestDeployment(5, 2), newFieldsTestDeployment(5, 3), now.Add(time.Second), nil),
+		"throttled field changes within the window are not synced")
+	assert.True(t, extractor.changedFieldsAllowed(id, newFieldsTestDeployment(5, 3), newFieldsTestDeployment(5, 4), now.Add(time.Minute), nil),
+		"throttled field changes are synced after the window")
+
+	// health is not throttled, so its change is synced within the readyReplicas window
+	assert.True(t, extractor.changedFieldsAllowed(id, newFieldsTestDeployment(5, 4), newFieldsTestDeployment(5, 5), now.Add(time.Minute+time.Second), nil))
+
+	extractor.forget(id)
+	assert.Empty(t, extractor.lastFieldSync)
+}
+
+func TestResourceSyncReconciler_MakeOnUpdate_FieldChanges(t *testing.T) {
+	extractor, err := newResourceFieldExtractor(deploymentFieldExtractions)
+	require.NoError(t, err)
+	state := &activeConfigState{changeQueue: make(chan *ResourceChange, 10), fields: extractor}
+	handler := newFilterTestReconciler().makeOnUpdate(state)
+
+	handler(newFieldsTestDeployment(3, 2), newFieldsTestDeployment(3, 3))
+
+	require.Len(t, state.changeQueue, 1)
+	change := <-state.changeQueue
+	assert.Equal(t, ActionUpsert, change.Action)
+	assert.Equal(t, "healthy", change.Data.Fields["health"])
+}
+
+// newFieldsTestState creates the state of a config watching Deployments with the field rules
+// of deploymentFieldExtractions, throttled for 100ms
+func newFieldsTestState(t *testing.T) (*activeConfigState, cache.Store) {
+	extractor, err := newResourceFieldExtractor(deploymentFieldExtractions)
+	require.NoError(t, err)
+	for _, rule := range extractor.extractions[0].rules {
+		if rule.throttle > 0 {
+			rule.throttle = 100 * time.Millisecond
+		}
+	}
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{}, &unstructured.Unstructured{}, 0, cache.Indexers{})
+	state := &activeConfigState{
+		changeQueue: make(chan *ResourceChange, 10),
+		fields:      extractor,
+		activeInformers: map[schema.GroupVersionResource]cache.SharedIndexInformer{
+			{Group: "apps", Version: "v1", Resource: "deployments"}: informer,
+		},
+	}
+	return state, informer.GetStore()
+}
+
+func TestResourceSyncReconciler_MakeOnUpdate_ThrottledFieldChanges(t *testing.T) {
+	state, store := newFieldsTestState(t)
+	extractor := state.fields
+	onUpdate := newFilterTestReconciler().makeOnUpdate(state)
+	// Informers update their cache before calling event handlers
+	handler := func(oldObj, newObj *unstructured.Unstructured) {
+		require.NoError(t, store.Update(newObj))
+		onUpdate(oldObj, newObj)
+	}
+
+	// The first change is synced, later changes within the window are deferred to its end
+	handler(newFieldsTestDeployment(5, 1), newFieldsTestDeployment(5, 2))
+	require.Len(t, state.changeQueue, 1)
+	<-state.changeQueue
+	handler(newFieldsTestDeployment(5, 2), newFieldsTestDeployment(5, 3))
+	handler(newFieldsTestDeployment(5, 3), newFieldsTestDeployment(5, 4))
+	assert.Empty(t, state.changeQueue, "throttled changes are not synced within the window")
+
+	require.Eventually(t, func() bool { return len(state.changeQueue) == 1 }, 2*time.Second, 10*time.Millisecond,
+		"the last throttled change is synced when the window ends")
+	change := <-state.changeQueue
+	assert.Equal(t, ActionUpsert, change.Action)
+	assert.Equal(t, int64(4), change.Data.Fields["readyReplicas"], "the latest version of the resource is synced")
+
+	// Deleted resources cancel their deferred sync
+	handler(newFieldsTestDeployment(6, 4), newFieldsTestDeployment(6, 5))
+	extractor.forget(buildResourceID(newFieldsTestDeployment(6, 5)))
+	time.Sleep(200 * time.Millisecond)
+	assert.Empty(t, state.changeQueue)
+
+	// Stopped watchers cancel their deferred syncs
+	handler(newFieldsTestDeployment(8, 5), newFieldsTestDeployment(8, 6))
+	<-state.changeQueue
+	handler(newFieldsTestDeployment(8, 6), newFieldsTestDeployment(8, 7))
+	extractor.stop()
+	time.Sleep(200 * time.Millisecond)
+	assert.Empty(t, state.changeQueue)
+	assert.Empty(t, extractor.pendingFieldSyncs)
+}
+
+func TestResourceSyncReconciler_MakeOnUpdate_ThrottledFieldChangesReadCurrentObject(t *testing.T) {
+	state, store := newFieldsTestState(t)
+	onUpdate := newFilterTestReconciler().makeOnUpdate(state)
+	handler := func(oldObj, newObj *unstructured.Unstructured) {
+		require.NoError(t, store.Update(newObj))
+		onUpdate(oldObj, newObj)
+	}
+	labeled := func(obj *unstructured.Unstructured) *unstructured.Unstructured {
+		obj.SetLabels(map[string]string{"team": "a"})
+		return obj
+	}
+
+	handler(newFieldsTestDeployment(9, 1), newFieldsTestDeployment(9, 2))
+	<-state.changeQueue
+	handler(newFieldsTestDeployment(9, 2), newFieldsTestDeployment(9, 3))
+
+	// A label change syncs the current fields and cancels the deferred sync
+	handler(newFieldsTestDeployment(9, 3), labeled(newFieldsTestDeployment(9, 3)))
+	require.Len(t, state.changeQueue, 1)
+	change := <-state.changeQueue
+	assert.Equal(t, map[string]string{"team": "a"}, change.Data.Labels)
+	assert.Empty(t, state.fields.pendingFieldSyncs, "the deferred sync is cancelled by the upsert")
+
+	// A deferred sync reads the resource from the cache, including later label changes
+	handler(labeled(newFieldsTestDeployment(9, 3)), labeled(newFieldsTestDeployment(9, 4)))
+	assert.Empty(t, state.changeQueue)
+	require.NoError(t, store.Update(labeled(newFieldsTestDeployment(9, 5))))
+	require.Eventually(t, func() bool { return len(state.changeQueue) == 1 }, 2*time.Second, 10*time.Millisecond)
+	change = <-state.changeQueue
+	assert.Equal(t, int64(5), change.Data.Fields["readyReplicas"])
+	assert.Equal(t, map[string]string{"team": "a"}, change.Data.Labels, "labels are not rolled back")
+
+	// Resources removed from the cache are not synced by their deferred sync
+	handler(labeled(newFieldsTestDeployment(9, 5)), labeled(newFieldsTestDeployment(9, 6)))
+	require.NoError(t, store.Delete(labeled(newFieldsTestDeployment(9, 6))))
+	time.Sleep(200 * time.Millisecond)
+	assert.Empty(t, state.changeQueue)
+}
@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// resourceSyncFilter selects the resource types and objects synced by a ResourceSyncConfig
+// A nil filter selects everything
+type resourceSyncFilter struct {
+	includeResources []string
+	excludeResources []string
+	// namespaceSelector is nil when resources in all namespaces are synced
+	namespaceSelector labels.Selector
+	// objectSelector is nil when resources with any labels are synced
+	objectSelector labels.Selector
+	// namespaces lists the namespaces of the synced cluster; nil reads the local manager cache
+	namespaces cache.GenericLister
+}
+
+// newResourceSyncFilter builds the filter of a ResourceSyncConfig
+func newResourceSyncFilter(config *dotaiv1alpha1.ResourceSyncConfig) (*resourceSyncFilter, error) {
+	filter := &resourceSyncFilter{
+		includeResources: config.Spec.IncludeResources,
+		excludeResources: config.Spec.ExcludeResources,
+	}
+
+	var err error
+	if filter.namespaceSelector, err = labelSelector(config.Spec.NamespaceSelector); err != nil {
+		return nil, fmt.Errorf("invalid namespaceSelector: %w", err)
+	}
+	if filter.objectSelector, err = labelSelector(config.Spec.ObjectSelector); err != nil {
+		return nil, fmt.Errorf("invalid objectSelector: %w", err)
+	}
+	return filter, nil
+}
+
+// labelSelector converts a label selector, returning nil when it selects everything
+func labelSelector(selector *metav1.LabelSelector) (labels.Selector, error) {
+	if selector == nil {
+		return nil, nil
+	}
+	converted, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	if converted.Empty() {
+		return nil, nil
+	}
+	return converted, nil
+}
+
+// resourceTypeID returns the ID of a resource type matched by include/exclude patterns
+// Format: "Kind.group" for grouped resources, "Kind" for core resources
+func resourceTypeID(kind, group string) string {
+	if group == "" {
+		return kind
+	}
+	return kind + "." + group
+}
+
+// matchesResource checks if a resource type passes the include/exclude patterns
+func (f *resourceSyncFilter) matchesResource(kind, group string) bool {
+	if f == nil {
+		return true
+	}
+	return matchesResourcePatterns(resourceTypeID(kind, group), f.includeResources, f.excludeResources)
+}
+
+// matchesLabels checks if object labels match the object selector
+func (f *resourceSyncFilter) matchesLabels(objectLabels map[string]string) bool {
+	if f == nil || f.objectSelector == nil {
+		return true
+	}
+	return f.objectSelector.Matches(labels.Set(objectLabels))
+}
+
+// selectsObject checks if an object passes the object and namespace selectors
+// Namespace labels are read from the manager cache, or the namespace informer of a remote cluster;
+// objects in namespaces that cannot be read are not selected
+func (r *ResourceSyncReconciler) selectsObject(ctx context.Context, filter *resourceSyncFilter, obj *unstructured.Unstructured) bool {
+	if !filter.matchesLabels(obj.GetLabels()) {
+		return false
+	}
+	if filter == nil || filter.namespaceSelector == nil || obj.GetNamespace() == "" {
+		return true
+	}
+
+	namespaceLabels, err := r.namespaceLabels(ctx, filter, obj.GetNamespace())
+	if err != nil {
+		logf.FromContext(ctx).WithName("resourcesync").V(1).Info("Failed to get namespace labels, skipping resource",
+			"namespace", obj.GetNamespace(), "name", obj.GetName(), "error", err)
+		return false
+	}
+	return filter.namespaceSelector.Matches(labels.Set(namespaceLabels))
+}
+
+// namespaceLabels returns the labels of a namespace of the synced cluster
+func (r *ResourceSyncReconciler) namespaceLabels(ctx context.Context, filter *resourceSyncFilter, name string) (map[string]string, error) {
+	if filter.namespaces != nil {
+		obj, err := filter.namespaces.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		namespace, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+		return namespace.GetLabels(), nil
+	}
+
+	var namespace corev1.Namespace
+	if err := r.Get(ctx, client.ObjectKey{Name: name}, &namespace); err != nil {
+		return nil, err
+	}
+	return namespace.Labels, nil
+}
+
+// setupNamespaceWatcher watches namespace label changes, which start or stop selecting the objects
+// of a namespace
+func (r *ResourceSyncReconciler) setupNamespaceWatcher(state *activeConfigState) error {
+	informer := state.metadataInformerFactory.ForResource(namespacesGVR).Informer()
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		// New namespaces are empty and the objects of deleted ones are deleted, so only updates matter
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			r.onNamespaceUpdate(state, oldObj, newObj)
+		},
+	})
+	return err
+}
+
+// onNamespaceUpdate queues the objects of a namespace whose labels started or stopped matching the
+// namespace selector: they are upserted when it starts matching and deleted when it stops
+func (r *ResourceSyncReconciler) onNamespaceUpdate(state *activeConfigState, oldObj, newObj interface{}) {
+	oldNamespace, err := meta.Accessor(oldObj)
+	if err != nil {
+		return
+	}
+	newNamespace, err := meta.Accessor(newObj)
+	if err != nil {
+		return
+	}
+	selector := state.filter.namespaceSelector
+	wasSelected := selector.Matches(labels.Set(oldNamespace.GetLabels()))
+	selected := selector.Matches(labels.Set(newNamespace.GetLabels()))
+	if wasSelected == selected {
+		return
+	}
+
+	state.informersMu.RLock()
+	gvrs := make([]schema.GroupVersionResource, 0, len(state.activeInformers))
+	for gvr := range state.activeInformers {
+		gvrs = append(gvrs, gvr)
+	}
+	state.informersMu.RUnlock()
+
+	queued, dropped := 0, 0
+	for _, gvr := range gvrs {
+		for _, cached := range state.cachedInNamespace(gvr, newNamespace.GetName()) {
+			u, ok := cachedUnstructured(cached)
+			if !ok || !state.filter.matchesLabels(u.GetLabels()) {
+				continue
+			}
+
+			id := buildResourceID(u)
+			var change *ResourceChange
+			if selected {
+				change = &ResourceChange{Action: ActionUpsert, Data: state.resourceData(u), ID: id}
+				state.fields.synced(id, time.Now())
+			} else {
+				state.fields.forget(id)
+				change = newDeleteChange(u, state.clusterName())
+			}
+			// Dropped changes are recovered by a targeted resync
+			if trySendChange(state, change) {
+				queued++
+			} else {
+				dropped++
+			}
+		}
+	}
+
+	logf.Log.WithName("resourcesync").Info("🏷️ Namespace selection changed, queued its resources",
+		"namespace", newNamespace.GetName(),
+		"selected", selected,
+		"queued", queued,
+		"dropped", dropped)
+}
+
+// watchedResources returns the synced resource types as sorted group/version/resource strings
+// The CRD watcher is not included since CRDs themselves are not synced
+func (s *activeConfigState) watchedResources() []string {
+	s.informersMu.RLock()
+	defer s.informersMu.RUnlock()
+
+	resources := make([]string, 0, len(s.activeInformers))
+	for gvr := range s.activeInformers {
+		if gvr == crdGVR {
+			continue
+		}
+		resources = append(resources, formatGVR(gvr))
+	}
+	sort.Strings(resources)
+	return resources
+}
+
+// formatGVR formats a GVR as group/version/resource, or version/resource for core resources
+func formatGVR(gvr schema.GroupVersionResource) string {
+	return gvr.GroupVersion().String() + "/" + gvr.Resource
+}
@@ -0,0 +1,215 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// newFilterTestObject creates a namespaced Deployment with the given labels
+func newFilterTestObject(namespace, name string, objectLabels map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+			"labels":    objectLabels,
+		},
+	}}
+}
+
+// newFilterTestReconciler creates a reconciler with a team-a and a team-b namespace
+func newFilterTestReconciler() *ResourceSyncReconciler {
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(newNotificationChannelTestScheme()).
+		WithRuntimeObjects(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"sync": "true"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+		).
+		Build()
+	return &ResourceSyncReconciler{Client: fakeClient}
+}
+
+func TestNewResourceSyncFilter(t *testing.T) {
+	config := &dotaiv1alpha1.ResourceSyncConfig{Spec: dotaiv1alpha1.ResourceSyncConfigSpec{
+		ObjectSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "app", Operator: "Invalid"},
+		}},
+	}}
+	_, err := newResourceSyncFilter(config)
+	assert.ErrorContains(t, err, "invalid objectSelector")
+
+	config.Spec.ObjectSelector = &metav1.LabelSelector{}
+	filter, err := newResourceSyncFilter(config)
+	require.NoError(t, err)
+	assert.Nil(t, filter.objectSelector, "an empty selector selects everything")
+}
+
+func TestResourceSyncFilter_MatchesResource(t *testing.T) {
+	filter := &resourceSyncFilter{
+		includeResources: []string{"*.apps", "Service", "*.crossplane.io"},
+		excludeResources: []string{"ReplicaSet.apps", "*.aws.crossplane.io"},
+	}
+
+	assert.True(t, filter.matchesResource("Deployment", "apps"))
+	assert.True(t, filter.matchesResource("Service", ""))
+	assert.True(t, filter.matchesResource("Composition", "apiextensions.crossplane.io"))
+	assert.False(t, filter.matchesResource("ReplicaSet", "apps"), "excluded resource")
+	assert.False(t, filter.matchesResource("Bucket", "s3.aws.crossplane.io"), "excluded group")
+	assert.False(t, filter.matchesResource("Secret", ""), "not included")
+
+	var noFilter *resourceSyncFilter
+	assert.True(t, noFilter.matchesResource("Secret", ""), "a nil filter selects everything")
+}
+
+func TestResourceSyncReconciler_SelectsObject(t *testing.T) {
+	reconciler := newFilterTestReconciler()
+	filter, err := newResourceSyncFilter(&dotaiv1alpha1.ResourceSyncConfig{Spec: dotaiv1alpha1.ResourceSyncConfigSpec{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"sync": "true"}},
+		ObjectSelector:    &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "frontend"}},
+	}})
+	require.NoError(t, err)
+
+	frontend := map[string]interface{}{"tier": "frontend"}
+	assert.True(t, reconciler.selectsObject(t.Context(), filter, newFilterTestObject("team-a", "web", frontend)))
+	assert.False(t, reconciler.selectsObject(t.Context(), filter, newFilterTestObject("team-b", "web", frontend)), "namespace not selected")
+	assert.False(t, reconciler.selectsObject(t.Context(), filter, newFilterTestObject("missing", "web", frontend)), "unknown namespace")
+	assert.False(t, reconciler.selectsObject(t.Context(), filter,
+		newFilterTestObject("team-a", "db", map[string]interface{}{"tier": "backend"})), "labels not selected")
+	assert.True(t, reconciler.selectsObject(t.Context(), filter, newFilterTestObject("", "web", frontend)),
+		"cluster-scoped resources ignore the namespace selector")
+	assert.True(t, reconciler.selectsObject(t.Context(), nil, newFilterTestObject("team-b", "db", nil)))
+}
+
+func TestResourceSyncReconciler_SelectsObject_RemoteNamespaces(t *testing.T) {
+	reconciler := newFilterTestReconciler()
+	filter, err := newResourceSyncFilter(&dotaiv1alpha1.ResourceSyncConfig{Spec: dotaiv1alpha1.ResourceSyncConfigSpec{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"sync": "true"}},
+	}})
+	require.NoError(t, err)
+
+	// Namespaces are read from the namespace informer of the synced cluster, not the local manager cache
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(&metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+		Name: "team-b", Labels: map[string]string{"sync": "true"},
+	}}))
+	filter.namespaces = cache.NewGenericLister(indexer, namespacesGVR.GroupResource())
+
+	assert.True(t, reconciler.selectsObject(t.Context(), filter, newFilterTestObject("team-b", "web", nil)))
+	assert.False(t, reconciler.selectsObject(t.Context(), filter, newFilterTestObject("team-a", "web", nil)),
+		"namespaces of the local cluster are not used")
+}
+
+func TestResourceSyncReconciler_MakeOnUpdate_DeselectedResource(t *testing.T) {
+	reconciler := newFilterTestReconciler()
+	filter, err := newResourceSyncFilter(&dotaiv1alpha1.ResourceSyncConfig{Spec: dotaiv1alpha1.ResourceSyncConfigSpec{
+		ObjectSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"sync": "true"}},
+	}})
+	require.NoError(t, err)
+	state := &activeConfigState{changeQueue: make(chan *ResourceChange, 10), filter: filter}
+	handler := reconciler.makeOnUpdate(state)
+
+	selected := newFilterTestObject("team-b", "web", map[string]interface{}{"sync": "true"})
+	deselected := newFilterTestObject("team-b", "web", map[string]interface{}{"sync": "false"})
+
+	handler(selected, deselected)
+	require.Len(t, state.changeQueue, 1)
+	change := <-state.changeQueue
+	assert.Equal(t, ActionDelete, change.Action, "resources whose labels stop matching are removed")
+	assert.Equal(t, "team-b:apps/v1:Deployment:web", change.ID)
+	assert.Equal(t, "web", change.DeleteIdentifier.Name)
+
+	handler(deselected, selected)
+	require.Len(t, state.changeQueue, 1)
+	assert.Equal(t, ActionUpsert, (<-state.changeQueue).Action, "resources whose labels start matching are added")
+
+	other := newFilterTestObject("team-b", "web", map[string]interface{}{"sync": "no"})
+	handler(deselected, other)
+	assert.Empty(t, state.changeQueue, "changes of unselected resources are ignored")
+}
+
+func TestResourceSyncReconciler_ListAllResources_AppliesSelectors(t *testing.T) {
+	reconciler := newFilterTestReconciler()
+	filter, err := newResourceSyncFilter(&dotaiv1alpha1.ResourceSyncConfig{Spec: dotaiv1alpha1.ResourceSyncConfigSpec{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"sync": "true"}},
+	}})
+	require.NoError(t, err)
+
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	state := &activeConfigState{
+		filter: filter,
+		activeInformers: map[schema.GroupVersionResource]cache.SharedIndexInformer{
+			deployments: &mockInformer{store: &mockStore{items: []interface{}{
+				newFilterTestObject("team-a", "web", nil),
+				newFilterTestObject("team-b", "web", nil),
+			}}},
+		},
+	}
+
+	resources := reconciler.listAllResources(state)
+	require.Len(t, resources, 1)
+	assert.Equal(t, "team-a", resources[0].Namespace)
+}
+
+func TestResourceSyncReconciler_OnNamespaceUpdate(t *testing.T) {
+	filter, err := newResourceSyncFilter(&dotaiv1alpha1.ResourceSyncConfig{Spec: dotaiv1alpha1.ResourceSyncConfigSpec{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"sync": "true"}},
+		ObjectSelector:    &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "frontend"}},
+	}})
+	require.NoError(t, err)
+	frontend := map[string]interface{}{"tier": "frontend"}
+	state := &activeConfigState{
+		changeQueue: make(chan *ResourceChange, 10),
+		filter:      filter,
+		activeInformers: map[schema.GroupVersionResource]cache.SharedIndexInformer{
+			{Group: "apps", Version: "v1", Resource: "deployments"}: &mockInformer{store: &mockStore{items: []interface{}{
+				newFilterTestObject("team-b", "web", frontend),
+				newFilterTestObject("team-b", "db", map[string]interface{}{"tier": "backend"}),
+				newFilterTestObject("team-c", "web", frontend),
+			}}},
+		},
+	}
+	reconciler := &ResourceSyncReconciler{}
+
+	unlabeled := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}}
+	labeled := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+		Name: "team-b", Labels: map[string]string{"sync": "true"},
+	}}
+
+	reconciler.onNamespaceUpdate(state, unlabeled, labeled)
+	require.Len(t, state.changeQueue, 1, "only selected objects of the namespace are queued")
+	change := <-state.changeQueue
+	assert.Equal(t, ActionUpsert, change.Action, "objects are added when their namespace starts matching")
+	assert.Equal(t, "team-b:apps/v1:Deployment:web", change.ID)
+
+	relabeled := labeled.DeepCopy()
+	relabeled.Labels["owner"] = "team-b"
+	reconciler.onNamespaceUpdate(state, labeled, relabeled)
+	assert.Empty(t, state.changeQueue, "label changes that keep the selection are ignored")
+
+	reconciler.onNamespaceUpdate(state, relabeled, unlabeled)
+	require.Len(t, state.changeQueue, 1)
+	change = <-state.changeQueue
+	assert.Equal(t, ActionDelete, change.Action, "objects are removed when their namespace stops matching")
+	assert.Equal(t, "team-b:apps/v1:Deployment:web", change.ID)
+}
+
+func TestActiveConfigState_WatchedResources(t *testing.T) {
+	state := &activeConfigState{activeInformers: map[schema.GroupVersionResource]cache.SharedIndexInformer{
+		crdGVR: &mockInformer{},
+		{Group: "apps", Version: "v1", Resource: "deployments"}: &mockInformer{},
+		{Version: "v1", Resource: "pods"}:                       &mockInformer{},
+	}}
+
+	assert.Equal(t, []string{"apps/v1/deployments", "v1/pods"}, state.watchedResources())
+}
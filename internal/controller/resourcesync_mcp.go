@@ -1,26 +1,32 @@
 // resourcesync_mcp.go contains the MCP client for syncing resources to the MCP server.
-// This file handles HTTP communication, request/response types, and retry logic
-// for the resource sync endpoint.
+// This file handles request/response types for the resource sync endpoint; HTTP
+// communication and retries are delegated to the shared internal/mcp client.
 package controller
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"math"
-	"math/rand"
 	"net/http"
 	"strings"
 	"time"
 
-	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+	"github.com/vfarcic/dot-ai-controller/internal/mcp"
+)
+
+// ResourceSyncPath is the path of the resource sync endpoint relative to the MCP server URL
+const ResourceSyncPath = "/api/v1/resources/sync"
+
+var (
+	// ErrResyncSessionsUnsupported is returned when the MCP server does not implement resync sessions
+	ErrResyncSessionsUnsupported = errors.New("MCP server does not support resync sessions")
+	// ErrResyncDigestsUnsupported is returned when the MCP server does not implement incremental resync digests
+	ErrResyncDigestsUnsupported = errors.New("MCP server does not support resync digests")
 )
 
 // SyncRequest is the request body for POST /api/v1/resources/sync
@@ -32,6 +38,8 @@ type SyncRequest struct {
 	Deletes []*ResourceIdentifier `json:"deletes,omitempty"`
 	// IsResync indicates this is a full resync (MCP should diff against Qdrant)
 	IsResync bool `json:"isResync,omitempty"`
+	// ClusterName scopes a resync to the resources of one cluster
+	ClusterName string `json:"clusterName,omitempty"`
 }
 
 // SyncResponse is the response from POST /api/v1/resources/sync
@@ -58,6 +66,69 @@ type SyncResponse struct {
 	} `json:"meta,omitempty"`
 }
 
+// ResyncSessionRequest is the request body for POST /api/v1/resources/sync/sessions
+// Beginning a session with the ID of an unfinished session resumes it
+type ResyncSessionRequest struct {
+	SessionID      string `json:"sessionId"`
+	TotalChunks    int    `json:"totalChunks"`
+	TotalResources int    `json:"totalResources"`
+	// ClusterName scopes the deletes of the session to the resources of one cluster
+	ClusterName string `json:"clusterName,omitempty"`
+}
+
+// ResyncChunkRequest is the request body for POST /api/v1/resources/sync/sessions/{id}/chunks
+type ResyncChunkRequest struct {
+	Index   int             `json:"index"`
+	Upserts []*ResourceData `json:"upserts"`
+}
+
+// ResyncCommitRequest is the request body for POST /api/v1/resources/sync/sessions/{id}/commit
+// MCP deletes the resources that were absent from the session and not synced since it began
+type ResyncCommitRequest struct {
+	TotalChunks int `json:"totalChunks"`
+}
+
+// ResyncDigestRequest is the request body for POST /api/v1/resources/sync/digest
+// MCP compares the root hash first and the bucket hashes only when the root differs
+type ResyncDigestRequest struct {
+	Root    string            `json:"root"`
+	Buckets map[string]string `json:"buckets"`
+	// ClusterName scopes the comparison to the resources of one cluster
+	ClusterName string `json:"clusterName,omitempty"`
+}
+
+// ResyncBucketDigestRequest is the request body for POST /api/v1/resources/sync/digest/resources
+// It contains the content hash of each resource by ID, for buckets whose hashes differ
+type ResyncBucketDigestRequest struct {
+	Buckets map[string]map[string]string `json:"buckets"`
+	// ClusterName scopes the comparison to the resources of one cluster
+	ClusterName string `json:"clusterName,omitempty"`
+}
+
+// ResyncResponse is the response of the resync session and digest endpoints
+type ResyncResponse struct {
+	Success bool `json:"success"`
+	Data    *struct {
+		SessionID string `json:"sessionId,omitempty"`
+		// ReceivedChunks lists the chunks MCP already received when a session is resumed
+		ReceivedChunks []int `json:"receivedChunks,omitempty"`
+		Upserted       int   `json:"upserted,omitempty"`
+		Deleted        int   `json:"deleted,omitempty"`
+		// Match reports whether the root hash of a digest matches
+		Match bool `json:"match,omitempty"`
+		// DifferingBuckets lists the buckets whose hashes differ, including buckets only MCP has
+		DifferingBuckets []string `json:"differingBuckets,omitempty"`
+		// Missing lists the IDs of resources that MCP lacks or stores with a different hash
+		Missing []string `json:"missing,omitempty"`
+		// Stale lists the resources MCP stores in the compared buckets that the controller does not have
+		Stale []*ResourceIdentifier `json:"stale,omitempty"`
+	} `json:"data,omitempty"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
 // SyncFailure represents a single resource that failed to sync
 type SyncFailure struct {
 	ID    string `json:"id"`
@@ -97,7 +168,7 @@ func (r *SyncResponse) GetFailures() []SyncFailure {
 type MCPResourceSyncClient struct {
 	// endpoint is the full URL for the sync endpoint (e.g., https://mcp.example.com/api/v1/resources/sync)
 	endpoint string
-	// httpClient is the HTTP client with configured timeout
+	// httpClient is the HTTP client with configured timeout (the shared MCP pool by default)
 	httpClient *http.Client
 	// k8sClient is for fetching secrets
 	k8sClient client.Client
@@ -105,6 +176,16 @@ type MCPResourceSyncClient struct {
 	authSecretRef dotaiv1alpha1.SecretReference
 	// authSecretNamespace is the namespace where the auth secret is located
 	authSecretNamespace string
+	// auth selects an alternative authentication mode (mTLS, OAuth2 or ServiceAccount token)
+	auth *dotaiv1alpha1.McpAuthConfig
+	// tls configures the CA bundle, minimum version and server name of TLS connections
+	tls *dotaiv1alpha1.TLSConfig
+	// proxyURL is the explicit HTTP proxy
+	proxyURL string
+	// serverRef names an MCPServer that provides the endpoint and credentials instead
+	serverRef string
+	// clusterName scopes resyncs to the resources of one cluster
+	clusterName string
 
 	// Retry configuration
 	maxRetries     int
@@ -119,28 +200,32 @@ type MCPResourceSyncClientConfig struct {
 	K8sClient           client.Client
 	AuthSecretRef       dotaiv1alpha1.SecretReference
 	AuthSecretNamespace string
-	MaxRetries          *int // Pointer to distinguish "not set" (nil->default 3) from "set to 0"
+	Auth                *dotaiv1alpha1.McpAuthConfig
+	TLS                 *dotaiv1alpha1.TLSConfig
+	ProxyURL            string
+	ServerRef           string
+	ClusterName         string
+	MaxRetries          *int // Pointer to distinguish "not set" (nil->MCP default) from "set to 0"
 	InitialBackoff      time.Duration
 	MaxBackoff          time.Duration
 }
 
 // NewMCPResourceSyncClient creates a new MCP resource sync client
 func NewMCPResourceSyncClient(cfg MCPResourceSyncClientConfig) *MCPResourceSyncClient {
-	// Apply defaults
-	maxRetries := 3
+	// Apply the process-wide MCP defaults
+	defaults := mcp.DefaultRetryPolicy()
+	maxRetries := defaults.MaxRetries
 	if cfg.MaxRetries != nil {
 		maxRetries = *cfg.MaxRetries
 	}
 	if cfg.InitialBackoff <= 0 {
-		cfg.InitialBackoff = 1 * time.Second
+		cfg.InitialBackoff = defaults.InitialBackoff
 	}
 	if cfg.MaxBackoff <= 0 {
-		cfg.MaxBackoff = 30 * time.Second
+		cfg.MaxBackoff = defaults.MaxBackoff
 	}
 	if cfg.HTTPClient == nil {
-		cfg.HTTPClient = &http.Client{
-			Timeout: 60 * time.Second,
-		}
+		cfg.HTTPClient = mcp.NewHTTPClient()
 	}
 
 	return &MCPResourceSyncClient{
@@ -149,6 +234,11 @@ func NewMCPResourceSyncClient(cfg MCPResourceSyncClientConfig) *MCPResourceSyncC
 		k8sClient:           cfg.K8sClient,
 		authSecretRef:       cfg.AuthSecretRef,
 		authSecretNamespace: cfg.AuthSecretNamespace,
+		auth:                cfg.Auth,
+		tls:                 cfg.TLS,
+		proxyURL:            cfg.ProxyURL,
+		serverRef:           cfg.ServerRef,
+		clusterName:         cfg.ClusterName,
 		maxRetries:          maxRetries,
 		initialBackoff:      cfg.InitialBackoff,
 		maxBackoff:          cfg.MaxBackoff,
@@ -169,170 +259,163 @@ func (c *MCPResourceSyncClient) SyncResources(ctx context.Context, upserts []*Re
 // MCP will diff against Qdrant and handle deletions of orphaned records
 func (c *MCPResourceSyncClient) Resync(ctx context.Context, allResources []*ResourceData) (*SyncResponse, error) {
 	req := SyncRequest{
-		Upserts:  allResources,
-		IsResync: true,
+		Upserts:     allResources,
+		IsResync:    true,
+		ClusterName: c.clusterName,
 	}
 	return c.sendWithRetry(ctx, req)
 }
 
-// sendWithRetry sends the request with exponential backoff retry
-func (c *MCPResourceSyncClient) sendWithRetry(ctx context.Context, req SyncRequest) (*SyncResponse, error) {
-	logger := logf.FromContext(ctx).WithName("resourcesync-mcp")
-
-	var lastErr error
-	for attempt := 0; attempt <= c.maxRetries; attempt++ {
-		if attempt > 0 {
-			// Calculate backoff with jitter
-			backoff := c.calculateBackoff(attempt)
-			logger.V(1).Info("Retrying MCP request",
-				"attempt", attempt,
-				"maxRetries", c.maxRetries,
-				"backoff", backoff,
-			)
-
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(backoff):
-			}
-		}
-
-		resp, err := c.send(ctx, req)
-		if err == nil && resp.Success {
-			return resp, nil
-		}
-
-		if err != nil {
-			lastErr = err
-			logger.V(1).Info("MCP request failed",
-				"attempt", attempt,
-				"error", err,
-			)
-		} else if !resp.Success {
-			lastErr = fmt.Errorf("MCP returned error: %s", resp.GetErrorMessage())
-			logger.V(1).Info("MCP returned error response",
-				"attempt", attempt,
-				"error", resp.GetErrorMessage(),
-			)
-			// If we got a response but with partial success, return it
-			if resp.Error != nil && resp.Error.Details != nil {
-				return resp, lastErr
-			}
-		}
+// resyncIdempotencyKey returns the idempotency key of a resync session request
+// Keys include the attempt, so retries within an attempt are deduplicated but a resumed session is not
+// answered with the responses of an earlier attempt
+func resyncIdempotencyKey(sessionID string, attempt int, request string) string {
+	return fmt.Sprintf("%s-%d-%s", sessionID, attempt, request)
+}
 
-		// Don't retry on context cancellation
-		if ctx.Err() != nil {
-			return nil, ctx.Err()
+// BeginResync begins or resumes a chunked resync session and returns the chunks MCP already received
+// Returns ErrResyncSessionsUnsupported when the server only supports single-request resyncs
+func (c *MCPResourceSyncClient) BeginResync(ctx context.Context, sessionID string, attempt, totalChunks, totalResources int) ([]int, error) {
+	resp, err := c.sendResyncRequest(ctx, "/sessions", resyncIdempotencyKey(sessionID, attempt, "begin"), ResyncSessionRequest{
+		SessionID:      sessionID,
+		TotalChunks:    totalChunks,
+		TotalResources: totalResources,
+		ClusterName:    c.clusterName,
+	})
+	if err != nil {
+		if isUnsupportedEndpoint(err) {
+			return nil, ErrResyncSessionsUnsupported
 		}
+		return nil, err
 	}
-
-	return nil, fmt.Errorf("failed after %d retries: %w", c.maxRetries, lastErr)
+	if resp.Data == nil {
+		return nil, nil
+	}
+	return resp.Data.ReceivedChunks, nil
 }
 
-// calculateBackoff returns the backoff duration with jitter for the given attempt
-func (c *MCPResourceSyncClient) calculateBackoff(attempt int) time.Duration {
-	// Exponential backoff: initialBackoff * 2^attempt
-	backoff := float64(c.initialBackoff) * math.Pow(2, float64(attempt-1))
+// UploadResyncChunk uploads a chunk of a resync session; transient failures are retried per chunk
+func (c *MCPResourceSyncClient) UploadResyncChunk(ctx context.Context, sessionID string, attempt, index int, upserts []*ResourceData) error {
+	_, err := c.sendResyncRequest(ctx, "/sessions/"+sessionID+"/chunks", resyncIdempotencyKey(sessionID, attempt, fmt.Sprintf("chunk-%d", index)),
+		ResyncChunkRequest{Index: index, Upserts: upserts})
+	return err
+}
 
-	// Cap at maxBackoff
-	if backoff > float64(c.maxBackoff) {
-		backoff = float64(c.maxBackoff)
+// CommitResync completes a resync session, so MCP deletes the resources absent from it
+func (c *MCPResourceSyncClient) CommitResync(ctx context.Context, sessionID string, attempt, totalChunks int) (upserted, deleted int, err error) {
+	resp, err := c.sendResyncRequest(ctx, "/sessions/"+sessionID+"/commit", resyncIdempotencyKey(sessionID, attempt, "commit"),
+		ResyncCommitRequest{TotalChunks: totalChunks})
+	if err != nil {
+		return 0, 0, err
 	}
-
-	// Add jitter (±25%)
-	jitter := backoff * 0.25 * (rand.Float64()*2 - 1)
-	return time.Duration(backoff + jitter)
+	if resp.Data != nil {
+		upserted, deleted = resp.Data.Upserted, resp.Data.Deleted
+	}
+	return upserted, deleted, nil
 }
 
-// send performs a single HTTP request to the MCP endpoint
-func (c *MCPResourceSyncClient) send(ctx context.Context, req SyncRequest) (*SyncResponse, error) {
-	logger := logf.FromContext(ctx).WithName("resourcesync-mcp")
-
-	// Get auth token if configured
-	authToken, err := c.getAuthToken(ctx)
+// CompareDigest sends the root and bucket hashes of all resources and returns the buckets that differ in MCP
+// Returns nil when the root hashes match, and ErrResyncDigestsUnsupported when the server does not compare hashes
+func (c *MCPResourceSyncClient) CompareDigest(ctx context.Context, root string, buckets map[string]string) ([]string, error) {
+	resp, err := c.sendResyncRequest(ctx, "/digest", "", ResyncDigestRequest{Root: root, Buckets: buckets, ClusterName: c.clusterName})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get auth token: %w", err)
+		if isUnsupportedEndpoint(err) {
+			return nil, ErrResyncDigestsUnsupported
+		}
+		return nil, err
 	}
+	if resp.Data == nil || resp.Data.Match {
+		return nil, nil
+	}
+	return resp.Data.DifferingBuckets, nil
+}
 
-	// Marshal request
-	requestBody, err := json.Marshal(req)
+// CompareBucketDigests sends the resource hashes of differing buckets and returns the resources
+// MCP lacks or stores with a different hash, and the stale resources to delete
+func (c *MCPResourceSyncClient) CompareBucketDigests(ctx context.Context, buckets map[string]map[string]string) (missing []string, stale []*ResourceIdentifier, err error) {
+	resp, err := c.sendResyncRequest(ctx, "/digest/resources", "", ResyncBucketDigestRequest{Buckets: buckets, ClusterName: c.clusterName})
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, nil, err
 	}
+	if resp.Data == nil {
+		return nil, nil, nil
+	}
+	return resp.Data.Missing, resp.Data.Stale, nil
+}
 
-	logger.V(1).Info("Sending sync request",
-		"endpoint", c.endpoint,
-		"upserts", len(req.Upserts),
-		"deletes", len(req.Deletes),
-		"isResync", req.IsResync,
-		"bodySize", len(requestBody),
-	)
+// isUnsupportedEndpoint checks if MCP rejected a request because it does not implement the endpoint
+func isUnsupportedEndpoint(err error) bool {
+	var statusErr *mcp.StatusError
+	return errors.As(err, &statusErr) &&
+		(statusErr.StatusCode == http.StatusNotFound || statusErr.StatusCode == http.StatusMethodNotAllowed)
+}
 
-	// Create HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewBuffer(requestBody))
+// sendResyncRequest sends a resync session or digest request to a path below the sync endpoint
+// The idempotency key lets MCP detect retries of a chunk that was already received
+func (c *MCPResourceSyncClient) sendResyncRequest(ctx context.Context, path, idempotencyKey string, body interface{}) (*ResyncResponse, error) {
+	endpoint, credentials, mcpClient, err := c.connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	// Set headers
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("User-Agent", "dot-ai-controller/v1.0.0")
-	if authToken != "" {
-		httpReq.Header.Set("Authorization", "Bearer "+authToken)
+	resp, err := mcpClient.Do(ctx, mcp.Request{
+		URL:            endpoint + path,
+		Body:           body,
+		Token:          credentials.Token,
+		IdempotencyKey: idempotencyKey,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Send request
-	startTime := time.Now()
-	resp, err := c.httpClient.Do(httpReq)
-	duration := time.Since(startTime)
-
-	if err != nil {
-		logger.Error(err, "HTTP request failed",
-			"duration", duration,
-		)
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	var sessionResponse ResyncResponse
+	if err := json.Unmarshal(resp.Body, &sessionResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse resync response: %w", err)
 	}
-	defer resp.Body.Close()
+	if !sessionResponse.Success {
+		message := "unknown error"
+		if sessionResponse.Error != nil && sessionResponse.Error.Message != "" {
+			message = sessionResponse.Error.Message
+		}
+		return nil, fmt.Errorf("MCP returned error: %s", message)
+	}
+	return &sessionResponse, nil
+}
+
+// sendWithRetry sends the request through the shared MCP client, which retries transport
+// errors, HTTP error statuses and "success": false responses with exponential backoff
+func (c *MCPResourceSyncClient) sendWithRetry(ctx context.Context, req SyncRequest) (*SyncResponse, error) {
+	logger := logf.FromContext(ctx).WithName("resourcesync-mcp")
 
-	// Read response body
-	responseBody, err := io.ReadAll(resp.Body)
+	endpoint, credentials, mcpClient, err := c.connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
 
-	logger.V(1).Info("Received response",
-		"statusCode", resp.StatusCode,
-		"duration", duration,
-		"bodySize", len(responseBody),
+	logger.V(1).Info("Sending sync request",
+		"endpoint", endpoint,
+		"upserts", len(req.Upserts),
+		"deletes", len(req.Deletes),
+		"isResync", req.IsResync,
 	)
 
-	// Handle HTTP errors
-	if resp.StatusCode >= 400 {
-		return &SyncResponse{
-			Success: false,
-			Error: &struct {
-				Code    string `json:"code"`
-				Message string `json:"message"`
-				Details *struct {
-					Upserted int           `json:"upserted,omitempty"`
-					Deleted  int           `json:"deleted,omitempty"`
-					Failures []SyncFailure `json:"failures,omitempty"`
-				} `json:"details,omitempty"`
-			}{
-				Code:    fmt.Sprintf("%d", resp.StatusCode),
-				Message: fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(responseBody)),
-			},
-		}, nil
+	resp, err := mcpClient.Do(ctx, mcp.Request{
+		URL:   endpoint,
+		Body:  req,
+		Token: credentials.Token,
+		Retry: mcp.RetryFailures,
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Parse JSON response
 	var syncResponse SyncResponse
-	if err := json.Unmarshal(responseBody, &syncResponse); err != nil {
+	if err := json.Unmarshal(resp.Body, &syncResponse); err != nil {
 		// If JSON parsing fails but HTTP was successful, treat as success
 		// Log at Info level (not V(1)) to make unexpected responses visible
 		logger.Info("Response is not JSON, treating as successful",
-			"response", string(responseBody),
+			"response", string(resp.Body),
 			"parseError", err.Error(),
 		)
 		return &SyncResponse{
@@ -347,56 +430,72 @@ func (c *MCPResourceSyncClient) send(ctx context.Context, req SyncRequest) (*Syn
 		}, nil
 	}
 
+	if !syncResponse.Success {
+		err := fmt.Errorf("MCP returned error: %s", syncResponse.GetErrorMessage())
+		// If we got a response with partial success, return it
+		if syncResponse.Error != nil && syncResponse.Error.Details != nil {
+			return &syncResponse, err
+		}
+		return nil, err
+	}
+
 	upserted, deleted := syncResponse.GetSuccessCounts()
 	logger.Info("Sync completed",
 		"success", syncResponse.Success,
 		"upserted", upserted,
 		"deleted", deleted,
-		"duration", duration,
+		"duration", resp.Duration,
+		"attempts", resp.Attempts,
 	)
 
 	return &syncResponse, nil
 }
 
-// getAuthToken resolves the auth token from the secret reference
-func (c *MCPResourceSyncClient) getAuthToken(ctx context.Context) (string, error) {
-	// If no auth secret is configured (empty name), skip auth
-	// This allows tests to work without auth while production requires it via API validation
-	if c.authSecretRef.Name == "" {
-		return "", nil
+// connect resolves the endpoint, credentials and MCP client of the next request. With an
+// MCPServer reference they are resolved from the MCPServer, whose retry settings take precedence.
+func (c *MCPResourceSyncClient) connect(ctx context.Context) (string, mcp.Credentials, *mcp.Client, error) {
+	if c.serverRef == "" {
+		settings, err := mcp.ResolveTLSSettings(ctx, c.k8sClient, c.authSecretNamespace, c.tls, c.proxyURL)
+		if err != nil {
+			return "", mcp.Credentials{}, nil, fmt.Errorf("invalid MCP TLS settings: %w", err)
+		}
+		credentials, err := mcp.ResolveEndpointCredentials(ctx, c.k8sClient, c.authSecretNamespace, c.authSecretRef, c.auth, settings)
+		if err != nil {
+			return "", mcp.Credentials{}, nil, fmt.Errorf("failed to get MCP credentials: %w", err)
+		}
+		return c.endpoint, credentials, c.mcpClient(credentials.HTTPClient(c.httpClient), c.retryPolicy()), nil
 	}
 
-	logger := logf.FromContext(ctx).WithName("resourcesync-mcp")
-
-	secret := &corev1.Secret{}
-	secretKey := client.ObjectKey{
-		Namespace: c.authSecretNamespace,
-		Name:      c.authSecretRef.Name,
+	connection, err := resolveMCPServerConnection(ctx, c.k8sClient, c.serverRef)
+	if err != nil {
+		return "", mcp.Credentials{}, nil, err
 	}
-
-	if err := c.k8sClient.Get(ctx, secretKey, secret); err != nil {
-		if apierrors.IsNotFound(err) {
-			return "", fmt.Errorf("auth secret '%s' not found in namespace '%s'",
-				c.authSecretRef.Name, c.authSecretNamespace)
-		}
-		return "", fmt.Errorf("failed to fetch auth secret: %w", err)
+	retry := c.retryPolicy()
+	if connection.Retry != nil {
+		retry = *connection.Retry
 	}
+	return connection.URL(ResourceSyncPath), connection.Credentials, c.mcpClient(connection.HTTPClient(c.httpClient), retry), nil
+}
 
-	tokenBytes, exists := secret.Data[c.authSecretRef.Key]
-	if !exists {
-		return "", fmt.Errorf("auth secret '%s' does not contain key '%s'",
-			c.authSecretRef.Name, c.authSecretRef.Key)
-	}
+// mcpClient returns the shared MCP client with the given HTTP client and retry policy
+func (c *MCPResourceSyncClient) mcpClient(httpClient *http.Client, retry mcp.RetryPolicy) *mcp.Client {
+	return mcp.NewClient(mcp.Config{
+		Component:  "resourcesync",
+		HTTPClient: httpClient,
+		Retry:      retry,
+	})
+}
 
-	if len(tokenBytes) == 0 {
-		return "", fmt.Errorf("auth secret '%s' key '%s' is empty",
-			c.authSecretRef.Name, c.authSecretRef.Key)
+// retryPolicy returns the retry policy of this client
+func (c *MCPResourceSyncClient) retryPolicy() mcp.RetryPolicy {
+	return mcp.RetryPolicy{
+		MaxRetries:     c.maxRetries,
+		InitialBackoff: c.initialBackoff,
+		MaxBackoff:     c.maxBackoff,
 	}
+}
 
-	logger.V(2).Info("Resolved auth token from secret",
-		"secretName", c.authSecretRef.Name,
-		"secretKey", c.authSecretRef.Key,
-	)
-
-	return string(tokenBytes), nil
+// calculateBackoff returns the backoff duration with jitter for the given attempt
+func (c *MCPResourceSyncClient) calculateBackoff(attempt int) time.Duration {
+	return c.retryPolicy().Backoff(attempt)
 }
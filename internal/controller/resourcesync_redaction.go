@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+const (
+	// lastAppliedConfigAnnotation is never synced since it can be very large
+	lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+	// redactedValue replaces masked values
+	redactedValue = "[REDACTED]"
+	// redactedHashLength is the number of hex characters kept from hashed values (128 bits)
+	redactedHashLength = 32
+)
+
+// compiledRedaction is a redaction rule ready for evaluation
+type compiledRedaction struct {
+	pattern *regexp.Regexp
+	action  dotaiv1alpha1.RedactionAction
+	// keys is empty when the rule applies to all keys
+	keys []*regexp.Regexp
+}
+
+// resourceMetadataRules select the synced annotations and labels and redact synced values
+// A nil value keeps the default annotations and all labels without redaction
+type resourceMetadataRules struct {
+	// annotationAllowlist is empty when the default description annotations are synced
+	annotationAllowlist []*regexp.Regexp
+	labelDenylist       []*regexp.Regexp
+	redactions          []compiledRedaction
+	// hashKey is the HMAC key of hash redactions
+	hashKey []byte
+}
+
+// newResourceMetadataRules compiles the annotation, label and redaction rules of a ResourceSyncConfig
+// hashKey is the HMAC key of hash redactions, read from redactionKeySecretRef.
+// Returns nil when none are configured
+func newResourceMetadataRules(spec dotaiv1alpha1.ResourceSyncConfigSpec, hashKey []byte) (*resourceMetadataRules, error) {
+	if len(spec.AnnotationAllowlist) == 0 && len(spec.LabelDenylist) == 0 && len(spec.Redactions) == 0 {
+		return nil, nil
+	}
+
+	rules := &resourceMetadataRules{hashKey: hashKey}
+	var err error
+	if rules.annotationAllowlist, err = compilePatterns(spec.AnnotationAllowlist); err != nil {
+		return nil, fmt.Errorf("invalid annotationAllowlist: %w", err)
+	}
+	if rules.labelDenylist, err = compilePatterns(spec.LabelDenylist); err != nil {
+		return nil, fmt.Errorf("invalid labelDenylist: %w", err)
+	}
+	for i, redaction := range spec.Redactions {
+		compiled := compiledRedaction{action: redaction.Action}
+		if compiled.action == "" {
+			compiled.action = dotaiv1alpha1.RedactionActionMask
+		}
+		if compiled.pattern, err = regexp.Compile(redaction.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid redactions[%d].pattern: %w", i, err)
+		}
+		if compiled.keys, err = compilePatterns(redaction.Keys); err != nil {
+			return nil, fmt.Errorf("invalid redactions[%d].keys: %w", i, err)
+		}
+		if compiled.action == dotaiv1alpha1.RedactionActionHash && len(hashKey) == 0 {
+			return nil, fmt.Errorf("redactions[%d] uses the hash action, which requires redactionKeySecretRef", i)
+		}
+		rules.redactions = append(rules.redactions, compiled)
+	}
+	return rules, nil
+}
+
+// compilePatterns compiles a list of regular expressions
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// matchesAnyPattern checks if a value matches at least one regular expression
+func matchesAnyPattern(patterns []*regexp.Regexp, value string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// apply selects the annotations and labels of resource data and redacts its values
+func (m *resourceMetadataRules) apply(obj *unstructured.Unstructured, data *ResourceData) {
+	if m == nil {
+		return
+	}
+
+	if len(m.annotationAllowlist) > 0 {
+		data.Annotations = make(map[string]string)
+		for k, v := range obj.GetAnnotations() {
+			if k != lastAppliedConfigAnnotation && matchesAnyPattern(m.annotationAllowlist, k) {
+				data.Annotations[k] = v
+			}
+		}
+	}
+
+	for k := range data.Labels {
+		if matchesAnyPattern(m.labelDenylist, k) {
+			delete(data.Labels, k)
+		}
+	}
+
+	if len(m.redactions) == 0 {
+		return
+	}
+	for k, v := range data.Labels {
+		data.Labels[k] = m.redact(k, v)
+	}
+	for k, v := range data.Annotations {
+		data.Annotations[k] = m.redact(k, v)
+	}
+	for k, v := range data.Fields {
+		data.Fields[k] = m.redactField(k, v)
+	}
+}
+
+// redact applies the redaction rules of a key to a value
+func (m *resourceMetadataRules) redact(key, value string) string {
+	for _, redaction := range m.redactions {
+		if len(redaction.keys) > 0 && !matchesAnyPattern(redaction.keys, key) {
+			continue
+		}
+		value = redaction.pattern.ReplaceAllStringFunc(value, func(match string) string {
+			if redaction.action == dotaiv1alpha1.RedactionActionHash {
+				mac := hmac.New(sha256.New, m.hashKey)
+				mac.Write([]byte(match))
+				return "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil))[:redactedHashLength]
+			}
+			return redactedValue
+		})
+	}
+	return value
+}
+
+// redactField redacts the strings of an extracted field value, including strings in lists and maps
+// and the keys of maps, which can carry values such as emails as well
+func (m *resourceMetadataRules) redactField(key string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return m.redact(key, v)
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = m.redactField(key, item)
+		}
+		return redacted
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			redacted[m.redact(key, k)] = m.redactField(key, item)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+// redactionKeySecretReferences returns the Secret holding the HMAC key of hash redactions
+func redactionKeySecretReferences(config *dotaiv1alpha1.ResourceSyncConfig) []secretKeyReference {
+	return appendSecretRef(nil, "redactionKeySecretRef", config.Spec.RedactionKeySecretRef)
+}
+
+// redactionKey reads the HMAC key of hash redactions from the Secret referenced by a ResourceSyncConfig
+// Returns nil when no key is referenced
+func redactionKey(ctx context.Context, c client.Reader, config *dotaiv1alpha1.ResourceSyncConfig) ([]byte, error) {
+	ref := config.Spec.RedactionKeySecretRef
+	if ref == nil || ref.Name == "" {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: config.Namespace, Name: ref.Name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to fetch redaction key Secret '%s': %w", ref.Name, err)
+	}
+	key := secret.Data[ref.Key]
+	if len(key) == 0 {
+		return nil, fmt.Errorf("redaction key Secret '%s' does not contain a value for key '%s'", ref.Name, ref.Key)
+	}
+	return key, nil
+}
+
+// redactionKeyChanged checks if the HMAC key of hash redactions was rotated since the watcher started
+func (r *ResourceSyncReconciler) redactionKeyChanged(ctx context.Context, config *dotaiv1alpha1.ResourceSyncConfig, state *activeConfigState) bool {
+	version := secretReferencesVersion(ctx, r.Client, config.Namespace, redactionKeySecretReferences(config))
+	return version != state.redactionKeyVersion
+}
@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// redactionTestKey is the HMAC key of hash redactions in tests
+var redactionTestKey = []byte("redaction-test-key")
+
+// newRedactionTestObject creates a Deployment with owner labels and annotations
+func newRedactionTestObject() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      "web",
+			"namespace": "default",
+			"labels": map[string]interface{}{
+				"app":                              "web",
+				"owner":                            "jane.doe@example.com",
+				"pod-template-hash":                "5d4b9c",
+				"internal.example.com/cost-center": "4711",
+			},
+			"annotations": map[string]interface{}{
+				"description":                       "Frontend for jane.doe@example.com",
+				"team.example.com/runbook":          "https://wiki.corp.internal/web",
+				"deployment.kubernetes.io/revision": "3",
+				lastAppliedConfigAnnotation:         "{}",
+			},
+		},
+	}}
+}
+
+func TestNewResourceMetadataRules(t *testing.T) {
+	rules, err := newResourceMetadataRules(dotaiv1alpha1.ResourceSyncConfigSpec{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, rules, "no rules keep the default behavior")
+
+	_, err = newResourceMetadataRules(dotaiv1alpha1.ResourceSyncConfigSpec{LabelDenylist: []string{"("}}, nil)
+	assert.ErrorContains(t, err, "invalid labelDenylist")
+
+	_, err = newResourceMetadataRules(dotaiv1alpha1.ResourceSyncConfigSpec{
+		Redactions: []dotaiv1alpha1.RedactionRule{{Pattern: "a", Keys: []string{"["}}},
+	}, nil)
+	assert.ErrorContains(t, err, "invalid redactions[0].keys")
+
+	_, err = newResourceMetadataRules(dotaiv1alpha1.ResourceSyncConfigSpec{
+		Redactions: []dotaiv1alpha1.RedactionRule{{Pattern: "a", Action: dotaiv1alpha1.RedactionActionHash}},
+	}, nil)
+	assert.ErrorContains(t, err, "requires redactionKeySecretRef", "hashes are never computed without a key")
+}
+
+func TestResourceMetadataRules_Apply(t *testing.T) {
+	rules, err := newResourceMetadataRules(dotaiv1alpha1.ResourceSyncConfigSpec{
+		AnnotationAllowlist: []string{`^description$`, `^team\.example\.com/`, `last-applied`},
+		LabelDenylist:       []string{`^pod-template-hash$`, `^internal\.example\.com/`},
+		Redactions: []dotaiv1alpha1.RedactionRule{
+			{Pattern: `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+`, Action: dotaiv1alpha1.RedactionActionHash, Keys: []string{"^owner$"}},
+			{Pattern: `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+`},
+			{Pattern: `[a-z0-9.-]+\.corp\.internal`},
+		},
+	}, redactionTestKey)
+	require.NoError(t, err)
+
+	obj := newRedactionTestObject()
+	data := extractResourceData(obj)
+	data.Fields = map[string]interface{}{
+		"contacts": []interface{}{"ops@example.com", int64(1)},
+		"oncall":   map[string]interface{}{"ops@example.com": "primary"},
+	}
+	rules.apply(obj, data)
+
+	mac := hmac.New(sha256.New, redactionTestKey)
+	mac.Write([]byte("jane.doe@example.com"))
+	assert.Equal(t, map[string]string{
+		"app":   "web",
+		"owner": "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil))[:redactedHashLength],
+	}, data.Labels, "denied labels are removed and owner emails are hashed with the redaction key")
+	assert.Equal(t, map[string]string{
+		"description":              "Frontend for [REDACTED]",
+		"team.example.com/runbook": "https://[REDACTED]/web",
+	}, data.Annotations, "allowlisted annotations are kept, never the last applied configuration")
+	assert.Equal(t, []interface{}{"[REDACTED]", int64(1)}, data.Fields["contacts"])
+	assert.Equal(t, map[string]interface{}{"[REDACTED]": "primary"}, data.Fields["oncall"], "map keys are redacted too")
+
+	var noRules *resourceMetadataRules
+	data = extractResourceData(obj)
+	noRules.apply(obj, data)
+	assert.Equal(t, map[string]string{"description": "Frontend for jane.doe@example.com"}, data.Annotations)
+}
+
+func TestResourceSyncReconciler_MakeOnUpdate_SyncedMetadata(t *testing.T) {
+	rules, err := newResourceMetadataRules(dotaiv1alpha1.ResourceSyncConfigSpec{
+		AnnotationAllowlist: []string{`^team\.example\.com/`},
+		LabelDenylist:       []string{`^pod-template-hash$`},
+		Redactions: []dotaiv1alpha1.RedactionRule{
+			{Pattern: `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+`, Action: dotaiv1alpha1.RedactionActionHash, Keys: []string{"^owner$"}},
+		},
+	}, redactionTestKey)
+	require.NoError(t, err)
+	state := &activeConfigState{changeQueue: make(chan *ResourceChange, 10), metadata: rules}
+	handler := (&ResourceSyncReconciler{}).makeOnUpdate(state)
+
+	tests := []struct {
+		name   string
+		update func(obj *unstructured.Unstructured)
+		queued bool
+	}{
+		{
+			name: "allowlisted annotation",
+			update: func(obj *unstructured.Unstructured) {
+				require.NoError(t, unstructured.SetNestedField(obj.Object, "https://wiki.corp.internal/web-v2",
+					"metadata", "annotations", "team.example.com/runbook"))
+			},
+			queued: true,
+		},
+		{
+			name: "redacted label value",
+			update: func(obj *unstructured.Unstructured) {
+				require.NoError(t, unstructured.SetNestedField(obj.Object, "john.doe@example.com", "metadata", "labels", "owner"))
+			},
+			queued: true,
+		},
+		{
+			name: "annotation outside the allowlist",
+			update: func(obj *unstructured.Unstructured) {
+				require.NoError(t, unstructured.SetNestedField(obj.Object, "4", "metadata", "annotations", "deployment.kubernetes.io/revision"))
+			},
+		},
+		{
+			name: "denied label",
+			update: func(obj *unstructured.Unstructured) {
+				require.NoError(t, unstructured.SetNestedField(obj.Object, "7f8e1a", "metadata", "labels", "pod-template-hash"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldObj := newRedactionTestObject()
+			newObj := oldObj.DeepCopy()
+			tt.update(newObj)
+
+			handler(oldObj, newObj)
+			if tt.queued {
+				require.Len(t, state.changeQueue, 1)
+				change := <-state.changeQueue
+				assert.Equal(t, ActionUpsert, change.Action)
+			} else {
+				assert.Empty(t, state.changeQueue)
+			}
+		})
+	}
+}
+
+func TestRedactionKey(t *testing.T) {
+	config := &dotaiv1alpha1.ResourceSyncConfig{ObjectMeta: metav1.ObjectMeta{Name: "sync", Namespace: "dot-ai"}}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "redaction", Namespace: "dot-ai"},
+		Data:       map[string][]byte{"key": redactionTestKey},
+	}
+	c := fake.NewClientBuilder().WithObjects(secret).Build()
+
+	key, err := redactionKey(t.Context(), c, config)
+	require.NoError(t, err)
+	assert.Nil(t, key, "no key is read without redactionKeySecretRef")
+
+	config.Spec.RedactionKeySecretRef = &dotaiv1alpha1.SecretReference{Name: "redaction", Key: "key"}
+	key, err = redactionKey(t.Context(), c, config)
+	require.NoError(t, err)
+	assert.Equal(t, redactionTestKey, key)
+
+	config.Spec.RedactionKeySecretRef.Key = "missing"
+	_, err = redactionKey(t.Context(), c, config)
+	assert.ErrorContains(t, err, "does not contain a value for key 'missing'")
+}
@@ -0,0 +1,481 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// Relationship types
+const (
+	// RelationshipOwnedBy links a resource to the owners in its ownerReferences
+	RelationshipOwnedBy = "ownedBy"
+	// RelationshipSelects links a Service to the Pods matching its selector
+	RelationshipSelects = "selects"
+	// RelationshipRoutesTo links an Ingress or HTTPRoute to its backends
+	RelationshipRoutesTo = "routesTo"
+	// RelationshipBoundTo links a PersistentVolumeClaim to its PersistentVolume
+	RelationshipBoundTo = "boundTo"
+	// RelationshipUses links a Pod to the ConfigMaps, Secrets, ServiceAccount and PVCs it references
+	RelationshipUses = "uses"
+)
+
+// ResourceRelationship is an edge from a synced resource to another resource
+type ResourceRelationship struct {
+	// Type of the relationship (e.g., "ownedBy", "selects")
+	Type string `json:"type"`
+	// Namespace of the related resource ("_cluster" for cluster-scoped resources)
+	Namespace string `json:"namespace"`
+	// APIVersion of the related resource
+	APIVersion string `json:"apiVersion"`
+	// Kind of the related resource
+	Kind string `json:"kind"`
+	// Name of the related resource
+	Name string `json:"name"`
+}
+
+var (
+	podsGVR     = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	servicesGVR = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+
+	podGroupKind     = schema.GroupKind{Kind: "Pod"}
+	serviceGroupKind = schema.GroupKind{Kind: "Service"}
+)
+
+// relationshipSource computes the edges of a resource kind from its spec
+type relationshipSource struct {
+	// specFields are the spec fields kept in the informer cache for computing edges
+	specFields []string
+	// containerFields are the container fields kept in the informer cache, for Pods
+	containerFields []string
+	// edges computes the edges from the spec; Service selector edges are computed by the state
+	edges func(obj *unstructured.Unstructured) []ResourceRelationship
+}
+
+// relationshipSources are the resource kinds with computed edges
+var relationshipSources = map[schema.GroupKind]relationshipSource{
+	serviceGroupKind: {specFields: []string{"selector"}},
+	podGroupKind: {
+		specFields:      []string{"serviceAccountName", "volumes", "imagePullSecrets", "containers", "initContainers"},
+		containerFields: []string{"name", "env", "envFrom"},
+		edges:           podEdges,
+	},
+	{Group: "networking.k8s.io", Kind: "Ingress"}:           {specFields: []string{"defaultBackend", "rules"}, edges: ingressEdges},
+	{Group: "gateway.networking.k8s.io", Kind: "HTTPRoute"}: {specFields: []string{"rules"}, edges: httpRouteEdges},
+	{Kind: "PersistentVolumeClaim"}:                         {specFields: []string{"volumeName"}, edges: pvcEdges},
+}
+
+// computesEdges checks if computed edges are synced for a resource kind
+func (s *activeConfigState) computesEdges(groupKind schema.GroupKind) bool {
+	if s.config == nil || s.config.GetRelationshipMode() != dotaiv1alpha1.RelationshipModeAll {
+		return false
+	}
+	_, ok := relationshipSources[groupKind]
+	return ok
+}
+
+// pruneForRelationships drops everything but metadata and the spec fields needed for computing edges
+// Relationship sources without field rules are cached this way instead of as full objects
+func pruneForRelationships(source relationshipSource) func(obj *unstructured.Unstructured) {
+	return func(obj *unstructured.Unstructured) {
+		spec, _, _ := unstructured.NestedMap(obj.Object, "spec")
+		pruned := make(map[string]interface{}, len(source.specFields))
+		for _, field := range source.specFields {
+			if value, ok := spec[field]; ok {
+				pruned[field] = value
+			}
+		}
+		for _, field := range []string{"containers", "initContainers"} {
+			if containers, ok := pruned[field].([]interface{}); ok && len(source.containerFields) > 0 {
+				pruned[field] = pruneContainers(containers, source.containerFields)
+			}
+		}
+		for key := range obj.Object {
+			if key != "apiVersion" && key != "kind" && key != "metadata" {
+				delete(obj.Object, key)
+			}
+		}
+		if len(pruned) > 0 {
+			obj.Object["spec"] = pruned
+		}
+	}
+}
+
+// pruneContainers keeps only the given fields of containers
+func pruneContainers(containers []interface{}, fields []string) []interface{} {
+	pruned := make([]interface{}, 0, len(containers))
+	for _, item := range containers {
+		container, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kept := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			if value, ok := container[field]; ok {
+				kept[field] = value
+			}
+		}
+		pruned = append(pruned, kept)
+	}
+	return pruned
+}
+
+// relationships returns the edges of a resource, sorted and without duplicates
+func (s *activeConfigState) relationships(obj *unstructured.Unstructured) []ResourceRelationship {
+	if s.config == nil || s.config.GetRelationshipMode() == dotaiv1alpha1.RelationshipModeNone {
+		return nil
+	}
+
+	edges := ownerEdges(obj)
+	groupKind := obj.GroupVersionKind().GroupKind()
+	if s.computesEdges(groupKind) {
+		edges = append(edges, specEdges(obj)...)
+		if groupKind == serviceGroupKind {
+			edges = append(edges, s.serviceEdges(obj)...)
+		}
+	}
+	return sortRelationships(edges)
+}
+
+// relationshipsChanged checks if an update changed the edges of a resource
+// Service edges to Pods are updated through Pod events, so only the selector is compared
+func (s *activeConfigState) relationshipsChanged(oldObj, newObj *unstructured.Unstructured) bool {
+	if s.config == nil || s.config.GetRelationshipMode() == dotaiv1alpha1.RelationshipModeNone {
+		return false
+	}
+	if !reflect.DeepEqual(ownerEdges(oldObj), ownerEdges(newObj)) {
+		return true
+	}
+	groupKind := newObj.GroupVersionKind().GroupKind()
+	if !s.computesEdges(groupKind) {
+		return false
+	}
+	if groupKind == serviceGroupKind {
+		oldSelector, _, _ := unstructured.NestedStringMap(oldObj.Object, "spec", "selector")
+		newSelector, _, _ := unstructured.NestedStringMap(newObj.Object, "spec", "selector")
+		return !reflect.DeepEqual(oldSelector, newSelector)
+	}
+	return !reflect.DeepEqual(specEdges(oldObj), specEdges(newObj))
+}
+
+// ownerEdges returns the edges to the owners of a resource
+// Owners of namespaced resources are in the same namespace or cluster-scoped; they are assumed to be
+// in the same namespace, like the garbage collector resolves them
+func ownerEdges(obj *unstructured.Unstructured) []ResourceRelationship {
+	var edges []ResourceRelationship
+	for _, owner := range obj.GetOwnerReferences() {
+		edges = append(edges, ResourceRelationship{
+			Type:       RelationshipOwnedBy,
+			Namespace:  resourceNamespace(obj.GetNamespace()),
+			APIVersion: owner.APIVersion,
+			Kind:       owner.Kind,
+			Name:       owner.Name,
+		})
+	}
+	return edges
+}
+
+// specEdges returns the edges computed from the spec of a relationship source
+func specEdges(obj *unstructured.Unstructured) []ResourceRelationship {
+	source, ok := relationshipSources[obj.GroupVersionKind().GroupKind()]
+	if !ok || source.edges == nil {
+		return nil
+	}
+	return source.edges(obj)
+}
+
+// serviceEdges returns the edges from a Service to the cached Pods matching its selector
+func (s *activeConfigState) serviceEdges(service *unstructured.Unstructured) []ResourceRelationship {
+	selector, found, _ := unstructured.NestedStringMap(service.Object, "spec", "selector")
+	if !found || len(selector) == 0 {
+		return nil
+	}
+
+	var edges []ResourceRelationship
+	matcher := labels.SelectorFromSet(selector)
+	for _, pod := range s.cachedInNamespace(podsGVR, service.GetNamespace()) {
+		if matcher.Matches(labels.Set(pod.GetLabels())) {
+			edges = append(edges, ResourceRelationship{
+				Type:       RelationshipSelects,
+				Namespace:  pod.GetNamespace(),
+				APIVersion: "v1",
+				Kind:       "Pod",
+				Name:       pod.GetName(),
+			})
+		}
+	}
+	return edges
+}
+
+// cachedInNamespace returns the cached objects of a watched resource type in a namespace
+// Objects are returned as cached, so metadata-only objects are not converted to read their labels.
+func (s *activeConfigState) cachedInNamespace(gvr schema.GroupVersionResource, namespace string) []metav1.Object {
+	s.informersMu.RLock()
+	informer, ok := s.activeInformers[gvr]
+	s.informersMu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	var items []interface{}
+	if indexer := informer.GetIndexer(); indexer != nil {
+		items, _ = indexer.ByIndex(cache.NamespaceIndex, namespace)
+	} else {
+		items = informer.GetStore().List()
+	}
+
+	objects := make([]metav1.Object, 0, len(items))
+	for _, item := range items {
+		if obj, err := meta.Accessor(item); err == nil && obj.GetNamespace() == namespace {
+			objects = append(objects, obj)
+		}
+	}
+	return objects
+}
+
+// queueSelectingServices re-syncs the Services whose selectors match a Pod's old or new labels,
+// so their edges follow Pods as they are created, relabeled and deleted
+func (r *ResourceSyncReconciler) queueSelectingServices(state *activeConfigState, namespace string, oldLabels, newLabels map[string]string) {
+	if !state.computesEdges(serviceGroupKind) {
+		return
+	}
+	logger := logf.Log.WithName("resourcesync")
+
+	for _, cached := range state.cachedInNamespace(servicesGVR, namespace) {
+		service, ok := cachedUnstructured(cached)
+		if !ok {
+			continue
+		}
+		selector, found, _ := unstructured.NestedStringMap(service.Object, "spec", "selector")
+		if !found || len(selector) == 0 {
+			continue
+		}
+		matcher := labels.SelectorFromSet(selector)
+		if !(oldLabels != nil && matcher.Matches(labels.Set(oldLabels))) && !(newLabels != nil && matcher.Matches(labels.Set(newLabels))) {
+			continue
+		}
+		if !r.selectsObject(context.Background(), state.filter, service) {
+			continue
+		}
+
+		id := buildResourceID(service)
+		if trySendChange(state, &ResourceChange{Action: ActionUpsert, Data: state.resourceData(service), ID: id}) {
+			logger.V(2).Info("Queued Service for relationship update", "id", id)
+		} else {
+			logger.V(1).Info("Change queue full or closed, dropping Service relationship update", "id", id)
+		}
+	}
+}
+
+// podEdges returns the ConfigMaps, Secrets, ServiceAccount and PVCs referenced by a Pod
+func podEdges(pod *unstructured.Unstructured) []ResourceRelationship {
+	namespace := pod.GetNamespace()
+	var edges []ResourceRelationship
+	uses := func(kind, name string) {
+		if name != "" {
+			edges = append(edges, ResourceRelationship{Type: RelationshipUses, Namespace: namespace, APIVersion: "v1", Kind: kind, Name: name})
+		}
+	}
+
+	if name, _, _ := unstructured.NestedString(pod.Object, "spec", "serviceAccountName"); name != "" {
+		uses("ServiceAccount", name)
+	}
+	secrets, _, _ := unstructured.NestedSlice(pod.Object, "spec", "imagePullSecrets")
+	for _, secret := range secrets {
+		uses("Secret", nestedString(secret, "name"))
+	}
+
+	volumes, _, _ := unstructured.NestedSlice(pod.Object, "spec", "volumes")
+	for _, volume := range volumes {
+		uses("ConfigMap", nestedString(volume, "configMap", "name"))
+		uses("Secret", nestedString(volume, "secret", "secretName"))
+		uses("PersistentVolumeClaim", nestedString(volume, "persistentVolumeClaim", "claimName"))
+		sources, _, _ := unstructured.NestedSlice(asMap(volume), "projected", "sources")
+		for _, source := range sources {
+			uses("ConfigMap", nestedString(source, "configMap", "name"))
+			uses("Secret", nestedString(source, "secret", "name"))
+		}
+	}
+
+	for _, field := range []string{"containers", "initContainers"} {
+		containers, _, _ := unstructured.NestedSlice(pod.Object, "spec", field)
+		for _, container := range containers {
+			envFrom, _, _ := unstructured.NestedSlice(asMap(container), "envFrom")
+			for _, source := range envFrom {
+				uses("ConfigMap", nestedString(source, "configMapRef", "name"))
+				uses("Secret", nestedString(source, "secretRef", "name"))
+			}
+			env, _, _ := unstructured.NestedSlice(asMap(container), "env")
+			for _, variable := range env {
+				uses("ConfigMap", nestedString(variable, "valueFrom", "configMapKeyRef", "name"))
+				uses("Secret", nestedString(variable, "valueFrom", "secretKeyRef", "name"))
+			}
+		}
+	}
+	return edges
+}
+
+// ingressEdges returns the Services and resources an Ingress routes to
+func ingressEdges(ingress *unstructured.Unstructured) []ResourceRelationship {
+	var edges []ResourceRelationship
+	routesTo := func(backend interface{}) {
+		if name := nestedString(backend, "service", "name"); name != "" {
+			edges = append(edges, ResourceRelationship{
+				Type: RelationshipRoutesTo, Namespace: ingress.GetNamespace(), APIVersion: "v1", Kind: "Service", Name: name,
+			})
+		}
+		if name := nestedString(backend, "resource", "name"); name != "" {
+			edges = append(edges, ResourceRelationship{
+				Type:       RelationshipRoutesTo,
+				Namespace:  ingress.GetNamespace(),
+				APIVersion: nestedString(backend, "resource", "apiGroup"),
+				Kind:       nestedString(backend, "resource", "kind"),
+				Name:       name,
+			})
+		}
+	}
+
+	if backend, found, _ := unstructured.NestedMap(ingress.Object, "spec", "defaultBackend"); found {
+		routesTo(backend)
+	}
+	rules, _, _ := unstructured.NestedSlice(ingress.Object, "spec", "rules")
+	for _, rule := range rules {
+		paths, _, _ := unstructured.NestedSlice(asMap(rule), "http", "paths")
+		for _, path := range paths {
+			routesTo(asMap(path)["backend"])
+		}
+	}
+	return edges
+}
+
+// httpRouteEdges returns the Services an HTTPRoute routes to
+// Backends of other kinds are left out, since their API version is unknown
+func httpRouteEdges(route *unstructured.Unstructured) []ResourceRelationship {
+	var edges []ResourceRelationship
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	for _, rule := range rules {
+		backendRefs, _, _ := unstructured.NestedSlice(asMap(rule), "backendRefs")
+		for _, backendRef := range backendRefs {
+			if group := nestedString(backendRef, "group"); group != "" {
+				continue
+			}
+			if kind := nestedString(backendRef, "kind"); kind != "" && kind != "Service" {
+				continue
+			}
+			namespace := nestedString(backendRef, "namespace")
+			if namespace == "" {
+				namespace = route.GetNamespace()
+			}
+			if name := nestedString(backendRef, "name"); name != "" {
+				edges = append(edges, ResourceRelationship{
+					Type: RelationshipRoutesTo, Namespace: namespace, APIVersion: "v1", Kind: "Service", Name: name,
+				})
+			}
+		}
+	}
+	return edges
+}
+
+// pvcEdges returns the PersistentVolume a PersistentVolumeClaim is bound to
+func pvcEdges(pvc *unstructured.Unstructured) []ResourceRelationship {
+	name, _, _ := unstructured.NestedString(pvc.Object, "spec", "volumeName")
+	if name == "" {
+		return nil
+	}
+	return []ResourceRelationship{{
+		Type: RelationshipBoundTo, Namespace: clusterScopeNamespace, APIVersion: "v1", Kind: "PersistentVolume", Name: name,
+	}}
+}
+
+// sortRelationships sorts edges and removes duplicates, so equal edges have the same content hash
+func sortRelationships(edges []ResourceRelationship) []ResourceRelationship {
+	if len(edges) == 0 {
+		return nil
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		a, b := edges[i], edges[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.APIVersion != b.APIVersion {
+			return a.APIVersion < b.APIVersion
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	unique := edges[:1]
+	for _, edge := range edges[1:] {
+		if edge != unique[len(unique)-1] {
+			unique = append(unique, edge)
+		}
+	}
+	return unique
+}
+
+// resourceNamespace returns the namespace of synced data ("_cluster" for cluster-scoped resources)
+func resourceNamespace(namespace string) string {
+	if namespace == "" {
+		return clusterScopeNamespace
+	}
+	return namespace
+}
+
+// asMap returns a value as a map, or nil when it is not one
+func asMap(value interface{}) map[string]interface{} {
+	m, _ := value.(map[string]interface{})
+	return m
+}
+
+// nestedString returns a nested string of a map value, or "" when it is missing
+func nestedString(value interface{}, fields ...string) string {
+	s, _, _ := unstructured.NestedString(asMap(value), fields...)
+	return s
+}
+
+// onPodEvent re-syncs the Services selecting a Pod when it is created, relabeled or deleted
+// A nil oldObj means the Pod was created, a nil newObj that it was deleted
+func (r *ResourceSyncReconciler) onPodEvent(state *activeConfigState, oldObj, newObj interface{}) {
+	oldPod, newPod := podFromEvent(oldObj), podFromEvent(newObj)
+	switch {
+	case oldPod == nil && newPod == nil:
+		return
+	case oldPod == nil:
+		r.queueSelectingServices(state, newPod.GetNamespace(), nil, newPod.GetLabels())
+	case newPod == nil:
+		r.queueSelectingServices(state, oldPod.GetNamespace(), oldPod.GetLabels(), nil)
+	case !reflect.DeepEqual(oldPod.GetLabels(), newPod.GetLabels()):
+		r.queueSelectingServices(state, newPod.GetNamespace(), oldPod.GetLabels(), newPod.GetLabels())
+	}
+}
+
+// podFromEvent returns the Pod of an informer event, or nil for other objects
+func podFromEvent(obj interface{}) metav1.Object {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	o, ok := obj.(runtime.Object)
+	if !ok || o.GetObjectKind().GroupVersionKind().GroupKind() != podGroupKind {
+		return nil
+	}
+	pod, err := meta.Accessor(o)
+	if err != nil {
+		return nil
+	}
+	return pod
+}
@@ -0,0 +1,256 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// newRelationshipsTestState creates a state syncing relationships in the given mode, with cached Pods and Services
+func newRelationshipsTestState(mode dotaiv1alpha1.RelationshipMode, pods, services []interface{}) *activeConfigState {
+	return &activeConfigState{
+		config: &dotaiv1alpha1.ResourceSyncConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "sync", Namespace: "dot-ai"},
+			Spec:       dotaiv1alpha1.ResourceSyncConfigSpec{Relationships: mode},
+		},
+		activeInformers: map[schema.GroupVersionResource]cache.SharedIndexInformer{
+			podsGVR:     &mockInformer{store: &mockStore{items: pods}},
+			servicesGVR: &mockInformer{store: &mockStore{items: services}},
+		},
+		changeQueue: make(chan *ResourceChange, 10),
+	}
+}
+
+// newRelationshipsTestPod creates a Pod with labels, using a ConfigMap, a Secret, a PVC and a ServiceAccount
+func newRelationshipsTestPod(name string, podLabels map[string]string) *unstructured.Unstructured {
+	pod := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "default",
+			"ownerReferences": []interface{}{
+				map[string]interface{}{"apiVersion": "apps/v1", "kind": "ReplicaSet", "name": "web-7d4b9", "uid": "1"},
+			},
+		},
+		"spec": map[string]interface{}{
+			"serviceAccountName": "web",
+			"imagePullSecrets":   []interface{}{map[string]interface{}{"name": "registry"}},
+			"volumes": []interface{}{
+				map[string]interface{}{"name": "config", "configMap": map[string]interface{}{"name": "web-config"}},
+				map[string]interface{}{"name": "data", "persistentVolumeClaim": map[string]interface{}{"claimName": "web-data"}},
+			},
+			"containers": []interface{}{
+				map[string]interface{}{
+					"name":    "web",
+					"image":   "nginx",
+					"envFrom": []interface{}{map[string]interface{}{"secretRef": map[string]interface{}{"name": "web-credentials"}}},
+					"env": []interface{}{
+						map[string]interface{}{"name": "MODE", "valueFrom": map[string]interface{}{
+							"configMapKeyRef": map[string]interface{}{"name": "web-config", "key": "mode"},
+						}},
+					},
+				},
+			},
+		},
+	}}
+	pod.SetLabels(podLabels)
+	return pod
+}
+
+// newRelationshipsTestService creates a Service selecting Pods by labels
+func newRelationshipsTestService(name string, selector map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		"spec":       map[string]interface{}{"selector": selector, "ports": []interface{}{map[string]interface{}{"port": int64(80)}}},
+	}}
+}
+
+func TestActiveConfigState_Relationships_Pod(t *testing.T) {
+	state := newRelationshipsTestState(dotaiv1alpha1.RelationshipModeAll, nil, nil)
+
+	edges := state.relationships(newRelationshipsTestPod("web-1", nil))
+	assert.Equal(t, []ResourceRelationship{
+		{Type: RelationshipOwnedBy, Namespace: "default", APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-7d4b9"},
+		{Type: RelationshipUses, Namespace: "default", APIVersion: "v1", Kind: "ConfigMap", Name: "web-config"},
+		{Type: RelationshipUses, Namespace: "default", APIVersion: "v1", Kind: "PersistentVolumeClaim", Name: "web-data"},
+		{Type: RelationshipUses, Namespace: "default", APIVersion: "v1", Kind: "Secret", Name: "registry"},
+		{Type: RelationshipUses, Namespace: "default", APIVersion: "v1", Kind: "Secret", Name: "web-credentials"},
+		{Type: RelationshipUses, Namespace: "default", APIVersion: "v1", Kind: "ServiceAccount", Name: "web"},
+	}, edges, "edges are sorted and the ConfigMap used twice is listed once")
+}
+
+func TestActiveConfigState_Relationships_Modes(t *testing.T) {
+	pod := newRelationshipsTestPod("web-1", nil)
+
+	owners := newRelationshipsTestState(dotaiv1alpha1.RelationshipModeOwnerReferences, nil, nil)
+	assert.Equal(t, []ResourceRelationship{
+		{Type: RelationshipOwnedBy, Namespace: "default", APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-7d4b9"},
+	}, owners.relationships(pod))
+	assert.False(t, owners.computesEdges(podGroupKind))
+
+	none := newRelationshipsTestState(dotaiv1alpha1.RelationshipModeNone, nil, nil)
+	assert.Nil(t, none.relationships(pod))
+
+	defaults := newRelationshipsTestState("", nil, nil)
+	assert.True(t, defaults.computesEdges(podGroupKind), "computed edges are synced by default")
+}
+
+func TestActiveConfigState_Relationships_Routes(t *testing.T) {
+	state := newRelationshipsTestState(dotaiv1alpha1.RelationshipModeAll, nil, nil)
+
+	ingress := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "networking.k8s.io/v1",
+		"kind":       "Ingress",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "default"},
+		"spec": map[string]interface{}{
+			"defaultBackend": map[string]interface{}{"service": map[string]interface{}{"name": "fallback"}},
+			"rules": []interface{}{map[string]interface{}{"http": map[string]interface{}{"paths": []interface{}{
+				map[string]interface{}{"path": "/", "backend": map[string]interface{}{"service": map[string]interface{}{"name": "web"}}},
+			}}}},
+		},
+	}}
+	assert.Equal(t, []ResourceRelationship{
+		{Type: RelationshipRoutesTo, Namespace: "default", APIVersion: "v1", Kind: "Service", Name: "fallback"},
+		{Type: RelationshipRoutesTo, Namespace: "default", APIVersion: "v1", Kind: "Service", Name: "web"},
+	}, state.relationships(ingress))
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "gateway.networking.k8s.io/v1",
+		"kind":       "HTTPRoute",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "default"},
+		"spec": map[string]interface{}{"rules": []interface{}{map[string]interface{}{"backendRefs": []interface{}{
+			map[string]interface{}{"name": "web"},
+			map[string]interface{}{"name": "api", "namespace": "backend", "kind": "Service"},
+			map[string]interface{}{"name": "bucket", "group": "storage.example.com", "kind": "Bucket"},
+		}}}},
+	}}
+	assert.Equal(t, []ResourceRelationship{
+		{Type: RelationshipRoutesTo, Namespace: "backend", APIVersion: "v1", Kind: "Service", Name: "api"},
+		{Type: RelationshipRoutesTo, Namespace: "default", APIVersion: "v1", Kind: "Service", Name: "web"},
+	}, state.relationships(route))
+
+	pvc := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "PersistentVolumeClaim",
+		"metadata":   map[string]interface{}{"name": "web-data", "namespace": "default"},
+		"spec":       map[string]interface{}{"volumeName": "pv-1234"},
+	}}
+	assert.Equal(t, []ResourceRelationship{
+		{Type: RelationshipBoundTo, Namespace: clusterScopeNamespace, APIVersion: "v1", Kind: "PersistentVolume", Name: "pv-1234"},
+	}, state.relationships(pvc))
+}
+
+func TestActiveConfigState_Relationships_ServiceSelectsPods(t *testing.T) {
+	other := newRelationshipsTestPod("other-1", map[string]string{"app": "other"})
+	otherNamespace := newRelationshipsTestPod("web-2", map[string]string{"app": "web"})
+	otherNamespace.SetNamespace("team-a")
+	state := newRelationshipsTestState(dotaiv1alpha1.RelationshipModeAll, []interface{}{
+		newRelationshipsTestPod("web-1", map[string]string{"app": "web", "tier": "frontend"}),
+		other,
+		otherNamespace,
+	}, nil)
+
+	edges := state.relationships(newRelationshipsTestService("web", map[string]interface{}{"app": "web"}))
+	assert.Equal(t, []ResourceRelationship{
+		{Type: RelationshipSelects, Namespace: "default", APIVersion: "v1", Kind: "Pod", Name: "web-1"},
+	}, edges)
+
+	assert.Nil(t, state.relationships(newRelationshipsTestService("external", nil)), "Services without selectors select no Pods")
+}
+
+func TestActiveConfigState_RelationshipsChanged(t *testing.T) {
+	state := newRelationshipsTestState(dotaiv1alpha1.RelationshipModeAll, nil, nil)
+
+	oldPod := newRelationshipsTestPod("web-1", nil)
+	newPod := oldPod.DeepCopy()
+	assert.False(t, state.relationshipsChanged(oldPod, newPod))
+
+	volumes, _, _ := unstructured.NestedSlice(newPod.Object, "spec", "volumes")
+	volumes = append(volumes, map[string]interface{}{"name": "tls", "secret": map[string]interface{}{"secretName": "web-tls"}})
+	require.NoError(t, unstructured.SetNestedSlice(newPod.Object, volumes, "spec", "volumes"))
+	assert.True(t, state.relationshipsChanged(oldPod, newPod), "a new Secret volume adds an edge")
+
+	oldService := newRelationshipsTestService("web", map[string]interface{}{"app": "web"})
+	newService := newRelationshipsTestService("web", map[string]interface{}{"app": "web", "tier": "frontend"})
+	assert.True(t, state.relationshipsChanged(oldService, newService))
+
+	owned := newPod.DeepCopy()
+	owned.SetOwnerReferences(nil)
+	owners := newRelationshipsTestState(dotaiv1alpha1.RelationshipModeOwnerReferences, nil, nil)
+	assert.False(t, owners.relationshipsChanged(oldPod, newPod), "spec edges are not compared without computed edges")
+	assert.True(t, owners.relationshipsChanged(newPod, owned))
+}
+
+func TestPruneForRelationships(t *testing.T) {
+	pod := newRelationshipsTestPod("web-1", map[string]string{"app": "web"})
+	pod.Object["status"] = map[string]interface{}{"phase": "Running"}
+	require.NoError(t, unstructured.SetNestedField(pod.Object, "node-1", "spec", "nodeName"))
+	before := (&activeConfigState{config: &dotaiv1alpha1.ResourceSyncConfig{}}).relationships(pod)
+
+	pruneForRelationships(relationshipSources[podGroupKind])(pod)
+
+	_, found, _ := unstructured.NestedFieldNoCopy(pod.Object, "status")
+	assert.False(t, found, "status is dropped")
+	_, found, _ = unstructured.NestedFieldNoCopy(pod.Object, "spec", "nodeName")
+	assert.False(t, found, "spec fields without edges are dropped")
+	containers, _, _ := unstructured.NestedSlice(pod.Object, "spec", "containers")
+	require.Len(t, containers, 1)
+	assert.NotContains(t, containers[0], "image")
+	assert.Equal(t, map[string]string{"app": "web"}, pod.GetLabels())
+
+	after := (&activeConfigState{config: &dotaiv1alpha1.ResourceSyncConfig{}}).relationships(pod)
+	assert.Equal(t, before, after, "pruned Pods have the same edges")
+}
+
+func TestResourceSyncReconciler_OnPodEvent(t *testing.T) {
+	web := newRelationshipsTestService("web", map[string]interface{}{"app": "web"})
+	api := newRelationshipsTestService("api", map[string]interface{}{"app": "api"})
+	oldPod := newRelationshipsTestPod("web-1", map[string]string{"app": "web"})
+	newPod := newRelationshipsTestPod("web-1", map[string]string{"app": "api"})
+	state := newRelationshipsTestState(dotaiv1alpha1.RelationshipModeAll, []interface{}{newPod}, []interface{}{web, api})
+	reconciler := &ResourceSyncReconciler{}
+
+	// Relabeling a Pod re-syncs the Services that selected it and the ones that select it now
+	reconciler.onPodEvent(state, oldPod, newPod)
+	require.Len(t, state.changeQueue, 2)
+	first, second := <-state.changeQueue, <-state.changeQueue
+	assert.Equal(t, "default:v1:Service:web", first.ID)
+	assert.Empty(t, first.Data.Relationships, "the relabeled Pod is no longer selected")
+	assert.Equal(t, "default:v1:Service:api", second.ID)
+	assert.Equal(t, []ResourceRelationship{
+		{Type: RelationshipSelects, Namespace: "default", APIVersion: "v1", Kind: "Pod", Name: "web-1"},
+	}, second.Data.Relationships)
+
+	// Updates without label changes and other kinds do not re-sync Services
+	reconciler.onPodEvent(state, newPod, newPod.DeepCopy())
+	reconciler.onPodEvent(state, nil, web)
+	assert.Empty(t, state.changeQueue)
+
+	reconciler.onPodEvent(state, cache.DeletedFinalStateUnknown{Key: "default/web-1", Obj: newPod}, nil)
+	require.Len(t, state.changeQueue, 1)
+	assert.Equal(t, "default:v1:Service:api", (<-state.changeQueue).ID)
+
+	// Pods cached as metadata are read without converting them
+	metadataPod := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+		Name: "web-2", Namespace: "default", Labels: map[string]string{"app": "web"},
+	}}
+	metadataPod.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "Pod"})
+	reconciler.onPodEvent(state, nil, metadataPod)
+	require.Len(t, state.changeQueue, 1)
+	assert.Equal(t, "default:v1:Service:web", (<-state.changeQueue).ID)
+
+	// Services are not re-synced without computed edges
+	state.config.Spec.Relationships = dotaiv1alpha1.RelationshipModeOwnerReferences
+	reconciler.onPodEvent(state, nil, newPod)
+	assert.Empty(t, state.changeQueue)
+}
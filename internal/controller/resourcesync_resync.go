@@ -0,0 +1,281 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+const (
+	// resyncRetryDelay is how long to wait before resuming an incomplete resync
+	resyncRetryDelay = time.Minute
+	// resyncSessionMaxAge is how long an incomplete resync session is resumed, including after a restart
+	// Older sessions are abandoned and a new session starts
+	resyncSessionMaxAge = time.Hour
+	// maxResyncChunkFailures caps the chunk failures reported in status
+	maxResyncChunkFailures = 10
+	// resyncProgressInterval is the number of uploaded chunks between status progress updates
+	resyncProgressInterval = 10
+	// resyncChunkHashLength is the number of hex characters kept from chunk hashes (128 bits),
+	// which keeps the hashes persisted in status small
+	resyncChunkHashLength = 32
+)
+
+// resumableResync returns the resync session to resume from a persisted status
+// Returns nil when the last session was committed or is too old to resume
+func resumableResync(progress *dotaiv1alpha1.ResyncProgress, now time.Time) *dotaiv1alpha1.ResyncProgress {
+	if progress == nil || progress.SessionID == "" || progress.TotalChunks == 0 ||
+		progress.Phase == dotaiv1alpha1.ResyncPhaseCompleted {
+		return nil
+	}
+	if progress.StartTime == nil || now.Sub(progress.StartTime.Time) > resyncSessionMaxAge {
+		return nil
+	}
+	return progress.DeepCopy()
+}
+
+// resyncChunkHash returns a hash of the IDs and content hashes of the resources in a chunk
+// A chunk whose hash changed since it was uploaded, for example because a resource was created or
+// deleted, is uploaded again when its session is resumed
+func resyncChunkHash(chunk []*ResourceData) string {
+	entries := make([]string, len(chunk))
+	for i, resource := range chunk {
+		entries[i] = resourceDataID(resource) + "=" + resource.ContentHash
+	}
+	sort.Strings(entries)
+
+	h := sha256.New()
+	for _, entry := range entries {
+		_, _ = fmt.Fprintln(h, entry)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:resyncChunkHashLength]
+}
+
+// resyncChunks splits resources sorted by ID into totalChunks chunks of at most chunkSize resources
+// The split is stable across attempts while resources do not change, so a resumed session skips the
+// chunks MCP received; a created or deleted resource shifts the chunks after it, which are uploaded again.
+// Chunks beyond the resources are empty, since MCP expects every chunk of the session.
+func resyncChunks(resources []*ResourceData, totalChunks, chunkSize int) [][]*ResourceData {
+	sorted := make([]*ResourceData, len(resources))
+	copy(sorted, resources)
+	sort.Slice(sorted, func(i, j int) bool { return resourceDataID(sorted[i]) < resourceDataID(sorted[j]) })
+
+	chunks := make([][]*ResourceData, totalChunks)
+	for i := range chunks {
+		start := min(i*chunkSize, len(sorted))
+		end := min(start+chunkSize, len(sorted))
+		chunks[i] = sorted[start:end:end]
+	}
+	return chunks
+}
+
+// resyncProgressSnapshot returns a copy of the current resync progress for status updates
+func (s *activeConfigState) resyncProgressSnapshot() *dotaiv1alpha1.ResyncProgress {
+	s.resyncMu.Lock()
+	defer s.resyncMu.Unlock()
+	return s.resyncProgress.DeepCopy()
+}
+
+// nextResyncSession resumes the unfinished resync session or begins a new one
+// A session is resumed only while its chunks still hold the resources without exceeding chunkSize
+func (s *activeConfigState) nextResyncSession(resourceCount, chunkSize int, now time.Time) *dotaiv1alpha1.ResyncProgress {
+	s.resyncMu.Lock()
+	defer s.resyncMu.Unlock()
+
+	if session := resumableResync(s.resyncProgress, now); session != nil &&
+		resourceCount <= session.TotalChunks*chunkSize {
+		session.Phase = dotaiv1alpha1.ResyncPhaseInProgress
+		session.TotalResources = resourceCount
+		session.CompletedChunks = 0
+		session.Attempt++
+		s.resyncProgress = session
+		return session.DeepCopy()
+	}
+
+	start := metav1.NewTime(now)
+	s.resyncProgress = &dotaiv1alpha1.ResyncProgress{
+		SessionID:      string(uuid.NewUUID()),
+		Phase:          dotaiv1alpha1.ResyncPhaseInProgress,
+		StartTime:      &start,
+		Attempt:        1,
+		TotalResources: resourceCount,
+		TotalChunks:    (resourceCount + chunkSize - 1) / chunkSize,
+	}
+	return s.resyncProgress.DeepCopy()
+}
+
+// uploadedChunkUnchanged reports whether a chunk was uploaded in the current session with the same hash
+// The hashes are persisted in status with the session, so they survive restarts
+func (s *activeConfigState) uploadedChunkUnchanged(index int, hash string) bool {
+	s.resyncMu.Lock()
+	defer s.resyncMu.Unlock()
+	if s.resyncProgress == nil || index >= len(s.resyncProgress.UploadedChunkHashes) {
+		return false
+	}
+	return s.resyncProgress.UploadedChunkHashes[index] == hash
+}
+
+// recordUploadedChunk remembers the hash of an uploaded chunk of the current session
+func (s *activeConfigState) recordUploadedChunk(index int, hash string) {
+	s.resyncMu.Lock()
+	defer s.resyncMu.Unlock()
+	if s.resyncProgress == nil || index >= s.resyncProgress.TotalChunks {
+		return
+	}
+	if len(s.resyncProgress.UploadedChunkHashes) != s.resyncProgress.TotalChunks {
+		hashes := make([]string, s.resyncProgress.TotalChunks)
+		copy(hashes, s.resyncProgress.UploadedChunkHashes)
+		s.resyncProgress.UploadedChunkHashes = hashes
+	}
+	s.resyncProgress.UploadedChunkHashes[index] = hash
+}
+
+// recordResyncProgress updates the progress of the current resync session
+func (s *activeConfigState) recordResyncProgress(update func(progress *dotaiv1alpha1.ResyncProgress)) {
+	s.resyncMu.Lock()
+	defer s.resyncMu.Unlock()
+	if s.resyncProgress != nil {
+		update(s.resyncProgress)
+	}
+}
+
+// performChunkedResync uploads all resources in chunks within a resync session and commits it
+// Failed chunks leave the session incomplete; it is resumed after resyncRetryDelay
+// Returns ErrResyncSessionsUnsupported when MCP only supports single-request resyncs
+func (r *ResourceSyncReconciler) performChunkedResync(ctx context.Context, state *activeConfigState, allResources []*ResourceData) error {
+	logger := logf.FromContext(ctx).WithName("resourcesync")
+
+	session := state.nextResyncSession(len(allResources), state.config.GetResyncChunkSize(), time.Now())
+	received, err := state.mcpClient.BeginResync(ctx, session.SessionID, session.Attempt, session.TotalChunks, len(allResources))
+	if err != nil {
+		if errors.Is(err, ErrResyncSessionsUnsupported) {
+			state.resyncMu.Lock()
+			state.resyncProgress = nil
+			state.resyncMu.Unlock()
+			return err
+		}
+		r.failResync(state, -1, err)
+		return fmt.Errorf("failed to begin resync session: %w", err)
+	}
+
+	// Received chunks are skipped only when their resources did not change since they were uploaded;
+	// a resource created since then would otherwise be absent from the session and deleted on commit
+	chunks := resyncChunks(allResources, session.TotalChunks, state.config.GetResyncChunkSize())
+	hashes := make([]string, len(chunks))
+	for index, chunk := range chunks {
+		hashes[index] = resyncChunkHash(chunk)
+	}
+	completed := make(map[int]bool, len(received))
+	for _, index := range received {
+		if index >= 0 && index < len(chunks) && state.uploadedChunkUnchanged(index, hashes[index]) {
+			completed[index] = true
+		}
+	}
+	logger.Info("Resync session started",
+		"sessionID", session.SessionID,
+		"attempt", session.Attempt,
+		"totalChunks", session.TotalChunks,
+		"resumedChunks", len(completed),
+		"changedChunks", len(received)-len(completed),
+	)
+
+	var failed int
+	var lastErr error
+	for index, chunk := range chunks {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !completed[index] {
+			if err := state.mcpClient.UploadResyncChunk(ctx, session.SessionID, session.Attempt, index, chunk); err != nil {
+				logger.Error(err, "Failed to upload resync chunk", "sessionID", session.SessionID, "chunk", index)
+				r.failResync(state, index, err)
+				failed++
+				lastErr = err
+				continue
+			}
+			completed[index] = true
+			state.recordUploadedChunk(index, hashes[index])
+		}
+
+		state.recordResyncProgress(func(progress *dotaiv1alpha1.ResyncProgress) {
+			progress.CompletedChunks = len(completed)
+		})
+		if (index+1)%resyncProgressInterval == 0 {
+			r.updateResyncStatus(ctx, state)
+		}
+	}
+
+	if failed > 0 {
+		time.AfterFunc(resyncRetryDelay, state.requestResync)
+		return fmt.Errorf("resync incomplete: %d of %d chunks failed: %w", failed, session.TotalChunks, lastErr)
+	}
+
+	upserted, deleted, err := state.mcpClient.CommitResync(ctx, session.SessionID, session.Attempt, session.TotalChunks)
+	if err != nil {
+		r.failResync(state, -1, err)
+		time.AfterFunc(resyncRetryDelay, state.requestResync)
+		return fmt.Errorf("failed to commit resync session: %w", err)
+	}
+
+	now := metav1.NewTime(time.Now())
+	state.recordResyncProgress(func(progress *dotaiv1alpha1.ResyncProgress) {
+		progress.Phase = dotaiv1alpha1.ResyncPhaseCompleted
+		progress.CompletionTime = &now
+	})
+	logger.Info("Resync session committed",
+		"sessionID", session.SessionID,
+		"upserted", upserted,
+		"deleted", deleted,
+	)
+	return nil
+}
+
+// failResync marks the current resync session incomplete and records a chunk failure
+// An index of -1 records a failure to begin or commit the session without a chunk entry
+func (r *ResourceSyncReconciler) failResync(state *activeConfigState, index int, err error) {
+	state.recordResyncProgress(func(progress *dotaiv1alpha1.ResyncProgress) {
+		progress.Phase = dotaiv1alpha1.ResyncPhaseIncomplete
+		if index < 0 {
+			return
+		}
+		progress.FailedChunks = append(progress.FailedChunks, dotaiv1alpha1.ResyncChunkFailure{
+			Index: index,
+			Error: truncateErrorMessage(err.Error()),
+			Time:  metav1.NewTime(time.Now()),
+		})
+		if len(progress.FailedChunks) > maxResyncChunkFailures {
+			progress.FailedChunks = progress.FailedChunks[len(progress.FailedChunks)-maxResyncChunkFailures:]
+		}
+	})
+}
+
+// updateResyncStatus writes the resync progress to the ResourceSyncConfig status while chunks are uploaded
+// Conflicts with other status writers are retried; other failures are only logged, and the status
+// is written again when the resync finishes
+func (r *ResourceSyncReconciler) updateResyncStatus(ctx context.Context, state *activeConfigState) {
+	logger := logf.FromContext(ctx).WithName("resourcesync")
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var config dotaiv1alpha1.ResourceSyncConfig
+		if err := r.Get(ctx, client.ObjectKey{Namespace: state.config.Namespace, Name: state.config.Name}, &config); err != nil {
+			return err
+		}
+		config.Status.Resync = state.resyncProgressSnapshot()
+		return r.Status().Update(ctx, &config)
+	})
+	if err != nil {
+		logger.V(1).Info("Failed to update resync progress", "error", err.Error())
+	}
+}
@@ -0,0 +1,318 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+	"github.com/vfarcic/dot-ai-controller/internal/mcp"
+)
+
+// resyncTestServer is an MCP server that records resync session requests
+type resyncTestServer struct {
+	mu sync.Mutex
+	// unsupported makes the session endpoints return 404 like servers without sessions
+	unsupported bool
+	// receivedChunks is returned when a session begins
+	receivedChunks []int
+	// failChunk is the chunk index that fails to upload, -1 for none
+	failChunk int
+
+	sessions        []ResyncSessionRequest
+	idempotencyKeys []string
+	chunks          map[int]int
+	commits         int
+	resyncs         int
+	resources       int
+}
+
+func (s *resyncTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key := r.Header.Get(mcp.IdempotencyKeyHeader); key != "" {
+		s.idempotencyKeys = append(s.idempotencyKeys, key)
+	}
+	data := map[string]interface{}{}
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/sync"):
+		var req SyncRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		s.resyncs++
+		s.resources += len(req.Upserts)
+	case s.unsupported:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	case strings.HasSuffix(r.URL.Path, "/sessions"):
+		var req ResyncSessionRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		s.sessions = append(s.sessions, req)
+		data["receivedChunks"] = s.receivedChunks
+	case strings.HasSuffix(r.URL.Path, "/chunks"):
+		var req ResyncChunkRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Index == s.failChunk {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		s.chunks[req.Index] = len(req.Upserts)
+		s.resources += len(req.Upserts)
+	case strings.HasSuffix(r.URL.Path, "/commit"):
+		s.commits++
+		data["upserted"] = s.resources
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": data})
+}
+
+// newResyncTestState creates a config state syncing to the test server with the given chunk size
+func newResyncTestState(t *testing.T, server *resyncTestServer, chunkSize int) (*ResourceSyncReconciler, *activeConfigState) {
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	config := &dotaiv1alpha1.ResourceSyncConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "sync", Namespace: "dot-ai"},
+		Spec:       dotaiv1alpha1.ResourceSyncConfigSpec{ResyncChunkSize: chunkSize},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(newNotificationChannelTestScheme()).
+		WithObjects(config).
+		WithStatusSubresource(config).
+		Build()
+
+	maxRetries := 0
+	state := &activeConfigState{
+		config:         config,
+		resyncRequests: make(chan struct{}, 1),
+		mcpClient: NewMCPResourceSyncClient(MCPResourceSyncClientConfig{
+			Endpoint:   httpServer.URL + ResourceSyncPath,
+			HTTPClient: httpServer.Client(),
+			MaxRetries: &maxRetries,
+		}),
+	}
+	return &ResourceSyncReconciler{Client: fakeClient}, state
+}
+
+// newResyncTestResources creates count Deployments
+func newResyncTestResources(count int) []*ResourceData {
+	resources := make([]*ResourceData, count)
+	for i := range resources {
+		resources[i] = &ResourceData{Namespace: "default", Name: fmt.Sprintf("web-%d", i), Kind: "Deployment", APIVersion: "apps/v1"}
+	}
+	return resources
+}
+
+func TestResyncChunks(t *testing.T) {
+	resources := newResyncTestResources(95)
+	chunks := resyncChunks(resources, 11, 10)
+	require.Len(t, chunks, 11)
+
+	total := 0
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, len(chunk), 10, "chunks never exceed the chunk size")
+		total += len(chunk)
+	}
+	assert.Equal(t, 95, total)
+	assert.Len(t, chunks[9], 5)
+	assert.Empty(t, chunks[10], "chunks beyond the resources are empty")
+
+	reversed := make([]*ResourceData, len(resources))
+	for i, resource := range resources {
+		reversed[len(resources)-1-i] = resource
+	}
+	assert.Equal(t, chunks, resyncChunks(reversed, 11, 10), "chunk assignment does not depend on the listing order")
+}
+
+func TestResumableResync(t *testing.T) {
+	now := time.Now()
+	started := metav1.NewTime(now.Add(-time.Minute))
+	incomplete := &dotaiv1alpha1.ResyncProgress{
+		SessionID: "s1", Phase: dotaiv1alpha1.ResyncPhaseIncomplete, StartTime: &started, TotalChunks: 3,
+	}
+	assert.Equal(t, incomplete, resumableResync(incomplete, now))
+
+	completed := incomplete.DeepCopy()
+	completed.Phase = dotaiv1alpha1.ResyncPhaseCompleted
+	assert.Nil(t, resumableResync(completed, now))
+	assert.Nil(t, resumableResync(incomplete, now.Add(2*resyncSessionMaxAge)), "old sessions are not resumed")
+	assert.Nil(t, resumableResync(nil, now))
+}
+
+func TestResourceSyncReconciler_PerformChunkedResync(t *testing.T) {
+	server := &resyncTestServer{chunks: map[int]int{}, failChunk: -1}
+	reconciler, state := newResyncTestState(t, server, 10)
+
+	require.NoError(t, reconciler.performChunkedResync(t.Context(), state, newResyncTestResources(95)))
+
+	require.Len(t, server.sessions, 1)
+	assert.Equal(t, 10, server.sessions[0].TotalChunks)
+	assert.Equal(t, 95, server.sessions[0].TotalResources)
+	assert.Len(t, server.chunks, 10, "every chunk is uploaded, including empty ones")
+	assert.Equal(t, 95, server.resources)
+	assert.Equal(t, 1, server.commits)
+
+	progress := state.resyncProgressSnapshot()
+	assert.Equal(t, dotaiv1alpha1.ResyncPhaseCompleted, progress.Phase)
+	assert.Equal(t, 10, progress.CompletedChunks)
+	assert.NotNil(t, progress.CompletionTime)
+
+	var config dotaiv1alpha1.ResourceSyncConfig
+	require.NoError(t, reconciler.Get(t.Context(), client.ObjectKeyFromObject(state.config), &config))
+	require.NotNil(t, config.Status.Resync, "progress is written to status while chunks are uploaded")
+	assert.Equal(t, progress.SessionID, config.Status.Resync.SessionID)
+}
+
+func TestResourceSyncReconciler_PerformChunkedResync_FailedChunkResumes(t *testing.T) {
+	server := &resyncTestServer{chunks: map[int]int{}, failChunk: 2}
+	reconciler, state := newResyncTestState(t, server, 10)
+	resources := newResyncTestResources(40)
+
+	err := reconciler.performChunkedResync(t.Context(), state, resources)
+	require.ErrorContains(t, err, "1 of 4 chunks failed")
+	assert.Zero(t, server.commits, "an incomplete session is not committed")
+	assert.Len(t, server.chunks, 3, "the other chunks are uploaded")
+
+	progress := state.resyncProgressSnapshot()
+	assert.Equal(t, dotaiv1alpha1.ResyncPhaseIncomplete, progress.Phase)
+	require.Len(t, progress.FailedChunks, 1)
+	assert.Equal(t, 2, progress.FailedChunks[0].Index)
+
+	// The next attempt resumes the session and uploads only the chunks MCP did not receive
+	server.failChunk = -1
+	server.receivedChunks = []int{0, 1, 3}
+	server.chunks = map[int]int{}
+	require.NoError(t, reconciler.performChunkedResync(t.Context(), state, resources))
+
+	require.Len(t, server.sessions, 2)
+	assert.Equal(t, progress.SessionID, server.sessions[1].SessionID)
+	assert.Equal(t, map[int]int{2: len(resyncChunks(resources, 4, 10)[2])}, server.chunks)
+	assert.Equal(t, 1, server.commits)
+	assert.Equal(t, dotaiv1alpha1.ResyncPhaseCompleted, state.resyncProgressSnapshot().Phase)
+}
+
+func TestResourceSyncReconciler_PerformChunkedResync_ResumeUploadsChangedChunks(t *testing.T) {
+	server := &resyncTestServer{chunks: map[int]int{}, failChunk: 2}
+	reconciler, state := newResyncTestState(t, server, 10)
+	resources := newResyncTestResources(35)
+
+	require.Error(t, reconciler.performChunkedResync(t.Context(), state, resources))
+	sessionID := state.resyncProgressSnapshot().SessionID
+	assert.Contains(t, server.idempotencyKeys, sessionID+"-1-begin")
+	assert.Contains(t, server.idempotencyKeys, sessionID+"-1-chunk-0")
+
+	// A resource created before the session is resumed sorts into the last chunk, which MCP already received
+	resources = append(resources, &ResourceData{Namespace: "default", Name: "zz-new", Kind: "Deployment", APIVersion: "apps/v1"})
+
+	server.failChunk = -1
+	server.receivedChunks = []int{0, 1, 3}
+	server.chunks = map[int]int{}
+	server.idempotencyKeys = nil
+	require.NoError(t, reconciler.performChunkedResync(t.Context(), state, resources))
+
+	chunks := resyncChunks(resources, 4, 10)
+	assert.Equal(t, map[int]int{2: len(chunks[2]), 3: len(chunks[3])}, server.chunks,
+		"the received chunk whose resources changed is uploaded again")
+	assert.Equal(t, []string{sessionID + "-2-begin", sessionID + "-2-chunk-2", sessionID + "-2-chunk-3", sessionID + "-2-commit"},
+		server.idempotencyKeys, "resumed requests use the keys of the new attempt")
+	assert.Equal(t, 2, state.resyncProgressSnapshot().Attempt)
+}
+
+func TestResourceSyncReconciler_PerformChunkedResync_ResumeAfterRestart(t *testing.T) {
+	server := &resyncTestServer{chunks: map[int]int{}, failChunk: 2}
+	reconciler, state := newResyncTestState(t, server, 10)
+	resources := newResyncTestResources(40)
+	require.Error(t, reconciler.performChunkedResync(t.Context(), state, resources))
+
+	// After a restart, the progress is read from status, including the hashes of the uploaded chunks
+	reconciler.updateResyncStatus(t.Context(), state)
+	var config dotaiv1alpha1.ResourceSyncConfig
+	require.NoError(t, reconciler.Get(t.Context(), client.ObjectKeyFromObject(state.config), &config))
+	require.NotNil(t, config.Status.Resync)
+	assert.Len(t, config.Status.Resync.UploadedChunkHashes, 4)
+	assert.Empty(t, config.Status.Resync.UploadedChunkHashes[2], "the failed chunk has no hash")
+
+	restarted := &activeConfigState{
+		config:         state.config,
+		mcpClient:      state.mcpClient,
+		resyncRequests: make(chan struct{}, 1),
+		resyncProgress: config.Status.Resync.DeepCopy(),
+	}
+	server.failChunk = -1
+	server.receivedChunks = []int{0, 1, 3}
+	server.chunks = map[int]int{}
+	require.NoError(t, reconciler.performChunkedResync(t.Context(), restarted, resources))
+
+	assert.Equal(t, map[int]int{2: 10}, server.chunks, "only the chunk MCP did not receive is uploaded")
+	assert.Equal(t, state.resyncProgressSnapshot().SessionID, restarted.resyncProgressSnapshot().SessionID, "the session is resumed")
+}
+
+func TestResourceSyncReconciler_PerformResync_LegacyFallback(t *testing.T) {
+	server := &resyncTestServer{chunks: map[int]int{}, failChunk: -1, unsupported: true}
+	reconciler, state := newResyncTestState(t, server, 10)
+
+	items := make([]interface{}, 3)
+	for i := range items {
+		items[i] = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": fmt.Sprintf("web-%d", i), "namespace": "default"},
+		}}
+	}
+	state.activeInformers = map[schema.GroupVersionResource]cache.SharedIndexInformer{
+		{Group: "apps", Version: "v1", Resource: "deployments"}: &mockInformer{store: &mockStore{items: items}},
+	}
+
+	count, err := reconciler.performResync(t.Context(), state)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, 1, server.resyncs, "servers without sessions receive a single resync request")
+	assert.Equal(t, 3, server.resources)
+	assert.Nil(t, state.resyncProgressSnapshot())
+}
+
+func TestResourceSyncReconciler_UpdateResyncStatus_RetriesConflicts(t *testing.T) {
+	config := &dotaiv1alpha1.ResourceSyncConfig{ObjectMeta: metav1.ObjectMeta{Name: "sync", Namespace: "dot-ai"}}
+	conflicts := 0
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(newNotificationChannelTestScheme()).
+		WithObjects(config).
+		WithStatusSubresource(config).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				if conflicts == 0 {
+					conflicts++
+					return apierrors.NewConflict(dotaiv1alpha1.GroupVersion.WithResource("resourcesyncconfigs").GroupResource(), obj.GetName(), nil)
+				}
+				return c.SubResource(subResource).Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	reconciler := &ResourceSyncReconciler{Client: fakeClient}
+	state := &activeConfigState{config: config, resyncProgress: &dotaiv1alpha1.ResyncProgress{SessionID: "s1", TotalChunks: 2}}
+
+	reconciler.updateResyncStatus(t.Context(), state)
+
+	var updated dotaiv1alpha1.ResourceSyncConfig
+	require.NoError(t, fakeClient.Get(t.Context(), client.ObjectKeyFromObject(config), &updated))
+	require.NotNil(t, updated.Status.Resync, "the progress is written after a conflict")
+	assert.Equal(t, "s1", updated.Status.Resync.SessionID)
+	assert.Equal(t, 1, conflicts)
+}
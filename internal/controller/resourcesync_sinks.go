@@ -0,0 +1,463 @@
+// resourcesync_sinks.go implements the sinks that receive the debounced resource changes.
+// MCP is the default sink; Webhook, CloudEvents and NDJSON sinks deliver the same
+// de-duplicated change stream to other consumers. The debounce buffer keeps the failed
+// changes of each sink separately, so an unavailable sink neither blocks nor duplicates
+// deliveries to the others.
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+	"github.com/vfarcic/dot-ai-controller/internal/mcp"
+)
+
+const (
+	// mcpSinkName is the reserved name of the MCP endpoint in the sink status
+	mcpSinkName = "mcp"
+	// mcpSinkType is the type reported for the MCP endpoint
+	mcpSinkType = "MCP"
+
+	// cloudEventsSpecVersion is the CloudEvents specification version of sent events
+	cloudEventsSpecVersion = "1.0"
+	// cloudEventsBatchContentType is the content type of CloudEvents batches
+	cloudEventsBatchContentType = "application/cloudevents-batch+json"
+	// CloudEventTypeResourceUpserted is the type of events for added or updated resources
+	CloudEventTypeResourceUpserted = "live.devopstoolkit.dot-ai.resource.upserted"
+	// CloudEventTypeResourceDeleted is the type of events for deleted resources
+	CloudEventTypeResourceDeleted = "live.devopstoolkit.dot-ai.resource.deleted"
+
+	// sinkActionUpsert and sinkActionDelete are the actions of NDJSON records
+	sinkActionUpsert = "upsert"
+	sinkActionDelete = "delete"
+
+	// maxSinkPendingChanges bounds the failed changes kept per sink for the next flush
+	maxSinkPendingChanges = changeQueueBufferSize
+)
+
+// ResourceSyncSink delivers batches of de-duplicated resource changes to a consumer
+type ResourceSyncSink interface {
+	// Name identifies the sink in status, logs and metrics
+	Name() string
+	// Type is reported in the sink status
+	Type() string
+	// Send delivers a batch of changes
+	// An error keeps the changes of the batch for the next flush.
+	Send(ctx context.Context, upserts []*ResourceData, deletes []*ResourceIdentifier) (*SinkResult, error)
+}
+
+// SinkResult reports a batch delivered by a sink
+type SinkResult struct {
+	// Upserted and Deleted are the numbers of changes the sink accepted
+	Upserted int
+	Deleted  int
+	// Error reports a batch the sink received but partially rejected
+	// Rejected changes are not retried; resyncs and recoveries of dropped changes send the
+	// current version of upserted resources again, but rejected deletes are lost.
+	Error string
+}
+
+// SinkChangeBatch is the JSON body posted by Webhook sinks
+type SinkChangeBatch struct {
+	// Config is the ResourceSyncConfig as namespace/name
+	Config string `json:"config"`
+	// ClusterName is the name of the synced cluster, if set
+	ClusterName string `json:"clusterName,omitempty"`
+	// Time is when the batch was flushed
+	Time time.Time `json:"time"`
+	// Upserts are the added or updated resources
+	Upserts []*ResourceData `json:"upserts"`
+	// Deletes identify the deleted resources
+	Deletes []*ResourceIdentifier `json:"deletes"`
+}
+
+// SinkChangeRecord is a line written by NDJSON sinks
+type SinkChangeRecord struct {
+	// Time is when the change was flushed
+	Time time.Time `json:"time"`
+	// Config is the ResourceSyncConfig as namespace/name
+	Config string `json:"config"`
+	// Action is "upsert" or "delete"
+	Action string `json:"action"`
+	// Resource is set for upserts
+	Resource *ResourceData `json:"resource,omitempty"`
+	// Identifier is set for deletes
+	Identifier *ResourceIdentifier `json:"identifier,omitempty"`
+}
+
+// cloudEvent is a CloudEvent in the structured JSON format
+type cloudEvent struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Subject         string      `json:"subject"`
+	Time            time.Time   `json:"time"`
+	DataContentType string      `json:"datacontenttype"`
+	ClusterName     string      `json:"clustername,omitempty"`
+	Data            interface{} `json:"data"`
+}
+
+// mcpSink delivers changes to the MCP resource sync endpoint
+type mcpSink struct {
+	client *MCPResourceSyncClient
+}
+
+// Name implements ResourceSyncSink
+func (s *mcpSink) Name() string {
+	return mcpSinkName
+}
+
+// Type implements ResourceSyncSink
+func (s *mcpSink) Type() string {
+	return mcpSinkType
+}
+
+// Send implements ResourceSyncSink
+func (s *mcpSink) Send(ctx context.Context, upserts []*ResourceData, deletes []*ResourceIdentifier) (*SinkResult, error) {
+	resp, err := s.client.SyncResources(ctx, upserts, deletes)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SinkResult{}
+	if !resp.Success {
+		result.Error = resp.GetErrorMessage()
+		logf.FromContext(ctx).WithName("debounce-buffer").Error(nil, "MCP sync returned error",
+			"error", result.Error,
+			"failures", resp.GetFailures(),
+		)
+		return result, nil
+	}
+	result.Upserted, result.Deleted = resp.GetSuccessCounts()
+	return result, nil
+}
+
+// httpSink posts payloads to the URL of a Webhook or CloudEvents sink
+type httpSink struct {
+	spec       dotaiv1alpha1.ResourceSyncSink
+	reader     client.Reader
+	namespace  string
+	httpClient *http.Client
+	retry      WebhookRetryConfig
+}
+
+// newHTTPSink creates the transport shared by Webhook and CloudEvents sinks
+func newHTTPSink(spec dotaiv1alpha1.ResourceSyncSink, reader client.Reader, namespace string, httpClient *http.Client) httpSink {
+	if httpClient == nil {
+		httpClient = mcp.NewHTTPClient()
+	}
+	retry := defaultWebhookRetryConfig
+	retry.MaxAttempts = spec.GetMaxAttempts()
+	return httpSink{spec: spec, reader: reader, namespace: namespace, httpClient: httpClient, retry: retry}
+}
+
+// Name implements ResourceSyncSink
+func (s *httpSink) Name() string {
+	return s.spec.Name
+}
+
+// Type implements ResourceSyncSink
+func (s *httpSink) Type() string {
+	return string(s.spec.Type)
+}
+
+// post sends a payload with the bearer token and TLS settings of the sink
+// Secrets are read on every flush, so rotated tokens apply without restarting the watcher
+func (s *httpSink) post(ctx context.Context, payload []byte, contentType string) error {
+	httpClient, err := webhookHTTPClient(ctx, s.reader, s.namespace, s.spec.TLS, "", s.httpClient)
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	if s.spec.AuthSecretRef != nil && s.spec.AuthSecretRef.Name != "" {
+		token, err := mcp.ResolveToken(ctx, s.reader, s.namespace, *s.spec.AuthSecretRef)
+		if err != nil {
+			return err
+		}
+		header.Set("Authorization", "Bearer "+token)
+	}
+
+	return postWebhookWithHeaders(ctx, httpClient, s.spec.URL, fmt.Sprintf("sink '%s'", s.spec.Name), payload, header, s.retry)
+}
+
+// webhookSink posts each batch as a SinkChangeBatch
+type webhookSink struct {
+	httpSink
+	configName  string
+	clusterName string
+}
+
+// Send implements ResourceSyncSink
+func (s *webhookSink) Send(ctx context.Context, upserts []*ResourceData, deletes []*ResourceIdentifier) (*SinkResult, error) {
+	batch := SinkChangeBatch{
+		Config:      s.configName,
+		ClusterName: s.clusterName,
+		Time:        time.Now().UTC(),
+		Upserts:     upserts,
+		Deletes:     deletes,
+	}
+	// Consumers get empty arrays rather than null
+	if batch.Upserts == nil {
+		batch.Upserts = []*ResourceData{}
+	}
+	if batch.Deletes == nil {
+		batch.Deletes = []*ResourceIdentifier{}
+	}
+
+	payload, err := json.Marshal(batch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal change batch: %w", err)
+	}
+	if err := s.post(ctx, payload, "application/json"); err != nil {
+		return nil, err
+	}
+	return &SinkResult{Upserted: len(upserts), Deleted: len(deletes)}, nil
+}
+
+// cloudEventsSink posts each batch as a CloudEvents batch with one event per change
+type cloudEventsSink struct {
+	httpSink
+	source      string
+	clusterName string
+}
+
+// Send implements ResourceSyncSink
+func (s *cloudEventsSink) Send(ctx context.Context, upserts []*ResourceData, deletes []*ResourceIdentifier) (*SinkResult, error) {
+	now := time.Now().UTC()
+	events := make([]cloudEvent, 0, len(upserts)+len(deletes))
+	for _, data := range upserts {
+		events = append(events, s.event(CloudEventTypeResourceUpserted, resourceDataID(data), now, data))
+	}
+	for _, identifier := range deletes {
+		events = append(events, s.event(CloudEventTypeResourceDeleted, resourceIdentifierID(identifier), now, identifier))
+	}
+
+	payload, err := json.Marshal(events)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal CloudEvents: %w", err)
+	}
+	if err := s.post(ctx, payload, cloudEventsBatchContentType); err != nil {
+		return nil, err
+	}
+	return &SinkResult{Upserted: len(upserts), Deleted: len(deletes)}, nil
+}
+
+// event creates the CloudEvent of a change
+func (s *cloudEventsSink) event(eventType, subject string, now time.Time, data interface{}) cloudEvent {
+	return cloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		ID:              string(uuid.NewUUID()),
+		Source:          s.source,
+		Type:            eventType,
+		Subject:         subject,
+		Time:            now,
+		DataContentType: "application/json",
+		ClusterName:     s.clusterName,
+		Data:            data,
+	}
+}
+
+// ndjsonSink appends one SinkChangeRecord per change to a file
+type ndjsonSink struct {
+	name       string
+	path       string
+	maxSize    int64
+	configName string
+}
+
+var (
+	// ndjsonFileLocks serializes writes and rotations of each NDJSON file, so sinks of several
+	// ResourceSyncConfigs can share a file. Entries are bounded by the configured sink paths.
+	ndjsonFileLocks   = make(map[string]*sync.Mutex)
+	ndjsonFileLocksMu sync.Mutex
+)
+
+// ndjsonFileLock returns the lock shared by all sinks writing the file at path
+func ndjsonFileLock(path string) *sync.Mutex {
+	ndjsonFileLocksMu.Lock()
+	defer ndjsonFileLocksMu.Unlock()
+	lock, ok := ndjsonFileLocks[path]
+	if !ok {
+		lock = &sync.Mutex{}
+		ndjsonFileLocks[path] = lock
+	}
+	return lock
+}
+
+// Name implements ResourceSyncSink
+func (s *ndjsonSink) Name() string {
+	return s.name
+}
+
+// Type implements ResourceSyncSink
+func (s *ndjsonSink) Type() string {
+	return string(dotaiv1alpha1.ResourceSyncSinkTypeNDJSON)
+}
+
+// Send implements ResourceSyncSink
+func (s *ndjsonSink) Send(_ context.Context, upserts []*ResourceData, deletes []*ResourceIdentifier) (*SinkResult, error) {
+	now := time.Now().UTC()
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, data := range upserts {
+		if err := encoder.Encode(SinkChangeRecord{Time: now, Config: s.configName, Action: sinkActionUpsert, Resource: data}); err != nil {
+			return nil, fmt.Errorf("failed to encode change: %w", err)
+		}
+	}
+	for _, identifier := range deletes {
+		if err := encoder.Encode(SinkChangeRecord{Time: now, Config: s.configName, Action: sinkActionDelete, Identifier: identifier}); err != nil {
+			return nil, fmt.Errorf("failed to encode change: %w", err)
+		}
+	}
+
+	lock := ndjsonFileLock(s.path)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create NDJSON directory: %w", err)
+	}
+	if err := s.rotate(int64(buf.Len())); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open NDJSON file: %w", err)
+	}
+	_, err = file.Write(buf.Bytes())
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write NDJSON file: %w", err)
+	}
+	return &SinkResult{Upserted: len(upserts), Deleted: len(deletes)}, nil
+}
+
+// rotate renames the file to "<path>.1" when the next write would grow it beyond the maximum size
+// The previous rotated file is replaced, so at most twice the maximum size is kept on the volume.
+func (s *ndjsonSink) rotate(size int64) error {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to stat NDJSON file: %w", err)
+	}
+	if info.Size() == 0 || info.Size()+size <= s.maxSize {
+		return nil
+	}
+	if err := os.Rename(s.path, s.path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate NDJSON file: %w", err)
+	}
+	return nil
+}
+
+// resourceIdentifierID returns the ID of a deleted resource, in the format of buildResourceID
+func resourceIdentifierID(identifier *ResourceIdentifier) string {
+	return fmt.Sprintf("%s:%s:%s:%s", identifier.Namespace, identifier.APIVersion, identifier.Kind, identifier.Name)
+}
+
+// ndjsonSinkPath returns the file of an NDJSON sink: its path below the directory of the
+// ResourceSyncConfig's namespace in the sink directory, so namespaces cannot write each other's files
+func ndjsonSinkPath(sinkDir, namespace, path string) (string, error) {
+	if sinkDir == "" {
+		return "", errors.New("NDJSON sinks are disabled; start the controller with --resourcesync-sink-dir")
+	}
+	if !filepath.IsLocal(path) {
+		return "", errors.New("the path of NDJSON sinks must be relative and must not leave the sink directory")
+	}
+	base := filepath.Join(sinkDir, namespace)
+	resolved := filepath.Join(base, filepath.Clean(path))
+	if !strings.HasPrefix(resolved, base+string(filepath.Separator)) {
+		return "", errors.New("the path of NDJSON sinks must not leave the sink directory")
+	}
+	return resolved, nil
+}
+
+// validateResourceSyncSinks checks that each sink has the settings of its type
+// NDJSON sinks are only allowed when the controller has a sink directory
+func validateResourceSyncSinks(config *dotaiv1alpha1.ResourceSyncConfig, sinkDir string) error {
+	names := make(map[string]bool)
+	for _, sink := range config.Spec.Sinks {
+		switch {
+		case sink.Name == "":
+			return errors.New("sink name cannot be empty")
+		case sink.Name == mcpSinkName:
+			return fmt.Errorf("sink name '%s' is reserved for the MCP endpoint", mcpSinkName)
+		case names[sink.Name]:
+			return fmt.Errorf("sink '%s' is configured more than once", sink.Name)
+		}
+		names[sink.Name] = true
+
+		switch sink.Type {
+		case dotaiv1alpha1.ResourceSyncSinkTypeWebhook, dotaiv1alpha1.ResourceSyncSinkTypeCloudEvents:
+			if sink.URL == "" {
+				return fmt.Errorf("sink '%s': url is required for %s sinks", sink.Name, sink.Type)
+			}
+		case dotaiv1alpha1.ResourceSyncSinkTypeNDJSON:
+			if _, err := ndjsonSinkPath(sinkDir, config.Namespace, sink.Path); err != nil {
+				return fmt.Errorf("sink '%s': %w", sink.Name, err)
+			}
+		default:
+			return fmt.Errorf("sink '%s': unsupported type '%s'", sink.Name, sink.Type)
+		}
+	}
+	return nil
+}
+
+// newResourceSyncSinks creates the sinks configured in a ResourceSyncConfig
+// The MCP endpoint is not included; the debounce buffer adds it as the default sink.
+func (r *ResourceSyncReconciler) newResourceSyncSinks(config *dotaiv1alpha1.ResourceSyncConfig) ([]ResourceSyncSink, error) {
+	if err := validateResourceSyncSinks(config, r.SinkDir); err != nil {
+		return nil, err
+	}
+
+	sinks := make([]ResourceSyncSink, 0, len(config.Spec.Sinks))
+	for _, spec := range config.Spec.Sinks {
+		switch spec.Type {
+		case dotaiv1alpha1.ResourceSyncSinkTypeWebhook:
+			sinks = append(sinks, &webhookSink{
+				httpSink:    newHTTPSink(spec, r.Client, config.Namespace, r.HttpClient),
+				configName:  configKey(config),
+				clusterName: config.GetClusterName(),
+			})
+		case dotaiv1alpha1.ResourceSyncSinkTypeCloudEvents:
+			sinks = append(sinks, &cloudEventsSink{
+				httpSink: newHTTPSink(spec, r.Client, config.Namespace, r.HttpClient),
+				source: fmt.Sprintf("/apis/%s/namespaces/%s/resourcesyncconfigs/%s",
+					dotaiv1alpha1.GroupVersion.String(), config.Namespace, config.Name),
+				clusterName: config.GetClusterName(),
+			})
+		case dotaiv1alpha1.ResourceSyncSinkTypeNDJSON:
+			path, _ := ndjsonSinkPath(r.SinkDir, config.Namespace, spec.Path)
+			sinks = append(sinks, &ndjsonSink{
+				name:       spec.Name,
+				path:       path,
+				maxSize:    int64(spec.GetMaxFileSizeMB()) * 1024 * 1024,
+				configName: configKey(config),
+			})
+		}
+	}
+	return sinks, nil
+}
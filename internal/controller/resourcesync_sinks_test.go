@@ -0,0 +1,429 @@
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// fakeSink records the batches it receives and fails while err is set
+type fakeSink struct {
+	name    string
+	mu      sync.Mutex
+	err     error
+	batches [][]string
+}
+
+func (s *fakeSink) Name() string { return s.name }
+func (s *fakeSink) Type() string { return "Fake" }
+
+func (s *fakeSink) Send(_ context.Context, upserts []*ResourceData, deletes []*ResourceIdentifier) (*SinkResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []string
+	for _, data := range upserts {
+		ids = append(ids, resourceDataID(data))
+	}
+	for _, identifier := range deletes {
+		ids = append(ids, resourceIdentifierID(identifier))
+	}
+	s.batches = append(s.batches, ids)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &SinkResult{Upserted: len(upserts), Deleted: len(deletes)}, nil
+}
+
+func (s *fakeSink) setError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// newSinkTestConfig returns a ResourceSyncConfig with the given sinks
+func newSinkTestConfig(sinks ...dotaiv1alpha1.ResourceSyncSink) *dotaiv1alpha1.ResourceSyncConfig {
+	return &dotaiv1alpha1.ResourceSyncConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "dot-ai"},
+		Spec:       dotaiv1alpha1.ResourceSyncConfigSpec{ClusterName: "prod", Sinks: sinks},
+	}
+}
+
+func TestDebounceBuffer_SinksRetryIndependently(t *testing.T) {
+	healthy := &fakeSink{name: "archive"}
+	failing := &fakeSink{name: "webhook", err: errors.New("connection refused")}
+	buffer := NewDebounceBuffer(DebounceBufferConfig{
+		Window: time.Hour,
+		Sinks:  []ResourceSyncSink{healthy, failing},
+	})
+
+	buffer.record(&ResourceChange{
+		Action: ActionUpsert,
+		ID:     "test:v1:Pod:foo",
+		Data:   &ResourceData{Name: "foo", Kind: "Pod", APIVersion: "v1", Namespace: "test"},
+	})
+	buffer.flush(context.Background())
+
+	assert.Equal(t, 1, buffer.PendingCount(), "the failed sink keeps its change")
+	assert.Equal(t, "sink 'webhook': connection refused", buffer.GetMetrics().LastError)
+	statuses := buffer.SinkStatuses()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, int64(1), statuses[0].TotalDelivered)
+	assert.Empty(t, statuses[0].LastError)
+	assert.Equal(t, 1, statuses[1].PendingChanges)
+	assert.Equal(t, "connection refused", statuses[1].LastError)
+	assert.NotNil(t, statuses[1].LastErrorTime)
+
+	// The next flush retries the failed change only for the failed sink, with the new change
+	failing.setError(nil)
+	buffer.record(&ResourceChange{
+		Action:           ActionDelete,
+		ID:               "test:v1:Pod:bar",
+		DeleteIdentifier: &ResourceIdentifier{Name: "bar", Kind: "Pod", APIVersion: "v1", Namespace: "test"},
+	})
+	buffer.flush(context.Background())
+
+	assert.Equal(t, [][]string{{"test:v1:Pod:foo"}, {"test:v1:Pod:bar"}}, healthy.batches)
+	require.Len(t, failing.batches, 2)
+	assert.ElementsMatch(t, []string{"test:v1:Pod:foo", "test:v1:Pod:bar"}, failing.batches[1])
+	assert.Equal(t, 0, buffer.PendingCount())
+	assert.Empty(t, buffer.GetMetrics().LastError)
+	assert.Equal(t, int64(2), buffer.SinkStatuses()[1].TotalDelivered)
+}
+
+func TestDebounceBuffer_NewerChangeReplacesFailedChange(t *testing.T) {
+	sink := &fakeSink{name: "webhook", err: errors.New("unavailable")}
+	buffer := NewDebounceBuffer(DebounceBufferConfig{Window: time.Hour, Sinks: []ResourceSyncSink{sink}})
+
+	buffer.record(&ResourceChange{
+		Action: ActionUpsert,
+		ID:     "test:v1:Pod:foo",
+		Data:   &ResourceData{Name: "foo", Kind: "Pod", APIVersion: "v1", Namespace: "test"},
+	})
+	buffer.flush(context.Background())
+	assert.Equal(t, "unavailable", buffer.GetMetrics().LastError, "a single sink reports its error unprefixed")
+
+	buffer.record(&ResourceChange{
+		Action:           ActionDelete,
+		ID:               "test:v1:Pod:foo",
+		DeleteIdentifier: &ResourceIdentifier{Name: "foo", Kind: "Pod", APIVersion: "v1", Namespace: "test"},
+	})
+	assert.Equal(t, 1, buffer.PendingCount())
+
+	sink.setError(nil)
+	buffer.flush(context.Background())
+	require.Len(t, sink.batches, 2)
+	assert.Equal(t, []string{"test:v1:Pod:foo"}, sink.batches[1])
+	assert.Equal(t, int64(1), buffer.SinkStatuses()[0].TotalDelivered, "only the delete is delivered")
+}
+
+func TestDebounceBuffer_MCPSinkComesFirst(t *testing.T) {
+	buffer := NewDebounceBuffer(DebounceBufferConfig{
+		Window:    time.Hour,
+		MCPClient: NewMCPResourceSyncClient(MCPResourceSyncClientConfig{Endpoint: "http://mcp"}),
+		Sinks:     []ResourceSyncSink{&fakeSink{name: "archive"}},
+	})
+
+	statuses := buffer.SinkStatuses()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, mcpSinkName, statuses[0].Name)
+	assert.Equal(t, mcpSinkType, statuses[0].Type)
+	assert.Equal(t, "archive", statuses[1].Name)
+
+	buffer.SetMCPClient(nil)
+	statuses = buffer.SinkStatuses()
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "archive", statuses[0].Name)
+}
+
+func TestDebounceBuffer_ResyncSinks(t *testing.T) {
+	archive := &fakeSink{name: "archive"}
+	failing := &fakeSink{name: "webhook", err: errors.New("connection refused")}
+	buffer := NewDebounceBuffer(DebounceBufferConfig{
+		Window:    time.Hour,
+		MCPClient: NewMCPResourceSyncClient(MCPResourceSyncClientConfig{Endpoint: "http://mcp.invalid"}),
+		Sinks:     []ResourceSyncSink{archive, failing},
+	})
+	assert.True(t, buffer.hasSinks())
+
+	resources := make([]*ResourceData, maxSinkPendingChanges+1)
+	for i := range resources {
+		resources[i] = &ResourceData{Name: fmt.Sprintf("pod-%d", i), Kind: "Pod", APIVersion: "v1", Namespace: "test"}
+	}
+	buffer.ResyncSinks(context.Background(), resources)
+
+	// MCP reconciles with its own resync, the other sinks receive all resources in bounded batches
+	require.Len(t, archive.batches, 2)
+	assert.Len(t, archive.batches[0], maxSinkPendingChanges)
+	assert.Len(t, archive.batches[1], 1)
+	assert.Equal(t, int64(maxSinkPendingChanges+1), buffer.SinkStatuses()[1].TotalDelivered)
+
+	// A failing sink keeps its first batch for the next flush and skips the rest until the next resync
+	require.Len(t, failing.batches, 1)
+	assert.Equal(t, maxSinkPendingChanges, buffer.SinkStatuses()[2].PendingChanges)
+	assert.Equal(t, "connection refused", buffer.SinkStatuses()[2].LastError)
+
+	assert.False(t, NewDebounceBuffer(DebounceBufferConfig{
+		MCPClient: NewMCPResourceSyncClient(MCPResourceSyncClientConfig{Endpoint: "http://mcp.invalid"}),
+	}).hasSinks(), "MCP is not resynced as a sink")
+}
+
+func TestValidateResourceSyncSinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		sinkDir string
+		sinks   []dotaiv1alpha1.ResourceSyncSink
+		wantErr string
+	}{
+		{name: "no sinks"},
+		{
+			name: "valid sinks",
+			sinks: []dotaiv1alpha1.ResourceSyncSink{
+				{Name: "hook", Type: dotaiv1alpha1.ResourceSyncSinkTypeWebhook, URL: "https://example.com/changes"},
+				{Name: "broker", Type: dotaiv1alpha1.ResourceSyncSinkTypeCloudEvents, URL: "http://broker"},
+				{Name: "archive", Type: dotaiv1alpha1.ResourceSyncSinkTypeNDJSON, Path: "audit/changes.ndjson"},
+			},
+		},
+		{
+			name:    "reserved name",
+			sinks:   []dotaiv1alpha1.ResourceSyncSink{{Name: "mcp", Type: dotaiv1alpha1.ResourceSyncSinkTypeWebhook, URL: "http://mcp"}},
+			wantErr: "reserved",
+		},
+		{
+			name: "duplicate name",
+			sinks: []dotaiv1alpha1.ResourceSyncSink{
+				{Name: "hook", Type: dotaiv1alpha1.ResourceSyncSinkTypeWebhook, URL: "http://a"},
+				{Name: "hook", Type: dotaiv1alpha1.ResourceSyncSinkTypeWebhook, URL: "http://b"},
+			},
+			wantErr: "more than once",
+		},
+		{
+			name:    "webhook without URL",
+			sinks:   []dotaiv1alpha1.ResourceSyncSink{{Name: "hook", Type: dotaiv1alpha1.ResourceSyncSinkTypeWebhook}},
+			wantErr: "url is required",
+		},
+		{
+			name:    "NDJSON with absolute path",
+			sinks:   []dotaiv1alpha1.ResourceSyncSink{{Name: "archive", Type: dotaiv1alpha1.ResourceSyncSinkTypeNDJSON, Path: "/etc/passwd"}},
+			wantErr: "must be relative",
+		},
+		{
+			name:    "NDJSON path leaving the namespace directory",
+			sinks:   []dotaiv1alpha1.ResourceSyncSink{{Name: "archive", Type: dotaiv1alpha1.ResourceSyncSinkTypeNDJSON, Path: "audit/../../other/changes.ndjson"}},
+			wantErr: "must not leave the sink directory",
+		},
+		{
+			name:    "NDJSON without sink directory",
+			sinkDir: "none",
+			sinks:   []dotaiv1alpha1.ResourceSyncSink{{Name: "archive", Type: dotaiv1alpha1.ResourceSyncSinkTypeNDJSON, Path: "changes.ndjson"}},
+			wantErr: "NDJSON sinks are disabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sinkDir := "/data"
+			if tt.sinkDir == "none" {
+				sinkDir = ""
+			}
+			err := validateResourceSyncSinks(newSinkTestConfig(tt.sinks...), sinkDir)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookSink_Send(t *testing.T) {
+	var received SinkChangeBatch
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	config := newSinkTestConfig(dotaiv1alpha1.ResourceSyncSink{
+		Name:          "hook",
+		Type:          dotaiv1alpha1.ResourceSyncSinkTypeWebhook,
+		URL:           server.URL,
+		AuthSecretRef: &dotaiv1alpha1.SecretReference{Name: "hook-token", Key: "token"},
+	})
+	reconciler := &ResourceSyncReconciler{
+		Client: fake.NewClientBuilder().WithScheme(newNotificationChannelTestScheme()).WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "hook-token", Namespace: "dot-ai"},
+			Data:       map[string][]byte{"token": []byte("secret-token")},
+		}).Build(),
+		HttpClient: server.Client(),
+	}
+	sinks, err := reconciler.newResourceSyncSinks(config)
+	require.NoError(t, err)
+	require.Len(t, sinks, 1)
+
+	result, err := sinks[0].Send(t.Context(), []*ResourceData{{Name: "foo", Kind: "Pod", APIVersion: "v1", Namespace: "test"}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Upserted)
+	assert.Equal(t, "Bearer secret-token", authorization)
+	assert.Equal(t, "dot-ai/cluster", received.Config)
+	assert.Equal(t, "prod", received.ClusterName)
+	assert.Len(t, received.Upserts, 1)
+	assert.NotNil(t, received.Deletes, "empty deletes are sent as an array")
+}
+
+func TestWebhookSink_SendError(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sink := &webhookSink{httpSink: newHTTPSink(dotaiv1alpha1.ResourceSyncSink{
+		Name: "hook", Type: dotaiv1alpha1.ResourceSyncSinkTypeWebhook, URL: server.URL, MaxAttempts: 2,
+	}, nil, "dot-ai", server.Client())}
+	sink.retry.BaseBackoff = time.Millisecond
+
+	_, err := sink.Send(t.Context(), nil, []*ResourceIdentifier{{Name: "foo", Kind: "Pod", APIVersion: "v1", Namespace: "test"}})
+	assert.ErrorContains(t, err, "sink 'hook' webhook returned status 503")
+	assert.Equal(t, 2, attempts)
+}
+
+func TestCloudEventsSink_Send(t *testing.T) {
+	var events []cloudEvent
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		_ = json.NewDecoder(r.Body).Decode(&events)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	reconciler := &ResourceSyncReconciler{HttpClient: server.Client()}
+	sinks, err := reconciler.newResourceSyncSinks(newSinkTestConfig(dotaiv1alpha1.ResourceSyncSink{
+		Name: "broker", Type: dotaiv1alpha1.ResourceSyncSinkTypeCloudEvents, URL: server.URL,
+	}))
+	require.NoError(t, err)
+
+	result, err := sinks[0].Send(t.Context(),
+		[]*ResourceData{{Name: "foo", Kind: "Deployment", APIVersion: "apps/v1", Namespace: "test"}},
+		[]*ResourceIdentifier{{Name: "bar", Kind: "Pod", APIVersion: "v1", Namespace: "test"}},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, &SinkResult{Upserted: 1, Deleted: 1}, result)
+	assert.Equal(t, cloudEventsBatchContentType, contentType)
+	require.Len(t, events, 2)
+	assert.Equal(t, "1.0", events[0].SpecVersion)
+	assert.Equal(t, CloudEventTypeResourceUpserted, events[0].Type)
+	assert.Equal(t, "test:apps/v1:Deployment:foo", events[0].Subject)
+	assert.Equal(t, "/apis/dot-ai.devopstoolkit.live/v1alpha1/namespaces/dot-ai/resourcesyncconfigs/cluster", events[0].Source)
+	assert.Equal(t, "prod", events[0].ClusterName)
+	assert.NotEmpty(t, events[0].ID)
+	assert.Equal(t, CloudEventTypeResourceDeleted, events[1].Type)
+	assert.Equal(t, "test:v1:Pod:bar", events[1].Subject)
+}
+
+func TestNDJSONSink_Send(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changes.ndjson")
+	sink := &ndjsonSink{name: "archive", path: path, maxSize: 1024 * 1024, configName: "dot-ai/cluster"}
+
+	_, err := sink.Send(t.Context(),
+		[]*ResourceData{{Name: "foo", Kind: "Pod", APIVersion: "v1", Namespace: "test"}},
+		[]*ResourceIdentifier{{Name: "bar", Kind: "Pod", APIVersion: "v1", Namespace: "test"}},
+	)
+	require.NoError(t, err)
+	_, err = sink.Send(t.Context(), []*ResourceData{{Name: "baz", Kind: "Pod", APIVersion: "v1", Namespace: "test"}}, nil)
+	require.NoError(t, err)
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	var records []SinkChangeRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record SinkChangeRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.Len(t, records, 3, "each change is appended as one line")
+	assert.Equal(t, "upsert", records[0].Action)
+	assert.Equal(t, "foo", records[0].Resource.Name)
+	assert.Equal(t, "delete", records[1].Action)
+	assert.Equal(t, "bar", records[1].Identifier.Name)
+	assert.Equal(t, "dot-ai/cluster", records[2].Config)
+}
+
+func TestNDJSONSink_Rotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changes.ndjson")
+	sink := &ndjsonSink{name: "archive", path: path, maxSize: 200}
+	change := []*ResourceData{{Name: "foo", Kind: "Pod", APIVersion: "v1", Namespace: "test"}}
+
+	_, err := sink.Send(t.Context(), change, nil)
+	require.NoError(t, err)
+	_, err = sink.Send(t.Context(), change, nil)
+	require.NoError(t, err)
+
+	rotated, err := os.ReadFile(path + ".1")
+	require.NoError(t, err, "the full file is rotated")
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, bytes.Count(rotated, []byte("\n")))
+	assert.Equal(t, 1, bytes.Count(current, []byte("\n")), "the new file starts with the next batch")
+}
+
+func TestNDJSONSink_SharedFile(t *testing.T) {
+	sinkDir := t.TempDir()
+	reconciler := &ResourceSyncReconciler{SinkDir: sinkDir}
+	config := newSinkTestConfig(dotaiv1alpha1.ResourceSyncSink{
+		Name: "archive", Type: dotaiv1alpha1.ResourceSyncSinkTypeNDJSON, Path: "audit/changes.ndjson", MaxFileSizeMB: 1,
+	})
+	first, err := reconciler.newResourceSyncSinks(config)
+	require.NoError(t, err)
+	second, err := reconciler.newResourceSyncSinks(config)
+	require.NoError(t, err)
+
+	path := filepath.Join(sinkDir, "dot-ai", "audit", "changes.ndjson")
+	assert.Equal(t, path, first[0].(*ndjsonSink).path, "files are kept in the directory of the namespace")
+
+	// Sinks of several configs writing the same file do not interleave their writes
+	var wg sync.WaitGroup
+	for _, sink := range []ResourceSyncSink{first[0], second[0]} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				_, err := sink.Send(t.Context(), []*ResourceData{{Name: fmt.Sprintf("pod-%d", i), Kind: "Pod", APIVersion: "v1", Namespace: "test"}}, nil)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := bytes.Split(bytes.TrimSpace(content), []byte("\n"))
+	assert.Len(t, lines, 100)
+	for _, line := range lines {
+		var record SinkChangeRecord
+		assert.NoError(t, json.Unmarshal(line, &record))
+	}
+}
@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+)
+
+// preferredResourcesDiscovery returns fixed preferred resources, which the fake discovery client does not support
+type preferredResourcesDiscovery struct {
+	*fakediscovery.FakeDiscovery
+	preferred []*metav1.APIResourceList
+}
+
+func (d *preferredResourcesDiscovery) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	return d.preferred, nil
+}
+
+// newVersionsTestCRD creates a Database CRD serving v1beta1 and v1 with the given storage version
+func newVersionsTestCRD(storageVersion string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "databases.example.com"},
+		"spec": map[string]interface{}{
+			"group": "example.com",
+			"names": map[string]interface{}{"kind": "Database", "plural": "databases"},
+			"versions": []interface{}{
+				map[string]interface{}{"name": "v1beta1", "served": true, "storage": storageVersion == "v1beta1"},
+				map[string]interface{}{"name": "v1", "served": true, "storage": storageVersion == "v1"},
+			},
+		},
+	}}
+}
+
+// newVersionsTestDynamicClient creates a fake dynamic client that lists CRDs and both Database versions
+func newVersionsTestDynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			crdGVR: "CustomResourceDefinitionList",
+			{Group: "example.com", Version: "v1beta1", Resource: "databases"}: "DatabaseList",
+			{Group: "example.com", Version: "v1", Resource: "databases"}:      "DatabaseList",
+		}, objects...)
+}
+
+func TestResourceSyncReconciler_DiscoverResources_PreferredVersions(t *testing.T) {
+	listWatch := []string{"get", "list", "watch"}
+	clients := &clusterClients{
+		dynamic: newVersionsTestDynamicClient(newVersionsTestCRD("v1beta1")),
+		discovery: &preferredResourcesDiscovery{
+			FakeDiscovery: &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}},
+			preferred: []*metav1.APIResourceList{
+				{GroupVersion: "autoscaling/v2", APIResources: []metav1.APIResource{
+					{Name: "horizontalpodautoscalers", Kind: "HorizontalPodAutoscaler", Verbs: listWatch},
+				}},
+				{GroupVersion: "autoscaling/v1", APIResources: []metav1.APIResource{
+					{Name: "horizontalpodautoscalers", Kind: "HorizontalPodAutoscaler", Verbs: listWatch},
+				}},
+				{GroupVersion: "example.com/v1", APIResources: []metav1.APIResource{
+					{Name: "databases", Kind: "Database", Verbs: listWatch},
+				}},
+			},
+		},
+	}
+
+	gvrs, err := (&ResourceSyncReconciler{}).discoverResources(t.Context(), clients, nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []schema.GroupVersionResource{
+		{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"},
+		{Group: "example.com", Version: "v1beta1", Resource: "databases"},
+	}, gvrs, "each resource type is watched once, CRDs under their storage version")
+}
+
+func TestResourceSyncReconciler_OnCRDAdd_VersionChange(t *testing.T) {
+	dynamicClient := newVersionsTestDynamicClient()
+	reconciler := &ResourceSyncReconciler{dynamicClient: dynamicClient}
+	state := &activeConfigState{
+		clients: &clusterClients{
+			dynamic:  dynamicClient,
+			metadata: metadatafake.NewSimpleMetadataClient(metadatafake.NewTestScheme()),
+		},
+		activeInformers: make(map[schema.GroupVersionResource]cache.SharedIndexInformer),
+		stopCh:          make(chan struct{}),
+		resyncRequests:  make(chan struct{}, 1),
+	}
+	defer close(state.stopCh)
+
+	reconciler.onCRDAdd(state, newVersionsTestCRD("v1beta1"))
+	assert.Equal(t, []string{"example.com/v1beta1/databases"}, state.watchedResources())
+	assert.Empty(t, state.resyncRequests, "a new CRD does not need a resync")
+
+	// Unchanged versions keep the informer
+	v1beta1 := schema.GroupVersionResource{Group: "example.com", Version: "v1beta1", Resource: "databases"}
+	oldStop := state.informerStops[v1beta1]
+	require.NotNil(t, oldStop)
+	reconciler.onCRDAdd(state, newVersionsTestCRD("v1beta1"))
+	assert.Len(t, state.handlerRegistrations, 1)
+	assert.Equal(t, oldStop, state.informerStops[v1beta1])
+
+	reconciler.onCRDAdd(state, newVersionsTestCRD("v1"))
+	assert.Equal(t, []string{"example.com/v1/databases"}, state.watchedResources(), "the informer follows the new version")
+	assert.Len(t, state.handlerRegistrations, 1, "the old version's handlers are removed")
+	assert.Len(t, state.resyncRequests, 1, "a resync replaces resources synced under the old version")
+	assert.NotContains(t, state.informerStops, v1beta1)
+	select {
+	case <-oldStop:
+	default:
+		t.Error("the old version's informer is stopped")
+	}
+
+	reconciler.onCRDDelete(state, newVersionsTestCRD("v1"))
+	assert.Empty(t, state.watchedResources())
+	assert.Empty(t, state.handlerRegistrations)
+	assert.Empty(t, state.informerStops)
+}
+
+func TestResourceSyncReconciler_CRDVersionChanged(t *testing.T) {
+	reconciler := &ResourceSyncReconciler{}
+
+	statusUpdate := newVersionsTestCRD("v1beta1")
+	require.NoError(t, unstructured.SetNestedField(statusUpdate.Object, "True", "status", "conditions"))
+	assert.False(t, reconciler.crdVersionChanged(newVersionsTestCRD("v1beta1"), statusUpdate),
+		"status writes keep the version")
+	assert.True(t, reconciler.crdVersionChanged(newVersionsTestCRD("v1beta1"), newVersionsTestCRD("v1")))
+	assert.False(t, reconciler.crdVersionChanged(newVersionsTestCRD("v1beta1"), "not a CRD"))
+}
@@ -0,0 +1,318 @@
+// secret_references.go validates the Secrets referenced by dot-ai resources and maps Secret
+// events to the resources that reference them. Secrets are still read when they are used;
+// validating them on every reconciliation surfaces a deleted Secret or a mistyped key in the
+// CredentialsResolved condition before an incident needs it, and re-triggers dependent syncs
+// when a Secret is rotated.
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+const (
+	// ConditionTypeCredentialsResolved reports whether every referenced Secret and key exists
+	ConditionTypeCredentialsResolved = "CredentialsResolved"
+)
+
+// secretKeyReference is a key of a Secret referenced by a resource
+type secretKeyReference struct {
+	// Field is the spec field that references the Secret, used in condition messages
+	Field string
+	Name  string
+	Key   string
+}
+
+// appendSecretRef appends a Secret reference when it is set
+func appendSecretRef(refs []secretKeyReference, field string, ref *dotaiv1alpha1.SecretReference) []secretKeyReference {
+	if ref == nil || ref.Name == "" {
+		return refs
+	}
+	return append(refs, secretKeyReference{Field: field, Name: ref.Name, Key: ref.Key})
+}
+
+// mcpFieldNames are the spec paths of the auth and TLS fields of an MCP endpoint
+type mcpFieldNames struct {
+	authSecretRef string
+	auth          string
+	tls           string
+}
+
+// mcpSpecFields are the MCP endpoint fields of RemediationPolicy and ResourceSyncConfig
+var mcpSpecFields = mcpFieldNames{authSecretRef: "mcpAuthSecretRef", auth: "mcpAuth", tls: "mcpTLS"}
+
+// nestedMCPFields returns the field names of an MCP endpoint nested under prefix, e.g. "mcp."
+func nestedMCPFields(prefix string) mcpFieldNames {
+	return mcpFieldNames{authSecretRef: prefix + "authSecretRef", auth: prefix + "auth", tls: prefix + "tls"}
+}
+
+// mcpSecretReferences returns the Secrets used by the auth mode and TLS settings of an MCP endpoint
+func mcpSecretReferences(fields mcpFieldNames, authSecretRef dotaiv1alpha1.SecretReference, auth *dotaiv1alpha1.McpAuthConfig, tls *dotaiv1alpha1.TLSConfig) []secretKeyReference {
+	var refs []secretKeyReference
+	switch auth.GetMode() {
+	case dotaiv1alpha1.McpAuthModeBearer:
+		refs = appendSecretRef(refs, fields.authSecretRef, &authSecretRef)
+	case dotaiv1alpha1.McpAuthModeMTLS:
+		if auth.MTLS != nil && auth.MTLS.SecretName != "" {
+			field := fields.auth + ".mtls.secretName"
+			refs = append(refs,
+				secretKeyReference{Field: field, Name: auth.MTLS.SecretName, Key: corev1.TLSCertKey},
+				secretKeyReference{Field: field, Name: auth.MTLS.SecretName, Key: corev1.TLSPrivateKeyKey},
+			)
+		}
+	case dotaiv1alpha1.McpAuthModeOAuth2:
+		if auth.OAuth2 != nil {
+			refs = appendSecretRef(refs, fields.auth+".oauth2.clientSecretRef", &auth.OAuth2.ClientSecretRef)
+		}
+	}
+	if tls != nil {
+		refs = appendSecretRef(refs, fields.tls+".caSecretRef", tls.CASecretRef)
+	}
+	return refs
+}
+
+// statusNotificationSecretReferences returns the webhook and CA Secrets of status notifications
+func statusNotificationSecretReferences(cfg *dotaiv1alpha1.StatusNotificationConfig) []secretKeyReference {
+	if cfg == nil {
+		return nil
+	}
+	var refs []secretKeyReference
+	if cfg.Slack != nil {
+		refs = appendSecretRef(refs, "notifications.slack.webhookUrlSecretRef", &cfg.Slack.WebhookUrlSecretRef)
+		if cfg.Slack.TLS != nil {
+			refs = appendSecretRef(refs, "notifications.slack.tls.caSecretRef", cfg.Slack.TLS.CASecretRef)
+		}
+	}
+	if cfg.GoogleChat != nil {
+		refs = appendSecretRef(refs, "notifications.googleChat.webhookUrlSecretRef", &cfg.GoogleChat.WebhookUrlSecretRef)
+		if cfg.GoogleChat.TLS != nil {
+			refs = appendSecretRef(refs, "notifications.googleChat.tls.caSecretRef", cfg.GoogleChat.TLS.CASecretRef)
+		}
+	}
+	return refs
+}
+
+// remediationPolicySecretReferences returns the Secrets referenced by a RemediationPolicy
+func remediationPolicySecretReferences(policy *dotaiv1alpha1.RemediationPolicy) []secretKeyReference {
+	var refs []secretKeyReference
+	if policy.Spec.McpServerRef == "" {
+		refs = append(refs, mcpSecretReferences(mcpSpecFields, policy.Spec.McpAuthSecretRef, policy.Spec.McpAuth, policy.Spec.McpTLS)...)
+	}
+
+	notifications := policy.Spec.Notifications
+	if notifications.Slack.Enabled {
+		refs = appendSecretRef(refs, "notifications.slack.webhookUrlSecretRef", notifications.Slack.WebhookUrlSecretRef)
+		if notifications.Slack.TLS != nil {
+			refs = appendSecretRef(refs, "notifications.slack.tls.caSecretRef", notifications.Slack.TLS.CASecretRef)
+		}
+	}
+	if notifications.GoogleChat.Enabled {
+		refs = appendSecretRef(refs, "notifications.googleChat.webhookUrlSecretRef", notifications.GoogleChat.WebhookUrlSecretRef)
+		if notifications.GoogleChat.TLS != nil {
+			refs = appendSecretRef(refs, "notifications.googleChat.tls.caSecretRef", notifications.GoogleChat.TLS.CASecretRef)
+		}
+	}
+	for _, route := range notifications.Routes {
+		if route.Slack != nil {
+			refs = appendSecretRef(refs, fmt.Sprintf("notifications.routes[%s].slack.webhookUrlSecretRef", route.Name), &route.Slack.WebhookUrlSecretRef)
+			if route.Slack.TLS != nil {
+				refs = appendSecretRef(refs, fmt.Sprintf("notifications.routes[%s].slack.tls.caSecretRef", route.Name), route.Slack.TLS.CASecretRef)
+			}
+		}
+		if route.GoogleChat != nil {
+			refs = appendSecretRef(refs, fmt.Sprintf("notifications.routes[%s].googleChat.webhookUrlSecretRef", route.Name), &route.GoogleChat.WebhookUrlSecretRef)
+			if route.GoogleChat.TLS != nil {
+				refs = appendSecretRef(refs, fmt.Sprintf("notifications.routes[%s].googleChat.tls.caSecretRef", route.Name), route.GoogleChat.TLS.CASecretRef)
+			}
+		}
+	}
+	return refs
+}
+
+// resourceSyncSecretReferences returns the Secrets referenced by a ResourceSyncConfig
+func resourceSyncSecretReferences(config *dotaiv1alpha1.ResourceSyncConfig) []secretKeyReference {
+	var refs []secretKeyReference
+	if config.Spec.McpServerRef == "" {
+		refs = append(refs, mcpSecretReferences(mcpSpecFields, config.Spec.McpAuthSecretRef, config.Spec.McpAuth, config.Spec.McpTLS)...)
+	}
+	refs = append(refs, clusterSecretReferences(config)...)
+	refs = append(refs, redactionKeySecretReferences(config)...)
+	for _, sink := range config.Spec.Sinks {
+		refs = appendSecretRef(refs, fmt.Sprintf("sinks[%s].authSecretRef", sink.Name), sink.AuthSecretRef)
+		if sink.TLS != nil {
+			refs = appendSecretRef(refs, fmt.Sprintf("sinks[%s].tls.caSecretRef", sink.Name), sink.TLS.CASecretRef)
+		}
+	}
+	return append(refs, statusNotificationSecretReferences(config.Spec.Notifications)...)
+}
+
+// capabilityScanSecretReferences returns the Secrets referenced by a CapabilityScanConfig
+func capabilityScanSecretReferences(config *dotaiv1alpha1.CapabilityScanConfig) []secretKeyReference {
+	var refs []secretKeyReference
+	if config.Spec.MCP.McpServerRef == "" {
+		refs = append(refs, mcpSecretReferences(nestedMCPFields("mcp."), config.Spec.MCP.AuthSecretRef, config.Spec.MCP.Auth, config.Spec.MCP.TLS)...)
+	}
+	return append(refs, statusNotificationSecretReferences(config.Spec.Notifications)...)
+}
+
+// gitKnowledgeSourceSecretReferences returns the Secrets referenced by a GitKnowledgeSource
+func gitKnowledgeSourceSecretReferences(gks *dotaiv1alpha1.GitKnowledgeSource) []secretKeyReference {
+	refs := appendSecretRef(nil, "repository.secretRef", gks.Spec.Repository.SecretRef)
+	if gks.Spec.McpServer.McpServerRef == "" {
+		refs = append(refs, mcpSecretReferences(nestedMCPFields("mcpServer."), gks.Spec.McpServer.AuthSecretRef, gks.Spec.McpServer.Auth, gks.Spec.McpServer.TLS)...)
+	}
+	return append(refs, statusNotificationSecretReferences(gks.Spec.Notifications)...)
+}
+
+// notificationChannelSecretReferences returns the Secrets referenced by a NotificationChannel
+func notificationChannelSecretReferences(channel *dotaiv1alpha1.NotificationChannel) []secretKeyReference {
+	refs := appendSecretRef(nil, "webhookUrlSecretRef", &channel.Spec.WebhookUrlSecretRef)
+	if channel.Spec.TLS != nil {
+		refs = appendSecretRef(refs, "tls.caSecretRef", channel.Spec.TLS.CASecretRef)
+	}
+	return refs
+}
+
+// mcpServerSecretReferences returns the Secrets referenced by an MCPServer.
+// They are resolved in the credentials namespace of the server.
+func mcpServerSecretReferences(server *dotaiv1alpha1.MCPServer) []secretKeyReference {
+	return mcpSecretReferences(nestedMCPFields(""), server.Spec.AuthSecretRef, server.Spec.Auth, server.Spec.TLS)
+}
+
+// referencesSecret returns whether refs include the Secret with the given name
+func referencesSecret(refs []secretKeyReference, name string) bool {
+	for _, ref := range refs {
+		if ref.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validateSecretReferences checks that every referenced Secret exists in namespace and contains
+// a non-empty value for its key. It returns the reason and error of the first failed reference.
+func validateSecretReferences(ctx context.Context, c client.Reader, namespace string, refs []secretKeyReference) (string, error) {
+	for _, ref := range refs {
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				return "SecretNotFound", fmt.Errorf("Secret '%s' referenced by %s not found in namespace '%s'", ref.Name, ref.Field, namespace)
+			}
+			return "SecretError", fmt.Errorf("failed to fetch Secret '%s' referenced by %s: %w", ref.Name, ref.Field, err)
+		}
+		if len(secret.Data[ref.Key]) == 0 {
+			return "SecretKeyMissing", fmt.Errorf("Secret '%s' referenced by %s does not contain a value for key '%s'", ref.Name, ref.Field, ref.Key)
+		}
+	}
+	return "", nil
+}
+
+// secretReferencesVersion returns a fingerprint of the values of the referenced Secret keys.
+// It changes when a referenced key is created, rotated or deleted, but not on metadata-only
+// updates such as new labels. Values are hashed with the UID of their Secret as salt and only a
+// truncated hash of all of them is returned, since the fingerprint is reported in status.
+func secretReferencesVersion(ctx context.Context, c client.Reader, namespace string, refs []secretKeyReference) string {
+	if len(refs) == 0 {
+		return ""
+	}
+
+	secrets := make(map[string]*corev1.Secret, len(refs))
+	versions := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		secret, seen := secrets[ref.Name]
+		if !seen {
+			secret = &corev1.Secret{}
+			if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+				secret = nil
+			}
+			secrets[ref.Name] = secret
+		}
+
+		version := ""
+		if secret == nil {
+			version = "-"
+		} else if value, ok := secret.Data[ref.Key]; ok {
+			valueHash := sha256.New()
+			_, _ = fmt.Fprintf(valueHash, "%s\n", secret.UID)
+			valueHash.Write(value)
+			version = hex.EncodeToString(valueHash.Sum(nil))
+		}
+		versions[ref.Name+"/"+ref.Key] = version
+	}
+
+	keys := make([]string, 0, len(versions))
+	for key := range versions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(hash, "%s=%s\n", key, versions[key])
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// setCredentialsResolvedCondition validates the referenced Secrets and sets the CredentialsResolved
+// condition. The condition is removed when no Secret is referenced. It returns whether conditions changed.
+func setCredentialsResolvedCondition(ctx context.Context, c client.Reader, conditions *[]metav1.Condition, namespace string, refs []secretKeyReference, generation int64) bool {
+	if len(refs) == 0 {
+		return meta.RemoveStatusCondition(conditions, ConditionTypeCredentialsResolved)
+	}
+
+	condition := metav1.Condition{
+		Type:               ConditionTypeCredentialsResolved,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             "Resolved",
+		Message:            fmt.Sprintf("%d referenced Secret keys resolved", len(refs)),
+	}
+	if reason, err := validateSecretReferences(ctx, c, namespace, refs); err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reason
+		condition.Message = err.Error()
+	}
+
+	existing := meta.FindStatusCondition(*conditions, ConditionTypeCredentialsResolved)
+	if existing != nil && existing.Status == condition.Status && existing.Reason == condition.Reason &&
+		existing.Message == condition.Message && existing.ObservedGeneration == condition.ObservedGeneration {
+		return false
+	}
+	meta.SetStatusCondition(conditions, condition)
+	return true
+}
+
+// secretDataChangedPredicate passes Secret creation and deletion, and updates that change
+// the Secret data. Metadata-only updates, e.g. of annotations, do not trigger reconciliation.
+func secretDataChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldSecret, ok := e.ObjectOld.(*corev1.Secret)
+			if !ok {
+				return false
+			}
+			newSecret, ok := e.ObjectNew.(*corev1.Secret)
+			if !ok {
+				return false
+			}
+			return !reflect.DeepEqual(oldSecret.Data, newSecret.Data) || !reflect.DeepEqual(oldSecret.StringData, newSecret.StringData)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
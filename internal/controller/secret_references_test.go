@@ -0,0 +1,251 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+func TestSecretReferences(t *testing.T) {
+	t.Run("remediation policy", func(t *testing.T) {
+		policy := &dotaiv1alpha1.RemediationPolicy{
+			Spec: dotaiv1alpha1.RemediationPolicySpec{
+				McpAuthSecretRef: dotaiv1alpha1.SecretReference{Name: "mcp-auth", Key: "token"},
+				Notifications: dotaiv1alpha1.NotificationConfig{
+					Slack: dotaiv1alpha1.SlackConfig{
+						Enabled:             true,
+						WebhookUrlSecretRef: &dotaiv1alpha1.SecretReference{Name: "slack", Key: "url"},
+					},
+					GoogleChat: dotaiv1alpha1.GoogleChatConfig{
+						WebhookUrlSecretRef: &dotaiv1alpha1.SecretReference{Name: "disabled", Key: "url"},
+					},
+					Routes: []dotaiv1alpha1.NotificationRoute{{
+						Name: "critical",
+						Slack: &dotaiv1alpha1.SlackRouteTarget{
+							WebhookUrlSecretRef: dotaiv1alpha1.SecretReference{Name: "oncall", Key: "url"},
+							TLS: &dotaiv1alpha1.TLSConfig{
+								CASecretRef: &dotaiv1alpha1.SecretReference{Name: "oncall-ca", Key: "ca.crt"},
+							},
+						},
+					}},
+				},
+			},
+		}
+
+		refs := remediationPolicySecretReferences(policy)
+		assert.Equal(t, []secretKeyReference{
+			{Field: "mcpAuthSecretRef", Name: "mcp-auth", Key: "token"},
+			{Field: "notifications.slack.webhookUrlSecretRef", Name: "slack", Key: "url"},
+			{Field: "notifications.routes[critical].slack.webhookUrlSecretRef", Name: "oncall", Key: "url"},
+			{Field: "notifications.routes[critical].slack.tls.caSecretRef", Name: "oncall-ca", Key: "ca.crt"},
+		}, refs)
+		assert.False(t, referencesSecret(refs, "disabled"), "disabled notifications are not validated")
+
+		// The MCPServer provides the credentials when it is referenced
+		policy.Spec.McpServerRef = "dot-ai"
+		assert.False(t, referencesSecret(remediationPolicySecretReferences(policy), "mcp-auth"))
+	})
+
+	t.Run("mTLS and CA bundle", func(t *testing.T) {
+		config := &dotaiv1alpha1.CapabilityScanConfig{
+			Spec: dotaiv1alpha1.CapabilityScanConfigSpec{
+				MCP: dotaiv1alpha1.MCPCapabilityConfig{
+					AuthSecretRef: dotaiv1alpha1.SecretReference{Name: "unused", Key: "token"},
+					Auth: &dotaiv1alpha1.McpAuthConfig{
+						Mode: dotaiv1alpha1.McpAuthModeMTLS,
+						MTLS: &dotaiv1alpha1.McpMTLSConfig{SecretName: "client-cert"},
+					},
+					TLS: &dotaiv1alpha1.TLSConfig{
+						CASecretRef: &dotaiv1alpha1.SecretReference{Name: "internal-ca", Key: "ca.crt"},
+					},
+				},
+			},
+		}
+
+		assert.Equal(t, []secretKeyReference{
+			{Field: "mcp.auth.mtls.secretName", Name: "client-cert", Key: "tls.crt"},
+			{Field: "mcp.auth.mtls.secretName", Name: "client-cert", Key: "tls.key"},
+			{Field: "mcp.tls.caSecretRef", Name: "internal-ca", Key: "ca.crt"},
+		}, capabilityScanSecretReferences(config))
+	})
+}
+
+func TestSetCredentialsResolvedCondition(t *testing.T) {
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(newNotificationChannelTestScheme()).
+		WithRuntimeObjects(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "mcp-auth", Namespace: "default"},
+				Data:       map[string][]byte{"token": []byte("secret-token"), "empty": {}},
+			},
+		).
+		Build()
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		refs   []secretKeyReference
+		status metav1.ConditionStatus
+		reason string
+	}{
+		{"resolved", []secretKeyReference{{Field: "mcpAuthSecretRef", Name: "mcp-auth", Key: "token"}}, metav1.ConditionTrue, "Resolved"},
+		{"missing Secret", []secretKeyReference{{Field: "mcpAuthSecretRef", Name: "missing", Key: "token"}}, metav1.ConditionFalse, "SecretNotFound"},
+		{"missing key", []secretKeyReference{{Field: "mcpAuthSecretRef", Name: "mcp-auth", Key: "tokn"}}, metav1.ConditionFalse, "SecretKeyMissing"},
+		{"empty key", []secretKeyReference{{Field: "mcpAuthSecretRef", Name: "mcp-auth", Key: "empty"}}, metav1.ConditionFalse, "SecretKeyMissing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conditions []metav1.Condition
+			assert.True(t, setCredentialsResolvedCondition(ctx, fakeClient, &conditions, "default", tt.refs, 1))
+			condition := meta.FindStatusCondition(conditions, ConditionTypeCredentialsResolved)
+			require.NotNil(t, condition)
+			assert.Equal(t, tt.status, condition.Status)
+			assert.Equal(t, tt.reason, condition.Reason)
+			assert.False(t, setCredentialsResolvedCondition(ctx, fakeClient, &conditions, "default", tt.refs, 1),
+				"unchanged credentials need no update")
+		})
+	}
+
+	conditions := []metav1.Condition{{Type: ConditionTypeCredentialsResolved, Status: metav1.ConditionTrue, Reason: "Resolved"}}
+	assert.True(t, setCredentialsResolvedCondition(ctx, fakeClient, &conditions, "default", nil, 1))
+	assert.Empty(t, conditions, "the condition is removed when no Secret is referenced")
+}
+
+func TestSecretReferencesVersion(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "mcp-auth", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("old-token")},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(newNotificationChannelTestScheme()).WithRuntimeObjects(secret).Build()
+	ctx := context.Background()
+	refs := []secretKeyReference{{Name: "mcp-auth", Key: "token"}, {Name: "mcp-auth", Key: "other"}}
+
+	assert.Empty(t, secretReferencesVersion(ctx, fakeClient, "default", nil))
+
+	version := secretReferencesVersion(ctx, fakeClient, "default", refs)
+	assert.NotEmpty(t, version)
+	assert.Equal(t, version, secretReferencesVersion(ctx, fakeClient, "default", refs))
+
+	relabeled := &corev1.Secret{}
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(secret), relabeled))
+	relabeled.Labels = map[string]string{"team": "platform"}
+	relabeled.Data["unreferenced"] = []byte("value")
+	require.NoError(t, fakeClient.Update(ctx, relabeled))
+	assert.Equal(t, version, secretReferencesVersion(ctx, fakeClient, "default", refs),
+		"metadata updates and unreferenced keys do not change the version")
+
+	rotated := &corev1.Secret{}
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(secret), rotated))
+	rotated.Data["token"] = []byte("new-token")
+	require.NoError(t, fakeClient.Update(ctx, rotated))
+	rotatedVersion := secretReferencesVersion(ctx, fakeClient, "default", refs)
+	assert.NotEqual(t, version, rotatedVersion, "a rotated Secret changes the version")
+
+	require.NoError(t, fakeClient.Delete(ctx, rotated))
+	assert.NotEqual(t, rotatedVersion, secretReferencesVersion(ctx, fakeClient, "default", refs), "a deleted Secret changes the version")
+}
+
+func TestSecretDataChangedPredicate(t *testing.T) {
+	predicate := secretDataChangedPredicate()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhooks", Namespace: "default"},
+		Data:       map[string][]byte{"url": []byte("https://hooks.slack.com/old")},
+	}
+
+	annotated := secret.DeepCopy()
+	annotated.Annotations = map[string]string{"reloader": "true"}
+	assert.False(t, predicate.Update(event.UpdateEvent{ObjectOld: secret, ObjectNew: annotated}), "metadata changes are ignored")
+
+	rotated := secret.DeepCopy()
+	rotated.Data["url"] = []byte("https://hooks.slack.com/new")
+	assert.True(t, predicate.Update(event.UpdateEvent{ObjectOld: secret, ObjectNew: rotated}))
+
+	assert.True(t, predicate.Create(event.CreateEvent{Object: secret}))
+	assert.True(t, predicate.Delete(event.DeleteEvent{Object: secret}))
+}
+
+func TestNotificationChannelReconciler_SecretRotation(t *testing.T) {
+	var checks atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checks.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	scheme := newNotificationChannelTestScheme()
+	channel := newTestNotificationChannel(dotaiv1alpha1.NotificationChannelTypeSlack)
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(channel, newWebhookSecret(server.URL)).
+		WithStatusSubresource(channel).
+		Build()
+	r := &NotificationChannelReconciler{
+		Client:     fakeClient,
+		Scheme:     scheme,
+		Recorder:   record.NewFakeRecorder(10),
+		HttpClient: http.DefaultClient,
+	}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(channel)}
+
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), checks.Load())
+
+	updated := &dotaiv1alpha1.NotificationChannel{}
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, updated))
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeCredentialsResolved))
+
+	// The health check is not due yet
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), checks.Load())
+
+	// A rotated Secret is mapped to the channel and checked right away
+	secret := &corev1.Secret{}
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "webhooks"}, secret))
+	secret.Data["url"] = []byte(server.URL + "/rotated")
+	require.NoError(t, fakeClient.Update(ctx, secret))
+	assert.Equal(t, []ctrl.Request{req}, r.mapSecretToChannels(ctx, secret))
+	assert.Empty(t, r.mapSecretToChannels(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}}))
+
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), checks.Load())
+
+	// A deleted Secret is reported in the CredentialsResolved condition
+	require.NoError(t, fakeClient.Delete(ctx, secret))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, updated))
+	condition := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeCredentialsResolved)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "SecretNotFound", condition.Reason)
+}
+
+func TestActiveConfigState_RequestResync(t *testing.T) {
+	state := &activeConfigState{resyncRequests: make(chan struct{}, 1)}
+
+	state.requestResync()
+	state.requestResync() // Coalesced with the pending request, does not block
+	assert.Len(t, state.resyncRequests, 1)
+
+	// States without a resync channel ignore requests
+	(&activeConfigState{}).requestResync()
+}
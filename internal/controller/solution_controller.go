@@ -19,6 +19,7 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+	"github.com/vfarcic/dot-ai-controller/internal/tracing"
 )
 
 // SolutionReconciler reconciles a Solution object
@@ -26,6 +27,7 @@ type SolutionReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+	Notifier *StatusNotifier
 }
 
 // +kubebuilder:rbac:groups=dot-ai.devopstoolkit.live,resources=solutions,verbs=get;list;watch;create;update;patch;delete
@@ -40,7 +42,10 @@ type SolutionReconciler struct {
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
-func (r *SolutionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *SolutionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	ctx, span := tracing.StartReconcile(ctx, "Solution", req.NamespacedName)
+	defer func() { tracing.End(span, err) }()
+
 	logger := logf.FromContext(ctx).WithValues("solution", req.NamespacedName)
 
 	// Fetch the Solution CR
@@ -49,6 +54,7 @@ func (r *SolutionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		if apierrors.IsNotFound(err) {
 			// Solution was deleted - nothing to do
 			logger.V(1).Info("Solution not found - likely deleted")
+			forgetSolutionState(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "failed to get Solution")
@@ -135,6 +141,8 @@ func (r *SolutionReconciler) initializeSolutionStatus(ctx context.Context, solut
 		return fmt.Errorf("failed to initialize status: %w", err)
 	}
 
+	recordSolutionState(fresh)
+
 	logger.Info("✅ Solution status initialized",
 		"state", fresh.Status.State,
 		"totalResources", fresh.Status.Resources.Total,
@@ -277,6 +285,13 @@ func (r *SolutionReconciler) updateSolutionStatus(ctx context.Context, solution
 			fresh.Status.Conditions = append(fresh.Status.Conditions, readyCondition)
 		}
 
+		// Record health transitions for notifications (pending solutions are not evaluated)
+		var transition *StatusTransition
+		if fresh.Status.State != "pending" {
+			transition = r.Notifier.Prepare(fresh.Spec.Notifications, &fresh.Status.Notifications,
+				fresh.Status.State == "deployed", readyCondition.Reason, readyCondition.Message)
+		}
+
 		// Update status subresource with retry logic
 		if err := r.Status().Update(ctx, fresh); err != nil {
 			if apierrors.IsConflict(err) {
@@ -292,6 +307,8 @@ func (r *SolutionReconciler) updateSolutionStatus(ctx context.Context, solution
 		}
 
 		// Success!
+		recordSolutionState(fresh)
+		r.Notifier.Send(ctx, fresh, "Solution", fresh.Spec.Notifications, transition)
 		return nil
 	}
 
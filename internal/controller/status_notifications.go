@@ -0,0 +1,292 @@
+// status_notifications.go contains the shared health-transition notifier used by the
+// GitKnowledgeSource, ResourceSyncConfig, CapabilityScanConfig and Solution controllers.
+// Controllers record the health state in status before updating it and send the
+// notification only after the status update succeeds, so each transition is
+// notified exactly once even across reconciliations and controller restarts.
+package controller
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+const (
+	// statusNotificationStateHealthy is recorded when a resource is healthy
+	statusNotificationStateHealthy = "healthy"
+	// statusNotificationStateUnhealthy is recorded when a resource is unhealthy
+	statusNotificationStateUnhealthy = "unhealthy"
+
+	// maxStatusNotificationMessageLength limits the message length in notifications
+	maxStatusNotificationMessageLength = 500
+)
+
+// StatusTransition describes a health transition that should be notified
+type StatusTransition struct {
+	// Healthy is true when the resource recovered, false when it became unhealthy
+	Healthy bool
+	// Reason is a short machine-readable reason for the transition
+	Reason string
+	// Message is a human-readable description of the current state
+	Message string
+}
+
+// +kubebuilder:rbac:groups=dot-ai.devopstoolkit.live,resources=notificationchannels,verbs=get;list;watch
+// +kubebuilder:rbac:groups=dot-ai.devopstoolkit.live,resources=notificationchannels/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// StatusNotifier sends notifications when resources transition between healthy and unhealthy
+type StatusNotifier struct {
+	client.Client
+	HttpClient *http.Client
+	Recorder   record.EventRecorder
+}
+
+// Prepare records the current health state in the resource's notification state and
+// returns the transition to notify about, or nil when nothing should be sent.
+// Callers persist the state with their status update and call Send only after it succeeds.
+func (n *StatusNotifier) Prepare(config *dotaiv1alpha1.StatusNotificationConfig, state *dotaiv1alpha1.StatusNotificationState, healthy bool, reason, message string) *StatusTransition {
+	if n == nil || config == nil {
+		return nil
+	}
+
+	newState := statusNotificationStateUnhealthy
+	if healthy {
+		newState = statusNotificationStateHealthy
+	}
+	if state.State == newState {
+		// Already notified about this state
+		return nil
+	}
+
+	previous := state.State
+	now := metav1.NewTime(time.Now())
+	state.State = newState
+	state.Reason = reason
+	state.LastTransitionTime = &now
+
+	// A resource that is healthy when first observed has not recovered from anything
+	if healthy && (previous == "" || !config.IsNotifyOnRecoveryEnabled()) {
+		return nil
+	}
+
+	return &StatusTransition{
+		Healthy: healthy,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// Send delivers a transition notification to all configured targets.
+// Notifications are best-effort: errors are logged and recorded as Warning events.
+func (n *StatusNotifier) Send(ctx context.Context, obj client.Object, kind string, config *dotaiv1alpha1.StatusNotificationConfig, transition *StatusTransition) {
+	if n == nil || config == nil || transition == nil {
+		return
+	}
+	logger := logf.FromContext(ctx)
+
+	var errs []error
+	if config.Slack != nil {
+		webhookUrl, err := resolveWebhookSecret(ctx, n.Client, obj.GetNamespace(), config.Slack.WebhookUrlSecretRef)
+		var httpClient *http.Client
+		if err == nil {
+			httpClient, err = webhookHTTPClient(ctx, n.Client, obj.GetNamespace(), config.Slack.TLS, config.Slack.ProxyURL, n.HttpClient)
+		}
+		if err == nil {
+			message := createStatusSlackMessage(obj, kind, transition)
+			message.Channel = config.Slack.Channel
+			err = postSlackWebhook(ctx, httpClient, webhookUrl, message)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("slack: %w", err))
+		}
+	}
+	if config.GoogleChat != nil {
+		webhookUrl, err := resolveWebhookSecret(ctx, n.Client, obj.GetNamespace(), config.GoogleChat.WebhookUrlSecretRef)
+		var httpClient *http.Client
+		if err == nil {
+			httpClient, err = webhookHTTPClient(ctx, n.Client, obj.GetNamespace(), config.GoogleChat.TLS, config.GoogleChat.ProxyURL, n.HttpClient)
+		}
+		if err == nil {
+			err = postGoogleChatWebhook(ctx, httpClient, webhookUrl, createStatusGoogleChatMessage(obj, kind, transition))
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("google chat: %w", err))
+		}
+	}
+	for _, channelName := range config.Channels {
+		err := sendToNotificationChannel(ctx, n.Client, n.HttpClient, obj.GetNamespace(), channelName,
+			func() SlackMessage { return createStatusSlackMessage(obj, kind, transition) },
+			func() GoogleChatMessage { return createStatusGoogleChatMessage(obj, kind, transition) },
+		)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, err := range errs {
+		logger.Error(err, "failed to send status notification", "kind", kind, "name", obj.GetName())
+		if n.Recorder != nil {
+			n.Recorder.Eventf(obj, corev1.EventTypeWarning, "NotificationFailed",
+				"Failed to send status notification: %v", err)
+		}
+	}
+	if len(errs) == 0 {
+		logger.Info("📣 Status notification sent",
+			"kind", kind,
+			"name", obj.GetName(),
+			"healthy", transition.Healthy,
+			"reason", transition.Reason)
+	}
+}
+
+// statusNotificationTitle returns the notification title for a transition
+func statusNotificationTitle(kind string, transition *StatusTransition) string {
+	if transition.Healthy {
+		return fmt.Sprintf("✅ %s Recovered", kind)
+	}
+	return fmt.Sprintf("🚨 %s Unhealthy", kind)
+}
+
+// truncateStatusNotificationMessage limits the message length in notifications
+func truncateStatusNotificationMessage(message string) string {
+	if len(message) > maxStatusNotificationMessageLength {
+		return message[:maxStatusNotificationMessageLength-3] + "..."
+	}
+	return message
+}
+
+// createStatusSlackMessage creates a Slack message for a health transition using Block Kit
+func createStatusSlackMessage(obj client.Object, kind string, transition *StatusTransition) SlackMessage {
+	color := "#e01e5a" // Red vertical bar
+	if transition.Healthy {
+		color = "#2eb67d" // Green vertical bar
+	}
+
+	blocks := []SlackBlock{
+		{
+			Type: "header",
+			Text: &SlackBlockText{
+				Type: "plain_text",
+				Text: statusNotificationTitle(kind, transition),
+			},
+		},
+		{
+			Type: "section",
+			Fields: []SlackBlockText{
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Resource:*\n`%s/%s`", kind, obj.GetName())},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Namespace:*\n`%s`", obj.GetNamespace())},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Reason:*\n%s", transition.Reason)},
+			},
+		},
+	}
+	if transition.Message != "" {
+		blocks = append(blocks, SlackBlock{
+			Type: "section",
+			Text: &SlackBlockText{
+				Type: "mrkdwn",
+				Text: fmt.Sprintf("*Details:*\n%s", truncateStatusNotificationMessage(transition.Message)),
+			},
+		})
+	}
+	blocks = append(blocks,
+		SlackBlock{
+			Type: "divider",
+		},
+		SlackBlock{
+			Type: "context",
+			Elements: []SlackBlockElement{
+				{
+					Type: "mrkdwn",
+					Text: "dot-ai Kubernetes Event Controller",
+				},
+			},
+		},
+	)
+
+	return SlackMessage{
+		Username:  "dot-ai-controller",
+		IconEmoji: ":robot_face:",
+		Attachments: []SlackAttachment{
+			{
+				Color:  color,
+				Blocks: blocks,
+			},
+		},
+	}
+}
+
+// createStatusGoogleChatMessage creates a Google Chat message for a health transition using Card v2 API
+func createStatusGoogleChatMessage(obj client.Object, kind string, transition *StatusTransition) GoogleChatMessage {
+	widgets := []GoogleChatWidget{
+		{
+			DecoratedText: &GoogleChatDecoratedText{
+				TopLabel: "Resource",
+				Text:     html.EscapeString(fmt.Sprintf("%s/%s", kind, obj.GetName())),
+				Icon:     &GoogleChatIcon{KnownIcon: "DESCRIPTION"},
+			},
+		},
+		{
+			DecoratedText: &GoogleChatDecoratedText{
+				TopLabel: "Namespace",
+				Text:     html.EscapeString(obj.GetNamespace()),
+				Icon:     &GoogleChatIcon{KnownIcon: "BOOKMARK"},
+			},
+		},
+		{
+			DecoratedText: &GoogleChatDecoratedText{
+				TopLabel: "Reason",
+				Text:     html.EscapeString(transition.Reason),
+				Icon:     &GoogleChatIcon{KnownIcon: "BUG_REPORT"},
+			},
+		},
+	}
+	if transition.Message != "" {
+		widgets = append(widgets, GoogleChatWidget{
+			TextParagraph: &GoogleChatTextParagraph{
+				Text: html.EscapeString(truncateStatusNotificationMessage(transition.Message)),
+			},
+		})
+	}
+
+	return GoogleChatMessage{
+		CardsV2: []GoogleChatCardV2{
+			{
+				CardId: "status-notification",
+				Card: GoogleChatCard{
+					Header: &GoogleChatCardHeader{
+						Title:     statusNotificationTitle(kind, transition),
+						Subtitle:  fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName()),
+						ImageType: "CIRCLE",
+					},
+					Sections: []GoogleChatSection{
+						{
+							Header:  "Status",
+							Widgets: widgets,
+						},
+						{
+							Widgets: []GoogleChatWidget{
+								{
+									TextParagraph: &GoogleChatTextParagraph{
+										Text: "<i>dot-ai Kubernetes Event Controller</i>",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,193 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+func TestStatusNotifier_Prepare(t *testing.T) {
+	notifier := &StatusNotifier{}
+	config := &dotaiv1alpha1.StatusNotificationConfig{Channels: []string{"sre"}}
+
+	t.Run("healthy on first observation is recorded without notifying", func(t *testing.T) {
+		state := &dotaiv1alpha1.StatusNotificationState{}
+		assert.Nil(t, notifier.Prepare(config, state, true, "SyncComplete", ""))
+		assert.Equal(t, statusNotificationStateHealthy, state.State)
+		assert.NotNil(t, state.LastTransitionTime)
+	})
+
+	t.Run("unhealthy on first observation notifies", func(t *testing.T) {
+		state := &dotaiv1alpha1.StatusNotificationState{}
+		transition := notifier.Prepare(config, state, false, "AuthFailed", "Authentication failed")
+		require.NotNil(t, transition)
+		assert.False(t, transition.Healthy)
+		assert.Equal(t, "AuthFailed", transition.Reason)
+		assert.Equal(t, statusNotificationStateUnhealthy, state.State)
+	})
+
+	t.Run("repeated unhealthy state is de-duplicated", func(t *testing.T) {
+		state := &dotaiv1alpha1.StatusNotificationState{State: statusNotificationStateUnhealthy, Reason: "AuthFailed"}
+		assert.Nil(t, notifier.Prepare(config, state, false, "NotFound", "Repository not found"))
+		assert.Equal(t, "AuthFailed", state.Reason, "state should not change without a transition")
+	})
+
+	t.Run("recovery notifies", func(t *testing.T) {
+		state := &dotaiv1alpha1.StatusNotificationState{State: statusNotificationStateUnhealthy}
+		transition := notifier.Prepare(config, state, true, "SyncComplete", "Synced")
+		require.NotNil(t, transition)
+		assert.True(t, transition.Healthy)
+	})
+
+	t.Run("recovery is recorded silently when disabled", func(t *testing.T) {
+		noRecovery := &dotaiv1alpha1.StatusNotificationConfig{Channels: []string{"sre"}, NotifyOnRecovery: ptr.To(false)}
+		state := &dotaiv1alpha1.StatusNotificationState{State: statusNotificationStateUnhealthy}
+		assert.Nil(t, notifier.Prepare(noRecovery, state, true, "SyncComplete", "Synced"))
+		assert.Equal(t, statusNotificationStateHealthy, state.State)
+	})
+
+	t.Run("no configuration leaves state untouched", func(t *testing.T) {
+		state := &dotaiv1alpha1.StatusNotificationState{}
+		assert.Nil(t, notifier.Prepare(nil, state, false, "AuthFailed", ""))
+		assert.Empty(t, state.State)
+	})
+
+	t.Run("nil notifier is disabled", func(t *testing.T) {
+		var disabled *StatusNotifier
+		state := &dotaiv1alpha1.StatusNotificationState{}
+		assert.Nil(t, disabled.Prepare(config, state, false, "AuthFailed", ""))
+		disabled.Send(context.Background(), &dotaiv1alpha1.Solution{}, "Solution", config, &StatusTransition{})
+	})
+}
+
+func TestStatusNotifier_Send(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string][]SlackMessage{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message SlackMessage
+		_ = json.NewDecoder(r.Body).Decode(&message)
+		mu.Lock()
+		received[r.URL.Path] = append(received[r.URL.Path], message)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	scheme := newNotificationChannelTestScheme()
+	channel := newTestNotificationChannel(dotaiv1alpha1.NotificationChannelTypeSlack)
+	channel.Spec.WebhookUrlSecretRef.Key = "channel"
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhooks", Namespace: "default"},
+		Data: map[string][]byte{
+			"inline":  []byte(server.URL + "/inline"),
+			"channel": []byte(server.URL + "/channel"),
+		},
+	}
+	solution := &dotaiv1alpha1.Solution{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-app", Namespace: "default"},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(channel, secret, solution).
+		WithStatusSubresource(channel).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+	notifier := &StatusNotifier{Client: fakeClient, HttpClient: server.Client(), Recorder: recorder}
+
+	config := &dotaiv1alpha1.StatusNotificationConfig{
+		Channels: []string{"sre", "missing"},
+		Slack: &dotaiv1alpha1.SlackRouteTarget{
+			WebhookUrlSecretRef: dotaiv1alpha1.SecretReference{Name: "webhooks", Key: "inline"},
+			Channel:             "#solutions",
+		},
+	}
+	notifier.Send(context.Background(), solution, "Solution", config, &StatusTransition{
+		Healthy: false,
+		Reason:  "ResourcesNotReady",
+		Message: "Ready: 1/2, Failed: 1",
+	})
+
+	mu.Lock()
+	require.Len(t, received["/inline"], 1)
+	assert.Equal(t, "#solutions", received["/inline"][0].Channel)
+	assert.Equal(t, "🚨 Solution Unhealthy", received["/inline"][0].Attachments[0].Blocks[0].Text.Text)
+	assert.Len(t, received["/channel"], 1)
+	mu.Unlock()
+
+	updated := &dotaiv1alpha1.NotificationChannel{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(channel), updated))
+	assert.Equal(t, int64(1), updated.Status.SuccessfulDeliveries)
+
+	require.Len(t, recorder.Events, 1, "missing channel should be reported as an event")
+	assert.Contains(t, <-recorder.Events, "NotificationFailed")
+}
+
+func TestCapabilityScanReconciler_UpdateStatusNotifiesOnTransitions(t *testing.T) {
+	var mu sync.Mutex
+	var received []SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message SlackMessage
+		_ = json.NewDecoder(r.Body).Decode(&message)
+		mu.Lock()
+		received = append(received, message)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	scheme := newNotificationChannelTestScheme()
+	config := &dotaiv1alpha1.CapabilityScanConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "scan", Namespace: "default"},
+		Spec: dotaiv1alpha1.CapabilityScanConfigSpec{
+			Notifications: &dotaiv1alpha1.StatusNotificationConfig{
+				Slack: &dotaiv1alpha1.SlackRouteTarget{
+					WebhookUrlSecretRef: dotaiv1alpha1.SecretReference{Name: "webhooks", Key: "url"},
+				},
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(config, newWebhookSecret(server.URL)).
+		WithStatusSubresource(config).
+		Build()
+
+	r := &CapabilityScanReconciler{
+		Client:   fakeClient,
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+		Notifier: &StatusNotifier{Client: fakeClient, HttpClient: server.Client()},
+	}
+
+	ctx := context.Background()
+	r.updateStatus(ctx, config, true, "")
+	r.updateStatus(ctx, config, false, "scan failed: connection refused")
+	r.updateStatus(ctx, config, false, "scan failed: connection refused")
+	r.updateStatus(ctx, config, true, "")
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 2, "only the failure and the recovery should be notified")
+	assert.Equal(t, "🚨 CapabilityScanConfig Unhealthy", received[0].Attachments[0].Blocks[0].Text.Text)
+	assert.Equal(t, "✅ CapabilityScanConfig Recovered", received[1].Attachments[0].Blocks[0].Text.Text)
+
+	updated := &dotaiv1alpha1.CapabilityScanConfig{}
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(config), updated))
+	assert.Equal(t, statusNotificationStateHealthy, updated.Status.Notifications.State)
+}
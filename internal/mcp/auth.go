@@ -0,0 +1,38 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// ResolveToken reads a bearer token from a Secret key.
+// An empty secret name means that the endpoint does not require authentication.
+func ResolveToken(ctx context.Context, reader client.Reader, namespace string, ref dotaiv1alpha1.SecretReference) (string, error) {
+	if ref.Name == "" {
+		return "", nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("auth secret '%s' not found in namespace '%s'", ref.Name, namespace)
+		}
+		return "", fmt.Errorf("failed to fetch auth secret: %w", err)
+	}
+
+	tokenBytes, exists := secret.Data[ref.Key]
+	if !exists {
+		return "", fmt.Errorf("auth secret '%s' does not contain key '%s'", ref.Name, ref.Key)
+	}
+	if len(tokenBytes) == 0 {
+		return "", fmt.Errorf("auth secret '%s' key '%s' is empty", ref.Name, ref.Key)
+	}
+
+	return string(tokenBytes), nil
+}
@@ -0,0 +1,334 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/vfarcic/dot-ai-controller/internal/tracing"
+)
+
+const (
+	// UserAgent identifies the controller to the MCP server
+	UserAgent = "dot-ai-controller/v1.0.0"
+
+	// IdempotencyKeyHeader carries a key that stays the same across retries of a request,
+	// so that the MCP server can detect duplicates of non-idempotent operations
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// RequestIDHeader carries a unique ID of every request attempt for correlating logs
+	RequestIDHeader = "X-Request-ID"
+)
+
+// StatusError is returned when the MCP server responds with an HTTP error status
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	kind := "client"
+	if e.StatusCode >= 500 {
+		kind = "server"
+	}
+	return fmt.Sprintf("%s error (HTTP %d): %s", kind, e.StatusCode, e.Body)
+}
+
+// Config contains configuration for an MCP client
+type Config struct {
+	// Component names the controller using the client in logs and metrics (e.g. "resourcesync")
+	Component string
+
+	// HTTPClient overrides the HTTP client. When nil, requests go through the shared
+	// per-endpoint connection pool with the process-wide timeout.
+	HTTPClient *http.Client
+
+	// Retry is the retry policy; use DefaultRetryPolicy() for the process-wide default
+	Retry RetryPolicy
+}
+
+// Client sends requests to an MCP server
+type Client struct {
+	component  string
+	httpClient *http.Client
+	retry      RetryPolicy
+}
+
+// NewHTTPClient returns an HTTP client that uses the shared per-endpoint connection pool
+// and the process-wide request timeout
+func NewHTTPClient() *http.Client {
+	return NewHTTPClientWithTimeout(CurrentSettings().Timeout)
+}
+
+// NewHTTPClientWithTimeout returns an HTTP client that uses the shared per-endpoint
+// connection pool with a custom request timeout
+func NewHTTPClientWithTimeout(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: sharedPool,
+	}
+}
+
+// NewClient creates a new MCP client
+func NewClient(cfg Config) *Client {
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = NewHTTPClient()
+	}
+	if cfg.Component == "" {
+		cfg.Component = "mcp"
+	}
+
+	return &Client{
+		component:  cfg.Component,
+		httpClient: httpClient,
+		retry:      cfg.Retry,
+	}
+}
+
+// Request describes a request to the MCP server
+type Request struct {
+	// Method defaults to POST
+	Method string
+
+	// URL is the full request URL
+	URL string
+
+	// Body is encoded as JSON; nil sends no body
+	Body interface{}
+
+	// Token is sent as a bearer token when set
+	Token string
+
+	// Accept defaults to application/json
+	Accept string
+
+	// IdempotencyKey identifies the logical operation across retries.
+	// It is generated for POST requests when empty.
+	IdempotencyKey string
+
+	// Retry reports whether a response that would otherwise be returned is retried, such as
+	// an HTTP error status that is not transient or a body reporting a failure. Only set it
+	// for idempotent operations; RetryFailures retries every reported failure.
+	Retry func(*Response) bool
+}
+
+// RetryFailures retries responses with any HTTP error status or with "success": false in
+// their body, for idempotent operations whose failures are often caused by a briefly
+// unavailable backend of the MCP server
+func RetryFailures(resp *Response) bool {
+	if resp.StatusCode >= 400 {
+		return true
+	}
+	var body struct {
+		Success *bool `json:"success"`
+	}
+	if err := json.Unmarshal(resp.Body, &body); err != nil {
+		return false
+	}
+	return body.Success != nil && !*body.Success
+}
+
+// Response is a response received from the MCP server
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// RequestID is the ID of the attempt that produced the response
+	RequestID string
+
+	// Attempts is the number of attempts made
+	Attempts int
+
+	// Duration is the total time spent including backoff
+	Duration time.Duration
+}
+
+// Do sends a request, retrying transport errors, transient HTTP statuses and the responses
+// selected by the Retry function of the request according to the retry policy. A response with an HTTP error status is returned together with a
+// *StatusError. When retries are exhausted, the error of the last attempt is wrapped.
+// The request is traced, and its trace context is propagated to the MCP server.
+func (c *Client) Do(ctx context.Context, req Request) (*Response, error) {
+	ctx, span := tracing.Start(ctx, "mcp.request",
+		attribute.String("mcp.component", c.component),
+		attribute.String("mcp.endpoint", endpointLabel(req.URL)),
+	)
+	resp, err := c.do(ctx, req)
+	if resp != nil {
+		span.SetAttributes(
+			attribute.Int("http.response.status_code", resp.StatusCode),
+			attribute.Int("mcp.attempts", resp.Attempts),
+		)
+	}
+	tracing.End(span, err)
+	return resp, err
+}
+
+// do sends a request with retries
+func (c *Client) do(ctx context.Context, req Request) (*Response, error) {
+	logger := logf.FromContext(ctx).WithName(c.component + "-mcp")
+
+	if req.Method == "" {
+		req.Method = http.MethodPost
+	}
+	if req.Accept == "" {
+		req.Accept = "application/json"
+	}
+	if req.Method == http.MethodPost && req.IdempotencyKey == "" {
+		req.IdempotencyKey = string(uuid.NewUUID())
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = json.Marshal(req.Body); err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+	}
+
+	endpoint := endpointLabel(req.URL)
+	startTime := time.Now()
+
+	var (
+		lastResp   *Response
+		lastErr    error
+		retryAfter time.Duration
+		attempt    int
+	)
+	for attempt = 0; attempt <= c.retry.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := c.retry.retryDelay(attempt, retryAfter)
+			retriesTotal.WithLabelValues(c.component, endpoint).Inc()
+			logger.V(1).Info("Retrying MCP request",
+				"attempt", attempt,
+				"maxRetries", c.retry.MaxRetries,
+				"backoff", delay,
+				"idempotencyKey", req.IdempotencyKey,
+			)
+
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		resp, err := c.send(ctx, req, body, endpoint)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastResp, lastErr, retryAfter = nil, err, 0
+			logger.V(1).Info("MCP request failed", "attempt", attempt, "error", err)
+			continue
+		}
+
+		resp.Attempts = attempt + 1
+		resp.Duration = time.Since(startTime)
+		lastResp, lastErr, retryAfter = resp, nil, 0
+		if resp.StatusCode >= 400 {
+			lastErr = &StatusError{StatusCode: resp.StatusCode, Body: string(resp.Body)}
+		}
+		transient := resp.StatusCode >= 400 && IsRetryableStatus(resp.StatusCode)
+		if !transient && (req.Retry == nil || !req.Retry(resp)) {
+			return resp, lastErr
+		}
+		if resp.StatusCode >= 400 {
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		}
+		logger.V(1).Info("MCP returned failure, retrying",
+			"attempt", attempt,
+			"statusCode", resp.StatusCode,
+			"requestId", resp.RequestID,
+		)
+	}
+
+	// Failures reported in the body of a successful status are returned to the caller
+	if lastErr == nil {
+		return lastResp, nil
+	}
+	if attempt > 1 {
+		return lastResp, fmt.Errorf("MCP request failed after %d attempts: %w", attempt, lastErr)
+	}
+	return lastResp, lastErr
+}
+
+// send makes a single request attempt
+func (c *Client) send(ctx context.Context, req Request, body []byte, endpoint string) (*Response, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	requestID := string(uuid.NewUUID())
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpReq.Header.Set("Accept", req.Accept)
+	httpReq.Header.Set("User-Agent", UserAgent)
+	httpReq.Header.Set(RequestIDHeader, requestID)
+	if req.IdempotencyKey != "" {
+		httpReq.Header.Set(IdempotencyKeyHeader, req.IdempotencyKey)
+	}
+	if req.Token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+req.Token)
+	}
+	tracing.Inject(ctx, httpReq.Header)
+
+	startTime := time.Now()
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		requestDuration.WithLabelValues(c.component, endpoint).Observe(time.Since(startTime).Seconds())
+		requestsTotal.WithLabelValues(c.component, endpoint, "error").Inc()
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	requestDuration.WithLabelValues(c.component, endpoint).Observe(time.Since(startTime).Seconds())
+	if err != nil {
+		requestsTotal.WithLabelValues(c.component, endpoint, "error").Inc()
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	requestsTotal.WithLabelValues(c.component, endpoint, strconv.Itoa(resp.StatusCode)).Inc()
+
+	logf.FromContext(ctx).WithName(c.component+"-mcp").V(1).Info("Received MCP response",
+		"method", req.Method,
+		"statusCode", resp.StatusCode,
+		"requestId", requestID,
+		"duration", time.Since(startTime),
+		"bodySize", len(responseBody),
+	)
+
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       responseBody,
+		RequestID:  requestID,
+	}, nil
+}
+
+// endpointLabel returns the scheme and host of a URL, used as the endpoint metrics label
+func endpointLabel(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return "unknown"
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
@@ -0,0 +1,287 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+	"github.com/vfarcic/dot-ai-controller/internal/tracing"
+)
+
+// recordingServer fails the first failures requests with the given status and records request headers
+type recordingServer struct {
+	failures int
+	status   int
+
+	mu      sync.Mutex
+	headers []http.Header
+}
+
+func (s *recordingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.headers = append(s.headers, r.Header.Clone())
+	if len(s.headers) <= s.failures {
+		w.WriteHeader(s.status)
+		_, _ = w.Write([]byte("temporarily unavailable"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"success":true}`))
+}
+
+func fastRetryPolicy(maxRetries int) RetryPolicy {
+	return RetryPolicy{MaxRetries: maxRetries, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+}
+
+func TestClient_Do_RetriesTransientStatus(t *testing.T) {
+	recorder := &recordingServer{failures: 2, status: http.StatusServiceUnavailable}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	client := NewClient(Config{Component: "test-retry", Retry: fastRetryPolicy(3)})
+	resp, err := client.Do(context.Background(), Request{URL: server.URL, Body: map[string]string{"operation": "sync"}, Token: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 3, resp.Attempts)
+	assert.JSONEq(t, `{"success":true}`, string(resp.Body))
+
+	require.Len(t, recorder.headers, 3)
+	key := recorder.headers[0].Get(IdempotencyKeyHeader)
+	assert.NotEmpty(t, key)
+	requestIDs := map[string]bool{}
+	for _, header := range recorder.headers {
+		assert.Equal(t, key, header.Get(IdempotencyKeyHeader), "idempotency key must be stable across retries")
+		assert.Equal(t, "Bearer secret", header.Get("Authorization"))
+		assert.Equal(t, "application/json", header.Get("Content-Type"))
+		assert.Equal(t, UserAgent, header.Get("User-Agent"))
+		requestIDs[header.Get(RequestIDHeader)] = true
+	}
+	assert.Len(t, requestIDs, 3, "every attempt should have its own request ID")
+
+	endpoint := endpointLabel(server.URL)
+	assert.Equal(t, 2.0, testutil.ToFloat64(retriesTotal.WithLabelValues("test-retry", endpoint)))
+	assert.Equal(t, 2.0, testutil.ToFloat64(requestsTotal.WithLabelValues("test-retry", endpoint, "503")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(requestsTotal.WithLabelValues("test-retry", endpoint, "200")))
+}
+
+func TestClient_Do_ClientErrorIsNotRetried(t *testing.T) {
+	recorder := &recordingServer{failures: 5, status: http.StatusBadRequest}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	client := NewClient(Config{Component: "test-client-error", Retry: fastRetryPolicy(3)})
+	resp, err := client.Do(context.Background(), Request{URL: server.URL, Body: map[string]string{}})
+	require.Error(t, err)
+	assert.Len(t, recorder.headers, 1)
+
+	var statusErr *StatusError
+	require.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusBadRequest, statusErr.StatusCode)
+	assert.Contains(t, err.Error(), "client error (HTTP 400)")
+	require.NotNil(t, resp)
+	assert.Equal(t, "temporarily unavailable", string(resp.Body))
+}
+
+func TestClient_Do_RetryFailures(t *testing.T) {
+	responses := []struct {
+		status int
+		body   string
+	}{
+		{http.StatusBadRequest, `{"success":false,"error":{"message":"backend unavailable"}}`},
+		{http.StatusOK, `{"success":false,"error":{"message":"vector DB unavailable"}}`},
+		{http.StatusOK, `{"success":true}`},
+	}
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := responses[min(attempts, len(responses)-1)]
+		attempts++
+		w.WriteHeader(response.status)
+		_, _ = w.Write([]byte(response.body))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{Component: "test-retry-failures", Retry: fastRetryPolicy(3)})
+	resp, err := client.Do(context.Background(), Request{URL: server.URL, Body: map[string]string{}, Retry: RetryFailures})
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.Attempts, "client errors and success:false responses are retried")
+	assert.JSONEq(t, `{"success":true}`, string(resp.Body))
+
+	// Once retries are exhausted, a success:false response is returned for the caller to report
+	responses = responses[1:2]
+	attempts = 0
+	resp, err = client.Do(context.Background(), Request{URL: server.URL, Body: map[string]string{}, Retry: RetryFailures})
+	require.NoError(t, err)
+	assert.Equal(t, 4, resp.Attempts)
+	assert.Contains(t, string(resp.Body), "vector DB unavailable")
+}
+
+func TestClient_Do_ExhaustedRetries(t *testing.T) {
+	recorder := &recordingServer{failures: 5, status: http.StatusInternalServerError}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	client := NewClient(Config{Component: "test-exhausted", Retry: fastRetryPolicy(2)})
+	resp, err := client.Do(context.Background(), Request{Method: http.MethodDelete, URL: server.URL})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "after 3 attempts")
+	assert.Contains(t, err.Error(), "server error (HTTP 500)")
+	require.NotNil(t, resp)
+	assert.Equal(t, 3, resp.Attempts)
+
+	require.Len(t, recorder.headers, 3)
+	assert.Empty(t, recorder.headers[0].Get(IdempotencyKeyHeader), "DELETE is idempotent and needs no key")
+	assert.Empty(t, recorder.headers[0].Get("Content-Type"))
+}
+
+func TestClient_Do_ZeroPolicySendsSingleAttempt(t *testing.T) {
+	recorder := &recordingServer{failures: 5, status: http.StatusServiceUnavailable}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	client := NewClient(Config{HTTPClient: server.Client()})
+	_, err := client.Do(context.Background(), Request{URL: server.URL, IdempotencyKey: "event-uid"})
+	require.Error(t, err)
+	require.Len(t, recorder.headers, 1)
+	assert.Equal(t, "event-uid", recorder.headers[0].Get(IdempotencyKeyHeader))
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 30 * time.Second}
+
+	backoff1 := policy.Backoff(1)
+	assert.True(t, backoff1 >= 750*time.Millisecond && backoff1 <= 1250*time.Millisecond, "got %v", backoff1)
+	backoff3 := policy.Backoff(3)
+	assert.True(t, backoff3 >= 3*time.Second && backoff3 <= 5*time.Second, "got %v", backoff3)
+	backoff10 := policy.Backoff(10)
+	assert.True(t, backoff10 <= 38*time.Second, "got %v", backoff10)
+
+	assert.Equal(t, 30*time.Second, policy.retryDelay(1, time.Hour), "Retry-After is capped at MaxBackoff")
+	assert.Equal(t, 10*time.Second, policy.retryDelay(1, 10*time.Second))
+}
+
+func TestEndpointPool_TransportPerEndpoint(t *testing.T) {
+	first := sharedPool.transportFor("https://mcp-a.example.com")
+	assert.Same(t, first, sharedPool.transportFor("https://mcp-a.example.com"))
+	assert.NotSame(t, first, sharedPool.transportFor("https://mcp-b.example.com"))
+	assert.Equal(t, CurrentSettings().MaxIdleConnsPerEndpoint, first.MaxIdleConnsPerHost)
+
+	certificatePool := &endpointPool{identity: "client-cert"}
+	assert.NotSame(t, first, certificatePool.transportFor("https://mcp-a.example.com"),
+		"clients with a certificate must not share connections")
+}
+
+func TestEndpointPool_EvictsLeastRecentlyUsedTransports(t *testing.T) {
+	previous := maxTransports
+	maxTransports = 2
+	defer func() { maxTransports = previous }()
+
+	first := (&endpointPool{identity: "rotated-1"}).transportFor("https://mcp.example.com")
+	second := (&endpointPool{identity: "rotated-2"}).transportFor("https://mcp.example.com")
+	assert.Same(t, first, (&endpointPool{identity: "rotated-1"}).transportFor("https://mcp.example.com"))
+
+	(&endpointPool{identity: "rotated-3"}).transportFor("https://mcp.example.com")
+
+	transportsMu.Lock()
+	assert.Len(t, transports, 2)
+	assert.Contains(t, transports, "rotated-1|https://mcp.example.com", "recently used transports are kept")
+	assert.NotContains(t, transports, "rotated-2|https://mcp.example.com", "the least recently used transport is evicted")
+	transportsMu.Unlock()
+	assert.NotSame(t, second, (&endpointPool{identity: "rotated-2"}).transportFor("https://mcp.example.com"))
+}
+
+func TestSetSettings_AppliesDefaults(t *testing.T) {
+	defer SetSettings(DefaultSettings())
+
+	SetSettings(Settings{Timeout: 5 * time.Second, Retry: RetryPolicy{MaxRetries: 1}, RemediationMaxRetries: 2})
+	settings := CurrentSettings()
+	assert.Equal(t, 5*time.Second, settings.Timeout)
+	assert.Equal(t, 1, DefaultRetryPolicy().MaxRetries)
+	assert.Equal(t, DefaultInitialBackoff, DefaultRetryPolicy().InitialBackoff)
+	assert.Equal(t, 2, RemediationRetryPolicy().MaxRetries)
+	assert.Equal(t, DefaultMaxIdleConnsPerEndpoint, settings.MaxIdleConnsPerEndpoint)
+	assert.Equal(t, 5*time.Second, NewHTTPClient().Timeout)
+}
+
+func TestResolveToken(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "mcp-auth", Namespace: "default"},
+			Data:       map[string][]byte{"token": []byte("secret"), "empty": {}},
+		}).
+		Build()
+	ctx := context.Background()
+
+	token, err := ResolveToken(ctx, fakeClient, "default", dotaiv1alpha1.SecretReference{Name: "mcp-auth", Key: "token"})
+	require.NoError(t, err)
+	assert.Equal(t, "secret", token)
+
+	token, err = ResolveToken(ctx, fakeClient, "default", dotaiv1alpha1.SecretReference{})
+	require.NoError(t, err)
+	assert.Empty(t, token, "no secret name means no authentication")
+
+	_, err = ResolveToken(ctx, fakeClient, "default", dotaiv1alpha1.SecretReference{Name: "mcp-auth", Key: "missing"})
+	assert.ErrorContains(t, err, "does not contain key 'missing'")
+
+	_, err = ResolveToken(ctx, fakeClient, "default", dotaiv1alpha1.SecretReference{Name: "mcp-auth", Key: "empty"})
+	assert.ErrorContains(t, err, "is empty")
+
+	_, err = ResolveToken(ctx, fakeClient, "other", dotaiv1alpha1.SecretReference{Name: "mcp-auth", Key: "token"})
+	assert.ErrorContains(t, err, "not found in namespace 'other'")
+}
+
+func TestClient_Do_PropagatesTraceContext(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.NewTracerProvider(tracing.DefaultConfig(), sdktrace.WithSyncer(exporter))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	}()
+
+	recorder := &recordingServer{}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	ctx, parent := tracing.Start(context.Background(), "RemediationPolicy.Reconcile")
+	client := NewClient(Config{Component: "test-tracing"})
+	_, err := client.Do(ctx, Request{URL: server.URL, Body: map[string]string{"operation": "sync"}})
+	require.NoError(t, err)
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	request := spans[0]
+	assert.Equal(t, "mcp.request", request.Name)
+	assert.Equal(t, parent.SpanContext().SpanID(), request.Parent.SpanID())
+
+	// The MCP server receives the span of the request as its parent
+	require.Len(t, recorder.headers, 1)
+	traceparent := recorder.headers[0].Get("traceparent")
+	assert.Contains(t, traceparent, request.SpanContext.TraceID().String())
+	assert.Contains(t, traceparent, request.SpanContext.SpanID().String())
+}
@@ -0,0 +1,405 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+const (
+	// defaultOAuth2TokenLifetime is assumed when the token response has no expires_in
+	defaultOAuth2TokenLifetime = 5 * time.Minute
+
+	// defaultServiceAccountTokenExpiration is the requested ServiceAccount token lifetime
+	defaultServiceAccountTokenExpiration = int64(3600)
+
+	// tlsCAKey is the optional key of a TLS Secret holding the CA that verifies the server
+	tlsCAKey = "ca.crt"
+
+	// ServiceAccountTokenAudiencesAnnotation lists, comma-separated, the audiences the controller may
+	// request tokens of a ServiceAccount for. ServiceAccounts without it are never used for MCP auth.
+	ServiceAccountTokenAudiencesAnnotation = "dot-ai.devopstoolkit.live/mcp-token-audiences"
+)
+
+// apiServerAudiences are the usual audiences of the Kubernetes API server. Tokens for them would let
+// the MCP endpoint act as the ServiceAccount against the cluster, so they are never requested.
+var apiServerAudiences = []string{
+	"api",
+	"kubernetes",
+	"kubernetes.default.svc",
+	"https://kubernetes.default.svc",
+	"https://kubernetes.default.svc.cluster.local",
+}
+
+// controllerTokenPath is the token of the controller's own ServiceAccount, whose audiences are
+// those the API server accepts
+var controllerTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// Credentials are the resolved credentials of an MCP endpoint
+type Credentials struct {
+	// Token is sent as a bearer token when set
+	Token string
+
+	// TLSConfig holds the client certificate for mTLS and the CA that verifies the server
+	TLSConfig *tls.Config
+
+	// proxy is the explicit HTTP proxy; nil uses the proxy environment variables
+	proxy *url.URL
+
+	// identity keys the connection pool of the TLS and proxy configuration
+	identity string
+}
+
+// HTTPClient returns an HTTP client that applies the TLS and proxy settings of the credentials.
+// Without them it returns base, or a client of the shared pool when base is nil.
+// The timeout of base is preserved.
+func (c Credentials) HTTPClient(base *http.Client) *http.Client {
+	if c.TLSConfig == nil && c.proxy == nil {
+		if base == nil {
+			return NewHTTPClient()
+		}
+		return base
+	}
+
+	timeout := CurrentSettings().Timeout
+	if base != nil {
+		timeout = base.Timeout
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &endpointPool{identity: c.identity, tlsConfig: c.TLSConfig, proxy: c.proxy},
+	}
+}
+
+// cachedToken is a cached OAuth2 or ServiceAccount token
+type cachedToken struct {
+	token     string
+	refreshAt time.Time
+}
+
+var (
+	tokenCacheMu sync.Mutex
+	tokenCache   = make(map[string]cachedToken)
+)
+
+// ResolveCredentials resolves the credentials of an MCP endpoint for the configured auth mode.
+// The bearer mode (default) reads the static token from secretRef; the other modes ignore it.
+// OAuth2 and ServiceAccount tokens are cached and refreshed after 80% of their lifetime.
+func ResolveCredentials(ctx context.Context, c client.Client, namespace string, secretRef dotaiv1alpha1.SecretReference, auth *dotaiv1alpha1.McpAuthConfig) (Credentials, error) {
+	return resolveCredentials(ctx, c, namespace, secretRef, auth, TLSSettings{})
+}
+
+// ResolveEndpointCredentials resolves the credentials of an MCP endpoint like ResolveCredentials and
+// applies the TLS and proxy settings of the endpoint. OAuth2 token requests use the same settings,
+// so a token endpoint behind a private CA or a proxy is reached like the MCP endpoint.
+func ResolveEndpointCredentials(ctx context.Context, c client.Client, namespace string, secretRef dotaiv1alpha1.SecretReference,
+	auth *dotaiv1alpha1.McpAuthConfig, settings TLSSettings) (Credentials, error) {
+	credentials, err := resolveCredentials(ctx, c, namespace, secretRef, auth, settings)
+	if err != nil {
+		return Credentials{}, err
+	}
+	credentials, err = credentials.WithTLS(settings)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("invalid TLS settings: %w", err)
+	}
+	return credentials, nil
+}
+
+// resolveCredentials resolves the credentials of the configured auth mode; settings only apply
+// to OAuth2 token requests
+func resolveCredentials(ctx context.Context, c client.Client, namespace string, secretRef dotaiv1alpha1.SecretReference,
+	auth *dotaiv1alpha1.McpAuthConfig, settings TLSSettings) (Credentials, error) {
+	switch mode := auth.GetMode(); mode {
+	case dotaiv1alpha1.McpAuthModeBearer:
+		token, err := ResolveToken(ctx, c, namespace, secretRef)
+		return Credentials{Token: token}, err
+	case dotaiv1alpha1.McpAuthModeMTLS:
+		if auth.MTLS == nil || auth.MTLS.SecretName == "" {
+			return Credentials{}, fmt.Errorf("auth mode %s requires mtls.secretName", mode)
+		}
+		return resolveClientCertificate(ctx, c, namespace, auth.MTLS.SecretName)
+	case dotaiv1alpha1.McpAuthModeOAuth2:
+		if auth.OAuth2 == nil {
+			return Credentials{}, fmt.Errorf("auth mode %s requires the oauth2 settings", mode)
+		}
+		token, err := resolveOAuth2Token(ctx, c, namespace, auth.OAuth2, settings)
+		return Credentials{Token: token}, err
+	case dotaiv1alpha1.McpAuthModeServiceAccountToken:
+		if auth.ServiceAccountToken == nil || auth.ServiceAccountToken.Audience == "" {
+			return Credentials{}, fmt.Errorf("auth mode %s requires serviceAccountToken.audience", mode)
+		}
+		token, err := resolveServiceAccountToken(ctx, c, namespace, auth.ServiceAccountToken)
+		return Credentials{Token: token}, err
+	default:
+		return Credentials{}, fmt.Errorf("unsupported MCP auth mode %q", mode)
+	}
+}
+
+// resolveClientCertificate loads the client certificate (and optional CA) from a TLS Secret
+func resolveClientCertificate(ctx context.Context, c client.Client, namespace, secretName string) (Credentials, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: secretName}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return Credentials{}, fmt.Errorf("TLS secret '%s' not found in namespace '%s'", secretName, namespace)
+		}
+		return Credentials{}, fmt.Errorf("failed to fetch TLS secret: %w", err)
+	}
+
+	certificate, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return Credentials{}, fmt.Errorf("TLS secret '%s' does not contain a valid client certificate: %w", secretName, err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}
+	ca := secret.Data[tlsCAKey]
+	if len(ca) > 0 {
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(ca) {
+			return Credentials{}, fmt.Errorf("TLS secret '%s' key '%s' does not contain a valid CA certificate",
+				secretName, tlsCAKey)
+		}
+		tlsConfig.RootCAs = roots
+	}
+
+	return Credentials{TLSConfig: tlsConfig, identity: fingerprint(secret.Data[corev1.TLSCertKey], ca)}, nil
+}
+
+// resolveOAuth2Token returns a cached access token or requests one with the client-credentials grant,
+// using the TLS and proxy settings of the endpoint
+func resolveOAuth2Token(ctx context.Context, c client.Client, namespace string, cfg *dotaiv1alpha1.McpOAuth2Config, settings TLSSettings) (string, error) {
+	clientSecret, err := ResolveToken(ctx, c, namespace, cfg.ClientSecretRef)
+	if err != nil {
+		return "", fmt.Errorf("failed to get OAuth2 client secret: %w", err)
+	}
+	if clientSecret == "" {
+		return "", fmt.Errorf("OAuth2 clientSecretRef is required")
+	}
+
+	// The client secret is part of the key, so that a rotated secret requests a new token
+	key := strings.Join([]string{"oauth2", cfg.TokenURL, cfg.ClientID, strings.Join(cfg.Scopes, " "),
+		cfg.Audience, fingerprint([]byte(clientSecret))}, "|")
+	return cachedTokenFor(key, func() (string, time.Duration, error) {
+		transport, err := Credentials{}.WithTLS(settings)
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid TLS settings for OAuth2 token request: %w", err)
+		}
+		return requestOAuth2Token(ctx, transport.HTTPClient(nil), cfg, clientSecret)
+	})
+}
+
+// requestOAuth2Token requests an access token from the token endpoint
+func requestOAuth2Token(ctx context.Context, httpClient *http.Client, cfg *dotaiv1alpha1.McpOAuth2Config, clientSecret string) (string, time.Duration, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(cfg.Scopes, " "))
+	}
+	if cfg.Audience != "" {
+		form.Set("audience", cfg.Audience)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create OAuth2 token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", UserAgent)
+	req.SetBasicAuth(url.QueryEscape(cfg.ClientID), url.QueryEscape(clientSecret))
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("OAuth2 token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read OAuth2 token response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return "", 0, fmt.Errorf("OAuth2 token request failed (HTTP %d): %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResponse struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		return "", 0, fmt.Errorf("failed to parse OAuth2 token response: %w", err)
+	}
+	if tokenResponse.AccessToken == "" {
+		return "", 0, fmt.Errorf("OAuth2 token response does not contain an access token")
+	}
+
+	lifetime := defaultOAuth2TokenLifetime
+	if tokenResponse.ExpiresIn > 0 {
+		lifetime = time.Duration(tokenResponse.ExpiresIn) * time.Second
+	}
+	return tokenResponse.AccessToken, lifetime, nil
+}
+
+// resolveServiceAccountToken returns a cached ServiceAccount token or requests one with the TokenRequest API
+func resolveServiceAccountToken(ctx context.Context, c client.Client, namespace string, cfg *dotaiv1alpha1.McpServiceAccountTokenConfig) (string, error) {
+	serviceAccountName := cfg.ServiceAccountName
+	if serviceAccountName == "" {
+		serviceAccountName = "default"
+	}
+	expirationSeconds := cfg.ExpirationSeconds
+	if expirationSeconds <= 0 {
+		expirationSeconds = defaultServiceAccountTokenExpiration
+	}
+
+	if isAPIServerAudience(cfg.Audience) {
+		return "", fmt.Errorf("audience '%s' is accepted by the Kubernetes API server and cannot be used for MCP tokens", cfg.Audience)
+	}
+	if err := checkServiceAccountTokenAudience(ctx, c, namespace, serviceAccountName, cfg.Audience); err != nil {
+		return "", err
+	}
+
+	key := fmt.Sprintf("serviceaccount|%s|%s|%s|%d", namespace, serviceAccountName, cfg.Audience, expirationSeconds)
+	return cachedTokenFor(key, func() (string, time.Duration, error) {
+		serviceAccount := &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: serviceAccountName, Namespace: namespace},
+		}
+		tokenRequest := &authenticationv1.TokenRequest{
+			Spec: authenticationv1.TokenRequestSpec{
+				Audiences:         []string{cfg.Audience},
+				ExpirationSeconds: &expirationSeconds,
+			},
+		}
+		if err := c.SubResource("token").Create(ctx, serviceAccount, tokenRequest); err != nil {
+			return "", 0, fmt.Errorf("failed to request token for ServiceAccount '%s' in namespace '%s': %w",
+				serviceAccountName, namespace, err)
+		}
+
+		lifetime := time.Until(tokenRequest.Status.ExpirationTimestamp.Time)
+		if lifetime <= 0 {
+			lifetime = time.Duration(expirationSeconds) * time.Second
+		}
+		return tokenRequest.Status.Token, lifetime, nil
+	})
+}
+
+// checkServiceAccountTokenAudience verifies that the ServiceAccount opted in to tokens for the audience
+// with the ServiceAccountTokenAudiencesAnnotation. Only its metadata is read and cached.
+func checkServiceAccountTokenAudience(ctx context.Context, c client.Client, namespace, name, audience string) error {
+	serviceAccount := &metav1.PartialObjectMetadata{}
+	serviceAccount.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ServiceAccount"))
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, serviceAccount); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("ServiceAccount '%s' not found in namespace '%s'", name, namespace)
+		}
+		return fmt.Errorf("failed to fetch ServiceAccount '%s' in namespace '%s': %w", name, namespace, err)
+	}
+
+	for _, allowed := range strings.Split(serviceAccount.GetAnnotations()[ServiceAccountTokenAudiencesAnnotation], ",") {
+		if strings.TrimSpace(allowed) == audience {
+			return nil
+		}
+	}
+	return fmt.Errorf("ServiceAccount '%s' in namespace '%s' does not allow tokens for audience '%s' (annotation %s)",
+		name, namespace, audience, ServiceAccountTokenAudiencesAnnotation)
+}
+
+// isAPIServerAudience reports whether the API server accepts tokens with the audience: one of the
+// usual API server audiences or an audience of the controller's own token
+func isAPIServerAudience(audience string) bool {
+	for _, apiServerAudience := range apiServerAudiences {
+		if audience == apiServerAudience {
+			return true
+		}
+	}
+	for _, controllerAudience := range controllerTokenAudiences() {
+		if audience == controllerAudience {
+			return true
+		}
+	}
+	return false
+}
+
+// controllerTokenAudiences returns the audiences of the controller's own ServiceAccount token, or nil
+// when it runs outside the cluster
+func controllerTokenAudiences() []string {
+	token, err := os.ReadFile(controllerTokenPath)
+	if err != nil {
+		return nil
+	}
+	parts := strings.Split(strings.TrimSpace(string(token)), ".")
+	if len(parts) != 3 {
+		return nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil
+	}
+
+	// The aud claim is either a string or a list of strings
+	var claims struct {
+		Audience json.RawMessage `json:"aud"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil
+	}
+	var audiences []string
+	if err := json.Unmarshal(claims.Audience, &audiences); err == nil {
+		return audiences
+	}
+	var audience string
+	if err := json.Unmarshal(claims.Audience, &audience); err == nil && audience != "" {
+		return []string{audience}
+	}
+	return nil
+}
+
+// cachedTokenFor returns the cached token for a key, fetching a new one once 80% of its lifetime has passed
+func cachedTokenFor(key string, fetch func() (string, time.Duration, error)) (string, error) {
+	tokenCacheMu.Lock()
+	cached, ok := tokenCache[key]
+	tokenCacheMu.Unlock()
+	if ok && time.Now().Before(cached.refreshAt) {
+		return cached.token, nil
+	}
+
+	token, lifetime, err := fetch()
+	if err != nil {
+		return "", err
+	}
+
+	tokenCacheMu.Lock()
+	tokenCache[key] = cachedToken{token: token, refreshAt: time.Now().Add(lifetime * 4 / 5)}
+	tokenCacheMu.Unlock()
+	return token, nil
+}
+
+// fingerprint returns a short hash identifying secret material without exposing it
+func fingerprint(parts ...[]byte) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write(part)
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
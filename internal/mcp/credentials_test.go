@@ -0,0 +1,271 @@
+package mcp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+func newFakeClient(t *testing.T, objects ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+// newClientCertificate returns a self-signed client certificate and key in PEM format
+func newClientCertificate(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "dot-ai-controller"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestResolveCredentials_MTLS(t *testing.T) {
+	var clientCommonName string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientCommonName = r.TLS.PeerCertificates[0].Subject.CommonName
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	certPEM, keyPEM := newClientCertificate(t)
+	serverCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	fakeClient := newFakeClient(t, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "mcp-client-tls", Namespace: "default"},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+			tlsCAKey:                serverCA,
+		},
+	})
+	auth := &dotaiv1alpha1.McpAuthConfig{
+		Mode: dotaiv1alpha1.McpAuthModeMTLS,
+		MTLS: &dotaiv1alpha1.McpMTLSConfig{SecretName: "mcp-client-tls"},
+	}
+
+	credentials, err := ResolveCredentials(context.Background(), fakeClient, "default", dotaiv1alpha1.SecretReference{}, auth)
+	require.NoError(t, err)
+	assert.Empty(t, credentials.Token)
+	require.NotNil(t, credentials.TLSConfig)
+
+	client := NewClient(Config{Component: "test-mtls", HTTPClient: credentials.HTTPClient(nil)})
+	resp, err := client.Do(context.Background(), Request{URL: server.URL, Body: map[string]string{}})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "dot-ai-controller", clientCommonName)
+
+	_, err = ResolveCredentials(context.Background(), fakeClient, "other", dotaiv1alpha1.SecretReference{}, auth)
+	assert.ErrorContains(t, err, "TLS secret 'mcp-client-tls' not found in namespace 'other'")
+}
+
+func TestResolveCredentials_OAuth2(t *testing.T) {
+	var requests atomic.Int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "mcp:read mcp:write", r.PostForm.Get("scope"))
+		assert.Equal(t, "dot-ai", r.PostForm.Get("audience"))
+		clientID, clientSecret, ok := r.BasicAuth()
+		require.True(t, ok)
+		assert.Equal(t, "controller", clientID)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"token-for-` + clientSecret + `","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "oauth2-client", Namespace: "default"},
+		Data:       map[string][]byte{"client-secret": []byte("first")},
+	}
+	fakeClient := newFakeClient(t, secret)
+	auth := &dotaiv1alpha1.McpAuthConfig{
+		Mode: dotaiv1alpha1.McpAuthModeOAuth2,
+		OAuth2: &dotaiv1alpha1.McpOAuth2Config{
+			TokenURL:        tokenServer.URL,
+			ClientID:        "controller",
+			ClientSecretRef: dotaiv1alpha1.SecretReference{Name: "oauth2-client", Key: "client-secret"},
+			Scopes:          []string{"mcp:read", "mcp:write"},
+			Audience:        "dot-ai",
+		},
+	}
+	ctx := context.Background()
+
+	credentials, err := ResolveCredentials(ctx, fakeClient, "default", dotaiv1alpha1.SecretReference{}, auth)
+	require.NoError(t, err)
+	assert.Equal(t, "token-for-first", credentials.Token)
+
+	credentials, err = ResolveCredentials(ctx, fakeClient, "default", dotaiv1alpha1.SecretReference{}, auth)
+	require.NoError(t, err)
+	assert.Equal(t, "token-for-first", credentials.Token)
+	assert.Equal(t, int32(1), requests.Load(), "the cached token should be reused")
+
+	secret.Data["client-secret"] = []byte("second")
+	require.NoError(t, fakeClient.Update(ctx, secret))
+	credentials, err = ResolveCredentials(ctx, fakeClient, "default", dotaiv1alpha1.SecretReference{}, auth)
+	require.NoError(t, err)
+	assert.Equal(t, "token-for-second", credentials.Token, "a rotated client secret should request a new token")
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestResolveEndpointCredentials_OAuth2UsesEndpointTLS(t *testing.T) {
+	tokenServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"private-ca-token","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	fakeClient := newFakeClient(t, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "oauth2-client", Namespace: "default"},
+		Data:       map[string][]byte{"client-secret": []byte("secret")},
+	})
+	auth := &dotaiv1alpha1.McpAuthConfig{
+		Mode: dotaiv1alpha1.McpAuthModeOAuth2,
+		OAuth2: &dotaiv1alpha1.McpOAuth2Config{
+			TokenURL:        tokenServer.URL,
+			ClientID:        "private-ca",
+			ClientSecretRef: dotaiv1alpha1.SecretReference{Name: "oauth2-client", Key: "client-secret"},
+		},
+	}
+	ctx := context.Background()
+
+	_, err := ResolveCredentials(ctx, fakeClient, "default", dotaiv1alpha1.SecretReference{}, auth)
+	assert.ErrorContains(t, err, "certificate", "the system roots do not trust the private CA")
+
+	settings := TLSSettings{CA: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tokenServer.Certificate().Raw})}
+	credentials, err := ResolveEndpointCredentials(ctx, fakeClient, "default", dotaiv1alpha1.SecretReference{}, auth, settings)
+	require.NoError(t, err)
+	assert.Equal(t, "private-ca-token", credentials.Token)
+	require.NotNil(t, credentials.TLSConfig, "the TLS settings apply to the MCP endpoint as well")
+	assert.NotNil(t, credentials.TLSConfig.RootCAs)
+}
+
+func TestResolveCredentials_ServiceAccountToken(t *testing.T) {
+	fakeClient := newFakeClient(t, &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "mcp-client",
+			Namespace:   "default",
+			Annotations: map[string]string{ServiceAccountTokenAudiencesAnnotation: "other, dot-ai-mcp"},
+		},
+	})
+	auth := &dotaiv1alpha1.McpAuthConfig{
+		Mode: dotaiv1alpha1.McpAuthModeServiceAccountToken,
+		ServiceAccountToken: &dotaiv1alpha1.McpServiceAccountTokenConfig{
+			ServiceAccountName: "mcp-client",
+			Audience:           "dot-ai-mcp",
+		},
+	}
+
+	credentials, err := ResolveCredentials(context.Background(), fakeClient, "default", dotaiv1alpha1.SecretReference{}, auth)
+	require.NoError(t, err)
+	assert.Equal(t, "fake-token", credentials.Token)
+
+	auth.ServiceAccountToken.ServiceAccountName = "missing"
+	_, err = ResolveCredentials(context.Background(), fakeClient, "default", dotaiv1alpha1.SecretReference{}, auth)
+	assert.ErrorContains(t, err, "ServiceAccount 'missing' not found")
+}
+
+func TestResolveCredentials_ServiceAccountTokenRequiresOptIn(t *testing.T) {
+	fakeClient := newFakeClient(t,
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "default"}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+			Name:        "mcp-client",
+			Namespace:   "default",
+			Annotations: map[string]string{ServiceAccountTokenAudiencesAnnotation: "dot-ai-mcp,https://kubernetes.default.svc"},
+		}},
+	)
+	resolve := func(serviceAccountName, audience string) error {
+		_, err := ResolveCredentials(context.Background(), fakeClient, "default", dotaiv1alpha1.SecretReference{}, &dotaiv1alpha1.McpAuthConfig{
+			Mode: dotaiv1alpha1.McpAuthModeServiceAccountToken,
+			ServiceAccountToken: &dotaiv1alpha1.McpServiceAccountTokenConfig{
+				ServiceAccountName: serviceAccountName,
+				Audience:           audience,
+			},
+		})
+		return err
+	}
+
+	assert.ErrorContains(t, resolve("default", "dot-ai-mcp"), "does not allow tokens for audience 'dot-ai-mcp'",
+		"ServiceAccounts without the annotation are not used")
+	assert.ErrorContains(t, resolve("mcp-client", "other"), "does not allow tokens for audience 'other'")
+	assert.ErrorContains(t, resolve("mcp-client", "https://kubernetes.default.svc"), "accepted by the Kubernetes API server",
+		"API server audiences are rejected even when allowed by the ServiceAccount")
+}
+
+func TestIsAPIServerAudience(t *testing.T) {
+	claims := base64.RawURLEncoding.EncodeToString([]byte(`{"aud":["https://oidc.example.com/cluster"]}`))
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("header."+claims+".signature"), 0o600))
+	original := controllerTokenPath
+	controllerTokenPath = tokenPath
+	t.Cleanup(func() { controllerTokenPath = original })
+
+	assert.True(t, isAPIServerAudience("https://kubernetes.default.svc.cluster.local"))
+	assert.True(t, isAPIServerAudience("https://oidc.example.com/cluster"), "audiences of the controller's own token are the API server's")
+	assert.False(t, isAPIServerAudience("dot-ai-mcp"))
+}
+
+func TestResolveCredentials_ModeConfiguration(t *testing.T) {
+	fakeClient := newFakeClient(t, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "mcp-auth", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("secret")},
+	})
+	ctx := context.Background()
+	secretRef := dotaiv1alpha1.SecretReference{Name: "mcp-auth", Key: "token"}
+
+	credentials, err := ResolveCredentials(ctx, fakeClient, "default", secretRef, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", credentials.Token, "no auth config means the bearer mode")
+	assert.Nil(t, credentials.TLSConfig)
+
+	_, err = ResolveCredentials(ctx, fakeClient, "default", secretRef, &dotaiv1alpha1.McpAuthConfig{Mode: dotaiv1alpha1.McpAuthModeMTLS})
+	assert.ErrorContains(t, err, "requires mtls.secretName")
+
+	_, err = ResolveCredentials(ctx, fakeClient, "default", secretRef, &dotaiv1alpha1.McpAuthConfig{Mode: dotaiv1alpha1.McpAuthModeOAuth2})
+	assert.ErrorContains(t, err, "requires the oauth2 settings")
+
+	_, err = ResolveCredentials(ctx, fakeClient, "default", secretRef, &dotaiv1alpha1.McpAuthConfig{Mode: dotaiv1alpha1.McpAuthModeServiceAccountToken})
+	assert.ErrorContains(t, err, "requires serviceAccountToken.audience")
+}
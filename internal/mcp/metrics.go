@@ -0,0 +1,40 @@
+package mcp
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// requestsTotal counts MCP request attempts by component, endpoint and result code
+	requestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dot_ai_mcp_requests_total",
+			Help: "Total number of MCP request attempts by component, endpoint and HTTP status code (\"error\" for transport failures)",
+		},
+		[]string{"component", "endpoint", "code"},
+	)
+
+	// requestDuration observes the latency of MCP request attempts
+	requestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "dot_ai_mcp_request_duration_seconds",
+			Help:    "Latency of MCP request attempts by component and endpoint",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300, 900},
+		},
+		[]string{"component", "endpoint"},
+	)
+
+	// retriesTotal counts MCP request retries
+	retriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dot_ai_mcp_request_retries_total",
+			Help: "Total number of MCP request retries by component and endpoint",
+		},
+		[]string{"component", "endpoint"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(requestsTotal, requestDuration, retriesTotal)
+}
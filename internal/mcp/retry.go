@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed MCP requests are retried.
+// The zero value sends a single attempt without retries.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+
+	// InitialBackoff is the backoff before the first retry; it doubles with every retry
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the upper bound of the backoff between retries
+	MaxBackoff time.Duration
+}
+
+// Backoff returns the backoff before the given retry attempt (1-based),
+// growing exponentially up to MaxBackoff with ±25% jitter
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	backoff := float64(p.InitialBackoff) * math.Pow(2, float64(attempt-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	jitter := backoff * 0.25 * (rand.Float64()*2 - 1)
+	return time.Duration(backoff + jitter)
+}
+
+// retryDelay returns the delay before the given retry attempt, honoring a Retry-After
+// header of the previous response when it asks for a longer delay than the backoff
+func (p RetryPolicy) retryDelay(attempt int, retryAfter time.Duration) time.Duration {
+	delay := p.Backoff(attempt)
+	if retryAfter > delay {
+		delay = retryAfter
+		if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+			delay = p.MaxBackoff
+		}
+	}
+	return delay
+}
+
+// IsRetryableStatus reports whether an HTTP status code indicates a transient failure
+func IsRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+		return true
+	}
+	return statusCode >= 500
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
@@ -0,0 +1,110 @@
+// Package mcp provides the HTTP client shared by all controllers that talk to the MCP server.
+// It implements consistent retry policies, idempotency keys, request IDs, per-endpoint
+// connection pooling and metrics, so that retry and timeout behavior is tuned in one place.
+package mcp
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTimeout is the default timeout of a single MCP request attempt
+	DefaultTimeout = 60 * time.Second
+
+	// DefaultMaxRetries is the default number of retries after the first attempt
+	DefaultMaxRetries = 3
+
+	// DefaultInitialBackoff is the default backoff before the first retry
+	DefaultInitialBackoff = 1 * time.Second
+
+	// DefaultMaxBackoff is the default upper bound of the backoff between retries
+	DefaultMaxBackoff = 30 * time.Second
+
+	// DefaultMaxIdleConnsPerEndpoint is the default number of idle connections kept per endpoint
+	DefaultMaxIdleConnsPerEndpoint = 10
+)
+
+// Settings holds the process-wide MCP client settings.
+// They are set once at startup (see SetSettings) and apply to every client
+// that does not override them.
+type Settings struct {
+	// Timeout is the timeout of a single request attempt
+	Timeout time.Duration
+
+	// Retry is the retry policy of resource sync, capability scan and knowledge requests
+	Retry RetryPolicy
+
+	// RemediationMaxRetries is the number of retries of remediation requests.
+	// Remediation requests are long-running and not retried by default.
+	RemediationMaxRetries int
+
+	// MaxIdleConnsPerEndpoint is the number of idle connections kept per endpoint
+	MaxIdleConnsPerEndpoint int
+}
+
+var (
+	settingsMu sync.RWMutex
+	settings   = DefaultSettings()
+)
+
+// DefaultSettings returns the built-in MCP client settings
+func DefaultSettings() Settings {
+	return Settings{
+		Timeout: DefaultTimeout,
+		Retry: RetryPolicy{
+			MaxRetries:     DefaultMaxRetries,
+			InitialBackoff: DefaultInitialBackoff,
+			MaxBackoff:     DefaultMaxBackoff,
+		},
+		MaxIdleConnsPerEndpoint: DefaultMaxIdleConnsPerEndpoint,
+	}
+}
+
+// SetSettings replaces the process-wide MCP client settings.
+// Zero values fall back to the built-in defaults.
+func SetSettings(s Settings) {
+	defaults := DefaultSettings()
+	if s.Timeout <= 0 {
+		s.Timeout = defaults.Timeout
+	}
+	if s.Retry.MaxRetries < 0 {
+		s.Retry.MaxRetries = defaults.Retry.MaxRetries
+	}
+	if s.Retry.InitialBackoff <= 0 {
+		s.Retry.InitialBackoff = defaults.Retry.InitialBackoff
+	}
+	if s.Retry.MaxBackoff <= 0 {
+		s.Retry.MaxBackoff = defaults.Retry.MaxBackoff
+	}
+	if s.RemediationMaxRetries < 0 {
+		s.RemediationMaxRetries = 0
+	}
+	if s.MaxIdleConnsPerEndpoint <= 0 {
+		s.MaxIdleConnsPerEndpoint = defaults.MaxIdleConnsPerEndpoint
+	}
+
+	settingsMu.Lock()
+	settings = s
+	settingsMu.Unlock()
+}
+
+// CurrentSettings returns the process-wide MCP client settings
+func CurrentSettings() Settings {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return settings
+}
+
+// DefaultRetryPolicy returns the retry policy of resource sync, capability scan and knowledge requests
+func DefaultRetryPolicy() RetryPolicy {
+	return CurrentSettings().Retry
+}
+
+// RemediationRetryPolicy returns the retry policy of remediation requests
+func RemediationRetryPolicy() RetryPolicy {
+	s := CurrentSettings()
+	policy := s.Retry
+	policy.MaxRetries = s.RemediationMaxRetries
+	return policy
+}
@@ -0,0 +1,153 @@
+package mcp
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+// TLSSettings are the resolved TLS and proxy settings of an endpoint
+type TLSSettings struct {
+	// CA contains PEM-encoded CA certificates that verify the server
+	CA []byte
+
+	// MinVersion is the minimum TLS version (a tls.VersionTLS* constant); zero keeps TLS 1.2
+	MinVersion uint16
+
+	// ServerName overrides the server name sent with SNI and verified in the certificate
+	ServerName string
+
+	// InsecureSkipVerify disables verification of the server certificate
+	InsecureSkipVerify bool
+
+	// Proxy is the HTTP proxy; nil uses the proxy environment variables
+	Proxy *url.URL
+}
+
+// IsZero returns whether the settings leave the default TLS and proxy behavior unchanged
+func (s TLSSettings) IsZero() bool {
+	return len(s.CA) == 0 && s.MinVersion == 0 && s.ServerName == "" && !s.InsecureSkipVerify && s.Proxy == nil
+}
+
+// ResolveTLSSettings resolves the CA bundle references of a TLS configuration in namespace
+// and parses the minimum version and proxy URL. A nil configuration and an empty proxy URL
+// resolve to zero settings.
+func ResolveTLSSettings(ctx context.Context, reader client.Reader, namespace string, cfg *dotaiv1alpha1.TLSConfig, proxyURL string) (TLSSettings, error) {
+	var settings TLSSettings
+
+	if proxyURL != "" {
+		proxy, err := url.Parse(proxyURL)
+		if err != nil || proxy.Host == "" {
+			return TLSSettings{}, fmt.Errorf("invalid proxy URL '%s'", proxyURL)
+		}
+		settings.Proxy = proxy
+	}
+
+	if cfg == nil {
+		return settings, nil
+	}
+
+	switch cfg.MinVersion {
+	case "":
+	case dotaiv1alpha1.TLSVersion12:
+		settings.MinVersion = tls.VersionTLS12
+	case dotaiv1alpha1.TLSVersion13:
+		settings.MinVersion = tls.VersionTLS13
+	default:
+		return TLSSettings{}, fmt.Errorf("unsupported minimum TLS version %q", cfg.MinVersion)
+	}
+	settings.ServerName = cfg.ServerName
+	settings.InsecureSkipVerify = cfg.InsecureSkipVerify
+
+	if cfg.CASecretRef != nil {
+		ca, err := ResolveToken(ctx, reader, namespace, *cfg.CASecretRef)
+		if err != nil {
+			return TLSSettings{}, fmt.Errorf("failed to get CA bundle: %w", err)
+		}
+		settings.CA = append(settings.CA, ca...)
+	}
+	if cfg.CAConfigMapRef != nil {
+		ca, err := resolveConfigMapValue(ctx, reader, namespace, *cfg.CAConfigMapRef)
+		if err != nil {
+			return TLSSettings{}, fmt.Errorf("failed to get CA bundle: %w", err)
+		}
+		if len(settings.CA) > 0 {
+			settings.CA = append(settings.CA, '\n')
+		}
+		settings.CA = append(settings.CA, ca...)
+	}
+	return settings, nil
+}
+
+// resolveConfigMapValue reads a key of a ConfigMap
+func resolveConfigMapValue(ctx context.Context, reader client.Reader, namespace string, ref dotaiv1alpha1.ConfigMapReference) (string, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("ConfigMap '%s' not found in namespace '%s'", ref.Name, namespace)
+		}
+		return "", fmt.Errorf("failed to fetch ConfigMap: %w", err)
+	}
+
+	value, exists := configMap.Data[ref.Key]
+	if !exists {
+		return "", fmt.Errorf("ConfigMap '%s' does not contain key '%s'", ref.Name, ref.Key)
+	}
+	if value == "" {
+		return "", fmt.Errorf("ConfigMap '%s' key '%s' is empty", ref.Name, ref.Key)
+	}
+	return value, nil
+}
+
+// WithTLS returns the credentials with TLS and proxy settings applied. A CA bundle is added
+// to the CA of an mTLS Secret, if any; otherwise it replaces the system roots.
+func (c Credentials) WithTLS(settings TLSSettings) (Credentials, error) {
+	if settings.IsZero() {
+		return c, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.TLSConfig != nil {
+		tlsConfig = c.TLSConfig.Clone()
+	}
+	if len(settings.CA) > 0 {
+		roots := x509.NewCertPool()
+		if tlsConfig.RootCAs != nil {
+			roots = tlsConfig.RootCAs.Clone()
+		}
+		if !roots.AppendCertsFromPEM(settings.CA) {
+			return Credentials{}, fmt.Errorf("CA bundle does not contain a valid certificate")
+		}
+		tlsConfig.RootCAs = roots
+	}
+	if settings.MinVersion != 0 {
+		tlsConfig.MinVersion = settings.MinVersion
+	}
+	if settings.ServerName != "" {
+		tlsConfig.ServerName = settings.ServerName
+	}
+	tlsConfig.InsecureSkipVerify = settings.InsecureSkipVerify
+
+	proxy := ""
+	if settings.Proxy != nil {
+		proxy = settings.Proxy.String()
+	}
+	insecure := "verify"
+	if settings.InsecureSkipVerify {
+		insecure = "insecure"
+	}
+
+	c.TLSConfig = tlsConfig
+	c.proxy = settings.Proxy
+	c.identity = fingerprint([]byte(c.identity), settings.CA, []byte(fmt.Sprintf("%d|%s|%s|%s",
+		settings.MinVersion, settings.ServerName, insecure, proxy)))
+	return c, nil
+}
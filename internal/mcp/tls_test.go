@@ -0,0 +1,140 @@
+package mcp
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dotaiv1alpha1 "github.com/vfarcic/dot-ai-controller/api/v1alpha1"
+)
+
+func TestResolveTLSSettings_CABundle(t *testing.T) {
+	var serverName string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverName = r.TLS.ServerName
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+	server.StartTLS()
+	defer server.Close()
+
+	serverCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	fakeClient := newFakeClient(t, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "internal-ca", Namespace: "default"},
+		Data:       map[string]string{"ca.crt": string(serverCA)},
+	})
+	ctx := context.Background()
+
+	// The system roots do not trust the test server
+	_, err := NewHTTPClient().Get(server.URL)
+	require.Error(t, err)
+
+	// The httptest certificate is valid for example.com, which is sent with SNI instead of the address
+	cfg := &dotaiv1alpha1.TLSConfig{
+		CAConfigMapRef: &dotaiv1alpha1.ConfigMapReference{Name: "internal-ca", Key: "ca.crt"},
+		MinVersion:     dotaiv1alpha1.TLSVersion13,
+		ServerName:     "example.com",
+	}
+	settings, err := ResolveTLSSettings(ctx, fakeClient, "default", cfg, "")
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), settings.MinVersion)
+
+	credentials, err := Credentials{Token: "token"}.WithTLS(settings)
+	require.NoError(t, err)
+	assert.Equal(t, "token", credentials.Token)
+
+	resp, err := credentials.HTTPClient(nil).Get(server.URL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, "example.com", serverName)
+}
+
+func TestResolveTLSSettings_MinVersion(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	serverCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	fakeClient := newFakeClient(t, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "internal-ca", Namespace: "default"},
+		Data:       map[string][]byte{"ca.crt": serverCA},
+	})
+	ctx := context.Background()
+	caSecretRef := &dotaiv1alpha1.SecretReference{Name: "internal-ca", Key: "ca.crt"}
+
+	settings, err := ResolveTLSSettings(ctx, fakeClient, "default", &dotaiv1alpha1.TLSConfig{CASecretRef: caSecretRef}, "")
+	require.NoError(t, err)
+	credentials, err := Credentials{}.WithTLS(settings)
+	require.NoError(t, err)
+	resp, err := credentials.HTTPClient(nil).Get(server.URL)
+	require.NoError(t, err, "TLS 1.2 is accepted by default")
+	_ = resp.Body.Close()
+
+	settings, err = ResolveTLSSettings(ctx, fakeClient, "default",
+		&dotaiv1alpha1.TLSConfig{CASecretRef: caSecretRef, MinVersion: dotaiv1alpha1.TLSVersion13}, "")
+	require.NoError(t, err)
+	credentials, err = Credentials{}.WithTLS(settings)
+	require.NoError(t, err)
+	_, err = credentials.HTTPClient(nil).Get(server.URL)
+	require.Error(t, err, "a TLS 1.2 server is rejected with minimum version 1.3")
+}
+
+func TestResolveTLSSettings_Proxy(t *testing.T) {
+	var proxiedURL string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedURL = r.URL.String()
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+	defer proxy.Close()
+
+	settings, err := ResolveTLSSettings(context.Background(), newFakeClient(t), "default", nil, proxy.URL)
+	require.NoError(t, err)
+	credentials, err := Credentials{}.WithTLS(settings)
+	require.NoError(t, err)
+
+	resp, err := credentials.HTTPClient(nil).Get("http://dot-ai-mcp.dot-ai.svc:3456/api/v1/openapi")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, "http://dot-ai-mcp.dot-ai.svc:3456/api/v1/openapi", proxiedURL)
+}
+
+func TestResolveTLSSettings_Errors(t *testing.T) {
+	fakeClient := newFakeClient(t, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "not-a-ca", Namespace: "default"},
+		Data:       map[string]string{"ca.crt": "not a certificate"},
+	})
+	ctx := context.Background()
+
+	settings, err := ResolveTLSSettings(ctx, fakeClient, "default", nil, "")
+	require.NoError(t, err)
+	assert.True(t, settings.IsZero())
+	credentials, err := Credentials{Token: "token"}.WithTLS(settings)
+	require.NoError(t, err)
+	assert.Nil(t, credentials.TLSConfig, "zero settings keep the shared pool")
+
+	_, err = ResolveTLSSettings(ctx, fakeClient, "default", &dotaiv1alpha1.TLSConfig{
+		CAConfigMapRef: &dotaiv1alpha1.ConfigMapReference{Name: "missing", Key: "ca.crt"},
+	}, "")
+	assert.ErrorContains(t, err, "ConfigMap 'missing' not found")
+
+	_, err = ResolveTLSSettings(ctx, fakeClient, "default", &dotaiv1alpha1.TLSConfig{MinVersion: "1.1"}, "")
+	assert.ErrorContains(t, err, "unsupported minimum TLS version")
+
+	_, err = ResolveTLSSettings(ctx, fakeClient, "default", nil, "proxy.example.com")
+	assert.ErrorContains(t, err, "invalid proxy URL")
+
+	settings, err = ResolveTLSSettings(ctx, fakeClient, "default", &dotaiv1alpha1.TLSConfig{
+		CAConfigMapRef: &dotaiv1alpha1.ConfigMapReference{Name: "not-a-ca", Key: "ca.crt"},
+	}, "")
+	require.NoError(t, err)
+	_, err = Credentials{}.WithTLS(settings)
+	assert.ErrorContains(t, err, "does not contain a valid certificate")
+}
@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+var (
+	transportsMu sync.Mutex
+	// transports holds one transport per client identity and endpoint
+	transports = make(map[string]*pooledTransport)
+	// maxTransports bounds transports; identities change with every rotated client certificate,
+	// so the least recently used transports are evicted instead of being kept forever
+	maxTransports = 128
+)
+
+// pooledTransport is a transport of an endpoint pool and when it was last used
+type pooledTransport struct {
+	transport *http.Transport
+	lastUsed  time.Time
+}
+
+// endpointPool is an http.RoundTripper that keeps a separate connection pool per endpoint
+// (scheme and host), so that a slow or unreachable MCP server cannot exhaust the idle
+// connections used to reach other servers. Pools of clients that present a certificate
+// are keyed by the certificate, so that connections are never shared across identities.
+// Pools with custom TLS or proxy settings are keyed by those settings in the same way.
+type endpointPool struct {
+	identity  string
+	tlsConfig *tls.Config
+	proxy     *url.URL
+}
+
+// sharedPool is the connection pool of all clients without client certificate, TLS or proxy settings
+var sharedPool = &endpointPool{}
+
+// RoundTrip sends the request through the transport of its endpoint
+func (p *endpointPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	return p.transportFor(req.URL.Scheme + "://" + req.URL.Host).RoundTrip(req)
+}
+
+// transportFor returns the transport of an endpoint, creating it on first use
+func (p *endpointPool) transportFor(endpoint string) *http.Transport {
+	key := p.identity + "|" + endpoint
+
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+
+	if pooled, ok := transports[key]; ok {
+		pooled.lastUsed = time.Now()
+		return pooled.transport
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = CurrentSettings().MaxIdleConnsPerEndpoint
+	transport.IdleConnTimeout = 90 * time.Second
+	if p.tlsConfig != nil {
+		transport.TLSClientConfig = p.tlsConfig.Clone()
+	}
+	if p.proxy != nil {
+		transport.Proxy = http.ProxyURL(p.proxy)
+	}
+	evictLeastRecentlyUsedTransports(maxTransports - 1)
+	transports[key] = &pooledTransport{transport: transport, lastUsed: time.Now()}
+	return transport
+}
+
+// evictLeastRecentlyUsedTransports evicts transports until at most limit remain, closing their
+// idle connections; requests in flight on an evicted transport still complete
+// The caller must hold transportsMu.
+func evictLeastRecentlyUsedTransports(limit int) {
+	for len(transports) > limit {
+		var oldestKey string
+		var oldest *pooledTransport
+		for key, pooled := range transports {
+			if oldest == nil || pooled.lastUsed.Before(oldest.lastUsed) {
+				oldestKey, oldest = key, pooled
+			}
+		}
+		oldest.transport.CloseIdleConnections()
+		delete(transports, oldestKey)
+	}
+}
+
+// CloseIdleConnections closes the idle connections of all endpoints of this pool
+func (p *endpointPool) CloseIdleConnections() {
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+
+	prefix := p.identity + "|"
+	for key, pooled := range transports {
+		if len(key) >= len(prefix) && key[:len(prefix)] == prefix {
+			pooled.transport.CloseIdleConnections()
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// Package tracing provides optional OpenTelemetry tracing for the controller.
+// Spans are recorded around reconcile loops, MCP requests, debounce flushes, git
+// operations and notification sends. Without an OTLP endpoint, spans are no-ops.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// InstrumentationName identifies the controller's tracer
+	InstrumentationName = "github.com/vfarcic/dot-ai-controller"
+
+	// ShutdownTimeout bounds the time spent flushing spans on shutdown
+	ShutdownTimeout = 5 * time.Second
+)
+
+// Config contains configuration for tracing
+type Config struct {
+	// Endpoint is the host:port of the OTLP gRPC collector; tracing is disabled when empty
+	Endpoint string
+
+	// Insecure disables TLS for the connection to the collector
+	Insecure bool
+
+	// SampleRatio is the fraction of new traces that are sampled (0.0-1.0).
+	// Spans of requests with a sampled parent are always recorded.
+	SampleRatio float64
+
+	// ServiceName is reported as the service.name resource attribute
+	ServiceName string
+}
+
+// DefaultConfig returns the default tracing configuration, with tracing disabled
+func DefaultConfig() Config {
+	return Config{
+		SampleRatio: 1.0,
+		ServiceName: "dot-ai-controller",
+	}
+}
+
+// Setup configures the global tracer provider and the W3C trace context propagator.
+// The returned function flushes pending spans and must be called on shutdown.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	// Propagate trace context even without an exporter, so incoming context is passed on
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	if cfg.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	options := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		options = append(options, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	provider := NewTracerProvider(cfg, sdktrace.WithBatcher(exporter))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// NewTracerProvider creates a tracer provider with the service resource and sampler of
+// the configuration. Tests pass an in-memory span processor.
+func NewTracerProvider(cfg Config, options ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = DefaultConfig().ServiceName
+	}
+
+	options = append([]sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	}, options...)
+	return sdktrace.NewTracerProvider(options...)
+}
+
+// Start starts a span with the controller's tracer
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(InstrumentationName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// StartReconcile starts the span of a reconcile loop of a controller
+func StartReconcile(ctx context.Context, controller string, key types.NamespacedName) (context.Context, trace.Span) {
+	attributes := []attribute.KeyValue{attribute.String("k8s.object.name", key.Name)}
+	if key.Namespace != "" {
+		attributes = append(attributes, attribute.String("k8s.namespace.name", key.Namespace))
+	}
+	return Start(ctx, controller+".Reconcile", attributes...)
+}
+
+// End records an error, if any, and ends the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Inject adds the W3C traceparent of the span in the context to outgoing request headers
+func Inject(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
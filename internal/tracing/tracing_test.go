@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// useInMemoryExporter installs a tracer provider that records spans in memory for the test
+func useInMemoryExporter(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := NewTracerProvider(DefaultConfig(), sdktrace.WithSyncer(exporter))
+
+	previousProvider := otel.GetTracerProvider()
+	previousPropagator := otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return exporter
+}
+
+func TestSetup_DisabledWithoutEndpoint(t *testing.T) {
+	shutdown, err := Setup(context.Background(), DefaultConfig())
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	_, span := Start(context.Background(), "noop")
+	assert.False(t, span.SpanContext().IsSampled(), "spans are not recorded without an exporter")
+	span.End()
+}
+
+func TestStartReconcile(t *testing.T) {
+	exporter := useInMemoryExporter(t)
+
+	ctx, reconcileSpan := StartReconcile(context.Background(), "RemediationPolicy",
+		types.NamespacedName{Namespace: "default", Name: "sample-policy"})
+	_, childSpan := Start(ctx, "RemediationPolicy.sendMcpRequest")
+	End(childSpan, errors.New("connection refused"))
+	End(reconcileSpan, nil)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	child, reconcile := spans[0], spans[1]
+
+	assert.Equal(t, "RemediationPolicy.Reconcile", reconcile.Name)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("k8s.object.name", "sample-policy"),
+		attribute.String("k8s.namespace.name", "default"),
+	}, reconcile.Attributes)
+	assert.Equal(t, codes.Unset, reconcile.Status.Code)
+
+	assert.Equal(t, reconcile.SpanContext.SpanID(), child.Parent.SpanID())
+	assert.Equal(t, codes.Error, child.Status.Code)
+	assert.Equal(t, "connection refused", child.Status.Description)
+	require.Len(t, child.Events, 1, "the error is recorded as an event")
+}
+
+func TestInject(t *testing.T) {
+	useInMemoryExporter(t)
+
+	ctx, span := Start(context.Background(), "mcp.request")
+	defer span.End()
+	header := http.Header{}
+	Inject(ctx, header)
+
+	traceparent := header.Get("traceparent")
+	require.NotEmpty(t, traceparent)
+	assert.Contains(t, traceparent, span.SpanContext().TraceID().String())
+	assert.Contains(t, traceparent, span.SpanContext().SpanID().String())
+}